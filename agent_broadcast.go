@@ -0,0 +1,76 @@
+package dash
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// agentBroadcastType is the CONTEXT node type recording a broadcast.
+const agentBroadcastType = "agent_broadcast"
+
+// AgentBroadcast is an announcement from one agent to all other agents.
+type AgentBroadcast struct {
+	ID      string    `json:"id"`
+	From    string    `json:"from"`
+	Message string    `json:"message"`
+	SentAt  time.Time `json:"sent_at"`
+}
+
+// BroadcastToAgents records a broadcast from fromKey as a CONTEXT.agent_broadcast
+// node. Open agents receive it directly from the caller; agents started
+// later load it with ListAgentBroadcasts. The sender never receives its own
+// broadcast.
+func (d *Dash) BroadcastToAgents(ctx context.Context, fromKey, message string) (*AgentBroadcast, error) {
+	if fromKey == "" || message == "" {
+		return nil, fmt.Errorf("from and message are required")
+	}
+	now := time.Now().UTC()
+	b := &AgentBroadcast{
+		ID:      fmt.Sprintf("broadcast-%d-%s", now.UnixMilli(), fromKey),
+		From:    fromKey,
+		Message: message,
+		SentAt:  now,
+	}
+	data, err := json.Marshal(b)
+	if err != nil {
+		return nil, err
+	}
+	if err := d.CreateNode(ctx, &Node{
+		Layer: LayerContext,
+		Type:  agentBroadcastType,
+		Name:  b.ID,
+		Data:  data,
+	}); err != nil {
+		return nil, fmt.Errorf("record broadcast: %w", err)
+	}
+	return b, nil
+}
+
+// ListAgentBroadcasts returns broadcasts sent since the given time, oldest
+// first, excluding those sent by forKey.
+func (d *Dash) ListAgentBroadcasts(ctx context.Context, forKey string, since time.Time) ([]AgentBroadcast, error) {
+	nodes, err := d.SearchByTimeAndType(ctx, LayerContext, []string{agentBroadcastType}, since, 0)
+	if err != nil {
+		return nil, err
+	}
+	return broadcastsFor(nodes, forKey), nil
+}
+
+// broadcastsFor decodes broadcast nodes (newest first) into broadcasts,
+// oldest first, dropping those sent by forKey.
+func broadcastsFor(nodes []*Node, forKey string) []AgentBroadcast {
+	var out []AgentBroadcast
+	for i := len(nodes) - 1; i >= 0; i-- {
+		var b AgentBroadcast
+		if err := json.Unmarshal(nodes[i].Data, &b); err != nil || b.Message == "" {
+			continue
+		}
+		if b.From == forKey {
+			continue
+		}
+		out = append(out, b)
+	}
+	return out
+}
@@ -0,0 +1,35 @@
+package dash
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestBroadcastsForExcludesSender(t *testing.T) {
+	node := func(from, message string, sentAt time.Time) *Node {
+		data, _ := json.Marshal(AgentBroadcast{ID: "b-" + from + "-" + message, From: from, Message: message, SentAt: sentAt})
+		return &Node{Layer: LayerContext, Type: agentBroadcastType, Data: data}
+	}
+	now := time.Now()
+	nodes := []*Node{ // newest first, as SearchByTimeAndType returns them
+		node("planner", "third", now),
+		node("reviewer", "second", now.Add(-time.Minute)),
+		node("planner", "first", now.Add(-2*time.Minute)),
+		{Layer: LayerContext, Type: agentBroadcastType, Data: []byte(`{"from": "x"}`)},
+	}
+
+	got := broadcastsFor(nodes, "planner")
+	if len(got) != 1 || got[0].From != "reviewer" || got[0].Message != "second" {
+		t.Errorf("for planner got %+v, want only the reviewer's broadcast", got)
+	}
+
+	got = broadcastsFor(nodes, "orchestrator")
+	var messages []string
+	for _, b := range got {
+		messages = append(messages, b.Message)
+	}
+	if len(messages) != 3 || messages[0] != "first" || messages[2] != "third" {
+		t.Errorf("for orchestrator got %v, want [first second third]", messages)
+	}
+}
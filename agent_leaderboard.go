@@ -0,0 +1,178 @@
+package dash
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// minLeaderboardOrders is how many work orders an agent needs in the period
+// before it is ranked; below that it is flagged as insufficient data.
+const minLeaderboardOrders = 3
+
+// LeaderboardWeights controls how the leaderboard components are combined
+// into a composite score. Weights are relative; they need not sum to one.
+type LeaderboardWeights struct {
+	MergeRate float64 `json:"merge_rate"`
+	Quality   float64 `json:"quality"`
+	Speed     float64 `json:"speed"`
+	Volume    float64 `json:"volume"`
+}
+
+// DefaultLeaderboardWeights favours work that lands and holds up in review
+// over raw speed and volume.
+var DefaultLeaderboardWeights = LeaderboardWeights{MergeRate: 0.35, Quality: 0.30, Speed: 0.20, Volume: 0.15}
+
+// AgentRank is one agent's leaderboard entry. Rank is 0 for agents with
+// too few work orders, which are listed after the ranked ones.
+type AgentRank struct {
+	Rank         int     `json:"rank,omitempty"`
+	AgentKey     string  `json:"agent_key"`
+	Composite    float64 `json:"composite"`
+	Insufficient bool    `json:"insufficient_data,omitempty"`
+
+	WOCount         int           `json:"wo_count"`
+	MergedCount     int           `json:"merged_count"`
+	RejectedCount   int           `json:"rejected_count"`
+	MergeRate       float64       `json:"merge_rate"`         // merged / (merged + rejected)
+	AvgScore        float64       `json:"avg_score"`          // mean synthesis score, 0-1
+	MeanTimeToMerge time.Duration `json:"mean_time_to_merge"` // created → merged
+	Speed           float64       `json:"speed"`              // fastest ranked agent's MTTM / this agent's, 0-1
+	Volume          float64       `json:"volume"`             // merged / most merged by a ranked agent, 0-1
+}
+
+// AgentLeaderboard ranks agents by a composite of merge rate, synthesis
+// score, time to merge and merged volume over the work_order_event history
+// in period, weighted by Config.LeaderboardWeights. Entries are sorted by
+// composite score, descending.
+func (d *Dash) AgentLeaderboard(ctx context.Context, period TimeRange) ([]AgentRank, error) {
+	observations, err := d.ListAllObservationsByType(ctx, "work_order_event", period)
+	if err != nil {
+		return nil, err
+	}
+	grouped, err := groupEventsByNode(observations)
+	if err != nil {
+		return nil, err
+	}
+	return agentLeaderboard(grouped, d.leaderboardWeights), nil
+}
+
+// agentLeaderboard computes the ranked leaderboard from grouped events.
+// Force-merged work orders are left out: they skipped the pipeline, so they
+// say nothing about the agent's merge rate, quality or speed.
+func agentLeaderboard(grouped map[uuid.UUID][]timestampedEvent, w LeaderboardWeights) []AgentRank {
+	type agentAcc struct {
+		rank       AgentRank
+		scoreSum   float64
+		scoreCount int
+		mergeTime  time.Duration
+	}
+	byAgent := make(map[string]*agentAcc)
+
+	for _, events := range grouped {
+		var agentKey string
+		var score *float64
+		var forced bool
+		statusTimes := make(map[string]time.Time)
+		for _, te := range events {
+			if te.Event.AgentKey != "" && agentKey == "" {
+				agentKey = te.Event.AgentKey
+			}
+			if te.Event.Forced {
+				forced = true
+			}
+			if te.Event.Score != nil {
+				score = te.Event.Score
+			}
+			if _, seen := statusTimes[te.Event.Status]; !seen {
+				statusTimes[te.Event.Status] = te.At
+			}
+		}
+		if agentKey == "" || forced {
+			continue
+		}
+		acc := byAgent[agentKey]
+		if acc == nil {
+			acc = &agentAcc{rank: AgentRank{AgentKey: agentKey}}
+			byAgent[agentKey] = acc
+		}
+		acc.rank.WOCount++
+		if merged, ok := statusTimes[string(WOStatusMerged)]; ok {
+			acc.rank.MergedCount++
+			if created, ok := statusTimes[string(WOStatusCreated)]; ok {
+				acc.mergeTime += merged.Sub(created)
+			}
+		}
+		if _, ok := statusTimes[string(WOStatusRejected)]; ok {
+			acc.rank.RejectedCount++
+		}
+		if score != nil {
+			acc.scoreSum += *score
+			acc.scoreCount++
+		}
+	}
+
+	// Component values, and the bests among ranked agents for normalizing.
+	var fastest time.Duration
+	var mostMerged int
+	ranks := make([]AgentRank, 0, len(byAgent))
+	for _, acc := range byAgent {
+		r := acc.rank
+		if done := r.MergedCount + r.RejectedCount; done > 0 {
+			r.MergeRate = float64(r.MergedCount) / float64(done)
+		}
+		if acc.scoreCount > 0 {
+			r.AvgScore = acc.scoreSum / float64(acc.scoreCount)
+		}
+		if r.MergedCount > 0 {
+			r.MeanTimeToMerge = acc.mergeTime / time.Duration(r.MergedCount)
+		}
+		r.Insufficient = r.WOCount < minLeaderboardOrders
+		if !r.Insufficient {
+			if r.MeanTimeToMerge > 0 && (fastest == 0 || r.MeanTimeToMerge < fastest) {
+				fastest = r.MeanTimeToMerge
+			}
+			mostMerged = max(mostMerged, r.MergedCount)
+		}
+		ranks = append(ranks, r)
+	}
+
+	total := w.MergeRate + w.Quality + w.Speed + w.Volume
+	for i := range ranks {
+		r := &ranks[i]
+		if r.Insufficient {
+			continue
+		}
+		if r.MeanTimeToMerge > 0 {
+			r.Speed = float64(fastest) / float64(r.MeanTimeToMerge)
+		}
+		if mostMerged > 0 {
+			r.Volume = float64(r.MergedCount) / float64(mostMerged)
+		}
+		if total > 0 {
+			r.Composite = (w.MergeRate*r.MergeRate + w.Quality*r.AvgScore + w.Speed*r.Speed + w.Volume*r.Volume) / total
+		}
+	}
+
+	sort.Slice(ranks, func(i, j int) bool {
+		a, b := ranks[i], ranks[j]
+		if a.Insufficient != b.Insufficient {
+			return !a.Insufficient
+		}
+		if a.Composite != b.Composite {
+			return a.Composite > b.Composite
+		}
+		if a.WOCount != b.WOCount {
+			return a.WOCount > b.WOCount
+		}
+		return a.AgentKey < b.AgentKey
+	})
+	for i := range ranks {
+		if !ranks[i].Insufficient {
+			ranks[i].Rank = i + 1
+		}
+	}
+	return ranks
+}
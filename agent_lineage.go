@@ -0,0 +1,185 @@
+package dash
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// maxLineageDepth bounds ancestor walks in case spawn records form a cycle.
+const maxLineageDepth = 32
+
+const (
+	queryLatestAgentSessionByKey = `
+		SELECT id, layer, type, name, data, created_at, updated_at, deleted_at
+		FROM nodes
+		WHERE layer = 'CONTEXT' AND type = 'agent_session'
+		  AND data->>'agent_key' = $1
+		  AND deleted_at IS NULL
+		ORDER BY created_at DESC
+		LIMIT 1`
+
+	queryAgentSessionChildren = `
+		SELECT id, layer, type, name, data, created_at, updated_at, deleted_at
+		FROM nodes
+		WHERE layer = 'CONTEXT' AND type = 'agent_session'
+		  AND deleted_at IS NULL
+		  AND (data->>'spawned_by' = $1
+		       OR id IN (
+		           SELECT source_id FROM edges
+		           WHERE target_id = $2 AND relation = 'generated_by' AND deprecated_at IS NULL))
+		ORDER BY created_at`
+
+	queryAgentSessionsByIDs = `
+		SELECT id, layer, type, name, data, created_at, updated_at, deleted_at
+		FROM nodes
+		WHERE id = ANY($1) AND type = 'agent_session' AND deleted_at IS NULL`
+)
+
+// AgentLineageNode is an agent session with its spawn ancestry and direct children.
+type AgentLineageNode struct {
+	Session   AgentSession   `json:"session"`
+	Ancestors []AgentSession `json:"ancestors"` // root first, ending with the direct parent
+	Children  []AgentSession `json:"children"`
+
+	// MissingParent is set when the chain ends at a spawned_by reference that
+	// no longer resolves to a session (deleted, or spawned by a bare agent key).
+	MissingParent string `json:"missing_parent,omitempty"`
+	// CycleDetected is set when the ancestor walk revisited a session.
+	CycleDetected bool `json:"cycle_detected,omitempty"`
+}
+
+// agentSessionFromNode builds an AgentSession from a CONTEXT.agent_session node.
+func agentSessionFromNode(n *Node) AgentSession {
+	var data map[string]any
+	if err := json.Unmarshal(n.Data, &data); err != nil {
+		data = make(map[string]any)
+	}
+	return agentSessionFromData(n.ID, n.Name, data, n.CreatedAt)
+}
+
+// agentSessionFromData maps agent_session node data onto AgentSession.
+func agentSessionFromData(id uuid.UUID, name string, data map[string]any, createdAt time.Time) AgentSession {
+	agent := AgentSession{
+		ID:        id.String(),
+		Name:      name,
+		SessionID: name,
+		SpawnedAt: createdAt,
+	}
+	if v, ok := data["agent_key"].(string); ok {
+		agent.AgentKey = v
+	}
+	if v, ok := data["mission"].(string); ok {
+		agent.Mission = v
+	}
+	if v, ok := data["status"].(string); ok {
+		agent.Status = v
+	}
+	if v, ok := data["spawned_by"].(string); ok {
+		agent.SpawnedBy = v
+	}
+	if v, ok := data["controller"].(string); ok {
+		agent.Controller = v
+	}
+	if v, ok := data["controller_since"].(string); ok {
+		if t, err := time.Parse(time.RFC3339, v); err == nil {
+			agent.ControllerSince = t
+		}
+	}
+	return agent
+}
+
+// resolveSpawner finds the agent_session node for a spawner, given either a
+// session ID or an agent key (the latest session for that key wins).
+func (d *Dash) resolveSpawner(ctx context.Context, spawner string) (*Node, error) {
+	if spawner == "" {
+		return nil, ErrNodeNotFound
+	}
+	if n, err := d.GetNodeByName(ctx, LayerContext, "agent_session", spawner); err == nil {
+		return n, nil
+	}
+	row := d.db.QueryRowContext(ctx, queryLatestAgentSessionByKey, spawner)
+	n, err := scanNode(row)
+	if err != nil {
+		return nil, ErrNodeNotFound
+	}
+	return n, nil
+}
+
+// parentOf returns the node an agent session was spawned from, preferring
+// the generated_by edge over the spawned_by data field.
+func (d *Dash) parentOf(ctx context.Context, n *Node, spawnedBy string) (*Node, error) {
+	edges, err := d.ListEdgesBySourceRelation(ctx, n.ID, RelationGeneratedBy)
+	if err == nil && len(edges) > 0 {
+		ids := make([]uuid.UUID, len(edges))
+		for i, e := range edges {
+			ids[i] = e.TargetID
+		}
+		rows, qErr := d.db.QueryContext(ctx, queryAgentSessionsByIDs, pq.Array(ids))
+		if qErr == nil {
+			parents, sErr := scanNodes(rows)
+			rows.Close()
+			if sErr == nil && len(parents) > 0 {
+				return parents[0], nil
+			}
+		}
+	}
+	if spawnedBy == "" {
+		return nil, nil
+	}
+	return d.GetNodeByName(ctx, LayerContext, "agent_session", spawnedBy)
+}
+
+// GetAgentLineage returns the spawn ancestry (up to the root) and the direct
+// children of the agent session identified by sessionID.
+func (d *Dash) GetAgentLineage(ctx context.Context, sessionID string) (*AgentLineageNode, error) {
+	node, err := d.GetNodeByName(ctx, LayerContext, "agent_session", sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("agent session %s: %w", sessionID, err)
+	}
+	lineage := &AgentLineageNode{Session: agentSessionFromNode(node)}
+
+	// Walk ancestors, guarding against cycles.
+	seen := map[uuid.UUID]bool{node.ID: true}
+	cur, curSession := node, lineage.Session
+	for depth := 0; depth < maxLineageDepth; depth++ {
+		parent, err := d.parentOf(ctx, cur, curSession.SpawnedBy)
+		if err != nil {
+			lineage.MissingParent = curSession.SpawnedBy
+			break
+		}
+		if parent == nil {
+			break
+		}
+		if seen[parent.ID] {
+			lineage.CycleDetected = true
+			break
+		}
+		seen[parent.ID] = true
+		curSession = agentSessionFromNode(parent)
+		lineage.Ancestors = append([]AgentSession{curSession}, lineage.Ancestors...)
+		cur = parent
+	}
+
+	rows, err := d.db.QueryContext(ctx, queryAgentSessionChildren, node.Name, node.ID)
+	if err != nil {
+		return nil, fmt.Errorf("agent children: %w", err)
+	}
+	defer rows.Close()
+	children, err := scanNodes(rows)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range children {
+		if c.ID == node.ID {
+			continue
+		}
+		lineage.Children = append(lineage.Children, agentSessionFromNode(c))
+	}
+
+	return lineage, nil
+}
@@ -0,0 +1,100 @@
+package dash
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestAgentSessionFromData(t *testing.T) {
+	id := uuid.New()
+	created := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	s := agentSessionFromData(id, "agent-x-1", map[string]any{
+		"agent_key":        "x",
+		"spawned_by":       "agent-root-1",
+		"controller":       "llm",
+		"controller_since": "2026-01-02T03:04:05Z",
+	}, created)
+
+	if s.ID != id.String() || s.SessionID != "agent-x-1" || s.AgentKey != "x" {
+		t.Errorf("unexpected identity fields: %+v", s)
+	}
+	if s.SpawnedBy != "agent-root-1" {
+		t.Errorf("SpawnedBy = %q", s.SpawnedBy)
+	}
+	if !s.ControllerSince.Equal(created) {
+		t.Errorf("ControllerSince = %v, want %v", s.ControllerSince, created)
+	}
+}
+
+func createTestSession(t *testing.T, d *Dash, name string, data map[string]any) *Node {
+	t.Helper()
+	ctx := context.Background()
+	raw, _ := json.Marshal(data)
+	n := &Node{Layer: LayerContext, Type: "agent_session", Name: name, Data: raw}
+	if err := d.CreateNode(ctx, n); err != nil {
+		t.Fatalf("create session %s: %v", name, err)
+	}
+	t.Cleanup(func() { d.SoftDeleteNode(ctx, n.ID) })
+	return n
+}
+
+func TestGetAgentLineage(t *testing.T) {
+	d := testDash(t)
+	ctx := context.Background()
+	suffix := time.Now().UnixNano()
+
+	root := fmt.Sprintf("agent-root-%d", suffix)
+	mid := fmt.Sprintf("agent-mid-%d", suffix)
+	leaf := fmt.Sprintf("agent-leaf-%d", suffix)
+	createTestSession(t, d, root, map[string]any{"agent_key": "root"})
+	createTestSession(t, d, mid, map[string]any{"agent_key": "mid", "spawned_by": root})
+	createTestSession(t, d, leaf, map[string]any{"agent_key": "leaf", "spawned_by": mid})
+
+	lin, err := d.GetAgentLineage(ctx, mid)
+	if err != nil {
+		t.Fatalf("lineage: %v", err)
+	}
+	if len(lin.Ancestors) != 1 || lin.Ancestors[0].SessionID != root {
+		t.Errorf("ancestors = %+v, want [%s]", lin.Ancestors, root)
+	}
+	if len(lin.Children) != 1 || lin.Children[0].SessionID != leaf {
+		t.Errorf("children = %+v, want [%s]", lin.Children, leaf)
+	}
+
+	orphan := fmt.Sprintf("agent-orphan-%d", suffix)
+	createTestSession(t, d, orphan, map[string]any{"agent_key": "orphan", "spawned_by": "agent-gone"})
+	lin, err = d.GetAgentLineage(ctx, orphan)
+	if err != nil {
+		t.Fatalf("orphan lineage: %v", err)
+	}
+	if lin.MissingParent != "agent-gone" {
+		t.Errorf("MissingParent = %q, want agent-gone", lin.MissingParent)
+	}
+}
+
+func TestGetAgentLineageCycle(t *testing.T) {
+	d := testDash(t)
+	ctx := context.Background()
+	suffix := time.Now().UnixNano()
+
+	a := fmt.Sprintf("agent-a-%d", suffix)
+	b := fmt.Sprintf("agent-b-%d", suffix)
+	createTestSession(t, d, a, map[string]any{"agent_key": "a", "spawned_by": b})
+	createTestSession(t, d, b, map[string]any{"agent_key": "b", "spawned_by": a})
+
+	lin, err := d.GetAgentLineage(ctx, a)
+	if err != nil {
+		t.Fatalf("lineage: %v", err)
+	}
+	if !lin.CycleDetected {
+		t.Error("expected cycle to be detected")
+	}
+	if len(lin.Ancestors) != 1 {
+		t.Errorf("ancestors = %d, want 1", len(lin.Ancestors))
+	}
+}
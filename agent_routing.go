@@ -0,0 +1,109 @@
+package dash
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"unicode"
+)
+
+// ErrNoMatchingAgent is returned by SelectAgentForWorkOrder when no agent's
+// capabilities or scope globs fit the work order.
+var ErrNoMatchingAgent = errors.New("no agent matches the work order")
+
+// SelectAgentForWorkOrder returns the key of the agent that best fits wo,
+// judged by how many of its ScopePaths the agent's ScopeGlobs cover and how
+// many of the agent's Capabilities appear in its description and paths.
+func (d *Dash) SelectAgentForWorkOrder(ctx context.Context, wo *WorkOrder) (string, error) {
+	return selectAgent(LoadAgentDefs(ctx, d), wo)
+}
+
+// agentFit is how well one agent matches a work order.
+type agentFit struct {
+	key          string
+	scopeMatches int // scope paths covered by the agent's globs
+	capMatches   int // capabilities mentioned by the order
+}
+
+// score weighs owning a path above mentioning a capability.
+func (f agentFit) score() int {
+	return 2*f.scopeMatches + f.capMatches
+}
+
+// better reports whether f beats o. Ties go to the agent covering more
+// scope paths, then to the lowest key so routing is deterministic.
+func (f agentFit) better(o agentFit) bool {
+	if f.score() != o.score() {
+		return f.score() > o.score()
+	}
+	if f.scopeMatches != o.scopeMatches {
+		return f.scopeMatches > o.scopeMatches
+	}
+	return f.key < o.key
+}
+
+// selectAgent picks the best-fitting agent in defs for wo.
+func selectAgent(defs []AgentDef, wo *WorkOrder) (string, error) {
+	words := routingWords(wo)
+	var best *agentFit
+	for _, def := range defs {
+		fit := fitAgent(def, wo.ScopePaths, words)
+		if fit.score() == 0 {
+			continue
+		}
+		if best == nil || fit.better(*best) {
+			best = &fit
+		}
+	}
+	if best == nil {
+		return "", ErrNoMatchingAgent
+	}
+	return best.key, nil
+}
+
+// fitAgent matches one agent against the order's scope paths and words.
+func fitAgent(def AgentDef, scopePaths []string, words map[string]bool) agentFit {
+	fit := agentFit{key: def.Key}
+	for _, p := range scopePaths {
+		for _, g := range def.ScopeGlobs {
+			if globMatch(g, p) {
+				fit.scopeMatches++
+				break
+			}
+		}
+	}
+	for _, c := range def.Capabilities {
+		if capabilityMentioned(strings.ToLower(c), words) {
+			fit.capMatches++
+		}
+	}
+	return fit
+}
+
+// routingWords is the lowercased set of words in the order's description,
+// name and scope paths. Paths split on separators and dots, so "x.sql"
+// contributes "sql".
+func routingWords(wo *WorkOrder) map[string]bool {
+	text := wo.Description + " " + strings.Join(wo.ScopePaths, " ")
+	if wo.Node != nil {
+		text += " " + wo.Node.Name
+	}
+	words := make(map[string]bool)
+	for _, w := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		words[w] = true
+	}
+	return words
+}
+
+// capabilityMentioned reports whether every word of capability c appears.
+func capabilityMentioned(c string, words map[string]bool) bool {
+	parts := strings.Fields(c)
+	for _, p := range parts {
+		if !words[p] {
+			return false
+		}
+	}
+	return len(parts) > 0
+}
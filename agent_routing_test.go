@@ -0,0 +1,61 @@
+package dash
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSelectAgentMatchesScopeAndCapabilities(t *testing.T) {
+	defs := fallbackAgentDefs()
+	tests := []struct {
+		name string
+		wo   *WorkOrder
+		want string
+	}{
+		{"migration", &WorkOrder{ScopePaths: []string{"sql/migrations/031_x.sql"}}, "database-agent"},
+		{"cockpit ui", &WorkOrder{ScopePaths: []string{"cmd/cockpit/view.go"}, Description: "fix TUI rendering of the footer"}, "cockpit-frontend"},
+		{"core go", &WorkOrder{ScopePaths: []string{"nodes.go"}, Description: "speed up node lookups"}, "cockpit-backend"},
+		{"description only", &WorkOrder{ScopePaths: []string{"notes/x.txt"}, Description: "add an index to the query"}, "database-agent"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := selectAgent(defs, tt.wo)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("selectAgent = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSelectAgentTieBreaking(t *testing.T) {
+	wo := &WorkOrder{ScopePaths: []string{"pkg/a.go"}, Description: "go api"}
+
+	// Equal scores: the agent covering more scope paths wins.
+	defs := []AgentDef{
+		{Key: "a-caps", Capabilities: []string{"go", "api"}},
+		{Key: "b-scope", ScopeGlobs: []string{"**/*.go"}},
+	}
+	if got, _ := selectAgent(defs, wo); got != "b-scope" {
+		t.Errorf("scope tie-break = %q, want b-scope", got)
+	}
+
+	// Fully equal fits: the lowest key wins, whatever the order.
+	defs = []AgentDef{
+		{Key: "zeta", ScopeGlobs: []string{"**/*.go"}},
+		{Key: "alpha", ScopeGlobs: []string{"pkg/**"}},
+	}
+	if got, _ := selectAgent(defs, wo); got != "alpha" {
+		t.Errorf("key tie-break = %q, want alpha", got)
+	}
+}
+
+func TestSelectAgentNoMatch(t *testing.T) {
+	defs := []AgentDef{{Key: "orchestrator"}, {Key: "db", Capabilities: []string{"sql"}}}
+	_, err := selectAgent(defs, &WorkOrder{ScopePaths: []string{"README"}, Description: "typo"})
+	if !errors.Is(err, ErrNoMatchingAgent) {
+		t.Errorf("err = %v, want ErrNoMatchingAgent", err)
+	}
+}
@@ -13,6 +13,13 @@ type AgentDef struct {
 	Description string `json:"description"`
 	Favorite    bool   `json:"favorite"`
 	Mission     string `json:"mission"`
+	TokenBudget int    `json:"token_budget,omitempty"` // cumulative token cap per run; 0 = unlimited
+
+	// Capabilities (languages, domains) and ScopeGlobs (paths the agent
+	// owns) drive SelectAgentForWorkOrder. Agents with neither are never
+	// picked automatically.
+	Capabilities []string `json:"capabilities,omitempty"`
+	ScopeGlobs   []string `json:"scope_globs,omitempty"`
 }
 
 // defaultAgents is the seed list of agents.
@@ -22,7 +29,7 @@ var defaultAgents = []AgentDef{
 		Mission: `Du är ORKESTRATORN — den centrala pipeline-managern i Dash.
 Ditt ansvar:
 - Utvärdera uppgifter och skapa WorkOrders
-- Tilldela till rätt sub-agent
+- Tilldela till rätt sub-agent (work_order assign utan agent_key väljer via capabilities)
 - Övervaka progress, köra build gate och synthesis
 - Besluta merge/reject
 Använd work_order, build_gate, pipeline och spawn_agent verktygen.`,
@@ -35,6 +42,8 @@ Fokusera på:
 - Integration med Dash core APIs
 - Performance och stabilitet
 - Databasinteraktioner`,
+		Capabilities: []string{"go", "backend", "api", "postgresql", "performance"},
+		ScopeGlobs:   []string{"**/*.go"},
 	},
 	{
 		Key: "cockpit-frontend", DisplayName: "🎨 Frontend", Description: "TypeScript/React", Favorite: true,
@@ -44,6 +53,8 @@ Fokusera på:
 - Användarupplevelse och interaktivitet
 - Tangentbordsnavigering
 - Visuell feedback och animationer`,
+		Capabilities: []string{"tui", "bubbletea", "ui", "frontend", "rendering", "keybindings"},
+		ScopeGlobs:   []string{"**/cmd/cockpit/**"},
 	},
 	{
 		Key: "systemprompt-agent", DisplayName: "📝 Prompts", Description: "Prompt engineering", Favorite: true,
@@ -53,6 +64,8 @@ Fokusera på:
 - Skapa tydliga och effektiva instruktioner
 - Anpassa prompts för specifika uppgifter
 - Testa och iterera på prompt-förbättringar`,
+		Capabilities: []string{"prompt", "prompts", "llm"},
+		ScopeGlobs:   []string{"**/*prompt*"},
 	},
 	{
 		Key: "database-agent", DisplayName: "🗄️ DB", Description: "Database ops", Favorite: true,
@@ -62,6 +75,8 @@ Fokusera på:
 - Query-optimering
 - Index-strategier
 - Data integrity och constraints`,
+		Capabilities: []string{"sql", "postgresql", "migration", "schema", "query", "index"},
+		ScopeGlobs:   []string{"**/*.sql", "**/sql/**"},
 	},
 	{
 		Key: "system-agent", DisplayName: "⚙️ System", Description: "Architecture", Favorite: true,
@@ -71,6 +86,8 @@ Fokusera på:
 - API-kontrakt och interfaces
 - Modularitet och separation of concerns
 - Performance och skalbarhet`,
+		Capabilities: []string{"architecture", "design", "interface", "api", "docs"},
+		ScopeGlobs:   []string{"**/*.md", "**/docs/**"},
 	},
 	{
 		Key: "shift-agent", DisplayName: "🔄 Shift", Description: "Handoff", Favorite: true,
@@ -102,6 +119,8 @@ func EnsureDefaultAgents(ctx context.Context, d *Dash) error {
 			"description":  def.Description,
 			"favorite":     def.Favorite,
 			"mission":      def.Mission,
+			"capabilities": def.Capabilities,
+			"scope_globs":  def.ScopeGlobs,
 		}
 		node, err := d.GetOrCreateNode(ctx, LayerAutomation, "agent", def.Key, data)
 		if err != nil {
@@ -134,6 +153,10 @@ func LoadAgentDefs(ctx context.Context, d *Dash) []AgentDef {
 			Description: agentStrVal(data, "description", ""),
 			Favorite:    agentBoolVal(data, "favorite"),
 			Mission:     agentStrVal(data, "mission", ""),
+			TokenBudget: intVal(data, "token_budget"),
+
+			Capabilities: stringSlice(data, "capabilities"),
+			ScopeGlobs:   stringSlice(data, "scope_globs"),
 		}
 		if def.Key == "" {
 			def.Key = n.Name
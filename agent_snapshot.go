@@ -8,9 +8,11 @@ import (
 
 // LiveStatus holds ephemeral cockpit state (not from graph).
 type LiveStatus struct {
-	Streaming bool
-	ToolName  string
-	Exchanges int
+	Streaming   bool
+	ToolName    string
+	Exchanges   int
+	TokensSpent int // cumulative tokens this cockpit session
+	TokenBudget int // 0 = unlimited
 }
 
 // AgentContextSnapshot is the graph's interpretation at a point in time, projected for UI.
@@ -34,6 +36,7 @@ type AgentContextSnapshot struct {
 	TopFiles       []string // Most frequently touched files
 	WorkOrder      *WorkOrderSummary // Active work order for this agent
 	Controller     string   // "human", "llm", "idle"
+	Drift          *DriftReport      // Mission drift of the agent's recent activity, nil if unknown
 }
 
 // WorkOrderSummary is a lightweight WO representation for the agent dashboard.
@@ -2,7 +2,6 @@ package dash
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"path/filepath"
 	"strings"
@@ -243,40 +242,24 @@ func (d *Dash) ContextSearch(ctx context.Context, query string, limit int) ([]Co
 		return nil, nil
 	}
 
-	// Collect file IDs
-	fileIDs := make([]uuid.UUID, len(searchResults))
-	fileIDToResult := make(map[uuid.UUID]*ContextSearchResult)
 	results := make([]ContextSearchResult, len(searchResults))
-
 	for i, sr := range searchResults {
-		fileIDs[i] = sr.ID
 		results[i] = ContextSearchResult{
 			FilePath: sr.Path,
 			Distance: sr.Distance,
 		}
-		fileIDToResult[sr.ID] = &results[i]
 	}
 
-	// Enrich with activity data (last modification, counts)
-	// This is a simplified query - could be optimized with a single CTE
-	for _, id := range fileIDs {
-		row := d.db.QueryRowContext(ctx, `
-			SELECT
-				MAX(CASE WHEN relation = 'modified' THEN occurred_at END) as last_modified,
-				COUNT(CASE WHEN relation = 'modified' THEN 1 END) as modify_count,
-				MAX(CASE WHEN relation = 'observed' THEN occurred_at END) as last_observed
-			FROM edge_events
-			WHERE target_id = $1
-		`, id)
-
-		var lastMod, lastObs *time.Time
-		var modCount int
-		if err := row.Scan(&lastMod, &modCount, &lastObs); err == nil {
-			if r, ok := fileIDToResult[id]; ok {
-				r.LastModified = lastMod
-				r.ModifyCount = modCount
-				r.LastObserved = lastObs
-			}
+	// Enrich with activity data (last modification, counts) in one batch
+	activity, err := d.BatchGetPackActivity(ctx, searchResults)
+	if err != nil {
+		return nil, err
+	}
+	for i, sr := range searchResults {
+		if a, ok := activity[sr.ID]; ok {
+			results[i].LastModified = a.LastModified
+			results[i].ModifyCount = a.ModifyCount
+			results[i].LastObserved = a.LastObserved
 		}
 	}
 
@@ -423,12 +406,5 @@ func splitFilePath(filePath string) (component, stem string) {
 
 // Helper to extract data from node JSON.
 func extractNodeData(node *Node) map[string]any {
-	var data map[string]any
-	if node.Data != nil {
-		json.Unmarshal(node.Data, &data)
-	}
-	if data == nil {
-		data = make(map[string]any)
-	}
-	return data
+	return NodeDataOf(node)
 }
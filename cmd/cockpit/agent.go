@@ -13,7 +13,6 @@ import (
 type observationAgent struct {
 	db            *sql.DB
 	lastCheckedAt time.Time
-	notifications []observationNotification
 	mutedTypes    map[string]bool
 }
 
@@ -31,7 +30,6 @@ func newObservationAgent(db *sql.DB) *observationAgent {
 	return &observationAgent{
 		db:            db,
 		lastCheckedAt: time.Now().Add(-5 * time.Minute), // Start with last 5 minutes
-		notifications: make([]observationNotification, 0),
 		mutedTypes: map[string]bool{
 			"tool_event": false, // We want these
 		},
@@ -74,7 +72,6 @@ func (a *observationAgent) poll(ctx context.Context) ([]observationNotification,
 		notif := a.createNotification(id, obsType, value, observedAt)
 		if notif != nil {
 			notifications = append(notifications, *notif)
-			a.notifications = append(a.notifications, *notif)
 		}
 	}
 
@@ -127,37 +124,6 @@ func (a *observationAgent) createNotification(id, obsType string, value interfac
 	return nil
 }
 
-// getUnreadNotifications returns all unseen notifications
-func (a *observationAgent) getUnreadNotifications() []observationNotification {
-	var unread []observationNotification
-	for i := range a.notifications {
-		if !a.notifications[i].Seen {
-			unread = append(unread, a.notifications[i])
-		}
-	}
-	return unread
-}
-
-// markAsSeen marks notifications as seen
-func (a *observationAgent) markAsSeen(ids []string) {
-	idSet := make(map[string]bool)
-	for _, id := range ids {
-		idSet[id] = true
-	}
-	for i := range a.notifications {
-		if idSet[a.notifications[i].ID] {
-			a.notifications[i].Seen = true
-		}
-	}
-}
-
-// markAllAsSeen marks all notifications as seen
-func (a *observationAgent) markAllAsSeen() {
-	for i := range a.notifications {
-		a.notifications[i].Seen = true
-	}
-}
-
 // extractReasoningMessage extracts a message from agent_reasoning value
 func extractReasoningMessage(value interface{}) string {
 	if m, ok := value.(map[string]interface{}); ok {
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"dash"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// agentBudgetMsg carries the token budget currently stored in the graph for
+// an agent paused by its budget.
+type agentBudgetMsg struct {
+	agentKey string
+	budget   int
+}
+
+// defBudget returns the token budget from an agent's definition, or 0.
+func (m *model) defBudget(agentKey string) int {
+	for _, def := range m.allAgentDefs {
+		if def.Key == agentKey {
+			return def.TokenBudget
+		}
+	}
+	return 0
+}
+
+// checkAgentBudget pauses the agent owning a finished stream round once its
+// cumulative spend reaches its budget. Agents under human control are never
+// paused by the budget.
+func (m *model) checkAgentBudget(owner string) tea.Cmd {
+	tab := m.agents.findByKey(owner)
+	if tab == nil || tab.tokenBudget <= 0 || tab.budgetExhausted || tab.controller == "human" {
+		return nil
+	}
+	spent := tab.chat.meter.spent
+	if spent < tab.tokenBudget {
+		return nil
+	}
+
+	tab.budgetExhausted = true
+	prevController := tab.controller
+	tab.controller = "idle"
+	tab.chat.addSystemMessage(fmt.Sprintf("budget exhausted, paused (%d/%d tokens)", spent, tab.tokenBudget))
+	if oc := m.orchChat(); oc != nil && tab.agentKey != "orchestrator" {
+		oc.addSystemMessage(fmt.Sprintf("[BUDGET] %s paused at %d/%d tokens — raise token_budget with update_agent to resume", tab.agentKey, spent, tab.tokenBudget))
+	}
+	return tea.Batch(
+		pauseAgentCmd(m.d, tab, prevController),
+		recordBudgetExhaustedCmd(m.d, tab, spent),
+	)
+}
+
+// recordBudgetExhaustedCmd stores an agent_budget_exhausted observation on
+// the agent's session (or its definition, for tabs without one).
+func recordBudgetExhaustedCmd(d *dash.Dash, tab *agentTab, spent int) tea.Cmd {
+	agentKey, sessionID, budget := tab.agentKey, tab.sessionID, tab.tokenBudget
+	return func() tea.Msg {
+		if d == nil {
+			return nil
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+		layer, nodeType, name := dash.LayerAutomation, "agent", agentKey
+		if sessionID != "" {
+			layer, nodeType, name = dash.LayerContext, "agent_session", sessionID
+		}
+		_ = d.StoreObservationForNode(ctx, layer, nodeType, name, "agent_budget_exhausted", map[string]any{
+			"agent_key":    agentKey,
+			"session_id":   sessionID,
+			"tokens_spent": spent,
+			"token_budget": budget,
+		})
+		return nil
+	}
+}
+
+// fetchAgentBudgets reads the stored token_budget of every budget-paused
+// agent that has a session, so a budget raised via update_agent is noticed.
+func fetchAgentBudgets(d *dash.Dash, tabs []*agentTab) tea.Cmd {
+	var cmds []tea.Cmd
+	for _, tab := range tabs {
+		if !tab.budgetExhausted || tab.sessionID == "" || d == nil {
+			continue
+		}
+		agentKey, sessionID := tab.agentKey, tab.sessionID
+		cmds = append(cmds, func() tea.Msg {
+			ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+			defer cancel()
+			node, err := d.GetNodeByName(ctx, dash.LayerContext, "agent_session", sessionID)
+			if err != nil {
+				return nil
+			}
+			var data struct {
+				TokenBudget int `json:"token_budget"`
+			}
+			if json.Unmarshal(node.Data, &data) != nil {
+				return nil
+			}
+			return agentBudgetMsg{agentKey: agentKey, budget: data.TokenBudget}
+		})
+	}
+	return tea.Batch(cmds...)
+}
+
+// handleAgentBudget resumes a budget-paused agent whose budget was raised
+// above what it has spent.
+func (m *model) handleAgentBudget(msg agentBudgetMsg) tea.Cmd {
+	tab := m.agents.findByKey(msg.agentKey)
+	if tab == nil || !tab.budgetExhausted || msg.budget <= tab.chat.meter.spent {
+		return nil
+	}
+	tab.tokenBudget = msg.budget
+	tab.budgetExhausted = false
+	tab.chat.addSystemMessage(fmt.Sprintf("budget raised to %d tokens, resumed", msg.budget))
+	if m.activeStreamOwner != "" || tab.chat.streaming {
+		return nil
+	}
+	tab.controller = "llm"
+	tab.chat.appendMsg(dash.ChatMessage{Role: "user", Content: "Budget höjd — fortsätt där du slutade."})
+	return m.beginStream(tab.agentKey, tab.chat)
+}
+
+// budgetRemaining returns the tokens left before the tab is paused, and
+// false when the tab has no budget.
+func (tab *agentTab) budgetRemaining() (int, bool) {
+	if tab.tokenBudget <= 0 {
+		return 0, false
+	}
+	return max(tab.tokenBudget-tab.chat.meter.spent, 0), true
+}
@@ -35,6 +35,10 @@ type agentProgressMsg struct {
 
 // Enhanced spawn with automatic mission assignment
 func (m *model) spawnAgentWithMission(agentKey string) tea.Cmd {
+	if m.observer {
+		m.activeChat().addSystemMessage("Observer mode: spawning disabled.")
+		return nil
+	}
 	// Resolve mission and display name from DB-loaded defs
 	mission := fmt.Sprintf("Du är en %s agent. Analysera koden och föreslå förbättringar.", agentKey)
 	displayName := agentKey
@@ -73,6 +73,8 @@ type agentTab struct {
 	pendingMessage  string // saved input while waiting for lazy spawn
 	activeWorkOrder *activeWO // current work order assigned to this agent
 	answeringQuery  *pendingQuery // non-nil when answering a cross-agent query
+	tokenBudget     int           // cumulative token cap for autonomous runs; 0 = unlimited
+	budgetExhausted bool          // paused by the budget until it is raised
 }
 
 // activeWO holds the essential fields of an active work order for display.
@@ -133,6 +135,16 @@ func (am *agentManager) activateByID(id string) bool {
 	return false
 }
 
+// findByKey returns the first tab for agentKey, or nil.
+func (am *agentManager) findByKey(agentKey string) *agentTab {
+	for _, t := range am.tabs {
+		if t.agentKey == agentKey {
+			return t
+		}
+	}
+	return nil
+}
+
 func (am *agentManager) deactivate() {
 	am.activeIdx = -1
 }
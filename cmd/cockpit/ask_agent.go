@@ -2,6 +2,7 @@ package main
 
 import (
 	"encoding/json"
+	"strings"
 	"time"
 
 	"dash"
@@ -52,6 +53,44 @@ func parseAskResult(resultJSON, toolCallID string) *pendingQuery {
 	}
 }
 
+// waitChain reports whether agent from is waiting, directly or through other
+// agents, on an answer from agent to. pending is the wait-for graph: each
+// query's caller waits on its target. The returned chain runs from from to
+// to, or is nil when there is no such wait.
+func waitChain(pending map[string]*pendingQuery, from, to string) []string {
+	waitsOn := make(map[string][]string)
+	for _, q := range pending {
+		waitsOn[q.callerKey] = append(waitsOn[q.callerKey], q.targetKey)
+	}
+	visited := make(map[string]bool)
+	var walk func(agent string) []string
+	walk = func(agent string) []string {
+		if agent == to {
+			return []string{agent}
+		}
+		if visited[agent] {
+			return nil
+		}
+		visited[agent] = true
+		for _, next := range waitsOn[agent] {
+			if chain := walk(next); chain != nil {
+				return append([]string{agent}, chain...)
+			}
+		}
+		return nil
+	}
+	return walk(from)
+}
+
+// deadlockError is the tool result returned to a caller whose query would
+// close a wait cycle. chain runs from the target back to the caller.
+func deadlockError(q pendingQuery, chain []string) string {
+	cycle := strings.Join(append([]string{q.callerKey}, chain...), " → ")
+	msg := "would deadlock: " + cycle + " are waiting on each other. Answer the pending question first or proceed without asking " + q.targetKey
+	b, _ := json.Marshal(map[string]string{"error": msg, "query_id": q.id})
+	return string(b)
+}
+
 // parseAnswerResult extracts answer_query info from a tool result JSON.
 func parseAnswerResult(resultJSON string) (queryID, answer string) {
 	var data map[string]any
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWaitChainRejectsCycle(t *testing.T) {
+	pending := map[string]*pendingQuery{}
+
+	// A asks B: nothing is waiting on A yet.
+	ab := pendingQuery{id: "q1", callerKey: "A", targetKey: "B"}
+	if chain := waitChain(pending, ab.targetKey, ab.callerKey); chain != nil {
+		t.Fatalf("A→B should not deadlock, got chain %v", chain)
+	}
+	pending[ab.id] = &ab
+
+	// B asks A while A waits on B.
+	ba := pendingQuery{id: "q2", callerKey: "B", targetKey: "A"}
+	chain := waitChain(pending, ba.targetKey, ba.callerKey)
+	if strings.Join(chain, ",") != "A,B" {
+		t.Fatalf("B→A chain = %v, want [A B]", chain)
+	}
+	if msg := deadlockError(ba, chain); !strings.Contains(msg, "would deadlock: B → A → B") {
+		t.Errorf("error = %s", msg)
+	}
+}
+
+func TestWaitChainTransitive(t *testing.T) {
+	pending := map[string]*pendingQuery{
+		"q1": {id: "q1", callerKey: "A", targetKey: "B"},
+		"q2": {id: "q2", callerKey: "B", targetKey: "C"},
+	}
+	if chain := waitChain(pending, "A", "C"); strings.Join(chain, ",") != "A,B,C" {
+		t.Errorf("C→A chain = %v, want [A B C]", chain)
+	}
+	// D is not involved, so D asking A is fine.
+	if chain := waitChain(pending, "A", "D"); chain != nil {
+		t.Errorf("D→A should not deadlock, got %v", chain)
+	}
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"dash"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// maxChatBroadcasts caps how many broadcasts a chat keeps in its prompt.
+const maxChatBroadcasts = 5
+
+// chatToolResultWithBroadcast is sent when executeTools detects a broadcast_agents result.
+type chatToolResultWithBroadcast struct {
+	results   []dash.ChatMessage
+	calls     []streamToolCall
+	broadcast dash.AgentBroadcast
+}
+
+// parseBroadcastResult extracts a broadcast from a broadcast_agents tool result JSON.
+func parseBroadcastResult(resultJSON string) *dash.AgentBroadcast {
+	var data map[string]any
+	if err := json.Unmarshal([]byte(resultJSON), &data); err != nil {
+		return nil
+	}
+	id, _ := data["broadcast_id"].(string)
+	message, _ := data["message"].(string)
+	if id == "" || message == "" {
+		return nil
+	}
+	return &dash.AgentBroadcast{
+		ID:      id,
+		From:    strOr(data["from"], "default"),
+		Message: message,
+		SentAt:  time.Now(),
+	}
+}
+
+// receiveBroadcast shows a broadcast in the chat and keeps it for the
+// system prompt of the next turn.
+func (m *chatModel) receiveBroadcast(b dash.AgentBroadcast) {
+	m.broadcasts = append(m.broadcasts, b)
+	if len(m.broadcasts) > maxChatBroadcasts {
+		m.broadcasts = m.broadcasts[len(m.broadcasts)-maxChatBroadcasts:]
+	}
+	m.addSystemMessage(fmt.Sprintf("📣 BROADCAST från %s: %s", b.From, b.Message))
+}
+
+// broadcastPrompt renders received broadcasts for the system prompt.
+func (m *chatModel) broadcastPrompt() string {
+	if len(m.broadcasts) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString("\n\nBROADCASTS (meddelanden till alla agenter):\n")
+	for _, bc := range m.broadcasts {
+		b.WriteString(fmt.Sprintf("  - [%s] %s: %s\n", bc.SentAt.Format("15:04"), bc.From, bc.Message))
+	}
+	return b.String()
+}
+
+// deliverBroadcast hands a broadcast to every tab except the sender's and
+// remembers it so tabs opened later receive it too.
+func (m *model) deliverBroadcast(b dash.AgentBroadcast) {
+	m.broadcasts = append(m.broadcasts, b)
+	if len(m.broadcasts) > maxChatBroadcasts {
+		m.broadcasts = m.broadcasts[len(m.broadcasts)-maxChatBroadcasts:]
+	}
+	for _, tab := range m.agents.tabs {
+		if tab.agentKey != b.From && tab.chat != nil {
+			tab.chat.receiveBroadcast(b)
+		}
+	}
+}
+
+// broadcastReplayWindow is how far back a newly opened tab looks for stored
+// broadcasts.
+const broadcastReplayWindow = 24 * time.Hour
+
+// broadcastReplayMsg carries the stored broadcasts for a newly opened tab.
+type broadcastReplayMsg struct {
+	agentKey string
+	stored   []dash.AgentBroadcast
+}
+
+// replayBroadcastsCmd loads the broadcasts stored in the graph for a newly
+// opened tab in the background, so tabs opened after a restart see them too.
+// The result arrives as a broadcastReplayMsg.
+func (m *model) replayBroadcastsCmd(agentKey string) tea.Cmd {
+	d := m.d
+	return func() tea.Msg {
+		msg := broadcastReplayMsg{agentKey: agentKey}
+		if d == nil {
+			return msg
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		if stored, err := d.ListAgentBroadcasts(ctx, agentKey, time.Now().Add(-broadcastReplayWindow)); err == nil {
+			msg.stored = stored
+		}
+		return msg
+	}
+}
+
+// replayBroadcasts gives a tab the broadcasts it missed: the stored ones in
+// msg plus any delivered in this cockpit that are not stored. Broadcasts the
+// tab received live in the meantime are not repeated.
+func (m *model) replayBroadcasts(msg broadcastReplayMsg) {
+	chat := m.chatForAgent(msg.agentKey)
+	if chat == nil {
+		return
+	}
+	seen := make(map[string]bool)
+	for _, b := range chat.broadcasts {
+		seen[b.ID] = true
+	}
+	var missed []dash.AgentBroadcast
+	for _, b := range msg.stored {
+		if !seen[b.ID] {
+			seen[b.ID] = true
+			missed = append(missed, b)
+		}
+	}
+	for _, b := range m.broadcasts {
+		if b.From != msg.agentKey && !seen[b.ID] {
+			seen[b.ID] = true
+			missed = append(missed, b)
+		}
+	}
+	if len(missed) > maxChatBroadcasts {
+		missed = missed[len(missed)-maxChatBroadcasts:]
+	}
+	for _, b := range missed {
+		chat.receiveBroadcast(b)
+	}
+}
+
+// handleBroadcast delivers a broadcast, then continues the sender's turn.
+func (m model) handleBroadcast(msg chatToolResultWithBroadcast) (tea.Model, tea.Cmd) {
+	m.deliverBroadcast(msg.broadcast)
+	return m.Update(chatToolResultReady{results: msg.results, calls: msg.calls})
+}
@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
@@ -49,12 +50,22 @@ type chatModel struct {
 	height       int
 
 	errMsg              string
+	budgetExceeded      string // set when the router refused a request over the daily cap
 	toolStatus          string
-	toolIter            int // counts consecutive tool call rounds
-	maxToolIter         int // 0 = unlimited, default 20
-	consecutiveFailures int // counts rounds where ALL tool calls failed
+	toolIter            int  // counts consecutive tool call rounds
+	maxToolIter         int  // 0 = unlimited, default 20
+	consecutiveFailures int  // counts rounds where ALL tool calls failed
+	malformedRetried    bool // the model was already re-prompted for malformed tool arguments
 	showReasoning       bool
 	toolsCollapsed      bool
+	expandedTools       map[string]bool  // tool call ID → show full result
+	fullTools           map[string]bool  // tool call ID → past the file preview to the raw result
+	previews            *previewStore    // file previews of read results
+	forgotten           *forgottenStore  // node IDs deleted by forget calls
+	fullOutputs         *toolOutputStore // untruncated tool results for expanded boxes
+	selectedTool        string           // tool call ID picked with alt+up/down
+	selectedToolLine    int              // content line of the selected tool box, set by renderMessages
+	scrollToSelected    bool
 
 	scopedAgent  string
 	agentMission string
@@ -67,6 +78,21 @@ type chatModel struct {
 	fileCounts map[string]int // file frequency tracker
 
 	answeringQueryInfo *pendingQuery // non-nil when this chat is answering a cross-agent query
+
+	observer bool // read-only: only read tools are offered and executed
+
+	broadcasts []dash.AgentBroadcast // received from other agents, shown in the system prompt
+
+	undoStack []undoEntry // recent clears/forgets, newest last (max maxUndoDepth)
+
+	search *chatSearch // non-nil while the scrollback find is open
+
+	handoffState   string // "state so far" from the last rotation, appended to the system prompt
+	pendingHandoff string // rotation summary that arrived mid-stream, applied when the stream ends
+	rotating       bool   // a rotation summary is being generated
+
+	restoreOffer *chatHistory // persisted chat from an earlier session, restored with ctrl+y
+	persistedSum [32]byte     // hash of the last persisted messages, to skip unchanged saves
 }
 
 // chatToolResultReady is sent when tool execution completes.
@@ -107,16 +133,29 @@ const maxConsecutiveFailures = 3
 // Returns true if the agent should continue (stream next turn), false if stopped.
 func (m *chatModel) handleToolResults(results []dash.ChatMessage) bool {
 	m.appendMsgs(results)
+	m.trackForgetResults(results)
 	m.toolStatus = ""
 	m.scrollToBottom()
 
-	// Count failures in this batch
-	failCount := 0
+	// Count failures in this batch. Malformed arguments get one re-prompt
+	// of their own before they count towards the failure streak.
+	failCount, malformed := 0, 0
 	for _, r := range results {
+		if isMalformedArgsResult(r) {
+			malformed++
+		}
 		if r.ToolError {
 			failCount++
 		}
 	}
+	if malformed == 0 {
+		m.malformedRetried = false
+	} else if !m.malformedRetried {
+		m.malformedRetried = true
+		if failCount == len(results) {
+			return true // leave the streak as is while the model retries
+		}
+	}
 	if failCount == len(results) && len(results) > 0 {
 		m.consecutiveFailures++
 	} else {
@@ -192,6 +231,9 @@ func (m *chatModel) Update(msg tea.Msg, width, height int) tea.Cmd {
 	case chatToolCallMsg:
 		m.streaming = false
 		m.toolIter++
+		if msg.usage != nil {
+			m.meter.set(msg.usage.PromptTokens, msg.usage.CompletionTokens)
+		}
 		var names []string
 		for _, c := range msg.calls {
 			names = append(names, c.Name)
@@ -258,6 +300,7 @@ func (m *chatModel) Update(msg tea.Msg, width, height int) tea.Cmd {
 
 	case chatDoneMsg:
 		if m.streaming {
+			m.budgetExceeded = ""
 			m.streaming = false
 			m.toolStatus = ""
 			if m.streamBuf != "" {
@@ -279,20 +322,30 @@ func (m *chatModel) Update(msg tea.Msg, width, height int) tea.Cmd {
 				m.cancelFn = nil
 			}
 
-			// Auto-rotate at 85% context usage
-			if pct := m.meter.pct(); pct >= 85 {
-				m.clearAndContinue()
-				m.addSystemMessage(fmt.Sprintf("⚡ Context auto-roterad vid %d%% — konversationen fortsätter.", pct))
+			var rotateCmd tea.Cmd
+			if m.pendingHandoff != "" {
+				m.clearAndContinue(m.pendingHandoff)
+				m.addSystemMessage("⚡ Context roterad — konversationen fortsätter.")
+			} else if pct := m.meter.pct(); pct >= 85 {
+				// Auto-rotate at 85% context usage
+				if rotateCmd = m.rotateCmd(); rotateCmd != nil {
+					m.addSystemMessage(fmt.Sprintf("⚡ Context vid %d%% — sammanfattar och roterar...", pct))
+				}
 			}
 
 			m.scrollToBottom()
+			return rotateCmd
 		}
 		return nil
 
 	case chatErrorMsg:
 		m.streaming = false
 		m.toolStatus = ""
-		m.errMsg = msg.err.Error()
+		if errors.Is(msg.err, dash.ErrBudgetExceeded) {
+			m.budgetExceeded = msg.err.Error()
+		} else {
+			m.errMsg = msg.err.Error()
+		}
 		if m.streamBuf != "" {
 			m.appendMsg(dash.ChatMessage{Role: "assistant", Content: m.streamBuf, Reasoning: m.reasoningBuf})
 			m.streamBuf = ""
@@ -312,6 +365,10 @@ func (m *chatModel) Update(msg tea.Msg, width, height int) tea.Cmd {
 }
 
 func (m *chatModel) handleKey(msg tea.KeyMsg) tea.Cmd {
+	if m.handleSearchKey(msg) {
+		return nil
+	}
+
 	// Determine mode string for keybinding resolution
 	mode := "normal"
 	if m.streaming {
@@ -334,6 +391,15 @@ func (m *chatModel) handleKey(msg tea.KeyMsg) tea.Cmd {
 		m.showReasoning = !m.showReasoning
 	case ActionToggleToolCollapse:
 		m.toolsCollapsed = !m.toolsCollapsed
+	case ActionSelectToolPrev:
+		m.selectTool(-1)
+		return nil
+	case ActionSelectToolNext:
+		m.selectTool(1)
+		return nil
+	case ActionToggleToolExpand:
+		m.toggleToolExpand()
+		return nil
 	case ActionSendMessage:
 		return m.sendMessage()
 	case ActionDeleteCharBack:
@@ -378,11 +444,18 @@ func (m *chatModel) handleKey(msg tea.KeyMsg) tea.Cmd {
 		m.input = newInput
 		m.cursorPos += len(runes)
 	case ActionClearChat:
+		m.snapshotForClear()
 		m.messages = nil
 		m.uiMessages = nil
 		m.renderLog = nil
 		m.errMsg = ""
 		m.viewport.GotoTop()
+	case ActionUndo:
+		return m.undo()
+	case ActionRestoreChat:
+		m.restoreChat()
+	case ActionExportChat:
+		m.exportChat()
 	}
 	return nil
 }
@@ -408,6 +481,10 @@ func (m *chatModel) startStream() tea.Cmd {
 	} else {
 		sysPrompt = m.continuationPrompt() // Kort nudge (~100 chars)
 	}
+	sysPrompt += m.broadcastPrompt()
+	if m.handoffState != "" {
+		sysPrompt += "\n\n== SESSIONSTILLSTÅND (före rotation) ==\n" + m.handoffState
+	}
 	apiMsgs := []dash.ChatMessage{{Role: "system", Content: sysPrompt}}
 
 	// Compress old tool results to save context
@@ -443,6 +520,9 @@ func (m *chatModel) filteredTools() []map[string]any {
 	if m.d == nil || m.client == nil {
 		return nil
 	}
+	if m.observer {
+		return m.readOnlyTools()
+	}
 
 	var profileName string
 	switch {
@@ -5,8 +5,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"strings"
+	"time"
 
 	"dash"
+
+	tea "github.com/charmbracelet/bubbletea"
 )
 
 // conversationMessages returns all messages (already clean — only valid API roles).
@@ -49,19 +52,58 @@ func (m *chatModel) compressedConversationMessages() []dash.ChatMessage {
 	return result
 }
 
-// clearAndContinue summarizes the conversation and resets for a fresh context window.
-func (m *chatModel) clearAndContinue() {
-	summary := buildConversationSummary(m.conversationMessages())
+// sessionRotationMsg carries the handoff summary for a chat being rotated.
+type sessionRotationMsg struct {
+	owner   string // scopedAgent of the chat
+	summary string
+}
+
+// rotateCmd summarizes the conversation in the background for a rotation.
+// The result arrives as a sessionRotationMsg and is also stored as a
+// session_handoff_summary observation on the agent. Returns nil while a
+// rotation is already in progress.
+func (m *chatModel) rotateCmd() tea.Cmd {
+	if m.rotating {
+		return nil
+	}
+	m.rotating = true
+	d, owner, msgs, sessionID := m.d, m.scopedAgent, m.conversationMessages(), m.sessionID
+	return func() tea.Msg {
+		if d == nil {
+			return sessionRotationMsg{owner: owner, summary: buildConversationSummary(msgs)}
+		}
+		ctx, cancel := context.WithTimeout(dash.WithLLMSession(context.Background(), sessionID), 30*time.Second)
+		defer cancel()
+		summary, err := d.SummarizeSessionForHandoff(ctx, msgs)
+		if err != nil || summary == "" {
+			summary = buildConversationSummary(msgs)
+		}
+		if owner != "" {
+			_ = d.StoreObservationForNode(ctx, dash.LayerAutomation, "agent", owner, "session_handoff_summary", map[string]any{
+				"agent_key":     owner,
+				"summary":       summary,
+				"message_count": len(msgs),
+			})
+		}
+		return sessionRotationMsg{owner: owner, summary: summary}
+	}
+}
+
+// clearAndContinue resets for a fresh context window. summary becomes the
+// session state carried in the system prompt of the continued session.
+func (m *chatModel) clearAndContinue(summary string) {
 	// Reset all slices, then rebuild in correct order
 	m.messages = nil
 	m.uiMessages = nil
 	m.renderLog = nil
 	m.appendUI("system-marker", "--- session rotated ---")
-	m.appendMsg(dash.ChatMessage{Role: "user", Content: "[Sammanfattning av session]\n" + summary})
-	m.appendMsg(dash.ChatMessage{Role: "assistant", Content: "Förstått. Jag fortsätter med denna kontext."})
-	m.meter = newTokenMeter(m.meter.limit)
+	m.handoffState = summary
+	m.pendingHandoff = ""
+	m.rotating = false
+	m.meter.rotate()
 	m.toolIter = 0
 	m.consecutiveFailures = 0
+	m.malformedRetried = false
 	m.errMsg = ""
 	m.toolStatus = ""
 	m.streamBuf = ""
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"dash"
+)
+
+// exportChat writes the conversation to a Markdown file in the working
+// directory and reports the path as a system message. Reasoning is included
+// while it is shown (ctrl+o). uiMessages are TUI-only and left out.
+func (m *chatModel) exportChat() {
+	if len(m.messages) == 0 {
+		m.addSystemMessage("Export: nothing to save")
+		return
+	}
+	dir, err := os.Getwd()
+	if err != nil {
+		m.addSystemMessage(fmt.Sprintf("Export failed: %v", err))
+		return
+	}
+	now := time.Now()
+	label := m.scopedAgent
+	if label == "" {
+		label = "chat"
+	}
+	path := filepath.Join(dir, fmt.Sprintf("%s-%s.md", label, now.Format("20060102-150405")))
+
+	model := ""
+	if m.client != nil {
+		model = m.client.model
+	}
+	md := renderChatMarkdown(m.messages, chatExportHeader{
+		Agent:     m.scopedAgent,
+		Model:     model,
+		SessionID: m.sessionID,
+		At:        now,
+	}, m.showReasoning)
+	if err := os.WriteFile(path, []byte(md), 0o644); err != nil {
+		m.addSystemMessage(fmt.Sprintf("Export failed: %v", err))
+		return
+	}
+	m.addSystemMessage("Exported conversation to " + path)
+}
+
+// chatExportHeader is the metadata written at the top of an export.
+type chatExportHeader struct {
+	Agent     string
+	Model     string
+	SessionID string
+	At        time.Time
+}
+
+// renderChatMarkdown formats messages as Markdown: a heading per turn,
+// tool calls and their results as collapsible <details> blocks with the
+// payload in code fences, and the system prompt left out.
+func renderChatMarkdown(messages []dash.ChatMessage, h chatExportHeader, reasoning bool) string {
+	var b strings.Builder
+	title := "Conversation"
+	if h.Agent != "" {
+		title += " with " + h.Agent
+	}
+	b.WriteString("# " + title + "\n\n")
+	if h.Model != "" {
+		fmt.Fprintf(&b, "- Model: `%s`\n", h.Model)
+	}
+	if h.SessionID != "" {
+		fmt.Fprintf(&b, "- Session: `%s`\n", h.SessionID)
+	}
+	fmt.Fprintf(&b, "- Exported: %s\n", h.At.Format(time.RFC3339))
+
+	for _, msg := range messages {
+		switch msg.Role {
+		case "user":
+			b.WriteString("\n## User\n\n")
+			b.WriteString(strings.TrimSpace(msg.Content) + "\n")
+		case "assistant":
+			b.WriteString("\n## Assistant\n\n")
+			if reasoning && strings.TrimSpace(msg.Reasoning) != "" {
+				b.WriteString(detailsBlock("Reasoning", "", msg.Reasoning))
+			}
+			if content := strings.TrimSpace(msg.Content); content != "" {
+				b.WriteString(content + "\n")
+			}
+			for _, tc := range msg.ToolCalls {
+				b.WriteString(detailsBlock("Tool call: "+tc.Function.Name, "json", tc.Function.Arguments))
+			}
+		case "tool":
+			summary := "Tool result"
+			if msg.Name != "" {
+				summary += ": " + msg.Name
+			}
+			if msg.ToolError {
+				summary += " (error)"
+			}
+			b.WriteString(detailsBlock(summary, "", msg.Content))
+		}
+	}
+	return b.String()
+}
+
+// detailsBlock renders body in a fenced code block inside a collapsed
+// <details> element.
+func detailsBlock(summary, lang, body string) string {
+	fence := codeFence(body)
+	return fmt.Sprintf("\n<details>\n<summary>%s</summary>\n\n%s%s\n%s\n%s\n\n</details>\n",
+		summary, fence, lang, strings.TrimRight(body, "\n"), fence)
+}
+
+var backtickRunRe = regexp.MustCompile("`{3,}")
+
+// codeFence returns a backtick fence longer than any run of backticks in
+// body, so fenced content cannot close the block early.
+func codeFence(body string) string {
+	n := 3
+	for _, run := range backtickRunRe.FindAllString(body, -1) {
+		n = max(n, len(run)+1)
+	}
+	return strings.Repeat("`", n)
+}
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"dash"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// chatHistoryType is the CONTEXT node type holding a persisted chat, named
+// by the chat's session ID.
+const chatHistoryType = "chat_history"
+
+// chatSnapshotType is the observation type of a chat snapshot. Snapshots are
+// observations rather than node data so saves do not pile up node versions.
+const chatSnapshotType = "chat_snapshot"
+
+// keptChatSnapshots is how many snapshots a chat_history node keeps.
+const keptChatSnapshots = 2
+
+// maxPersistedChatBytes bounds a stored chat; the oldest turns are dropped
+// first.
+const maxPersistedChatBytes = 256 * 1024
+
+// chatHistory is the stored form of a chat. Only API messages are kept;
+// uiMessages are TUI-only and reasoning is never serialized.
+type chatHistory struct {
+	SessionID string             `json:"-"`
+	Agent     string             `json:"agent"`
+	Model     string             `json:"model"`
+	SavedAt   time.Time          `json:"saved_at"`
+	Messages  []dash.ChatMessage `json:"messages"`
+}
+
+// chatRestoreMsg offers the most recent persisted orchestrator chat.
+type chatRestoreMsg struct {
+	history *chatHistory
+}
+
+// persistChatCmd stores the chat's messages as a snapshot observation on its
+// chat_history node in the background. Returns nil for chats without a
+// session or messages, and when nothing changed since the last save.
+func (m *chatModel) persistChatCmd() tea.Cmd {
+	if m.d == nil || m.sessionID == "" || len(m.messages) == 0 || m.streaming {
+		return nil
+	}
+	d, sessionID := m.d, m.sessionID
+	h := chatHistory{
+		Agent:    m.scopedAgent,
+		SavedAt:  time.Now().UTC(),
+		Messages: boundChatHistory(m.compressedConversationMessages(), maxPersistedChatBytes),
+	}
+	if m.client != nil {
+		h.Model = m.client.model
+	}
+	data, err := json.Marshal(h)
+	if err != nil {
+		return nil
+	}
+	body, _ := json.Marshal(h.Messages)
+	sum := sha256.Sum256(append([]byte(h.Model), body...))
+	if sum == m.persistedSum {
+		return nil
+	}
+	m.persistedSum = sum
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		node, err := d.GetOrCreateNode(ctx, dash.LayerContext, chatHistoryType, sessionID, map[string]any{"agent": h.Agent})
+		if err != nil {
+			return nil
+		}
+		// Saves run concurrently; observed_at orders them by save time.
+		err = d.CreateObservation(ctx, &dash.Observation{
+			NodeID:     node.ID,
+			Type:       chatSnapshotType,
+			Data:       data,
+			ObservedAt: h.SavedAt,
+		})
+		if err == nil {
+			d.PruneObservations(ctx, node.ID, chatSnapshotType, keptChatSnapshots)
+		}
+		return nil
+	}
+}
+
+// boundChatHistory drops the oldest messages until the JSON encoding fits
+// maxBytes, then skips ahead to a user message so the history still starts
+// a valid API turn.
+func boundChatHistory(msgs []dash.ChatMessage, maxBytes int) []dash.ChatMessage {
+	size := 0
+	sizes := make([]int, len(msgs))
+	for i, msg := range msgs {
+		b, _ := json.Marshal(msg)
+		sizes[i] = len(b)
+		size += sizes[i]
+	}
+	start := 0
+	for start < len(msgs) && size > maxBytes {
+		size -= sizes[start]
+		start++
+	}
+	if start > 0 {
+		for start < len(msgs) && msgs[start].Role != "user" {
+			start++
+		}
+	}
+	return msgs[start:]
+}
+
+// fetchChatRestore loads the most recent orchestrator chat persisted by an
+// earlier cockpit session, if any.
+func fetchChatRestore(d *dash.Dash, currentSessionID string) tea.Cmd {
+	return func() tea.Msg {
+		if d == nil {
+			return nil
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		layer, typ := dash.LayerContext, chatHistoryType
+		nodes, err := d.SearchNodes(ctx, dash.NodeFilter{
+			Layer:      &layer,
+			Type:       &typ,
+			DataFilter: map[string]any{"agent": "orchestrator"},
+			Limit:      5,
+		})
+		if err != nil {
+			return nil
+		}
+		for _, n := range nodes {
+			if n.Name == currentSessionID {
+				continue
+			}
+			// Chats saved before snapshots were observations keep their
+			// messages in the node data.
+			raw := []byte(n.Data)
+			if obs, err := d.GetLatestObservation(ctx, n.ID, chatSnapshotType); err == nil && obs != nil {
+				raw = obs.Data
+			}
+			var h chatHistory
+			if json.Unmarshal(raw, &h) != nil || len(h.Messages) == 0 {
+				continue
+			}
+			h.SessionID = n.Name
+			return chatRestoreMsg{history: &h}
+		}
+		return nil
+	}
+}
+
+// offerRestore tells the user an earlier chat can be restored with ctrl+y.
+// The offer only stands while the chat is still empty.
+func (m *chatModel) offerRestore(h *chatHistory) {
+	if len(m.messages) > 0 {
+		return
+	}
+	m.restoreOffer = h
+	m.addSystemMessage(fmt.Sprintf("Föregående chatt finns (%d meddelanden, %s) — ctrl+y återställer",
+		len(h.Messages), formatReplayGap(time.Since(h.SavedAt))+" sedan"))
+}
+
+// restoreChat loads the offered history into the chat.
+func (m *chatModel) restoreChat() {
+	h := m.restoreOffer
+	m.restoreOffer = nil
+	if h == nil || len(m.messages) > 0 {
+		return
+	}
+	m.appendMsgs(h.Messages)
+	m.addSystemMessage(fmt.Sprintf("--- chatt återställd från %s ---", h.SessionID))
+}
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// chatSearch is an in-chat find over the rendered scrollback. It matches
+// the lines renderMessages produces, so message content, UI markers, tool
+// boxes and reasoning (only while showReasoning is on) are searchable.
+type chatSearch struct {
+	query   []rune
+	editing bool  // prompt open; false once enter confirms the query
+	matches []int // content line numbers, top to bottom
+	current int   // index into matches
+	jump    bool  // scroll to the current match on the next render
+}
+
+// capturesSearchKey reports whether msg belongs to the search: any key while
+// the prompt is open, n/N/esc/"/" while browsing matches, and "/" on an
+// empty input outside of streaming.
+func (m *chatModel) capturesSearchKey(msg tea.KeyMsg) bool {
+	if m.search != nil {
+		if m.search.editing {
+			return true
+		}
+		switch msg.String() {
+		case "n", "N", "esc", "/":
+			return len(m.input) == 0
+		}
+		return false
+	}
+	return !m.streaming && len(m.input) == 0 && msg.String() == "/"
+}
+
+// handleSearchKey edits the query or moves between matches. Keys that do not
+// belong to the search close it and return false so normal handling applies.
+func (m *chatModel) handleSearchKey(msg tea.KeyMsg) bool {
+	if !m.capturesSearchKey(msg) {
+		m.search = nil
+		return false
+	}
+	if m.search == nil {
+		m.search = &chatSearch{editing: true}
+		return true
+	}
+	s := m.search
+
+	if !s.editing {
+		switch msg.String() {
+		case "n":
+			s.step(-1) // older match, like vim's n after ?
+		case "N":
+			s.step(1)
+		case "/":
+			s.editing = true
+		case "esc":
+			m.search = nil
+		}
+		return true
+	}
+
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.search = nil
+	case tea.KeyEnter:
+		if len(s.query) == 0 {
+			m.search = nil
+			return true
+		}
+		s.editing = false
+		s.current = -1 // first step lands on the newest match
+		s.step(-1)
+	case tea.KeyBackspace:
+		if len(s.query) > 0 {
+			s.query = s.query[:len(s.query)-1]
+		}
+	case tea.KeySpace:
+		s.query = append(s.query, ' ')
+	case tea.KeyRunes:
+		s.query = append(s.query, msg.Runes...)
+	}
+	return true
+}
+
+// step moves the current match by delta, wrapping around. A negative delta
+// moves up (towards older messages).
+func (s *chatSearch) step(delta int) {
+	s.jump = true
+	n := len(s.matches)
+	if n == 0 {
+		return
+	}
+	if s.current < 0 {
+		s.current = n - 1
+		return
+	}
+	s.current = ((s.current+delta)%n + n) % n
+}
+
+// highlight finds the query in the rendered content, records the matching
+// lines and returns the content with matches highlighted. Matched lines are
+// re-rendered as plain text so the highlight is not broken by their styles.
+func (s *chatSearch) highlight(content string) string {
+	s.matches = s.matches[:0]
+	query := strings.ToLower(string(s.query))
+	if query == "" {
+		return content
+	}
+
+	lines := strings.Split(content, "\n")
+	for i, line := range lines {
+		plain := ansi.Strip(line)
+		if !strings.Contains(strings.ToLower(plain), query) {
+			continue
+		}
+		style := searchMatch
+		if len(s.matches) == s.current {
+			style = searchCurrent
+		}
+		s.matches = append(s.matches, i)
+		lines[i] = highlightMatches(plain, query, style.Render)
+	}
+	if s.current >= len(s.matches) {
+		s.current = len(s.matches) - 1
+	}
+	return strings.Join(lines, "\n")
+}
+
+// highlightMatches wraps every case-insensitive occurrence of query (already
+// lowercase) in line with render.
+func highlightMatches(line, query string, render func(...string) string) string {
+	lower := strings.ToLower(line)
+	if len(lower) != len(line) {
+		// Case folding changed byte offsets; fall back to highlighting the line.
+		return render(line)
+	}
+	var b strings.Builder
+	for {
+		idx := strings.Index(lower, query)
+		if idx < 0 {
+			b.WriteString(line)
+			return b.String()
+		}
+		b.WriteString(line[:idx])
+		b.WriteString(render(line[idx : idx+len(query)]))
+		line, lower = line[idx+len(query):], lower[idx+len(query):]
+	}
+}
+
+// searchPrompt renders the input line while a search is active.
+func (m *chatModel) searchPrompt() string {
+	s := m.search
+	status := textDim.Render("  no matches")
+	if len(s.matches) > 0 {
+		status = textDim.Render(fmt.Sprintf("  %d/%d", s.current+1, len(s.matches)))
+	}
+	if s.editing {
+		if len(s.matches) > 0 {
+			status = textDim.Render(fmt.Sprintf("  %d matches", len(s.matches)))
+		}
+		return chatInput.Render("  / ") + string(s.query) + chatCursor.Render("|") + status + textDim.Render("  [enter] find  [esc] cancel")
+	}
+	return chatInput.Render("  / ") + string(s.query) + status + textDim.Render("  [n/N] older/newer  [/] edit  [esc] clear")
+}
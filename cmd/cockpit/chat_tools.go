@@ -3,12 +3,31 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"fmt"
+	"strings"
 
 	"dash"
 
 	tea "github.com/charmbracelet/bubbletea"
 )
 
+// malformedArgsPrefix starts the tool result sent back when a call's
+// arguments are not valid JSON, so handleToolResults can tell a formatting
+// slip from a tool that actually ran and failed.
+const malformedArgsPrefix = "your arguments for tool "
+
+// malformedArgsResult builds the error result that re-prompts the model with
+// the parse error instead of running the tool with empty arguments.
+func malformedArgsResult(c streamToolCall, err error) dash.ChatMessage {
+	msg := fmt.Sprintf("%s%s were not valid JSON: %v. Call the tool again with a single valid JSON object as arguments.", malformedArgsPrefix, c.Name, err)
+	return dash.NewToolResult(c.ID, c.Name, msg, true)
+}
+
+// isMalformedArgsResult reports whether r came from malformedArgsResult.
+func isMalformedArgsResult(r dash.ChatMessage) bool {
+	return r.ToolError && strings.HasPrefix(r.Content, malformedArgsPrefix)
+}
+
 // fileToolNames lists tools that operate on files.
 var fileToolNames = map[string]string{
 	"read": "file_path", "write": "file_path", "edit": "file_path",
@@ -41,23 +60,59 @@ func (m *chatModel) trackFileFromTool(toolName string, args map[string]any) {
 	}
 }
 
+// toolCallerID namespaces the agent key so nodes a cockpit agent creates
+// are stamped with that agent rather than with "cockpit" as a whole.
+func toolCallerID(agentKey string) string {
+	if agentKey == "" {
+		return "cockpit"
+	}
+	return "cockpit:" + agentKey
+}
+
 func (m *chatModel) executeTools(calls []streamToolCall) tea.Cmd {
 	d := m.d
 	sessionID := m.sessionID
 	callerKey := m.scopedAgent
+	observer := m.observer
+	if m.previews == nil {
+		m.previews = &previewStore{}
+	}
+	previews := m.previews
+	if m.forgotten == nil {
+		m.forgotten = &forgottenStore{}
+	}
+	forgotten := m.forgotten
+	if m.fullOutputs == nil {
+		m.fullOutputs = &toolOutputStore{}
+	}
+	outputs := m.fullOutputs
 	return func() tea.Msg {
-		ctx := context.Background()
+		// Tag the caller so spawn_agent can record who spawned whom.
+		ctx := dash.WithLLMAgent(context.Background(), callerKey)
 		var toolResults []dash.ChatMessage
 		var spawnInfo *agentSpawnInfo
 		var askQuery *pendingQuery
 		var planReqInfo *planRequestInfo
+		var review *peerReviewDispatch
 		var answerQueryID, answerText string
+		var broadcast *dash.AgentBroadcast
 		for _, c := range calls {
 			var args map[string]any
-			if err := json.Unmarshal([]byte(c.ArgsBuf.String()), &args); err != nil {
+			if raw := strings.TrimSpace(c.ArgsBuf.String()); raw != "" {
+				if err := json.Unmarshal([]byte(raw), &args); err != nil {
+					toolResults = append(toolResults, malformedArgsResult(c, err))
+					continue
+				}
+			}
+			if args == nil {
 				args = map[string]any{}
 			}
 
+			if observer && !isReadOnlyTool(d, c.Name) {
+				toolResults = append(toolResults, dash.NewToolResult(c.ID, c.Name, `{"error": "observer mode: only read-only tools are allowed"}`, true))
+				continue
+			}
+
 			if d != nil {
 				// Self-ask guard
 				if c.Name == "ask_agent" {
@@ -70,7 +125,7 @@ func (m *chatModel) executeTools(calls []streamToolCall) tea.Cmd {
 
 				result := d.RunTool(ctx, c.Name, args, &dash.ToolOpts{
 					SessionID: sessionID,
-					CallerID:  "cockpit",
+					CallerID:  toolCallerID(callerKey),
 				})
 				if result.Success {
 					resultJSON, _ := json.Marshal(result.Data)
@@ -90,6 +145,10 @@ func (m *chatModel) executeTools(calls []streamToolCall) tea.Cmd {
 							planReqInfo = info
 						}
 					}
+					// Detect request_peer_review results
+					if c.Name == "request_peer_review" {
+						review = parseReviewResult(resultText)
+					}
 					// Detect ask_agent results
 					if c.Name == "ask_agent" {
 						if q := parseAskResult(resultText, c.ID); q != nil {
@@ -97,6 +156,10 @@ func (m *chatModel) executeTools(calls []streamToolCall) tea.Cmd {
 							askQuery = q
 						}
 					}
+					// Detect broadcast_agents results
+					if c.Name == "broadcast_agents" {
+						broadcast = parseBroadcastResult(resultText)
+					}
 					// Detect answer_query results
 					if c.Name == "answer_query" {
 						answerQueryID, _ = parseAnswerResult(resultText)
@@ -104,15 +167,22 @@ func (m *chatModel) executeTools(calls []streamToolCall) tea.Cmd {
 						answerText = answerRaw
 					}
 
-					if len(resultText) > 4000 {
-						resultText = resultText[:4000] + "\n... (truncated)"
+					// Remember what forget deleted, for undo; the text may be truncated
+					if c.Name == "forget" {
+						forgotten.put(c.ID, forgetDeletedIDs(result.Data))
+					}
+
+					// Keep a preview of read files before the result is cut short
+					if c.Name == "read" {
+						if p := parseFilePreview(resultText); p != nil {
+							previews.put(c.ID, p)
+						}
 					}
+
+					resultText = truncateToolMessage(outputs, c.ID, resultText)
 					toolResults = append(toolResults, dash.NewToolResult(c.ID, c.Name, resultText, false))
 				} else {
-					errText := result.Error
-					if len(errText) > 4000 {
-						errText = errText[:4000] + "\n... (truncated)"
-					}
+					errText := truncateToolMessage(outputs, c.ID, result.Error)
 					toolResults = append(toolResults, dash.NewToolResult(c.ID, c.Name, errText, true))
 				}
 			} else {
@@ -120,7 +190,7 @@ func (m *chatModel) executeTools(calls []streamToolCall) tea.Cmd {
 			}
 		}
 
-		// Priority: answer > ask > planRequest > spawn > normal
+		// Priority: answer > ask > planRequest > review > spawn > broadcast > normal
 		if answerQueryID != "" {
 			return chatToolResultWithAnswer{
 				results: toolResults,
@@ -146,6 +216,13 @@ func (m *chatModel) executeTools(calls []streamToolCall) tea.Cmd {
 				priority:  planReqInfo.priority,
 			}
 		}
+		if review != nil {
+			return chatToolResultWithReview{
+				results: toolResults,
+				calls:   calls,
+				review:  *review,
+			}
+		}
 		if spawnInfo != nil {
 			return chatToolResultWithSpawn{
 				results: toolResults,
@@ -153,6 +230,13 @@ func (m *chatModel) executeTools(calls []streamToolCall) tea.Cmd {
 				spawn:   *spawnInfo,
 			}
 		}
+		if broadcast != nil {
+			return chatToolResultWithBroadcast{
+				results:   toolResults,
+				calls:     calls,
+				broadcast: *broadcast,
+			}
+		}
 		return chatToolResultReady{results: toolResults, calls: calls}
 	}
 }
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"dash"
+)
+
+func testDash(t *testing.T) *dash.Dash {
+	t.Helper()
+	dbURL := os.Getenv("DASH_TEST_DATABASE_URL")
+	if dbURL == "" {
+		t.Skip("DASH_TEST_DATABASE_URL not set")
+	}
+	db, err := sql.Open("postgres", dbURL)
+	if err != nil {
+		t.Fatalf("db open: %v", err)
+	}
+	if err := db.Ping(); err != nil {
+		db.Close()
+		t.Skipf("db ping: %v", err)
+	}
+	d, err := dash.New(dash.Config{DB: db})
+	if err != nil {
+		db.Close()
+		t.Fatalf("new dash: %v", err)
+	}
+	t.Cleanup(func() { d.Close() })
+	return d
+}
+
+func TestToolCallerID(t *testing.T) {
+	if got := toolCallerID(""); got != "cockpit" {
+		t.Errorf("unscoped = %q, want cockpit", got)
+	}
+	if got := toolCallerID("planner"); got != "cockpit:planner" {
+		t.Errorf("scoped = %q, want cockpit:planner", got)
+	}
+}
+
+func TestToolCallerIDSeparatesAgents(t *testing.T) {
+	d := testDash(t)
+	suffix := time.Now().UnixNano()
+	agents := []string{fmt.Sprintf("agent-a-%d", suffix), fmt.Sprintf("agent-b-%d", suffix)}
+
+	created := map[string]string{}
+	for _, agent := range agents {
+		ctx := dash.WithLLMAgent(context.Background(), agent)
+		res := d.RunTool(ctx, "remember", map[string]any{"type": "insight", "text": agent + " insight"},
+			&dash.ToolOpts{CallerID: toolCallerID(agent)})
+		if !res.Success {
+			t.Fatalf("remember as %s: %s", agent, res.Error)
+		}
+		data, _ := res.Data.(map[string]any)
+		created[agent] = fmt.Sprint(data["id"])
+	}
+
+	for _, agent := range agents {
+		nodes, err := d.NodesByCreator(context.Background(), toolCallerID(agent))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, n := range nodes {
+			t.Cleanup(func() { d.SoftDeleteNode(context.Background(), n.ID) })
+		}
+		if len(nodes) != 1 || nodes[0].ID.String() != created[agent] {
+			t.Errorf("%s: got %d nodes, want only its own insight", agent, len(nodes))
+		}
+	}
+}
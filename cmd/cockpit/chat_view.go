@@ -51,6 +51,12 @@ func (m *chatModel) renderMessages(width int) string {
 				}
 			case "control-release":
 				content.WriteString(textCyan.Render("  ── "+ui.Content+" ──") + "\n")
+			case "completion-report":
+				head, rest, _ := strings.Cut(ui.Content, "\n")
+				content.WriteString("  " + textSuccess.Render(head) + "\n")
+				if rest != "" {
+					content.WriteString(textDim.Render(rest) + "\n")
+				}
 			}
 		} else {
 			if entry.Idx >= len(m.messages) {
@@ -77,13 +83,31 @@ func (m *chatModel) renderMessages(width int) string {
 				isLastAssistant := entry.Idx == lastAssistantIdx
 				for _, tc := range msg.ToolCalls {
 					if result, ok := toolResults[tc.ID]; ok {
-						if m.toolsCollapsed && !isLastAssistant {
+						// Selected results (alt+up/down) get a marker in the gutter
+						gutter := "  "
+						if tc.ID == m.selectedTool {
+							gutter = textCyan.Render("▍ ")
+							m.selectedToolLine = strings.Count(content.String(), "\n")
+						}
+						if m.expandedTools[tc.ID] {
+							full := result
+							if out, ok := m.fullOutputs.get(tc.ID); ok {
+								full = out
+							}
+							box := renderToolBoxFull(tc, full, boxWidth)
+							if p := m.previews.get(tc.ID); p != nil && !m.fullTools[tc.ID] {
+								box = renderFilePreview(tc, p, boxWidth)
+							}
+							for _, line := range strings.Split(box, "\n") {
+								content.WriteString(gutter + line + "\n")
+							}
+						} else if m.toolsCollapsed && !isLastAssistant {
 							// Collapsed: one-line badge
-							content.WriteString("  " + renderToolBadge(tc, result, boxWidth) + "\n")
+							content.WriteString(gutter + renderToolBadge(tc, result, boxWidth) + "\n")
 						} else {
 							box := renderToolBox(tc, result, boxWidth)
 							for _, line := range strings.Split(box, "\n") {
-								content.WriteString("  " + line + "\n")
+								content.WriteString(gutter + line + "\n")
 							}
 						}
 					} else {
@@ -120,6 +144,9 @@ func (m *chatModel) renderMessages(width int) string {
 	if m.toolStatus != "" {
 		content.WriteString(textDim.Render("  "+m.toolStatus) + "\n")
 	}
+	if m.budgetExceeded != "" {
+		content.WriteString("  " + budgetBanner.Render("BUDGET STOP: "+m.budgetExceeded+" — resets at midnight") + "\n")
+	}
 	if m.errMsg != "" {
 		content.WriteString(textAlert.Render("  Error: "+m.errMsg) + "\n")
 	}
@@ -144,9 +171,21 @@ func (m *chatModel) View(width, height int) string {
 
 	// Build and set content
 	content := m.renderMessages(width)
+	if m.search != nil {
+		content = m.search.highlight(content)
+	}
 	wasAtBottom := m.viewport.AtBottom()
 	m.viewport.SetContent(content)
-	if wasAtBottom || m.streaming {
+	if s := m.search; s != nil && !m.streaming {
+		// Stay where the search put the viewport instead of following the bottom
+		if s.jump && s.current >= 0 && s.current < len(s.matches) {
+			m.viewport.SetYOffset(max(s.matches[s.current]-vpH/3, 0))
+		}
+		s.jump = false
+	} else if m.scrollToSelected && m.selectedTool != "" {
+		m.viewport.SetYOffset(m.selectedToolLine)
+		m.scrollToSelected = false
+	} else if wasAtBottom || m.streaming {
 		m.viewport.GotoBottom()
 	}
 
@@ -166,7 +205,9 @@ func (m *chatModel) View(width, height int) string {
 	} else {
 		prompt = chatInput.Render("  > ")
 	}
-	if m.streaming {
+	if m.search != nil {
+		out.WriteString(m.searchPrompt())
+	} else if m.streaming {
 		out.WriteString(prompt + textDim.Render("streaming..."))
 	} else {
 		inputStr := string(m.input)
@@ -0,0 +1,264 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"dash"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// compareOwnerPrefix marks stream messages that belong to the compare view;
+// the side index follows the prefix.
+const compareOwnerPrefix = "compare:"
+
+// compareSide is one model's half of a comparison.
+type compareSide struct {
+	model   string
+	buf     strings.Builder
+	usage   *apiUsage
+	ch      chan any
+	done    bool
+	err     error
+	elapsed time.Duration
+}
+
+// compareView sends one prompt to two models at once and streams the
+// answers side by side. No tools are offered, so both sides are a pure
+// response comparison.
+type compareView struct {
+	client  *chatClient
+	sides   [2]*compareSide
+	input   []rune
+	prompt  string
+	focus   int
+	first   int // side that finished first, -1 until one has
+	started time.Time
+	cancel  context.CancelFunc
+}
+
+// newCompareView starts with the current chat model on the left and the
+// next available model on the right.
+func newCompareView(client *chatClient) *compareView {
+	right := client.model
+	if len(client.models) > 1 {
+		right = client.models[(client.modelIdx+1)%len(client.models)]
+	}
+	return &compareView{
+		client: client,
+		sides:  [2]*compareSide{{model: client.model}, {model: right}},
+		first:  -1,
+	}
+}
+
+func compareOwner(side int) string {
+	return fmt.Sprintf("%s%d", compareOwnerPrefix, side)
+}
+
+// running reports whether either side is still streaming.
+func (v *compareView) running() bool {
+	for _, s := range v.sides {
+		if s.ch != nil && !s.done {
+			return true
+		}
+	}
+	return false
+}
+
+// stop cancels any in-flight streams.
+func (v *compareView) stop() {
+	if v.cancel != nil {
+		v.cancel()
+		v.cancel = nil
+	}
+}
+
+// start sends the input to both models.
+func (v *compareView) start() tea.Cmd {
+	prompt := strings.TrimSpace(string(v.input))
+	if prompt == "" || v.running() {
+		return nil
+	}
+	v.stop()
+	ctx, cancel := context.WithCancel(context.Background())
+	v.cancel = cancel
+	v.prompt, v.input = prompt, nil
+	v.first = -1
+	v.started = time.Now()
+
+	messages := []dash.ChatMessage{{Role: "user", Content: prompt}}
+	var cmds []tea.Cmd
+	for i, s := range v.sides {
+		client := *v.client
+		client.model = s.model
+		ch := make(chan any, 64)
+		v.sides[i] = &compareSide{model: s.model, ch: ch}
+		go client.StreamWithTools(ctx, messages, nil, ch)
+		cmds = append(cmds, waitForChatMsg(ch, compareOwner(i)))
+	}
+	return tea.Batch(cmds...)
+}
+
+// handleStream applies a stream message addressed to owner and returns the
+// command that reads the side's next message.
+func (v *compareView) handleStream(owner string, msg tea.Msg) tea.Cmd {
+	var i int
+	if _, err := fmt.Sscanf(strings.TrimPrefix(owner, compareOwnerPrefix), "%d", &i); err != nil || i < 0 || i > 1 {
+		return nil
+	}
+	s := v.sides[i]
+	if s.ch == nil || s.done {
+		return nil
+	}
+	switch msg := msg.(type) {
+	case chatChunkMsg:
+		s.buf.WriteString(msg.chunk)
+	case chatReasoningMsg:
+		// Reasoning is not compared; keep reading.
+	case chatDoneMsg:
+		s.usage = msg.usage
+		v.finish(i)
+		return nil
+	case chatToolCallMsg:
+		// No tools are offered, but a model may still try to call one.
+		s.usage = msg.usage
+		s.err = fmt.Errorf("model requested a tool call")
+		v.finish(i)
+		return nil
+	case chatErrorMsg:
+		s.err = msg.err
+		v.finish(i)
+		return nil
+	}
+	return waitForChatMsg(s.ch, owner)
+}
+
+func (v *compareView) finish(i int) {
+	s := v.sides[i]
+	s.done = true
+	s.elapsed = time.Since(v.started)
+	if v.first < 0 && s.err == nil {
+		v.first = i
+	}
+}
+
+// cycleModel moves the focused side to the next (or previous) model.
+// Models are fixed while a comparison is running.
+func (v *compareView) cycleModel(step int) {
+	models := v.client.models
+	if len(models) == 0 || v.running() {
+		return
+	}
+	s := v.sides[v.focus]
+	idx := 0
+	for j, m := range models {
+		if m == s.model {
+			idx = j
+			break
+		}
+	}
+	idx = (idx + step + len(models)) % len(models)
+	v.sides[v.focus] = &compareSide{model: models[idx]}
+	v.first = -1
+}
+
+// handleKey edits the prompt and picks models. Returns false when the view
+// should close; esc first cancels a running comparison.
+func (v *compareView) handleKey(msg tea.KeyMsg) (bool, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		if v.running() {
+			v.stop()
+			return true, nil
+		}
+		v.stop()
+		return false, nil
+	case tea.KeyEnter:
+		return true, v.start()
+	case tea.KeyLeft:
+		v.focus = 0
+	case tea.KeyRight:
+		v.focus = 1
+	case tea.KeyUp:
+		v.cycleModel(-1)
+	case tea.KeyDown:
+		v.cycleModel(1)
+	case tea.KeyBackspace:
+		if len(v.input) > 0 {
+			v.input = v.input[:len(v.input)-1]
+		}
+	case tea.KeySpace:
+		v.input = append(v.input, ' ')
+	case tea.KeyRunes:
+		v.input = append(v.input, msg.Runes...)
+	}
+	return true, nil
+}
+
+// View renders the prompt line and the two answer panes.
+func (v *compareView) View(width, height int) string {
+	var b strings.Builder
+	b.WriteString(sectionHeader.Render("MODEL COMPARE"))
+	b.WriteString(textDim.Render("  no tools — same prompt to both models"))
+	b.WriteString("\n")
+	if v.prompt != "" {
+		b.WriteString(textDim.Render("  asked: ") + truncate(v.prompt, width-12) + "\n")
+	}
+	b.WriteString(chatInput.Render("> ") + string(v.input) + chatCursor.Render("█") + "\n")
+
+	paneW := max(width/2-4, 20)
+	paneH := max(height-7, 5)
+	var panes [2]string
+	for i, s := range v.sides {
+		style := panelNormal
+		if i == v.focus {
+			style = panelFocused
+		}
+		panes[i] = style.Width(paneW).Height(paneH).Render(v.renderSide(i, s, paneW, paneH))
+	}
+	b.WriteString(lipgloss.JoinHorizontal(lipgloss.Top, panes[0], panes[1]))
+	b.WriteString("\n")
+	b.WriteString(textDim.Render("  [enter] send  [←/→] side  [↑/↓] model  [esc] cancel/close"))
+	return b.String()
+}
+
+// renderSide renders a pane: model, status, tokens and cost, then the tail
+// of the answer.
+func (v *compareView) renderSide(i int, s *compareSide, width, height int) string {
+	var b strings.Builder
+	b.WriteString(modelStyle.Render(truncate(s.model, width-2)) + "\n")
+
+	status := textDim.Render("idle")
+	switch {
+	case s.err != nil:
+		status = textAlert.Render("failed: " + truncate(s.err.Error(), width-10))
+	case s.done:
+		status = textSuccess.Render("done " + formatReplayGap(s.elapsed))
+		if v.first == i {
+			status += textWarning.Render("  1st")
+		}
+	case s.ch != nil:
+		status = chatStreaming.Render("streaming " + formatReplayGap(time.Since(v.started)))
+	}
+	b.WriteString(status + "\n")
+
+	if s.usage != nil {
+		cost := v.client.router.EstimateCost(s.model, s.usage.PromptTokens, s.usage.CompletionTokens)
+		b.WriteString(textDim.Render(fmt.Sprintf("%d tok (%d in / %d out)  $%.4f",
+			s.usage.TotalTokens, s.usage.PromptTokens, s.usage.CompletionTokens, cost)) + "\n")
+	} else {
+		b.WriteString(textDim.Render("tokens: -") + "\n")
+	}
+	b.WriteString(sectionDivider.Render(strings.Repeat("─", max(width-2, 1))) + "\n")
+
+	lines := strings.Split(wrapText(s.buf.String(), width-2), "\n")
+	if rows := max(height-4, 1); len(lines) > rows {
+		lines = lines[len(lines)-rows:]
+	}
+	b.WriteString(strings.Join(lines, "\n"))
+	return b.String()
+}
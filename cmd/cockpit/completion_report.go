@@ -0,0 +1,55 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"dash"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/google/uuid"
+)
+
+// completionReportMsg carries the completion report of a just-merged work order.
+type completionReportMsg struct {
+	report *dash.CompletionReport
+	err    error
+}
+
+// fetchCompletionReports loads the report of every work order that was
+// merge_pending in prev and has left the active list in next. Orders that
+// were rejected instead come back with an error and are ignored.
+func fetchCompletionReports(d *dash.Dash, prev, next []*dash.WorkOrder) tea.Cmd {
+	if d == nil {
+		return nil
+	}
+	active := make(map[uuid.UUID]bool, len(next))
+	for _, wo := range next {
+		active[wo.Node.ID] = true
+	}
+	var cmds []tea.Cmd
+	for _, wo := range prev {
+		if wo.Status != dash.WOStatusMergePending || active[wo.Node.ID] {
+			continue
+		}
+		id := wo.Node.ID
+		cmds = append(cmds, func() tea.Msg {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			report, err := d.WorkOrderCompletionReport(ctx, id)
+			return completionReportMsg{report: report, err: err}
+		})
+	}
+	return tea.Batch(cmds...)
+}
+
+// postCompletionReport shows a merged work order's summary in the
+// orchestrator chat. It is display-only and not sent to the model.
+func (m *model) postCompletionReport(msg completionReportMsg) {
+	oc := m.orchChat()
+	if msg.err != nil || oc == nil {
+		return
+	}
+	oc.appendUI("completion-report", msg.report.Summary())
+	oc.scrollToBottom()
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// dbStatus samples the cockpit's connection pool on each tick so a stalled
+// UI can be told apart from a slow LLM: in-use connections at the limit and
+// a growing wait count mean queries are queueing for the pool. Enabled with
+// -debug-db.
+type dbStatus struct {
+	db   *sql.DB
+	cur  sql.DBStats
+	prev sql.DBStats
+}
+
+func newDBStatus(db *sql.DB) *dbStatus {
+	s := &dbStatus{db: db}
+	s.sample()
+	s.prev = s.cur
+	return s
+}
+
+// sample records the current pool stats, keeping the previous sample for
+// the wait delta.
+func (s *dbStatus) sample() {
+	s.prev = s.cur
+	s.cur = s.db.Stats()
+}
+
+// view renders "DB open/max use idle wait" for the footer. New waits since
+// the previous tick are shown as an alert.
+func (s *dbStatus) view() string {
+	st := s.cur
+	limit := "∞"
+	if st.MaxOpenConnections > 0 {
+		limit = fmt.Sprint(st.MaxOpenConnections)
+	}
+	line := fmt.Sprintf("[DB %d/%s use:%d idle:%d wait:%d", st.OpenConnections, limit, st.InUse, st.Idle, st.WaitCount)
+	if waits := st.WaitCount - s.prev.WaitCount; waits > 0 {
+		return textAlert.Render(fmt.Sprintf("%s +%d %s] ", line, waits, st.WaitDuration-s.prev.WaitDuration))
+	}
+	return textDim.Render(line + "] ")
+}
@@ -0,0 +1,207 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"dash"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+var (
+	diffAdded   = lipgloss.NewStyle().Foreground(cSuccess)
+	diffRemoved = lipgloss.NewStyle().Foreground(cAlert)
+	diffHunk    = lipgloss.NewStyle().Foreground(cCyan)
+	diffMeta    = lipgloss.NewStyle().Foreground(cPrimary).Bold(true)
+)
+
+// woDiffMsg carries the result of fetching a work order or plan diff.
+type woDiffMsg struct {
+	name   string
+	files  []string
+	diff   string
+	notice string // human-readable reason when no diff is available
+	err    error
+}
+
+// diffView is a scrollable overlay showing a work order or plan diff.
+type diffView struct {
+	name   string
+	files  []string
+	lines  []string
+	notice string
+	offset int
+}
+
+// fetchWorkOrderDiff runs UnifiedDiff inside the order's worktree.
+// A missing worktree yields a notice instead of an error.
+func fetchWorkOrderDiff(wo *dash.WorkOrder) tea.Cmd {
+	return func() tea.Msg {
+		msg := woDiffMsg{name: wo.Node.Name, files: wo.FilesChanged}
+		if wo.WorktreePath == "" {
+			msg.notice = "no worktree recorded for this work order"
+			return msg
+		}
+		if _, err := os.Stat(wo.WorktreePath); err != nil {
+			msg.notice = "worktree removed: " + wo.WorktreePath
+			return msg
+		}
+		git := dash.NewExecGitClient(wo.WorktreePath)
+		diff, err := git.UnifiedDiff(wo.BaseBranch)
+		if err != nil {
+			msg.err = err
+			return msg
+		}
+		if diff == "" {
+			msg.notice = "no changes against " + wo.BaseBranch
+		}
+		msg.diff = diff
+		return msg
+	}
+}
+
+// fetchPlanDiff diffs a plan's oldest recorded revision against its current
+// data and renders the changes as diff lines for the diff viewer.
+func fetchPlanDiff(d *dash.Dash, ps *dash.PlanState) tea.Cmd {
+	return func() tea.Msg {
+		msg := woDiffMsg{name: ps.Node.Name}
+		if d == nil {
+			msg.notice = "no database"
+			return msg
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+		diff, err := d.PlanDiff(ctx, ps.Node.ID, 1, 0)
+		if err != nil {
+			msg.err = err
+			return msg
+		}
+		if diff.FromRev == diff.ToRev {
+			msg.notice = "no earlier revisions recorded"
+			return msg
+		}
+		if len(diff.Changes) == 0 {
+			msg.notice = fmt.Sprintf("no changes between rev %d and rev %d", diff.FromRev, diff.ToRev)
+			return msg
+		}
+		msg.diff = formatPlanDiff(diff)
+		return msg
+	}
+}
+
+// formatPlanDiff renders plan changes with +/- prefixes so renderDiffLine colors them.
+func formatPlanDiff(diff *dash.PlanDiffResult) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "@@ rev %d (%s) → rev %d (%s) @@\n", diff.FromRev, diff.FromStage, diff.ToRev, diff.ToStage)
+	for _, c := range diff.Changes {
+		switch c.Kind {
+		case "added":
+			fmt.Fprintf(&b, "+ %s: %s\n", c.Field, c.To)
+		case "removed":
+			fmt.Fprintf(&b, "- %s: %s\n", c.Field, c.From)
+		default:
+			fmt.Fprintf(&b, "- %s: %s\n", c.Field, c.From)
+			fmt.Fprintf(&b, "+ %s: %s\n", c.Field, c.To)
+		}
+	}
+	return b.String()
+}
+
+func newDiffView(msg woDiffMsg) *diffView {
+	v := &diffView{name: msg.name, files: msg.files, notice: msg.notice}
+	if msg.err != nil {
+		v.notice = fmt.Sprintf("diff failed: %v", msg.err)
+	}
+	if msg.diff != "" {
+		v.lines = strings.Split(strings.TrimRight(msg.diff, "\n"), "\n")
+	}
+	return v
+}
+
+// handleKey scrolls the diff. Returns false when the view should close.
+func (v *diffView) handleKey(msg tea.KeyMsg, height int) bool {
+	page := height - 4
+	if page < 1 {
+		page = 1
+	}
+	switch msg.String() {
+	case "esc", "q", "d":
+		return false
+	case "j", "down":
+		v.offset++
+	case "k", "up":
+		v.offset--
+	case "pgdown", " ":
+		v.offset += page
+	case "pgup":
+		v.offset -= page
+	case "g":
+		v.offset = 0
+	case "G":
+		v.offset = len(v.lines)
+	}
+	v.clamp(height)
+	return true
+}
+
+func (v *diffView) clamp(height int) {
+	maxOffset := len(v.lines) - (height - 4)
+	if v.offset > maxOffset {
+		v.offset = maxOffset
+	}
+	if v.offset < 0 {
+		v.offset = 0
+	}
+}
+
+// View renders the diff with added/removed lines colored.
+func (v *diffView) View(width, height int) string {
+	var b strings.Builder
+	b.WriteString(sectionHeader.Render("DIFF " + v.name))
+	if len(v.files) > 0 {
+		b.WriteString(textDim.Render(fmt.Sprintf("  %d files", len(v.files))))
+	}
+	b.WriteString("\n")
+	b.WriteString(sectionDivider.Render(strings.Repeat("─", min(width, 60))))
+	b.WriteString("\n")
+
+	if v.notice != "" {
+		b.WriteString(textWarning.Render("  "+v.notice) + "\n")
+		for _, f := range v.files {
+			b.WriteString("  " + textCyan.Render(f) + "\n")
+		}
+		return b.String()
+	}
+
+	bodyH := height - 4
+	if bodyH < 1 {
+		bodyH = 1
+	}
+	v.clamp(height)
+	end := min(v.offset+bodyH, len(v.lines))
+	for _, line := range v.lines[v.offset:end] {
+		b.WriteString(renderDiffLine(truncate(line, width-2)) + "\n")
+	}
+	b.WriteString(textDim.Render(fmt.Sprintf("lines %d-%d of %d  [j/k] scroll  [pgup/pgdn] page  [esc] close", v.offset+1, end, len(v.lines))))
+	return b.String()
+}
+
+func renderDiffLine(line string) string {
+	switch {
+	case strings.HasPrefix(line, "+++"), strings.HasPrefix(line, "---"),
+		strings.HasPrefix(line, "diff "), strings.HasPrefix(line, "index "):
+		return diffMeta.Render(line)
+	case strings.HasPrefix(line, "@@"):
+		return diffHunk.Render(line)
+	case strings.HasPrefix(line, "+"):
+		return diffAdded.Render(line)
+	case strings.HasPrefix(line, "-"):
+		return diffRemoved.Render(line)
+	}
+	return textPrimary.Render(line)
+}
@@ -37,6 +37,8 @@ type serviceStatus struct {
 	Name    string
 	Running bool
 	PID     string
+
+	Heartbeat *dash.WatcherStatus // dashwatch only; nil when unknown
 }
 
 type tickMsg time.Time
@@ -60,6 +62,13 @@ func fetchDashData(d *dash.Dash) tea.Cmd {
 		sessions, _ := d.RecentActivity(ctx, 5)
 		plans, _ := d.ListActivePlans(ctx)
 		services := checkServices()
+		if ws, err := d.WatcherStatus(ctx); err == nil {
+			for i := range services {
+				if services[i].Name == "dashwatch" {
+					services[i].Heartbeat = ws
+				}
+			}
+		}
 		workOrders, _ := d.ListActiveWorkOrders(ctx)
 		return dashDataMsg{tasks: tasks, sessions: sessions, plans: plans, services: services, workOrders: workOrders}
 	}
@@ -80,11 +89,18 @@ type agentSnapshotMsg struct {
 	err      error
 }
 
-func fetchAgentSnapshot(d *dash.Dash, agentKey, mission string) tea.Cmd {
+// fetchAgentSnapshot assembles the agent dashboard snapshot. files is a copy
+// of the tab's file counts, used to score mission drift.
+func fetchAgentSnapshot(d *dash.Dash, agentKey, mission string, files map[string]int) tea.Cmd {
 	return func() tea.Msg {
 		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 		defer cancel()
 		snap, err := d.AssembleAgentSnapshot(ctx, agentKey, mission)
+		if err == nil && mission != "" {
+			dctx, dcancel := context.WithTimeout(context.Background(), 10*time.Second)
+			snap.Drift, _ = d.MeasureMissionDrift(dctx, agentKey, mission, dash.AgentActivity{Files: files})
+			dcancel()
+		}
 		return agentSnapshotMsg{snapshot: snap, err: err}
 	}
 }
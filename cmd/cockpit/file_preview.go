@@ -0,0 +1,207 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"dash"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// filePreviewLines is how many lines of a read result the preview shows.
+const filePreviewLines = 30
+
+var (
+	hlKeyword = lipgloss.NewStyle().Foreground(cMagenta)
+	hlString  = lipgloss.NewStyle().Foreground(cWarning)
+	hlNumber  = lipgloss.NewStyle().Foreground(cCyan)
+	hlComment = lipgloss.NewStyle().Foreground(cGray).Italic(true)
+)
+
+// filePreview is the head of a file returned by the read tool.
+type filePreview struct {
+	path   string
+	offset int
+	lines  []string
+	total  int // lines in the whole file
+}
+
+// previewStore keeps file previews by tool call ID. executeTools fills it
+// off the UI goroutine from the untruncated result, so it is locked.
+type previewStore struct {
+	mu       sync.Mutex
+	previews map[string]*filePreview
+}
+
+func (s *previewStore) put(id string, p *filePreview) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.previews == nil {
+		s.previews = make(map[string]*filePreview)
+	}
+	s.previews[id] = p
+}
+
+func (s *previewStore) get(id string) *filePreview {
+	if s == nil {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.previews[id]
+}
+
+// parseFilePreview extracts a preview from a read tool result. Returns nil
+// for results without content.
+func parseFilePreview(resultJSON string) *filePreview {
+	var obj struct {
+		Path       string `json:"path"`
+		Content    string `json:"content"`
+		Offset     int    `json:"offset"`
+		TotalLines int    `json:"total_lines"`
+	}
+	if err := json.Unmarshal([]byte(resultJSON), &obj); err != nil || obj.Content == "" {
+		return nil
+	}
+	lines := strings.Split(obj.Content, "\n")
+	if len(lines) > filePreviewLines {
+		lines = lines[:filePreviewLines]
+	}
+	return &filePreview{path: obj.Path, offset: max(obj.Offset, 1), lines: lines, total: obj.TotalLines}
+}
+
+// renderFilePreview renders a read tool call with the highlighted head of
+// the file, numbered from the read offset.
+func renderFilePreview(tc dash.ToolCallRef, p *filePreview, boxWidth int) string {
+	innerWidth := boxWidth - 4
+	if innerWidth < 20 {
+		innerWidth = 20
+	}
+
+	var lines []string
+	lines = append(lines, toolBoxHeader.Render(toolIcon[tc.Function.Name]+" "+tc.Function.Name+" (preview)"))
+	lines = append(lines, toolBoxArg.Render(shortenPath(p.path)))
+	lines = append(lines, "")
+
+	lang := previewLanguage(p.path)
+	numWidth := len(fmt.Sprint(p.offset + len(p.lines) - 1))
+	for i, line := range p.lines {
+		num := textDim.Render(fmt.Sprintf("%*d ", numWidth, p.offset+i))
+		line = strings.ReplaceAll(line, "\t", "    ")
+		lines = append(lines, truncate(num+highlightLine(line, lang), innerWidth-2))
+	}
+
+	shown := p.offset - 1 + len(p.lines)
+	if rest := p.total - shown; rest > 0 {
+		lines = append(lines, "", textDim.Render(fmt.Sprintf("… %d more lines  [ctrl+x] view full", rest)))
+	} else {
+		lines = append(lines, "", textDim.Render("[ctrl+x] view full"))
+	}
+	return toolBox.Width(innerWidth).Render(strings.Join(lines, "\n"))
+}
+
+// previewLang describes the little syntax highlightLine needs.
+type previewLang struct {
+	comment  string // line comment prefix
+	keywords map[string]bool
+	fold     bool // keywords are case-insensitive
+}
+
+func keywordSet(words string) map[string]bool {
+	set := make(map[string]bool)
+	for _, w := range strings.Fields(words) {
+		set[w] = true
+	}
+	return set
+}
+
+var (
+	langGo = previewLang{comment: "//", keywords: keywordSet(
+		"break case chan const continue default defer else fallthrough for func go goto if import interface map package range return select struct switch type var nil true false")}
+	langSQL = previewLang{comment: "--", fold: true, keywords: keywordSet(
+		"select from where and or not null is in as on join left right inner outer group by order having limit offset insert into values update set delete create table index alter add drop if exists primary key references default unique returning with case when then else end begin commit coalesce")}
+	langScript = previewLang{comment: "#", keywords: keywordSet(
+		"if then else elif fi for while do done case esac in function return def class import from as not and or is None True False true false null")}
+	langJS = previewLang{comment: "//", keywords: keywordSet(
+		"const let var function return if else for while do switch case break continue new class extends import export from default async await try catch finally throw typeof instanceof null undefined true false this")}
+)
+
+// previewLanguage picks highlighting rules from the file extension.
+func previewLanguage(path string) previewLang {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".go":
+		return langGo
+	case ".sql":
+		return langSQL
+	case ".sh", ".py", ".yaml", ".yml", ".toml":
+		return langScript
+	case ".js", ".ts", ".tsx", ".jsx":
+		return langJS
+	}
+	return previewLang{}
+}
+
+// highlightLine colors comments, string literals, numbers and keywords in a
+// single line. It does not track state across lines, so block comments and
+// multi-line strings are shown plain after their first line.
+func highlightLine(line string, lang previewLang) string {
+	if lang.keywords == nil {
+		return line
+	}
+	var b strings.Builder
+	for i := 0; i < len(line); {
+		c := line[i]
+		switch {
+		case lang.comment != "" && strings.HasPrefix(line[i:], lang.comment):
+			b.WriteString(hlComment.Render(line[i:]))
+			return b.String()
+		case c == '"' || c == '\'' || c == '`':
+			j := i + 1
+			for j < len(line) && line[j] != c {
+				if line[j] == '\\' && c != '`' {
+					j++
+				}
+				j++
+			}
+			j = min(j+1, len(line))
+			b.WriteString(hlString.Render(line[i:j]))
+			i = j
+		case isIdentStart(c):
+			j := i + 1
+			for j < len(line) && (isIdentStart(line[j]) || isDigit(line[j])) {
+				j++
+			}
+			word := line[i:j]
+			key := word
+			if lang.fold {
+				key = strings.ToLower(word)
+			}
+			if lang.keywords[key] {
+				word = hlKeyword.Render(word)
+			}
+			b.WriteString(word)
+			i = j
+		case isDigit(c):
+			j := i + 1
+			for j < len(line) && (isDigit(line[j]) || line[j] == '.' || line[j] == 'x' || line[j] == '_') {
+				j++
+			}
+			b.WriteString(hlNumber.Render(line[i:j]))
+			i = j
+		default:
+			b.WriteByte(c)
+			i++
+		}
+	}
+	return b.String()
+}
+
+func isIdentStart(c byte) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+func isDigit(c byte) bool { return c >= '0' && c <= '9' }
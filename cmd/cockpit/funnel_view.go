@@ -0,0 +1,96 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"dash"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// funnelPeriod is how far back the work order funnel looks.
+const funnelPeriod = 30 * 24 * time.Hour
+
+// funnelMsg carries the work order funnel.
+type funnelMsg struct {
+	funnel *dash.Funnel
+	err    error
+}
+
+// funnelView shows how many work orders reached each pipeline stage, as
+// bars scaled to the number created.
+type funnelView struct {
+	funnel *dash.Funnel
+	notice string
+}
+
+// fetchFunnel loads the funnel for the last funnelPeriod.
+func fetchFunnel(d *dash.Dash) tea.Cmd {
+	return func() tea.Msg {
+		if d == nil {
+			return funnelMsg{err: fmt.Errorf("no database")}
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		now := time.Now()
+		f, err := d.WorkOrderFunnel(ctx, dash.TimeRange{Start: now.Add(-funnelPeriod), End: now})
+		return funnelMsg{funnel: f, err: err}
+	}
+}
+
+func newFunnelView(msg funnelMsg) *funnelView {
+	v := &funnelView{funnel: msg.funnel}
+	switch {
+	case msg.err != nil:
+		v.notice = fmt.Sprintf("funnel failed: %v", msg.err)
+	case len(msg.funnel.Stages) == 0 || msg.funnel.Stages[0].Reached == 0:
+		v.notice = "no work orders created in the last 30 days"
+	}
+	return v
+}
+
+// handleKey returns false when the view should close.
+func (v *funnelView) handleKey(msg tea.KeyMsg) bool {
+	switch msg.String() {
+	case "esc", "q", "F":
+		return false
+	}
+	return true
+}
+
+// View renders one bar per stage with its count, share of created orders
+// and drop-off from the stage before. The worst drop-off is highlighted.
+func (v *funnelView) View(width, height int) string {
+	var b strings.Builder
+	b.WriteString(sectionHeader.Render("WORK ORDER FUNNEL"))
+	b.WriteString(textDim.Render("  last 30 days"))
+	b.WriteString("\n")
+	b.WriteString(sectionDivider.Render(strings.Repeat("─", min(width, 60))))
+	b.WriteString("\n")
+
+	if v.notice != "" {
+		b.WriteString(textWarning.Render("  "+v.notice) + "\n")
+		return b.String()
+	}
+
+	barWidth := max(min(width-50, 40), 10)
+	for _, st := range v.funnel.Stages {
+		bar := strings.Repeat("█", int(st.Pct*float64(barWidth)+0.5))
+		drop := ""
+		if st.DropOff > 0 {
+			drop = fmt.Sprintf("-%.0f%%", st.DropOff*100)
+		}
+		line := fmt.Sprintf("  %-18s %-*s %4d %4.0f%%", st.Status, barWidth, bar, st.Reached, st.Pct*100)
+		if st.Status == v.funnel.WorstStage {
+			b.WriteString(line + " " + textAlert.Render(drop+" ◀ worst") + "\n")
+		} else {
+			b.WriteString(line + " " + textDim.Render(drop) + "\n")
+		}
+	}
+	b.WriteString("\n")
+	b.WriteString(textDim.Render("  [esc] close"))
+	return b.String()
+}
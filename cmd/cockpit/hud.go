@@ -15,7 +15,7 @@ type hudModel struct{}
 func newHudModel() hudModel { return hudModel{} }
 
 // View renders the HUD: 1-2 content lines inside a rounded border.
-func (h hudModel) View(ws *dash.WorkingSet, services []serviceStatus, streaming bool, width int) string {
+func (h hudModel) View(ws *dash.WorkingSet, services []serviceStatus, streaming bool, unread, width int) string {
 	if width < 20 {
 		width = 80
 	}
@@ -60,6 +60,11 @@ func (h hudModel) View(ws *dash.WorkingSet, services []serviceStatus, streaming
 		line1.WriteString(hudSep)
 	}
 
+	if unread > 0 {
+		line1.WriteString(textWarning.Render(fmt.Sprintf("\u2709 %d", unread)))
+		line1.WriteString(hudSep)
+	}
+
 	// Service indicators
 	for _, s := range services {
 		if s.Running {
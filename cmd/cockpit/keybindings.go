@@ -15,6 +15,9 @@ const (
 	ActionQuit
 	ActionToggleDash
 	ActionCycleAgentNext
+	ActionPalette
+	ActionToggleObserver
+	ActionToggleNotifications
 
 	// Chat actions — input editing
 	ActionSendMessage
@@ -35,7 +38,13 @@ const (
 	ActionCancelStream
 	ActionToggleReasoning
 	ActionToggleToolCollapse
+	ActionSelectToolPrev
+	ActionSelectToolNext
+	ActionToggleToolExpand
 	ActionClearChat
+	ActionUndo
+	ActionRestoreChat
+	ActionExportChat
 
 	// Model switching
 	ActionModelNext
@@ -59,10 +68,18 @@ const (
 	ActionDashToolLimit
 	ActionDashClearContinue
 	ActionDashFilter
+	ActionDashDiff
+	ActionDashReplay
+	ActionDashLeaderboard
+	ActionDashCompare
+	ActionDashApprove
+	ActionDashReject
+	ActionDashFunnel
 
 	// Agent view actions
 	ActionAgentBack
 	ActionPauseAgent
+	ActionAgentLineage
 )
 
 // resolveGlobalKey maps global key events that apply regardless of view.
@@ -72,6 +89,12 @@ func resolveGlobalKey(msg tea.KeyMsg) KeyAction {
 		return ActionQuit
 	case tea.KeyShiftTab:
 		return ActionCycleAgentNext
+	case tea.KeyCtrlF:
+		return ActionPalette
+	case tea.KeyCtrlR:
+		return ActionToggleObserver
+	case tea.KeyCtrlN:
+		return ActionToggleNotifications
 	}
 	switch msg.String() {
 	case "tab":
@@ -93,6 +116,16 @@ func resolveChatKey(msg tea.KeyMsg, mode string) KeyAction {
 		return ActionScrollDown
 	}
 
+	// Tool result selection is always available
+	switch msg.String() {
+	case "alt+up":
+		return ActionSelectToolPrev
+	case "alt+down":
+		return ActionSelectToolNext
+	case "ctrl+x":
+		return ActionToggleToolExpand
+	}
+
 	switch mode {
 	case "streaming":
 		switch msg.String() {
@@ -134,6 +167,12 @@ func resolveChatKey(msg tea.KeyMsg, mode string) KeyAction {
 	switch msg.String() {
 	case "ctrl+l":
 		return ActionClearChat
+	case "ctrl+z":
+		return ActionUndo
+	case "ctrl+y":
+		return ActionRestoreChat
+	case "ctrl+s":
+		return ActionExportChat
 	case "ctrl+o":
 		return ActionToggleReasoning
 	case "ctrl+t":
@@ -180,6 +219,20 @@ func resolveDashKey(msg tea.KeyMsg) KeyAction {
 		return ActionDashClearContinue
 	case "/":
 		return ActionDashFilter
+	case "d":
+		return ActionDashDiff
+	case "R":
+		return ActionDashReplay
+	case "L":
+		return ActionDashLeaderboard
+	case "M":
+		return ActionDashCompare
+	case "a":
+		return ActionDashApprove
+	case "x":
+		return ActionDashReject
+	case "F":
+		return ActionDashFunnel
 	}
 	return ActionNone
 }
@@ -191,6 +244,8 @@ func resolveAgentViewKey(msg tea.KeyMsg) KeyAction {
 		return ActionAgentBack
 	case "ctrl+p":
 		return ActionPauseAgent
+	case "ctrl+g":
+		return ActionAgentLineage
 	}
 	return ActionNone
 }
@@ -200,33 +255,41 @@ func resolveAgentViewKey(msg tea.KeyMsg) KeyAction {
 type chatKeyMap struct {
 	Send      key.Binding
 	Clear     key.Binding
+	Undo      key.Binding
 	Tools     key.Binding
+	Expand    key.Binding
 	Reasoning key.Binding
 	Scroll    key.Binding
 	Model     key.Binding
 	Stop      key.Binding
+	Find      key.Binding
+	Export    key.Binding
 }
 
 func newChatKeyMap() chatKeyMap {
 	return chatKeyMap{
 		Send:      key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "send")),
 		Clear:     key.NewBinding(key.WithKeys("ctrl+l"), key.WithHelp("ctrl+l", "clear")),
+		Undo:      key.NewBinding(key.WithKeys("ctrl+z"), key.WithHelp("ctrl+z", "undo")),
 		Tools:     key.NewBinding(key.WithKeys("ctrl+t"), key.WithHelp("ctrl+t", "tools")),
+		Expand:    key.NewBinding(key.WithKeys("alt+up", "alt+down", "ctrl+x"), key.WithHelp("alt+↑/↓ ctrl+x", "expand tool")),
 		Reasoning: key.NewBinding(key.WithKeys("ctrl+o"), key.WithHelp("ctrl+o", "thinking")),
 		Scroll:    key.NewBinding(key.WithKeys("pgup", "pgdn"), key.WithHelp("pgup/dn", "scroll")),
 		Model:     key.NewBinding(key.WithKeys("å", "ä"), key.WithHelp("tab+å/ä", "model")),
 		Stop:      key.NewBinding(key.WithKeys("esc"), key.WithHelp("esc", "stop")),
+		Find:      key.NewBinding(key.WithKeys("/"), key.WithHelp("/", "find")),
+		Export:    key.NewBinding(key.WithKeys("ctrl+s"), key.WithHelp("ctrl+s", "export .md")),
 	}
 }
 
 func (k chatKeyMap) ShortHelp() []key.Binding {
-	return []key.Binding{k.Send, k.Clear, k.Tools, k.Reasoning, k.Scroll, k.Model}
+	return []key.Binding{k.Send, k.Clear, k.Tools, k.Reasoning, k.Scroll, k.Find, k.Model}
 }
 
 func (k chatKeyMap) FullHelp() [][]key.Binding {
 	return [][]key.Binding{
-		{k.Send, k.Clear, k.Tools},
-		{k.Reasoning, k.Scroll, k.Model},
+		{k.Send, k.Clear, k.Undo, k.Tools, k.Export},
+		{k.Expand, k.Reasoning, k.Scroll, k.Find, k.Model},
 	}
 }
 
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"dash"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// leaderboardPeriod is how far back the leaderboard looks.
+const leaderboardPeriod = 30 * 24 * time.Hour
+
+// leaderboardMsg carries the agent leaderboard.
+type leaderboardMsg struct {
+	ranks []dash.AgentRank
+	err   error
+}
+
+// leaderboardView shows agents ranked by AgentLeaderboard's composite score
+// with the components behind it.
+type leaderboardView struct {
+	ranks  []dash.AgentRank
+	notice string
+	offset int
+}
+
+// fetchLeaderboard loads the leaderboard for the last leaderboardPeriod.
+func fetchLeaderboard(d *dash.Dash) tea.Cmd {
+	return func() tea.Msg {
+		if d == nil {
+			return leaderboardMsg{err: fmt.Errorf("no database")}
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		now := time.Now()
+		ranks, err := d.AgentLeaderboard(ctx, dash.TimeRange{Start: now.Add(-leaderboardPeriod), End: now})
+		return leaderboardMsg{ranks: ranks, err: err}
+	}
+}
+
+func newLeaderboardView(msg leaderboardMsg) *leaderboardView {
+	v := &leaderboardView{ranks: msg.ranks}
+	switch {
+	case msg.err != nil:
+		v.notice = fmt.Sprintf("leaderboard failed: %v", msg.err)
+	case len(msg.ranks) == 0:
+		v.notice = "no work orders in the last 30 days"
+	}
+	return v
+}
+
+// handleKey scrolls the list. Returns false when the view should close.
+func (v *leaderboardView) handleKey(msg tea.KeyMsg) bool {
+	switch msg.String() {
+	case "esc", "q", "L":
+		return false
+	case "j", "down":
+		v.offset++
+	case "k", "up":
+		v.offset--
+	case "g":
+		v.offset = 0
+	}
+	v.offset = max(min(v.offset, len(v.ranks)-1), 0)
+	return true
+}
+
+// View renders one row per agent: rank, composite and its components.
+func (v *leaderboardView) View(width, height int) string {
+	var b strings.Builder
+	b.WriteString(sectionHeader.Render("AGENT LEADERBOARD"))
+	b.WriteString(textDim.Render("  last 30 days"))
+	b.WriteString("\n")
+	b.WriteString(sectionDivider.Render(strings.Repeat("─", min(width, 60))))
+	b.WriteString("\n")
+
+	if v.notice != "" {
+		b.WriteString(textWarning.Render("  "+v.notice) + "\n")
+		return b.String()
+	}
+
+	b.WriteString(textDim.Render(fmt.Sprintf("  %-4s %-20s %6s  %6s %6s %7s %6s  %s", "#", "agent", "score", "merge", "qual", "mttm", "vol", "orders")) + "\n")
+	rows := max(height-6, 3)
+	end := min(v.offset+rows, len(v.ranks))
+	for _, r := range v.ranks[v.offset:end] {
+		b.WriteString(truncate(formatLeaderboardRow(r), width-2) + "\n")
+	}
+	b.WriteString("\n")
+	b.WriteString(textDim.Render("  [j/k] scroll  [g] top  [esc] close"))
+	return b.String()
+}
+
+// formatLeaderboardRow renders one agent's entry. Agents with too few
+// orders show their counts but no rank or score.
+func formatLeaderboardRow(r dash.AgentRank) string {
+	orders := fmt.Sprintf("%d (%d✔ %d✘)", r.WOCount, r.MergedCount, r.RejectedCount)
+	if r.Insufficient {
+		return textDim.Render(fmt.Sprintf("  %-4s %-20s %6s  %s  %s", "-", truncate(r.AgentKey, 20), "", "insufficient data", orders))
+	}
+	mttm := "-"
+	if r.MeanTimeToMerge > 0 {
+		mttm = formatReplayGap(r.MeanTimeToMerge)
+	}
+	rank := fmt.Sprintf("%-4d", r.Rank)
+	if r.Rank == 1 {
+		rank = textSuccess.Render(rank)
+	}
+	return fmt.Sprintf("  %s %-20s %s  %5.0f%% %6.2f %7s %5.0f%%  %s",
+		rank, truncate(r.AgentKey, 20), textMagenta.Render(fmt.Sprintf("%6.2f", r.Composite)),
+		r.MergeRate*100, r.AvgScore, mttm, r.Volume*100, textDim.Render(orders))
+}
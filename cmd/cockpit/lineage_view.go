@@ -0,0 +1,129 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"dash"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// agentLineageMsg carries the result of fetching an agent's spawn lineage.
+type agentLineageMsg struct {
+	sessionID string
+	lineage   *dash.AgentLineageNode
+	err       error
+}
+
+// lineageView is an overlay showing who spawned an agent and whom it spawned.
+type lineageView struct {
+	sessionID string
+	lineage   *dash.AgentLineageNode
+	notice    string
+}
+
+// fetchAgentLineage loads the lineage for a session in the background.
+func fetchAgentLineage(d *dash.Dash, sessionID string) tea.Cmd {
+	return func() tea.Msg {
+		if d == nil {
+			return agentLineageMsg{sessionID: sessionID, err: fmt.Errorf("no database")}
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+		lin, err := d.GetAgentLineage(ctx, sessionID)
+		return agentLineageMsg{sessionID: sessionID, lineage: lin, err: err}
+	}
+}
+
+func newLineageView(msg agentLineageMsg) *lineageView {
+	v := &lineageView{sessionID: msg.sessionID, lineage: msg.lineage}
+	if msg.err != nil {
+		v.notice = fmt.Sprintf("lineage failed: %v", msg.err)
+	}
+	return v
+}
+
+// handleKey returns false when the view should close.
+func (v *lineageView) handleKey(msg tea.KeyMsg) bool {
+	switch msg.String() {
+	case "esc", "q", "ctrl+g":
+		return false
+	}
+	return true
+}
+
+// View renders ancestors root-first, the session itself, then its children.
+func (v *lineageView) View(width, height int) string {
+	var b strings.Builder
+	b.WriteString(sectionHeader.Render("LINEAGE " + v.sessionID))
+	b.WriteString("\n")
+	b.WriteString(sectionDivider.Render(strings.Repeat("─", min(width, 60))))
+	b.WriteString("\n")
+
+	if v.notice != "" || v.lineage == nil {
+		b.WriteString(textWarning.Render("  "+v.notice) + "\n")
+		return b.String()
+	}
+
+	lin := v.lineage
+	var lines []string
+	if lin.MissingParent != "" {
+		lines = append(lines, textWarning.Render("? "+lin.MissingParent+" (missing)"))
+	}
+	if lin.CycleDetected {
+		lines = append(lines, textWarning.Render("↻ cycle in spawn chain"))
+	}
+
+	depth := 0
+	if len(lines) > 0 {
+		depth = 1
+	}
+	for _, a := range lin.Ancestors {
+		lines = append(lines, lineagePrefix(depth, true)+lineageLabel(a, false))
+		depth++
+	}
+	lines = append(lines, lineagePrefix(depth, true)+lineageLabel(lin.Session, true))
+	depth++
+	for i, c := range lin.Children {
+		lines = append(lines, lineagePrefix(depth, i == len(lin.Children)-1)+lineageLabel(c, false))
+	}
+	if len(lin.Children) == 0 {
+		lines = append(lines, lineagePrefix(depth, true)+textDim.Render("(no children)"))
+	}
+
+	bodyH := max(height-4, 1)
+	if len(lines) > bodyH {
+		lines = lines[len(lines)-bodyH:]
+	}
+	for _, l := range lines {
+		b.WriteString("  " + truncate(l, width-4) + "\n")
+	}
+	return b.String()
+}
+
+// lineagePrefix returns the tree connector for an entry at depth.
+func lineagePrefix(depth int, last bool) string {
+	if depth == 0 {
+		return ""
+	}
+	conn := "├─ "
+	if last {
+		conn = "└─ "
+	}
+	return strings.Repeat("   ", depth-1) + conn
+}
+
+func lineageLabel(s dash.AgentSession, current bool) string {
+	label := s.SessionID
+	if current {
+		label = textCyan.Render("● " + label)
+	}
+	meta := s.AgentKey
+	if s.Status != "" {
+		meta += " · " + s.Status
+	}
+	return label + "  " + textDim.Render(meta)
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
 	"os"
 	"time"
@@ -12,6 +13,10 @@ import (
 )
 
 func main() {
+	noHistory := flag.Bool("no-history", false, "do not persist chat history to the graph")
+	debugDB := flag.Bool("debug-db", false, "show connection pool stats (open/in-use/idle/waits) in the footer")
+	flag.Parse()
+
 	db, err := dash.ConnectDB()
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "cockpit: %v\n", err)
@@ -60,8 +65,12 @@ func main() {
 	chatCl.tools = toolDefs
 
 	sessionID := fmt.Sprintf("cockpit-%d", os.Getpid())
+	m := newModel(d, chatCl, sessionID, db, !*noHistory)
+	if *debugDB {
+		m.dbStatus = newDBStatus(db)
+	}
 	p := tea.NewProgram(
-		newModel(d, chatCl, sessionID, db),
+		m,
 		tea.WithAltScreen(),
 		tea.WithMouseCellMotion(),
 	)
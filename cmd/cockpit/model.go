@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"maps"
 	"strings"
 	"time"
 
@@ -34,13 +35,45 @@ type model struct {
 	overlay overlayModel
 	agents  *agentManager
 
+	// Work order diff viewer (dashboard)
+	diffView *diffView
+
+	// Session history overlay (dashboard)
+	sessionView *sessionView
+	replayView  *replayView
+
+	// Agent leaderboard overlay (dashboard)
+	leaderboardView *leaderboardView
+	funnelView      *funnelView
+	// Side-by-side model comparison (dashboard)
+	compareView *compareView
+
+	// Spawn lineage overlay (agent view)
+	lineageView *lineageView
+
+	// Command palette (ctrl+f, any view)
+	palette *paletteModel
+
+	// Read-only observer mode (ctrl+r): no take-control, spawns or write tools
+	observer bool
+
+	// Persist chat history to the graph after each assistant turn (off with -no-history)
+	persistChats bool
+
+	// Connection pool stats in the footer (-debug-db), nil when off
+	dbStatus *dbStatus
+
+	// Recent cross-agent broadcasts, replayed to tabs opened later
+	broadcasts []dash.AgentBroadcast
+
 	// Spawn agent from dashboard
 	spawnInput bool
 	spawnBuf   []rune
 
 	// Observation agent
 	agent         *observationAgent
-	notifications []observationNotification
+	notifications notificationLog
+	notifView     *notificationsView // notifications overlay, nil when closed
 
 	// Shared data (fetched async)
 	ws         *dash.WorkingSet
@@ -64,7 +97,7 @@ type model struct {
 	allAgentDefs []dash.AgentDef
 }
 
-func newModel(d *dash.Dash, chatCl *chatClient, sessionID string, db *sql.DB) model {
+func newModel(d *dash.Dash, chatCl *chatClient, sessionID string, db *sql.DB, persistChats bool) model {
 	// Load agent definitions from DB
 	defs := dash.LoadAgentDefs(context.Background(), d)
 
@@ -76,9 +109,9 @@ func newModel(d *dash.Dash, chatCl *chatClient, sessionID string, db *sql.DB) mo
 		overlay:        newOverlayModel(),
 		agents:         newAgentManager(),
 		agent:          newObservationAgent(db),
-		notifications:  make([]observationNotification, 0),
 		pendingQueries: make(map[string]*pendingQuery),
 		allAgentDefs:   defs,
+		persistChats:   persistChats,
 	}
 
 	// Pre-create favorite agent tabs as idle (lazy spawn on first message)
@@ -94,6 +127,7 @@ func newModel(d *dash.Dash, chatCl *chatClient, sessionID string, db *sql.DB) mo
 		agentChat.agentMission = def.Mission
 		tab := m.agents.spawn(def.DisplayName, def.Key, "", "", "", agentChat)
 		tab.controller = "idle"
+		tab.tokenBudget = def.TokenBudget
 	}
 
 	// Activate orchestrator tab
@@ -108,13 +142,17 @@ func newModel(d *dash.Dash, chatCl *chatClient, sessionID string, db *sql.DB) mo
 }
 
 func (m model) Init() tea.Cmd {
-	return tea.Batch(
+	cmds := []tea.Cmd{
 		fetchContext(m.d),
 		fetchDashData(m.d),
 		fetchIntel(m.d),
 		tickCmd(),
 		observationTickCmd(),
-	)
+	}
+	if orch := m.orchChat(); m.persistChats && orch != nil {
+		cmds = append(cmds, fetchChatRestore(m.d, orch.sessionID))
+	}
+	return tea.Batch(cmds...)
 }
 
 func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
@@ -125,6 +163,17 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case tea.KeyMsg:
+		// Command palette intercepts all keys except quit
+		if m.palette != nil && msg.Type != tea.KeyCtrlC {
+			return m, m.handlePaletteKey(msg)
+		}
+		// Notifications overlay intercepts all keys except quit
+		if m.notifView != nil && msg.Type != tea.KeyCtrlC {
+			if !m.notifView.handleKey(msg, &m.notifications) {
+				m.notifView = nil
+			}
+			return m, nil
+		}
 		// Resolve global keybindings first
 		globalAction := resolveGlobalKey(msg)
 		switch globalAction {
@@ -137,13 +186,30 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m.toggleDashboard()
 		case ActionCycleAgentNext:
 			return m.cycleAgentNext()
+		case ActionPalette:
+			return m, m.openPalette()
+		case ActionToggleObserver:
+			return m.toggleObserver()
+		case ActionToggleNotifications:
+			m.notifView = &notificationsView{}
+			return m, nil
 		}
 
 		// View-specific keys handled before routing
 		if m.state == viewAgent {
+			// Lineage overlay intercepts all keys
+			if m.lineageView != nil {
+				if !m.lineageView.handleKey(msg) {
+					m.lineageView = nil
+				}
+				return m, nil
+			}
 			action := resolveAgentViewKey(msg)
 			switch action {
 			case ActionAgentBack:
+				if tab := m.agents.active(); tab != nil && tab.chat.search != nil {
+					break // esc closes the chat search first
+				}
 				var cmds []tea.Cmd
 				if tab := m.agents.active(); tab != nil && tab.controller == "human" {
 					tab.controller = "idle"
@@ -158,11 +224,15 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 				return m, tea.Batch(cmds...)
 			case ActionPauseAgent:
-				if tab := m.agents.active(); tab != nil {
+				if tab := m.agents.active(); tab != nil && !m.observer {
 					prevController := tab.controller
 					tab.controller = "idle"
 					return m, pauseAgentCmd(m.d, tab, prevController)
 				}
+			case ActionAgentLineage:
+				if tab := m.agents.active(); tab != nil && tab.sessionID != "" {
+					return m, fetchAgentLineage(m.d, tab.sessionID)
+				}
 			}
 		}
 		// Route keys to active view
@@ -170,7 +240,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case viewAgent:
 			if tab := m.agents.active(); tab != nil {
 				// Auto take-control on first input character
-				if tab.controller != "human" && isInputChar(msg) {
+				if !m.observer && tab.controller != "human" && isInputChar(msg) && !tab.chat.capturesSearchKey(msg) {
 					var cmds []tea.Cmd
 					// Release any other human-controlled agent (agent→agent, no orchestrator)
 					for _, other := range m.agents.tabs {
@@ -185,6 +255,10 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					return m, tea.Batch(cmds...)
 				}
 				// Lazy spawn: intercept Enter on idle agent
+				if tab.status == agentIdle && m.observer && msg.Type == tea.KeyEnter {
+					tab.chat.addSystemMessage("Observer mode: spawning disabled.")
+					return m, nil
+				}
 				if tab.status == agentIdle {
 					if msg.Type == tea.KeyEnter {
 						text := strings.TrimSpace(string(tab.chat.input))
@@ -212,10 +286,48 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			if m.spawnInput {
 				return m.handleSpawnInput(msg)
 			}
+			// Diff viewer intercepts all keys
+			if m.diffView != nil {
+				if !m.diffView.handleKey(msg, m.contentHeight()) {
+					m.diffView = nil
+				}
+				return m, nil
+			}
+			if m.sessionView != nil {
+				if !m.sessionView.handleKey(msg, m.contentHeight()) {
+					m.sessionView = nil
+				}
+				return m, nil
+			}
+			if m.replayView != nil {
+				if !m.replayView.handleKey(msg) {
+					m.replayView = nil
+				}
+				return m, nil
+			}
+			if m.leaderboardView != nil {
+				if !m.leaderboardView.handleKey(msg) {
+					m.leaderboardView = nil
+				}
+				return m, nil
+			}
+			if m.funnelView != nil {
+				if !m.funnelView.handleKey(msg) {
+					m.funnelView = nil
+				}
+				return m, nil
+			}
+			if m.compareView != nil {
+				keep, cmd := m.compareView.handleKey(msg)
+				if !keep {
+					m.compareView = nil
+				}
+				return m, cmd
+			}
 			cmd := m.overlay.handleKey(msg)
 			// Rebuild items after filter changes
 			if m.overlay.filtering || m.overlay.filterText != "" {
-				m.overlay.rebuildItems(m.plans, m.tasks)
+				m.overlay.rebuildItems(m.plans, m.workOrders, m.tasks, m.sessions)
 			}
 			if cmd != nil {
 				return m, cmd
@@ -257,10 +369,76 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.sessions = msg.sessions
 			m.plans = msg.plans
 			m.services = msg.services
+			reports := fetchCompletionReports(m.d, m.workOrders, msg.workOrders)
 			m.workOrders = msg.workOrders
-			m.overlay.rebuildItems(m.plans, m.tasks)
+			m.overlay.rebuildItems(m.plans, m.workOrders, m.tasks, m.sessions)
 			// Sync work orders to agent tabs
 			m.agents.updateWorkOrders(msg.workOrders)
+			return m, reports
+		}
+		return m, nil
+
+	case completionReportMsg:
+		m.postCompletionReport(msg)
+		return m, nil
+
+	case woDiffMsg:
+		if m.state == viewDashboard {
+			m.diffView = newDiffView(msg)
+		}
+		return m, nil
+
+	case sessionHistoryMsg:
+		if m.state == viewDashboard {
+			m.sessionView = newSessionView(msg)
+		}
+		return m, nil
+
+	case sessionReplayMsg:
+		if m.state == viewDashboard {
+			m.replayView = newReplayView(msg)
+		}
+		return m, nil
+
+	case chatRestoreMsg:
+		if orch := m.orchChat(); orch != nil {
+			orch.offerRestore(msg.history)
+		}
+		return m, nil
+
+	case planDecisionMsg:
+		m.overlay.notice = decisionNotice(msg)
+		m.overlay.decisionNote = ""
+		return m, fetchDashData(m.d)
+
+	case leaderboardMsg:
+		if m.state == viewDashboard {
+			m.leaderboardView = newLeaderboardView(msg)
+		}
+		return m, nil
+
+	case funnelMsg:
+		if m.state == viewDashboard {
+			m.funnelView = newFunnelView(msg)
+		}
+		return m, nil
+
+	case paletteSearchMsg:
+		if m.palette != nil && msg.seq == m.palette.seq {
+			return m, searchPaletteNodes(m.d, msg.seq, msg.query)
+		}
+		return m, nil
+
+	case paletteResultsMsg:
+		if m.palette != nil && msg.seq == m.palette.seq {
+			m.palette.remote, m.palette.err = msg.nodes, msg.err
+			m.palette.refilter()
+		}
+		return m, nil
+
+	case agentLineageMsg:
+		if m.state == viewAgent {
+			m.lineageView = newLineageView(msg)
 		}
 		return m, nil
 
@@ -274,12 +452,19 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tickMsg:
 		var cmds []tea.Cmd
 		cmds = append(cmds, tickCmd())
+		if m.dbStatus != nil {
+			m.dbStatus.sample()
+		}
 		cmds = append(cmds, fetchContext(m.d))
+		cmds = append(cmds, fetchAgentBudgets(m.d, m.agents.tabs))
 		if m.state == viewDashboard {
 			cmds = append(cmds, fetchDashData(m.d))
 		}
 		return m, tea.Batch(cmds...)
 
+	case agentBudgetMsg:
+		return m, m.handleAgentBudget(msg)
+
 	case observationTickMsg:
 		return m, tea.Batch(
 			pollCmd(m.agent),
@@ -288,26 +473,49 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case observationPollMsg:
 		if msg.err == nil && len(msg.notifications) > 0 {
-			m.notifications = append(m.notifications, msg.notifications...)
-			if oc := m.orchChat(); oc != nil {
-				for _, n := range msg.notifications {
-					oc.addSystemMessage(fmt.Sprintf("[OBSERVATION] %s: %s", n.Type, n.Message))
-				}
-			}
+			m.notifications.add(msg.notifications...)
 		}
 		return m, nil
 
 	case handoffCompleteMsg:
-		if tab := m.agents.active(); tab != nil && tab.id == msg.agentID {
+		for _, tab := range m.agents.tabs {
+			if tab.id != msg.agentID {
+				continue
+			}
 			if msg.err != nil {
 				tab.chat.addSystemMessage(fmt.Sprintf("Handoff error: %v", msg.err))
 			} else {
+				tab.chat.clearAndContinue(msg.summary)
+				tab.meter.rotate()
 				tab.chat.addSystemMessage("Session handoff complete. Starting new session...")
 				tab.status = agentWaiting
 			}
 		}
 		return m, nil
 
+	case broadcastReplayMsg:
+		m.replayBroadcasts(msg)
+		return m, nil
+
+	case undoForgetMsg:
+		if chat := m.chatForAgent(msg.owner); chat != nil {
+			chat.handleUndoForget(msg)
+		}
+		return m, nil
+
+	case sessionRotationMsg:
+		chat := m.chatForAgent(msg.owner)
+		if chat == nil {
+			return m, nil
+		}
+		if chat.streaming {
+			chat.pendingHandoff = msg.summary
+			return m, nil
+		}
+		chat.clearAndContinue(msg.summary)
+		chat.addSystemMessage("⚡ Context roterad — konversationen fortsätter.")
+		return m, nil
+
 	case spawnAgentResultMsg:
 		if msg.err != nil {
 			// Check if this was a lazy spawn failure
@@ -357,11 +565,12 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 			}
 			// Fallback: new tab (orchestrator-triggered spawn)
-			m.spawnAgentTab(*msg.info)
+			replay := m.spawnAgentTab(*msg.info)
 			m.state = viewAgent
 			if tab := m.agents.active(); tab != nil {
-				return m, m.beginStream(tab.agentKey, tab.chat)
+				return m, tea.Batch(replay, m.beginStream(tab.agentKey, tab.chat))
 			}
+			return m, replay
 		}
 		return m, nil
 		
@@ -397,6 +606,10 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					ToolName:  tab.chat.toolStatus,
 					Exchanges: tab.chat.meter.exchanges,
 				}
+				if _, ok := tab.budgetRemaining(); ok {
+					m.agentSnapshot.Live.TokensSpent = tab.chat.meter.spent
+					m.agentSnapshot.Live.TokenBudget = tab.tokenBudget
+				}
 				// Inject file tracking from TUI agent chat
 				if tab.chat.lastFile != "" {
 					m.agentSnapshot.RecentFiles = []string{tab.chat.lastFile}
@@ -464,12 +677,27 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case chatChunkMsg, chatReasoningMsg, chatDoneMsg, chatErrorMsg, chatToolCallMsg:
 		// Owner-based routing — no active-tab fallback
 		owner := streamMsgOwner(msg)
+		if strings.HasPrefix(owner, compareOwnerPrefix) {
+			if m.compareView == nil {
+				return m, nil
+			}
+			return m, m.compareView.handleStream(owner, msg)
+		}
 		targetChat := m.chatForAgent(owner)
 		if targetChat == nil {
 			targetChat = m.orchChat() // emergency fallback
 		}
 		cmd := targetChat.Update(msg, m.width, m.contentHeight())
+		switch msg.(type) {
+		case chatDoneMsg, chatToolCallMsg:
+			if budgetCmd := m.checkAgentBudget(owner); budgetCmd != nil {
+				cmd = tea.Batch(cmd, budgetCmd)
+			}
+		}
 		if _, isDone := msg.(chatDoneMsg); isDone {
+			if m.persistChats {
+				cmd = tea.Batch(cmd, targetChat.persistChatCmd())
+			}
 			if m.activeStreamOwner != "" {
 				for _, tab := range m.agents.tabs {
 					if tab.agentKey == m.activeStreamOwner && tab.meter.shouldHandoff(20) {
@@ -487,7 +715,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if m.activeStreamOwner != "" {
 			for _, tab := range m.agents.tabs {
 				if tab.agentKey == m.activeStreamOwner {
-					if !tab.chat.handleToolResults(msg.results) {
+					if !tab.chat.handleToolResults(msg.results) || (tab.budgetExhausted && tab.controller != "human") {
 						m.activeStreamOwner = ""
 						return m, nil
 					}
@@ -508,9 +736,15 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case chatToolResultWithAnswer:
 		return m.handleAnswerRoute(msg)
 
+	case chatToolResultWithBroadcast:
+		return m.handleBroadcast(msg)
+
 	case chatToolResultWithPlanRequest:
 		return m.handlePlanRequest(msg)
 
+	case chatToolResultWithReview:
+		return m.handleReviewDispatch(msg)
+
 	case chatToolResultWithSpawn:
 		// Feed tool results back to orchestrator chat
 		oc := m.orchChat()
@@ -519,10 +753,10 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		oc.scrollToBottom()
 
 		// Spawn the agent tab
-		m.spawnAgentTab(msg.spawn)
+		replay := m.spawnAgentTab(msg.spawn)
 
 		// Continue orchestrator streaming
-		return m, m.beginStream("orchestrator", oc)
+		return m, tea.Batch(replay, m.beginStream("orchestrator", oc))
 
 	}
 
@@ -553,7 +787,7 @@ func (m model) View() string {
 	if tab := m.agents.active(); tab != nil {
 		streaming = tab.chat.streaming
 	}
-	b.WriteString(m.hud.View(m.ws, m.services, streaming, m.width))
+	b.WriteString(m.hud.View(m.ws, m.services, streaming, m.notifications.unread(), m.width))
 	b.WriteString("\n")
 
 	// Agent tab bar (if agents exist)
@@ -564,10 +798,50 @@ func (m model) View() string {
 
 	// Content
 	ch := m.contentHeight()
+	if m.palette != nil {
+		b.WriteString(m.palette.View(m.width, ch))
+		b.WriteString("\n")
+		b.WriteString(footerStyle.Render("[↑/↓] navigate  [enter] open  [esc] close"))
+		return b.String()
+	}
+	if m.notifView != nil {
+		b.WriteString(m.notifView.View(m.width, ch, &m.notifications))
+		b.WriteString("\n")
+		b.WriteString(footerStyle.Render(m.footer()))
+		return b.String()
+	}
 	switch m.state {
 	case viewDashboard:
+		if m.diffView != nil {
+			b.WriteString(m.diffView.View(m.width, ch))
+			break
+		}
+		if m.sessionView != nil {
+			b.WriteString(m.sessionView.View(m.width, ch))
+			break
+		}
+		if m.replayView != nil {
+			b.WriteString(m.replayView.View(m.width, ch))
+			break
+		}
+		if m.leaderboardView != nil {
+			b.WriteString(m.leaderboardView.View(m.width, ch))
+			break
+		}
+		if m.funnelView != nil {
+			b.WriteString(m.funnelView.View(m.width, ch))
+			break
+		}
+		if m.compareView != nil {
+			b.WriteString(m.compareView.View(m.width, ch))
+			break
+		}
 		b.WriteString(m.overlay.View(m.width, ch, m.tasks, m.proposals, m.plans, m.sessions, m.services, m.ws, m.tree, m.chatCl, m.agents, m.spawnInput, m.spawnBuf, m.activeChat().maxToolIter, m.agentSnapshot, m.workOrders, m.activeChat().meter.View()))
 	case viewAgent:
+		if m.lineageView != nil {
+			b.WriteString(m.lineageView.View(m.width, ch))
+			break
+		}
 		if tab := m.agents.active(); tab != nil {
 			b.WriteString(tab.chat.View(m.width, ch))
 		}
@@ -605,20 +879,32 @@ func (m *model) releaseAllAgents() []tea.Cmd {
 }
 
 func (m model) footer() string {
+	var prefix string
+	if m.dbStatus != nil {
+		prefix = m.dbStatus.view()
+	}
+	if m.observer {
+		prefix += textWarning.Render(observerIndicator)
+	}
+	return prefix + m.modeFooter()
+}
+
+// modeFooter returns the key hints for the current view.
+func (m model) modeFooter() string {
 	switch m.state {
 	case viewDashboard:
 		prefix := "[tab] agents"
 		if m.agentSnapshot != nil {
 			prefix = fmt.Sprintf("[%s rev:%d]", m.agentSnapshot.AgentKey, m.agentSnapshot.Revision)
 		}
-		return prefix + "  [h/l] column  [j/k] navigate  [enter] select  [/] filter  [å/ä] model  [n] spawn  [t] tools  [c] clear+continue  [r] refresh"
+		return prefix + "  [h/l] column  [j/k] navigate  [enter] select  [d] diff  [a/x] approve/reject plan  [R] replay  [L] leaderboard  [F] funnel  [M] compare  [/] filter  [ctrl+f] jump  [å/ä] model  [n] spawn  [t] tools  [c] clear+continue  [r] refresh"
 	case viewAgent:
 		if tab := m.agents.active(); tab != nil {
 			ctrlHint := "peek"
 			if tab.controller == "human" {
 				ctrlHint = "controlling"
 			}
-			agentHelp := fmt.Sprintf("[%s] %s  [shift+tab] switch  [esc] back  [ctrl+p] pause  ",
+			agentHelp := fmt.Sprintf("[%s] %s  [shift+tab] switch  [esc] back  [ctrl+p] pause  [ctrl+g] lineage  [ctrl+f] jump  ",
 				tab.agentKey, ctrlHint)
 			return agentHelp + tab.chat.FooterHelp()
 		}
@@ -635,6 +921,15 @@ func (m *model) toggleDashboard() (tea.Model, tea.Cmd) {
 			m.state = viewAgent
 		}
 		m.agentSnapshot = nil
+		m.diffView = nil
+		m.sessionView = nil
+		m.replayView = nil
+		m.leaderboardView = nil
+		m.funnelView = nil
+		if m.compareView != nil {
+			m.compareView.stop()
+			m.compareView = nil
+		}
 		return m, nil
 	default:
 		m.preDashState = m.state
@@ -642,7 +937,7 @@ func (m *model) toggleDashboard() (tea.Model, tea.Cmd) {
 		cmds := []tea.Cmd{fetchDashData(m.d)}
 		if m.preDashState == viewAgent {
 			if tab := m.agents.active(); tab != nil {
-				cmds = append(cmds, fetchAgentSnapshot(m.d, tab.agentKey, tab.mission))
+				cmds = append(cmds, fetchAgentSnapshot(m.d, tab.agentKey, tab.mission, maps.Clone(tab.chat.fileCounts)))
 			}
 		} else {
 			m.agentSnapshot = nil
@@ -745,11 +1040,12 @@ func (m *model) cycleAgentPrev() (tea.Model, tea.Cmd) {
 }
 
 // ensureTempTab returns an existing tab for agentKey or creates a new idle tab.
-// Used for non-favorite agents that need a temporary tab (e.g. via ask_agent or give_to_planner).
-func (m *model) ensureTempTab(agentKey string) *agentTab {
+// Used for non-favorite agents that need a temporary tab (e.g. via ask_agent, give_to_planner or request_peer_review).
+// The returned command replays missed broadcasts into a new tab; it is nil for an existing one.
+func (m *model) ensureTempTab(agentKey string) (*agentTab, tea.Cmd) {
 	for _, tab := range m.agents.tabs {
 		if tab.agentKey == agentKey {
-			return tab
+			return tab, nil
 		}
 	}
 	// Find def
@@ -769,16 +1065,21 @@ func (m *model) ensureTempTab(agentKey string) *agentTab {
 	agentChat := newChatModel(m.chatCl, m.d, "")
 	agentChat.scopedAgent = agentKey
 	agentChat.agentMission = mission
+	agentChat.observer = m.observer
 	tab := m.agents.spawn(displayName, agentKey, "", "", "", agentChat)
 	tab.controller = "idle"
-	return tab
+	tab.tokenBudget = m.defBudget(agentKey)
+	return tab, m.replayBroadcastsCmd(agentKey)
 }
 
-func (m *model) spawnAgentTab(info agentSpawnInfo) {
+// spawnAgentTab opens and activates a tab for a spawned agent. The returned
+// command replays missed broadcasts into it.
+func (m *model) spawnAgentTab(info agentSpawnInfo) tea.Cmd {
 	sessionID := info.SessionID
 	agentChat := newChatModel(m.chatCl, m.d, sessionID)
 	agentChat.scopedAgent = info.AgentKey
 	agentChat.agentMission = info.Mission
+	agentChat.observer = m.observer
 
 	displayName := info.Name
 	if displayName == "" {
@@ -787,6 +1088,10 @@ func (m *model) spawnAgentTab(info agentSpawnInfo) {
 
 	tab := m.agents.spawn(displayName, info.AgentKey, info.Mission, sessionID, "orchestrator", agentChat)
 	tab.status = agentActive
+	tab.tokenBudget = info.TokenBudget
+	if tab.tokenBudget <= 0 {
+		tab.tokenBudget = m.defBudget(info.AgentKey)
+	}
 
 	// Auto-start: inject mission as first user message
 	tab.chat.appendMsg(dash.ChatMessage{
@@ -796,6 +1101,7 @@ func (m *model) spawnAgentTab(info agentSpawnInfo) {
 	
 	// Activate the new tab
 	m.agents.activateByID(tab.id)
+	return m.replayBroadcastsCmd(info.AgentKey)
 }
 
 func (m *model) handleOverlayAction(action string) tea.Cmd {
@@ -828,6 +1134,60 @@ func (m *model) handleOverlayAction(action string) tea.Cmd {
 		m.state = viewAgent
 		return m.beginStream("orchestrator", oc)
 
+	case strings.HasPrefix(action, "diff:"):
+		woName := strings.TrimPrefix(action, "diff:")
+		for _, wo := range m.workOrders {
+			if wo.Node.Name == woName {
+				return fetchWorkOrderDiff(wo)
+			}
+		}
+		return nil
+
+	case strings.HasPrefix(action, "plandiff:"):
+		planName := strings.TrimPrefix(action, "plandiff:")
+		for _, ps := range m.plans {
+			if ps.Node.Name == planName {
+				return fetchPlanDiff(m.d, ps)
+			}
+		}
+		return nil
+
+	case strings.HasPrefix(action, "planapprove:"), strings.HasPrefix(action, "planreject:"):
+		approve := strings.HasPrefix(action, "planapprove:")
+		planName := action[strings.Index(action, ":")+1:]
+		if m.observer {
+			m.overlay.notice = textWarning.Render("Observer mode: plan decisions disabled.")
+			return nil
+		}
+		for _, ps := range m.plans {
+			if ps.Node.Name == planName {
+				return decidePlan(m.d, ps, approve, m.overlay.decisionNote)
+			}
+		}
+		return nil
+
+	case strings.HasPrefix(action, "session:"):
+		sessionID := strings.TrimPrefix(action, "session:")
+		for _, s := range m.sessions {
+			if s.SessionID == sessionID {
+				return fetchSessionHistory(m.d, s)
+			}
+		}
+		return nil
+
+	case strings.HasPrefix(action, "replay:"):
+		return fetchSessionReplay(m.d, strings.TrimPrefix(action, "replay:"))
+
+	case action == "leaderboard":
+		return fetchLeaderboard(m.d)
+
+	case action == "funnel":
+		return fetchFunnel(m.d)
+
+	case action == "compare":
+		m.compareView = newCompareView(m.chatCl)
+		return nil
+
 	case action == "refresh":
 		return tea.Batch(fetchDashData(m.d), fetchIntel(m.d))
 
@@ -854,13 +1214,16 @@ func (m *model) handleOverlayAction(action string) tea.Cmd {
 		return nil
 
 	case action == "clear-continue":
-		m.activeChat().clearAndContinue()
-		m.activeChat().addSystemMessage("Session roterad manuellt.")
+		chat := m.activeChat()
+		cmd := chat.rotateCmd()
+		if cmd != nil {
+			chat.addSystemMessage("Sammanfattar session för manuell rotation...")
+		}
 		m.state = m.preDashState
 		if m.state == viewDashboard {
 			m.state = viewAgent
 		}
-		return nil
+		return cmd
 
 	case action == "spawn":
 		m.spawnInput = true
@@ -940,7 +1303,7 @@ func (m *model) handlePlanRequest(msg chatToolResultWithPlanRequest) (tea.Model,
 	}
 
 	// 2. Ensure planner tab exists (should be favorite, but ensureTempTab handles it)
-	plannerTab := m.ensureTempTab("planner-agent")
+	plannerTab, replay := m.ensureTempTab("planner-agent")
 
 	// 3. Inject request as user message in planner tab
 	reqMsg := fmt.Sprintf("── PLAN REQUEST (%s) ──\n%s", msg.requestID, msg.desc)
@@ -958,7 +1321,7 @@ func (m *model) handlePlanRequest(msg chatToolResultWithPlanRequest) (tea.Model,
 	plannerTab.chat.scrollToBottom()
 
 	// 4. Restart caller stream immediately (fire-and-forget)
-	var cmds []tea.Cmd
+	cmds := []tea.Cmd{replay}
 	if m.activeStreamOwner != "" {
 		cmds = append(cmds, m.beginStream(m.activeStreamOwner, callerChat))
 	} else {
@@ -988,7 +1351,16 @@ func (m *model) handleAskDispatch(msg chatToolResultWithAsk) (tea.Model, tea.Cmd
 		callerChat.scrollToBottom()
 	}
 
-	// 2. Store in pendingQueries
+	// 2. Reject the query if the target is already waiting on the caller,
+	// then store it in pendingQueries
+	if chain := waitChain(m.pendingQueries, q.targetKey, q.callerKey); chain != nil {
+		if callerChat == nil {
+			return m, nil
+		}
+		replaceToolResult(callerChat.messages, q.toolCallID, deadlockError(q, chain))
+		callerChat.toolStatus = ""
+		return m, m.beginStream(q.callerKey, callerChat)
+	}
 	m.pendingQueries[q.id] = &q
 
 	// 3. Find target agent tab
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// maxNotifications bounds the notification history; the oldest are dropped.
+const maxNotifications = 200
+
+// notificationLog is the cockpit's notification history, oldest first.
+type notificationLog struct {
+	items []observationNotification
+}
+
+// add appends notifications, dropping the oldest beyond maxNotifications.
+func (l *notificationLog) add(ns ...observationNotification) {
+	l.items = append(l.items, ns...)
+	if over := len(l.items) - maxNotifications; over > 0 {
+		l.items = append(l.items[:0:0], l.items[over:]...)
+	}
+}
+
+// unread counts notifications not yet marked as seen.
+func (l *notificationLog) unread() int {
+	n := 0
+	for _, it := range l.items {
+		if !it.Seen {
+			n++
+		}
+	}
+	return n
+}
+
+func (l *notificationLog) dismiss(i int) {
+	if i >= 0 && i < len(l.items) {
+		l.items = append(l.items[:i], l.items[i+1:]...)
+	}
+}
+
+func (l *notificationLog) markAllSeen() {
+	for i := range l.items {
+		l.items[i].Seen = true
+	}
+}
+
+// notificationsView is an overlay listing the notification history, newest
+// first. cursor indexes that newest-first order.
+type notificationsView struct {
+	cursor int
+}
+
+// index maps the cursor to a position in log.items.
+func (v *notificationsView) index(log *notificationLog) int {
+	return len(log.items) - 1 - v.cursor
+}
+
+// handleKey returns false when the view should close.
+func (v *notificationsView) handleKey(msg tea.KeyMsg, log *notificationLog) bool {
+	switch msg.String() {
+	case "esc", "q", "ctrl+n":
+		return false
+	case "j", "down":
+		v.cursor++
+	case "k", "up":
+		v.cursor--
+	case "enter", " ":
+		if i := v.index(log); i >= 0 && i < len(log.items) {
+			log.items[i].Seen = !log.items[i].Seen
+		}
+	case "a":
+		log.markAllSeen()
+	case "d", "x":
+		log.dismiss(v.index(log))
+	case "D", "X":
+		log.items = nil
+	}
+	v.cursor = max(min(v.cursor, len(log.items)-1), 0)
+	return true
+}
+
+// View renders the history with type, time, message and read state.
+func (v *notificationsView) View(width, height int, log *notificationLog) string {
+	var b strings.Builder
+	b.WriteString(sectionHeader.Render(fmt.Sprintf("NOTIFICATIONS (%d unread / %d)", log.unread(), len(log.items))))
+	b.WriteString("\n")
+	b.WriteString(sectionDivider.Render(strings.Repeat("─", min(width, 60))))
+	b.WriteString("\n")
+
+	if len(log.items) == 0 {
+		b.WriteString(textDim.Render("  no notifications") + "\n")
+		return b.String()
+	}
+
+	bodyH := max(height-4, 1)
+	start := max(v.cursor-bodyH+1, 0)
+	for row := start; row < len(log.items) && row < start+bodyH; row++ {
+		n := log.items[len(log.items)-1-row]
+		marker := textCyan.Render("●")
+		msgStyle := textPrimary
+		if n.Seen {
+			marker = textDim.Render("○")
+			msgStyle = textDim
+		}
+		typeStyle := textDim
+		switch n.Type {
+		case "alert":
+			typeStyle = textAlert
+		case "pattern", "insight":
+			typeStyle = textWarning
+		}
+		cursor := "  "
+		if row == v.cursor {
+			cursor = textCyan.Render("> ")
+		}
+		line := fmt.Sprintf("%s%s %s %s %s", cursor, marker,
+			textDim.Render(n.Timestamp.Format("15:04:05")),
+			typeStyle.Render(fmt.Sprintf("%-8s", n.Type)),
+			msgStyle.Render(n.Message))
+		b.WriteString(truncate(line, width-2) + "\n")
+	}
+	b.WriteString("\n")
+	b.WriteString(textDim.Render("  [j/k] move  [enter] read/unread  [a] all read  [d] dismiss  [D] dismiss all  [esc] close"))
+	return b.String()
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"slices"
+
+	"dash"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// observerIndicator prefixes the footer while observer mode is on.
+const observerIndicator = "[OBSERVER] "
+
+// toggleObserver switches read-only observer mode. While on, keystrokes never
+// take control of an agent, nothing is spawned or paused, and chats only get
+// read-only tools. Agents already under human control keep their controller.
+func (m *model) toggleObserver() (tea.Model, tea.Cmd) {
+	m.observer = !m.observer
+	for _, tab := range m.agents.tabs {
+		if tab.chat != nil {
+			tab.chat.observer = m.observer
+		}
+	}
+	if c := m.activeChat(); c != nil {
+		if m.observer {
+			c.addSystemMessage("Observer mode on: read-only, no take-control.")
+		} else {
+			c.addSystemMessage("Observer mode off.")
+		}
+	}
+	return m, nil
+}
+
+// isReadOnlyTool reports whether a registered tool is tagged read and not write.
+func isReadOnlyTool(d *dash.Dash, name string) bool {
+	if d == nil {
+		return false
+	}
+	def, ok := d.Registry().Get(name)
+	if !ok {
+		return false
+	}
+	return slices.Contains(def.Tags, "read") && !slices.Contains(def.Tags, "write")
+}
+
+// readOnlyTools returns the client's tools restricted to read-only ones.
+func (m *chatModel) readOnlyTools() []map[string]any {
+	filtered := []map[string]any{}
+	for _, tool := range m.client.tools {
+		fn, ok := tool["function"].(map[string]any)
+		if !ok {
+			continue
+		}
+		name, _ := fn["name"].(string)
+		if isReadOnlyTool(m.d, name) {
+			filtered = append(filtered, tool)
+		}
+	}
+	return filtered
+}
@@ -14,9 +14,10 @@ import (
 )
 
 type overlayItem struct {
-	kind  string // "plan", "task"
+	kind  string // "plan", "wo", "task", "session"
 	name  string
 	label string
+	gated bool // plan awaiting user approval
 }
 
 type overlayModel struct {
@@ -29,6 +30,12 @@ type overlayModel struct {
 	filterInput textinput.Model
 	filtering   bool
 	filterText  string
+
+	// Plan gate decisions
+	confirm      *planConfirm
+	noteInput    textinput.Model
+	decisionNote string // note for a "planreject:" action
+	notice       string // outcome of the last decision
 }
 
 func newOverlayModel() overlayModel {
@@ -37,11 +44,11 @@ func newOverlayModel() overlayModel {
 	ti.CharLimit = 50
 	ti.Prompt = "/ "
 	ti.PromptStyle = hudLabel
-	return overlayModel{filterInput: ti}
+	return overlayModel{filterInput: ti, noteInput: newNoteInput()}
 }
 
 // rebuildItems updates selectable items for navigation, applying filter if set.
-func (o *overlayModel) rebuildItems(plans []*dash.PlanState, tasks []dash.TaskWithDeps) {
+func (o *overlayModel) rebuildItems(plans []*dash.PlanState, workOrders []*dash.WorkOrder, tasks []dash.TaskWithDeps, sessions []dash.ActivitySummary) {
 	filter := strings.ToLower(o.filterText)
 
 	o.items[0] = nil
@@ -59,6 +66,18 @@ func (o *overlayModel) rebuildItems(plans []*dash.PlanState, tasks []dash.TaskWi
 			kind:  "plan",
 			name:  p.Node.Name,
 			label: label,
+			gated: p.NeedsUserApproval(),
+		})
+	}
+	for _, wo := range workOrders {
+		label := fmt.Sprintf("%s [%s]", wo.Node.Name, wo.Status)
+		if filter != "" && !strings.Contains(strings.ToLower(label), filter) {
+			continue
+		}
+		o.items[0] = append(o.items[0], overlayItem{
+			kind:  "wo",
+			name:  wo.Node.Name,
+			label: label,
 		})
 	}
 	for _, t := range tasks {
@@ -76,6 +95,15 @@ func (o *overlayModel) rebuildItems(plans []*dash.PlanState, tasks []dash.TaskWi
 			label: label,
 		})
 	}
+	// Sessions are not filtered: the timeline shares one time axis.
+	o.items[2] = nil
+	for _, s := range sessions {
+		o.items[2] = append(o.items[2], overlayItem{
+			kind:  "session",
+			name:  s.SessionID,
+			label: sessionLabel(s),
+		})
+	}
 	// Clamp cursors
 	for i := range o.cursor {
 		max := len(o.items[i])
@@ -88,6 +116,9 @@ func (o *overlayModel) rebuildItems(plans []*dash.PlanState, tasks []dash.TaskWi
 }
 
 func (o *overlayModel) handleKey(msg tea.KeyMsg) tea.Cmd {
+	if o.confirm != nil {
+		return o.handleConfirmKey(msg)
+	}
 	// Filter mode: delegate to textinput
 	if o.filtering {
 		switch msg.Type {
@@ -122,8 +153,6 @@ func (o *overlayModel) handleKey(msg tea.KeyMsg) tea.Cmd {
 		max := len(o.items[o.focusCol])
 		if o.focusCol == 1 {
 			max = 10 // intel items are view-only, allow some scrolling
-		} else if o.focusCol == 2 {
-			max = 10
 		}
 		if o.cursor[o.focusCol] < max-1 {
 			o.cursor[o.focusCol]++
@@ -171,6 +200,47 @@ func (o *overlayModel) handleKey(msg tea.KeyMsg) tea.Cmd {
 	case ActionDashClearContinue:
 		o.action = "clear-continue"
 		return nil
+	case ActionDashDiff:
+		items := o.items[o.focusCol]
+		cur := o.cursor[o.focusCol]
+		if o.focusCol == 0 && cur < len(items) {
+			switch items[cur].kind {
+			case "wo":
+				o.action = "diff:" + items[cur].name
+			case "plan":
+				o.action = "plandiff:" + items[cur].name
+			}
+		}
+		return nil
+	case ActionDashApprove, ActionDashReject:
+		items := o.items[o.focusCol]
+		cur := o.cursor[o.focusCol]
+		if o.focusCol == 0 && cur < len(items) && items[cur].gated {
+			o.confirm = &planConfirm{approve: action == ActionDashApprove, plan: items[cur].name}
+			o.notice = ""
+			if !o.confirm.approve {
+				o.noteInput.Reset()
+				o.noteInput.Focus()
+				return o.noteInput.Cursor.BlinkCmd()
+			}
+		}
+		return nil
+	case ActionDashReplay:
+		items := o.items[o.focusCol]
+		cur := o.cursor[o.focusCol]
+		if cur < len(items) && items[cur].kind == "session" {
+			o.action = "replay:" + items[cur].name
+		}
+		return nil
+	case ActionDashLeaderboard:
+		o.action = "leaderboard"
+		return nil
+	case ActionDashCompare:
+		o.action = "compare"
+		return nil
+	case ActionDashFunnel:
+		o.action = "funnel"
+		return nil
 	case ActionDashFilter:
 		o.filtering = true
 		o.filterInput.Reset()
@@ -203,6 +273,13 @@ func (o *overlayModel) View(width, height int, tasks []dash.TaskWithDeps, propos
 		barLines++
 		bar += "\n" + filterLine
 	}
+	if o.confirm != nil {
+		barLines++
+		bar += "\n" + o.confirmLine()
+	} else if o.notice != "" {
+		barLines++
+		bar += "\n" + o.notice
+	}
 
 	colHeight := height - barLines - 1
 
@@ -320,8 +397,13 @@ func (o *overlayModel) renderWorkColumn(w, h int, plans []*dash.PlanState, tasks
 			if agent == "" {
 				agent = "unassigned"
 			}
-			line := fmt.Sprintf("  %s %s [%s] \u2192 %s", icon, truncate(wo.Node.Name, w-25), wo.Status, agent)
-			b.WriteString(line + "\n")
+			line := fmt.Sprintf("%s %s [%s] \u2192 %s", icon, truncate(wo.Node.Name, w-25), wo.Status, agent)
+			if o.focusCol == 0 && o.cursor[0] == o.findItemIndex("wo", wo.Node.Name) {
+				b.WriteString(cursorActive.Render("> ") + textPrimary.Render(line))
+			} else {
+				b.WriteString("  " + line)
+			}
+			b.WriteString("\n")
 		}
 		b.WriteString("\n")
 	}
@@ -483,6 +565,14 @@ func (o *overlayModel) renderSystemColumn(w, h int, services []serviceStatus, se
 		} else if s.PID != "" {
 			pidInfo = textDim.Render(" pid:" + s.PID)
 		}
+		if s.Running && s.Heartbeat != nil {
+			if s.Heartbeat.Stale {
+				indicator = textWarning.Render("\u25cf")
+				pidInfo += textWarning.Render(" stale")
+			} else {
+				pidInfo += textDim.Render(" beat:" + s.Heartbeat.Age.Round(time.Second).String())
+			}
+		}
 		b.WriteString(fmt.Sprintf("  %s %s%s\n", indicator, s.Name, pidInfo))
 	}
 	b.WriteString("\n")
@@ -491,23 +581,12 @@ func (o *overlayModel) renderSystemColumn(w, h int, services []serviceStatus, se
 	if len(sessions) > 0 {
 		b.WriteString(textPrimary.Render(fmt.Sprintf("SESSIONS (%d)", len(sessions))))
 		b.WriteString("\n")
-		for i, s := range sessions {
-			if i >= 5 {
-				b.WriteString(textDim.Render(fmt.Sprintf("  +%d more...", len(sessions)-5)))
-				b.WriteString("\n")
-				break
-			}
-			statusIcon := textSuccess.Render("\u25cf")
-			if s.Status == "ended" {
-				statusIcon = textDim.Render("\u25cb")
-			}
-			dur := formatDuration(s.StartedAt, s.EndedAt)
-			sid := s.SessionID
-			if len(sid) > 8 {
-				sid = sid[:8]
-			}
-			b.WriteString(fmt.Sprintf("  %s %s %s %dR %dW\n",
-				statusIcon, sid, dur, s.FilesRead, s.FilesWrote))
+		selected := -1
+		if o.focusCol == 2 {
+			selected = o.cursor[2]
+		}
+		for _, line := range renderSessionTimeline(sessions, w, selected, time.Now()) {
+			b.WriteString(line + "\n")
 		}
 		b.WriteString("\n")
 	}
@@ -673,8 +752,41 @@ func (o *overlayModel) renderAgentSystemColumn(w, h int, s *dash.AgentContextSna
 	if s.Live.Exchanges > 0 {
 		b.WriteString(fmt.Sprintf("  exchanges: %d\n", s.Live.Exchanges))
 	}
+	if s.Live.TokenBudget > 0 {
+		remaining := max(s.Live.TokenBudget-s.Live.TokensSpent, 0)
+		style := textDim
+		switch {
+		case remaining == 0:
+			style = textAlert
+		case remaining*5 < s.Live.TokenBudget:
+			style = textWarning
+		}
+		b.WriteString("  " + style.Render(fmt.Sprintf("budget: %d/%d left", remaining, s.Live.TokenBudget)) + "\n")
+	}
 	b.WriteString("\n")
 
+	// Mission drift
+	if s.Drift != nil && s.Drift.Activity != "" {
+		b.WriteString(textPrimary.Render("MISSION DRIFT"))
+		b.WriteString("\n")
+		style := textSuccess
+		switch {
+		case s.Drift.Drifting:
+			style = textAlert
+		case s.Drift.Score > dash.MissionDriftThreshold*0.8:
+			style = textWarning
+		}
+		b.WriteString("  " + style.Render(fmt.Sprintf("drift: %.2f", s.Drift.Score)))
+		if s.Drift.Drifting {
+			b.WriteString(" " + textAlert.Render("off mission"))
+		}
+		b.WriteString("\n")
+		for _, f := range s.Drift.OffMissionFiles[:min(3, len(s.Drift.OffMissionFiles))] {
+			b.WriteString("  " + textDim.Render(filepath.Base(f)) + "\n")
+		}
+		b.WriteString("\n")
+	}
+
 	// Active peers
 	if len(s.Peers) > 0 {
 		b.WriteString(textPrimary.Render(fmt.Sprintf("ACTIVE PEERS (%d)", len(s.Peers))))
@@ -760,8 +872,12 @@ func woStatusIcon(status dash.WorkOrderStatus) string {
 }
 
 func (o *overlayModel) findTaskIndex(name string) int {
+	return o.findItemIndex("task", name)
+}
+
+func (o *overlayModel) findItemIndex(kind, name string) int {
 	for i, item := range o.items[0] {
-		if item.name == name {
+		if item.kind == kind && item.name == name {
 			return i
 		}
 	}
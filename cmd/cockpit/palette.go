@@ -0,0 +1,329 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"dash"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	paletteDebounce    = 200 * time.Millisecond
+	paletteMinQuery    = 2 // shorter queries only match local items
+	paletteRemoteLimit = 15
+	paletteMaxItems    = 30
+)
+
+// paletteItem is a selectable palette entry.
+type paletteItem struct {
+	kind  string // "agent", "plan", "task", "node"
+	name  string
+	label string // LAYER.type or "agent"
+	tabID string
+	node  *dash.Node
+}
+
+// paletteSearchMsg fires after the debounce delay; stale seqs are dropped.
+type paletteSearchMsg struct {
+	seq   int
+	query string
+}
+
+// paletteResultsMsg carries graph search results for a query.
+type paletteResultsMsg struct {
+	seq   int
+	nodes []*dash.Node
+	err   error
+}
+
+// paletteModel is a fuzzy command palette for jumping to agents, plans,
+// tasks and arbitrary graph nodes.
+type paletteModel struct {
+	input  textinput.Model
+	local  []paletteItem // agents, plans, tasks known to the cockpit
+	remote []*dash.Node  // latest graph search results
+	items  []paletteItem // filtered view of local + remote
+	cursor int
+	seq    int
+	err    error
+	detail *dash.Node // node shown in detail mode
+}
+
+func newPaletteModel(local []paletteItem) *paletteModel {
+	ti := textinput.New()
+	ti.Placeholder = "jump to agent, plan, task or node..."
+	ti.CharLimit = 80
+	ti.Prompt = "› "
+	ti.PromptStyle = hudLabel
+	ti.Focus()
+	p := &paletteModel{input: ti, local: local}
+	p.refilter()
+	return p
+}
+
+// paletteLocalItems collects the agents, plans and tasks already loaded.
+func (m *model) paletteLocalItems() []paletteItem {
+	var items []paletteItem
+	for _, tab := range m.agents.tabs {
+		items = append(items, paletteItem{kind: "agent", name: tab.displayName, label: "agent " + tab.agentKey, tabID: tab.id})
+	}
+	for _, p := range m.plans {
+		items = append(items, paletteItem{kind: "plan", name: p.Node.Name, label: "CONTEXT.plan", node: p.Node})
+	}
+	for _, t := range m.tasks {
+		items = append(items, paletteItem{kind: "task", name: t.Node.Name, label: "CONTEXT.task", node: t.Node})
+	}
+	return items
+}
+
+// fuzzyMatch reports whether every rune of query appears in s in order.
+func fuzzyMatch(query, s string) bool {
+	if query == "" {
+		return true
+	}
+	q := []rune(strings.ToLower(query))
+	i := 0
+	for _, r := range strings.ToLower(s) {
+		if r == q[i] {
+			i++
+			if i == len(q) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// refilter rebuilds items from local matches followed by unseen remote nodes.
+func (p *paletteModel) refilter() {
+	query := strings.TrimSpace(p.input.Value())
+	p.items = nil
+	seen := make(map[string]bool)
+	for _, it := range p.local {
+		if !fuzzyMatch(query, it.label+" "+it.name) {
+			continue
+		}
+		p.items = append(p.items, it)
+		if it.node != nil {
+			seen[it.node.ID.String()] = true
+		}
+	}
+	for _, n := range p.remote {
+		if seen[n.ID.String()] {
+			continue
+		}
+		seen[n.ID.String()] = true
+		p.items = append(p.items, paletteItem{kind: "node", name: n.Name, label: string(n.Layer) + "." + n.Type, node: n})
+	}
+	if len(p.items) > paletteMaxItems {
+		p.items = p.items[:paletteMaxItems]
+	}
+	if p.cursor >= len(p.items) {
+		p.cursor = max(len(p.items)-1, 0)
+	}
+}
+
+// selected returns the highlighted item, if any.
+func (p *paletteModel) selected() (paletteItem, bool) {
+	if p.cursor < len(p.items) {
+		return p.items[p.cursor], true
+	}
+	return paletteItem{}, false
+}
+
+// handleKey updates the palette. It returns a command to run, and close=true
+// when the palette should be dismissed.
+func (p *paletteModel) handleKey(msg tea.KeyMsg) (cmd tea.Cmd, close bool) {
+	if p.detail != nil {
+		if msg.Type == tea.KeyEsc {
+			p.detail = nil
+		}
+		return nil, false
+	}
+	switch msg.Type {
+	case tea.KeyEsc:
+		return nil, true
+	case tea.KeyUp, tea.KeyCtrlP:
+		if p.cursor > 0 {
+			p.cursor--
+		}
+		return nil, false
+	case tea.KeyDown, tea.KeyCtrlN:
+		if p.cursor < len(p.items)-1 {
+			p.cursor++
+		}
+		return nil, false
+	}
+
+	before := p.input.Value()
+	p.input, cmd = p.input.Update(msg)
+	query := strings.TrimSpace(p.input.Value())
+	if p.input.Value() == before {
+		return cmd, false
+	}
+	p.cursor = 0
+	p.seq++
+	if len([]rune(query)) < paletteMinQuery {
+		p.remote = nil
+		p.refilter()
+		return cmd, false
+	}
+	p.refilter()
+	seq := p.seq
+	debounce := tea.Tick(paletteDebounce, func(time.Time) tea.Msg {
+		return paletteSearchMsg{seq: seq, query: query}
+	})
+	return tea.Batch(cmd, debounce), false
+}
+
+// searchPaletteNodes matches node names, then adds semantic hits when an
+// embedder is configured.
+func searchPaletteNodes(d *dash.Dash, seq int, query string) tea.Cmd {
+	return func() tea.Msg {
+		if d == nil {
+			return paletteResultsMsg{seq: seq}
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+
+		pattern := "%" + query + "%"
+		nodes, err := d.SearchNodes(ctx, dash.NodeFilter{NamePattern: &pattern, Limit: paletteRemoteLimit})
+		if err != nil {
+			return paletteResultsMsg{seq: seq, err: err}
+		}
+		if results, err := d.SearchSimilar(ctx, query, paletteRemoteLimit/2); err == nil {
+			for _, r := range results {
+				nodes = append(nodes, &dash.Node{
+					ID:    r.ID,
+					Layer: dash.Layer(r.Layer),
+					Type:  r.Type,
+					Name:  r.Name,
+					Data:  r.Data,
+				})
+			}
+		}
+		return paletteResultsMsg{seq: seq, nodes: nodes}
+	}
+}
+
+// View renders the palette list, or the selected node's detail.
+func (p *paletteModel) View(width, height int) string {
+	var b strings.Builder
+	if p.detail != nil {
+		n := p.detail
+		b.WriteString(sectionHeader.Render(fmt.Sprintf("[%s.%s] %s", n.Layer, n.Type, n.Name)))
+		b.WriteString("\n")
+		b.WriteString(sectionDivider.Render(strings.Repeat("─", min(width, 60))))
+		b.WriteString("\n")
+		b.WriteString(textDim.Render("  id: "+n.ID.String()) + "\n")
+		var pretty strings.Builder
+		var data any
+		if json.Unmarshal(n.Data, &data) == nil {
+			out, _ := json.MarshalIndent(data, "  ", "  ")
+			pretty.Write(out)
+		}
+		lines := strings.Split("  "+pretty.String(), "\n")
+		if len(lines) > height-4 {
+			lines = lines[:max(height-4, 1)]
+		}
+		for _, l := range lines {
+			b.WriteString(textPrimary.Render(truncate(l, width-2)) + "\n")
+		}
+		return b.String()
+	}
+
+	b.WriteString(sectionHeader.Render("JUMP TO"))
+	b.WriteString("\n")
+	b.WriteString(p.input.View())
+	b.WriteString("\n")
+	b.WriteString(sectionDivider.Render(strings.Repeat("─", min(width, 60))))
+	b.WriteString("\n")
+	if p.err != nil {
+		b.WriteString(textWarning.Render(fmt.Sprintf("  search failed: %v", p.err)) + "\n")
+	}
+	if len(p.items) == 0 {
+		b.WriteString(textDim.Render("  no matches") + "\n")
+		return b.String()
+	}
+
+	bodyH := max(height-4, 1)
+	start := 0
+	if p.cursor >= bodyH {
+		start = p.cursor - bodyH + 1
+	}
+	end := min(start+bodyH, len(p.items))
+	for i := start; i < end; i++ {
+		it := p.items[i]
+		line := textDim.Render("["+it.label+"] ") + textPrimary.Render(it.name)
+		if i == p.cursor {
+			b.WriteString(cursorActive.Render("> ") + truncate(line, width-4) + "\n")
+		} else {
+			b.WriteString("  " + truncate(line, width-4) + "\n")
+		}
+	}
+	return b.String()
+}
+
+// openPalette shows the command palette seeded with local items.
+func (m *model) openPalette() tea.Cmd {
+	m.palette = newPaletteModel(m.paletteLocalItems())
+	return m.palette.input.Cursor.BlinkCmd()
+}
+
+// handlePaletteKey routes a key to the palette and acts on selection.
+func (m *model) handlePaletteKey(msg tea.KeyMsg) tea.Cmd {
+	p := m.palette
+	if msg.Type == tea.KeyEnter && p.detail == nil {
+		it, ok := p.selected()
+		if !ok {
+			return nil
+		}
+		return m.selectPaletteItem(it)
+	}
+	cmd, closePalette := p.handleKey(msg)
+	if closePalette {
+		m.palette = nil
+	}
+	return cmd
+}
+
+// selectPaletteItem jumps to an agent, loads a plan/task into the
+// orchestrator, or shows node detail.
+func (m *model) selectPaletteItem(it paletteItem) tea.Cmd {
+	if it.kind == "node" && it.node != nil && it.node.Layer == dash.LayerContext {
+		switch it.node.Type {
+		case "plan", "task":
+			it.kind = it.node.Type
+		case "agent_session":
+			for _, tab := range m.agents.tabs {
+				if tab.sessionID == it.node.Name {
+					it.kind, it.tabID = "agent", tab.id
+				}
+			}
+		}
+	}
+
+	switch it.kind {
+	case "agent":
+		m.palette = nil
+		for i, tab := range m.agents.tabs {
+			if tab.id == it.tabID {
+				m.focusAgent(i)
+				break
+			}
+		}
+		return nil
+	case "plan", "task":
+		m.palette = nil
+		return m.handleOverlayAction(it.kind + ":" + it.name)
+	default:
+		m.palette.detail = it.node
+		return nil
+	}
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"dash"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// peerReviewDispatch is a review request routed to the reviewer's tab.
+type peerReviewDispatch struct {
+	reviewID  string
+	workOrder string
+	reviewer  string
+	prompt    string
+}
+
+// chatToolResultWithReview is sent when executeTools detects a request_peer_review result.
+type chatToolResultWithReview struct {
+	results []dash.ChatMessage
+	calls   []streamToolCall
+	review  peerReviewDispatch
+}
+
+// parseReviewResult extracts request_peer_review dispatch info from a tool result JSON.
+func parseReviewResult(resultJSON string) *peerReviewDispatch {
+	var data map[string]any
+	if err := json.Unmarshal([]byte(resultJSON), &data); err != nil {
+		return nil
+	}
+	reviewID, _ := data["review_id"].(string)
+	reviewer, _ := data["reviewer"].(string)
+	if reviewID == "" || reviewer == "" {
+		return nil
+	}
+	return &peerReviewDispatch{
+		reviewID:  reviewID,
+		workOrder: strOr(data["work_order"], ""),
+		reviewer:  reviewer,
+		prompt:    strOr(data["prompt"], ""),
+	}
+}
+
+// handleReviewDispatch processes a chatToolResultWithReview: the review is
+// injected into the reviewer's tab and the caller continues (fire-and-forget).
+// The reviewer answers with submit_peer_review.
+func (m *model) handleReviewDispatch(msg chatToolResultWithReview) (tea.Model, tea.Cmd) {
+	r := msg.review
+
+	// 1. Feed tool results back to caller's chat
+	callerChat := m.activeStreamChat()
+	if callerChat != nil {
+		callerChat.appendMsgs(msg.results)
+		callerChat.toolStatus = ""
+		callerChat.scrollToBottom()
+	}
+
+	// 2. Inject the review request in the reviewer tab
+	reviewerTab, replay := m.ensureTempTab(r.reviewer)
+	reviewerTab.chat.appendMsg(dash.ChatMessage{
+		Role:    "user",
+		Content: fmt.Sprintf("── PEER REVIEW (%s) ──\n%s", r.reviewID, r.prompt),
+	})
+	reviewerTab.chat.toolIter = 0
+	reviewerTab.chat.scrollToBottom()
+
+	// 3. Restart caller stream immediately
+	cmds := []tea.Cmd{replay}
+	if m.activeStreamOwner != "" {
+		cmds = append(cmds, m.beginStream(m.activeStreamOwner, callerChat))
+	} else {
+		cmds = append(cmds, m.beginStream("orchestrator", m.orchChat()))
+	}
+
+	// 4. Lazy spawn reviewer if idle
+	if reviewerTab.status == agentIdle {
+		reviewerTab.status = agentSpawned
+		reviewerTab.chat.toolStatus = "spawning reviewer..."
+		cmds = append(cmds, m.spawnAgentWithMission(r.reviewer))
+	}
+
+	return m, tea.Batch(cmds...)
+}
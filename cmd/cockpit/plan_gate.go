@@ -0,0 +1,110 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"dash"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// planConfirm is a pending approve/reject of a plan held at the
+// user_approve gate. Nothing is sent until the user confirms.
+type planConfirm struct {
+	approve bool
+	plan    string
+}
+
+// planDecisionMsg is the result of approving or rejecting a plan.
+type planDecisionMsg struct {
+	plan    string
+	approve bool
+	wo      *dash.WorkOrder
+	err     error
+}
+
+func newNoteInput() textinput.Model {
+	ti := textinput.New()
+	ti.Placeholder = "reason for rejecting..."
+	ti.CharLimit = 200
+	ti.Prompt = "note: "
+	ti.PromptStyle = hudLabel
+	return ti
+}
+
+// handleConfirmKey handles keys while a plan decision awaits confirmation.
+// Approving takes y; rejecting takes a note and enter. Esc (or n when
+// approving) cancels.
+func (o *overlayModel) handleConfirmKey(msg tea.KeyMsg) tea.Cmd {
+	c := o.confirm
+	if c.approve {
+		if msg.String() == "y" {
+			o.action = "planapprove:" + c.plan
+		}
+		o.confirm = nil
+		return nil
+	}
+	switch msg.Type {
+	case tea.KeyEsc:
+		o.confirm = nil
+		o.noteInput.Blur()
+		return nil
+	case tea.KeyEnter:
+		o.action = "planreject:" + c.plan
+		o.decisionNote = o.noteInput.Value()
+		o.confirm = nil
+		o.noteInput.Blur()
+		return nil
+	}
+	var cmd tea.Cmd
+	o.noteInput, cmd = o.noteInput.Update(msg)
+	return cmd
+}
+
+// confirmLine renders the pending confirmation prompt.
+func (o *overlayModel) confirmLine() string {
+	c := o.confirm
+	if c.approve {
+		return textWarning.Render(fmt.Sprintf("Approve plan %s and create its work order?", c.plan)) +
+			textDim.Render("  [y] approve  [n/esc] cancel")
+	}
+	return textWarning.Render(fmt.Sprintf("Reject plan %s back to planning. ", c.plan)) +
+		o.noteInput.View() + textDim.Render("  [enter] reject  [esc] cancel")
+}
+
+// decidePlan approves or rejects a gated plan in the background.
+func decidePlan(d *dash.Dash, ps *dash.PlanState, approve bool, note string) tea.Cmd {
+	return func() tea.Msg {
+		msg := planDecisionMsg{plan: ps.Node.Name, approve: approve}
+		if d == nil {
+			msg.err = fmt.Errorf("no database")
+			return msg
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		ctx = dash.WithCaller(ctx, "cockpit")
+		if approve {
+			_, msg.wo, msg.err = d.ApprovePlanGate(ctx, ps.Node.ID, note)
+		} else {
+			_, msg.err = d.RejectPlanGate(ctx, ps.Node.ID, note)
+		}
+		return msg
+	}
+}
+
+// decisionNotice describes a plan decision for the dashboard.
+func decisionNotice(msg planDecisionMsg) string {
+	switch {
+	case msg.err != nil:
+		return textAlert.Render(fmt.Sprintf("plan %s: %v", msg.plan, msg.err))
+	case !msg.approve:
+		return textWarning.Render(fmt.Sprintf("plan %s sent back to planning", msg.plan))
+	case msg.wo != nil:
+		return textSuccess.Render(fmt.Sprintf("plan %s approved → work order %s", msg.plan, msg.wo.Node.Name))
+	default:
+		return textSuccess.Render(fmt.Sprintf("plan %s approved (no step files, no work order)", msg.plan))
+	}
+}
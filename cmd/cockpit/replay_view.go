@@ -0,0 +1,198 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"dash"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// replayLongGap marks pauses between events worth noticing in a replay.
+const replayLongGap = 30 * time.Second
+
+// sessionReplayMsg carries the recorded events of one session.
+type sessionReplayMsg struct {
+	sessionID string
+	events    []dash.ReplayEvent
+	err       error
+}
+
+// replayView steps through a past session's events one at a time. Events
+// up to pos are revealed; the one at pos is shown in full.
+type replayView struct {
+	sessionID string
+	events    []dash.ReplayEvent
+	pos       int
+	notice    string
+}
+
+// fetchSessionReplay loads a session's events in the background.
+func fetchSessionReplay(d *dash.Dash, sessionID string) tea.Cmd {
+	return func() tea.Msg {
+		if d == nil {
+			return sessionReplayMsg{sessionID: sessionID, err: fmt.Errorf("no database")}
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		events, err := d.SessionReplay(ctx, sessionID)
+		return sessionReplayMsg{sessionID: sessionID, events: events, err: err}
+	}
+}
+
+func newReplayView(msg sessionReplayMsg) *replayView {
+	v := &replayView{sessionID: msg.sessionID, events: msg.events}
+	switch {
+	case msg.err != nil:
+		v.notice = fmt.Sprintf("replay failed: %v", msg.err)
+	case len(msg.events) == 0:
+		v.notice = "no events recorded"
+	}
+	return v
+}
+
+// handleKey steps through the events. Returns false when the view should close.
+func (v *replayView) handleKey(msg tea.KeyMsg) bool {
+	switch msg.String() {
+	case "esc", "q":
+		return false
+	case "n", "l", "j", "right", "down", " ":
+		v.pos++
+	case "p", "h", "k", "left", "up":
+		v.pos--
+	case "N":
+		v.pos = v.nextLongGap()
+	case "g":
+		v.pos = 0
+	case "G":
+		v.pos = len(v.events) - 1
+	}
+	v.pos = max(min(v.pos, len(v.events)-1), 0)
+	return true
+}
+
+// nextLongGap returns the first event after pos that follows a long pause,
+// or the last event.
+func (v *replayView) nextLongGap() int {
+	for i := v.pos + 1; i < len(v.events); i++ {
+		if v.events[i].Gap >= replayLongGap {
+			return i
+		}
+	}
+	return len(v.events) - 1
+}
+
+// View renders the revealed events as a timeline with the current one
+// expanded below it.
+func (v *replayView) View(width, height int) string {
+	var b strings.Builder
+	b.WriteString(sectionHeader.Render("REPLAY " + v.sessionID))
+	if len(v.events) > 0 {
+		cur := v.events[v.pos]
+		elapsed := cur.At.Sub(v.events[0].At).Round(time.Second)
+		b.WriteString(textDim.Render(fmt.Sprintf("  event %d/%d  t+%s", v.pos+1, len(v.events), elapsed)))
+	}
+	b.WriteString("\n")
+	b.WriteString(sectionDivider.Render(strings.Repeat("─", min(width, 60))))
+	b.WriteString("\n")
+
+	if v.notice != "" {
+		b.WriteString(textWarning.Render("  "+v.notice) + "\n")
+		return b.String()
+	}
+
+	cur := v.events[v.pos]
+	box := renderReplayEvent(cur, min(width-2, 100))
+	boxH := strings.Count(box, "\n") + 1
+
+	// Timeline of revealed events, scrolled so pos stays visible.
+	listH := max(height-4-boxH-1, 3)
+	start := max(v.pos-listH+1, 0)
+	for i := start; i <= v.pos; i++ {
+		cursor := "  "
+		if i == v.pos {
+			cursor = cursorActive.Render("> ")
+		}
+		b.WriteString(cursor + truncate(formatReplayLine(v.events[i]), width-4) + "\n")
+	}
+	b.WriteString("\n")
+	b.WriteString(box + "\n")
+	b.WriteString(textDim.Render("  [n/space] next  [p] prev  [N] next pause  [g/G] first/last  [esc] close"))
+	return b.String()
+}
+
+// formatReplayLine renders one event as "15:04:05 +gap ✔ tool(args) dur".
+func formatReplayLine(ev dash.ReplayEvent) string {
+	gap := textDim.Render(fmt.Sprintf("%7s", "+"+formatReplayGap(ev.Gap)))
+	if ev.Gap >= replayLongGap {
+		gap = textWarning.Render(fmt.Sprintf("%7s", "+"+formatReplayGap(ev.Gap)))
+	}
+
+	status := textDim.Render("•")
+	switch {
+	case ev.Event == "tool.failure" || ev.Success != nil && !*ev.Success:
+		status = textAlert.Render("✘")
+	case ev.Event == "tool.pre":
+		status = textWarning.Render("…") // never completed
+	case ev.ToolName != "":
+		status = textSuccess.Render("✔")
+	}
+
+	what := ev.Event
+	if ev.ToolName != "" {
+		what = textMagenta.Render(ev.ToolName)
+		if args := formatToolArgs(replayToolKey(ev.ToolName), string(ev.Args)); args != "" {
+			what += "(" + args + ")"
+		}
+	}
+	dur := ""
+	if ev.DurationMs > 0 {
+		dur = textDim.Render(fmt.Sprintf(" %dms", ev.DurationMs))
+	}
+	return fmt.Sprintf("%s %s %s %s%s", textDim.Render(ev.At.Local().Format("15:04:05")), gap, status, what, dur)
+}
+
+// renderReplayEvent shows a tool event in a tool box like the live chat, and
+// other events as a single line.
+func renderReplayEvent(ev dash.ReplayEvent, width int) string {
+	if ev.ToolName == "" {
+		return "  " + textPrimary.Render(ev.Event)
+	}
+	tc := dash.ToolCallRef{
+		ID:       ev.ToolUseID,
+		Function: dash.ToolCallFunc{Name: replayToolKey(ev.ToolName), Arguments: string(ev.Args)},
+	}
+	result := ev.Result
+	if ev.Error != "" {
+		result = "error: " + ev.Error
+	}
+	if ev.Event == "tool.pre" {
+		return renderToolBoxPending(tc, width)
+	}
+	return renderToolBox(tc, result, width)
+}
+
+// replayToolKey maps hook tool names (Read, Grep, Bash) onto the cockpit's
+// formatter names.
+func replayToolKey(name string) string {
+	if name == "Bash" {
+		return "exec"
+	}
+	return strings.ToLower(name)
+}
+
+// formatReplayGap renders a pause compactly: 850ms, 12s, 3m20s, 1h05m.
+func formatReplayGap(d time.Duration) string {
+	switch {
+	case d < time.Second:
+		return fmt.Sprintf("%dms", d.Milliseconds())
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm%02ds", int(d.Minutes()), int(d.Seconds())%60)
+	}
+	return fmt.Sprintf("%dh%02dm", int(d.Hours()), int(d.Minutes())%60)
+}
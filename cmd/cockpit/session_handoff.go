@@ -161,22 +161,30 @@ func generateBriefing(snap *dash.AgentContextSnapshot, now time.Time) string {
 
 type handoffCompleteMsg struct {
 	agentID string
+	summary string // state summary for the continued session
 	err     error
 }
 
 func performHandoff(d *dash.Dash, tab *agentTab) tea.Cmd {
+	msgs := tab.chat.conversationMessages()
 	return func() tea.Msg {
-		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		ctx, cancel := context.WithTimeout(dash.WithLLMSession(context.Background(), tab.sessionID), 30*time.Second)
 		defer cancel()
 
-		// 1. Build envelope summary from recent messages
+		// 1. Build envelope summary from recent messages, and the state
+		// summary that seeds the continued session
 		summary := buildEnvelopeSummary(tab)
+		state, err := d.SummarizeSessionForHandoff(ctx, msgs)
+		if err != nil || state == "" {
+			state = buildConversationSummary(msgs)
+		}
 
 		// 2. Store as observation
 		_ = d.StoreObservation(ctx, tab.sessionID, "session_handoff", map[string]any{
 			"agent_key":    tab.agentKey,
 			"mission":      tab.mission,
 			"summary":      summary,
+			"state_summary": state,
 			"token_usage":  tab.meter.total(),
 			"token_limit":  tab.meter.limit,
 			"handoff_at":   time.Now().UTC().Format(time.RFC3339),
@@ -197,7 +205,7 @@ func performHandoff(d *dash.Dash, tab *agentTab) tea.Cmd {
 			return handoffCompleteMsg{agentID: tab.id, err: fmt.Errorf("update_agent: %s", result.Error)}
 		}
 
-		return handoffCompleteMsg{agentID: tab.id}
+		return handoffCompleteMsg{agentID: tab.id, summary: state}
 	}
 }
 
@@ -251,7 +259,7 @@ func resumeAfterHandoff(d *dash.Dash, tab *agentTab, chatCl *chatClient) tea.Cmd
 		tab.chat.renderLog = nil
 		tab.chat.appendMsg(dash.ChatMessage{Role: "system", Content: text})
 		tab.chat.appendMsg(dash.ChatMessage{Role: "user", Content: "Handoff: du har nått token-gränsen. Här är sammanfattningen av ditt arbete hittills. Fortsätt med missionen."})
-		tab.meter = newTokenMeter(tab.meter.limit)
+		tab.meter.rotate()
 		tab.status = agentActive
 
 		return handoffCompleteMsg{agentID: tab.id}
@@ -260,11 +268,12 @@ func resumeAfterHandoff(d *dash.Dash, tab *agentTab, chatCl *chatClient) tea.Cmd
 
 // agentSpawnInfo is extracted from the spawn_agent tool result.
 type agentSpawnInfo struct {
-	ID        string `json:"id"`
-	AgentKey  string `json:"agent_key"`
-	Name      string `json:"name"`
-	Mission   string `json:"mission"`
-	SessionID string `json:"session_id"`
+	ID          string `json:"id"`
+	AgentKey    string `json:"agent_key"`
+	Name        string `json:"name"`
+	Mission     string `json:"mission"`
+	SessionID   string `json:"session_id"`
+	TokenBudget int    `json:"token_budget"`
 }
 
 func parseSpawnResult(resultJSON string) *agentSpawnInfo {
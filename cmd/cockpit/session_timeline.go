@@ -0,0 +1,221 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"dash"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// sessionHistoryMsg carries the file operations of one session.
+type sessionHistoryMsg struct {
+	session dash.ActivitySummary
+	ops     []dash.FileOperation
+	err     error
+}
+
+// sessionView is an overlay listing a session's file operations in order.
+type sessionView struct {
+	session dash.ActivitySummary
+	lines   []string
+	notice  string
+	offset  int
+}
+
+// fetchSessionHistory loads a session's file operations in the background.
+func fetchSessionHistory(d *dash.Dash, s dash.ActivitySummary) tea.Cmd {
+	return func() tea.Msg {
+		if d == nil {
+			return sessionHistoryMsg{session: s, err: fmt.Errorf("no database")}
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+		ops, err := d.SessionHistory(ctx, s.SessionID)
+		return sessionHistoryMsg{session: s, ops: ops, err: err}
+	}
+}
+
+func newSessionView(msg sessionHistoryMsg) *sessionView {
+	v := &sessionView{session: msg.session}
+	switch {
+	case msg.err != nil:
+		v.notice = fmt.Sprintf("history failed: %v", msg.err)
+	case len(msg.ops) == 0:
+		v.notice = "no file operations recorded"
+	}
+	for _, op := range msg.ops {
+		v.lines = append(v.lines, formatFileOperation(op))
+	}
+	return v
+}
+
+// formatFileOperation renders one operation as "15:04:05 op tool path (dur)".
+func formatFileOperation(op dash.FileOperation) string {
+	opStyle := textCyan
+	switch {
+	case !op.Success:
+		opStyle = textAlert
+	case op.Operation == string(dash.EventRelationModified):
+		opStyle = textWarning
+	}
+	dur := ""
+	if op.DurationMs != nil {
+		dur = textDim.Render(fmt.Sprintf(" %dms", *op.DurationMs))
+	}
+	return fmt.Sprintf("%s %s %s %s%s",
+		textDim.Render(op.OccurredAt.Local().Format("15:04:05")),
+		opStyle.Render(fmt.Sprintf("%-11s", op.Operation)),
+		textMagenta.Render(fmt.Sprintf("%-6s", op.ToolName)),
+		op.FilePath, dur)
+}
+
+// handleKey scrolls the history. Returns false when the view should close.
+func (v *sessionView) handleKey(msg tea.KeyMsg, height int) bool {
+	page := max(height-4, 1)
+	switch msg.String() {
+	case "esc", "q", "enter":
+		return false
+	case "j", "down":
+		v.offset++
+	case "k", "up":
+		v.offset--
+	case "pgdown", " ":
+		v.offset += page
+	case "pgup":
+		v.offset -= page
+	case "g":
+		v.offset = 0
+	case "G":
+		v.offset = len(v.lines)
+	}
+	v.clamp(height)
+	return true
+}
+
+func (v *sessionView) clamp(height int) {
+	v.offset = min(v.offset, len(v.lines)-(height-4))
+	v.offset = max(v.offset, 0)
+}
+
+// View renders the session header followed by its operations, oldest first.
+func (v *sessionView) View(width, height int) string {
+	s := v.session
+	var b strings.Builder
+	b.WriteString(sectionHeader.Render("SESSION " + s.SessionID))
+	b.WriteString(textDim.Render(fmt.Sprintf("  %s %s %dR %dW", s.Status, formatDuration(s.StartedAt, s.EndedAt), s.FilesRead, s.FilesWrote)))
+	if s.Headline != "" {
+		b.WriteString(textDim.Render("  " + s.Headline))
+	}
+	b.WriteString("\n")
+	b.WriteString(sectionDivider.Render(strings.Repeat("─", min(width, 60))))
+	b.WriteString("\n")
+
+	if v.notice != "" {
+		b.WriteString(textWarning.Render("  "+v.notice) + "\n")
+		return b.String()
+	}
+
+	bodyH := max(height-4, 1)
+	v.clamp(height)
+	end := min(v.offset+bodyH, len(v.lines))
+	for _, line := range v.lines[v.offset:end] {
+		b.WriteString("  " + truncate(line, width-4) + "\n")
+	}
+	b.WriteString(textDim.Render(fmt.Sprintf("ops %d-%d of %d  [j/k] scroll  [pgup/pgdn] page  [esc] close", v.offset+1, end, len(v.lines))))
+	return b.String()
+}
+
+// renderSessionTimeline draws one row per session with a bar positioned by
+// start time and sized by duration on a shared time axis. Ongoing sessions
+// extend to now. The row at selected (or none, when -1) gets the cursor.
+func renderSessionTimeline(sessions []dash.ActivitySummary, w, selected int, now time.Time) []string {
+	if len(sessions) == 0 {
+		return nil
+	}
+
+	end := func(s dash.ActivitySummary) time.Time {
+		if s.EndedAt != nil {
+			return *s.EndedAt
+		}
+		return now
+	}
+	from, to := sessions[0].StartedAt, end(sessions[0])
+	for _, s := range sessions[1:] {
+		if s.StartedAt.Before(from) {
+			from = s.StartedAt
+		}
+		if e := end(s); e.After(to) {
+			to = e
+		}
+	}
+	span := to.Sub(from)
+	if span <= 0 {
+		span = time.Second
+	}
+
+	// "> " + 8-char id + " " + bar + " " + duration
+	barW := max(w-2-8-1-1-4, 10)
+	col := func(t time.Time) int {
+		return min(int(float64(t.Sub(from))/float64(span)*float64(barW)), barW-1)
+	}
+
+	var lines []string
+	for i, s := range sessions {
+		lo := col(s.StartedAt)
+		hi := max(col(end(s)), lo)
+		bar := strings.Repeat(" ", lo) +
+			sessionBarStyle(s).Render(strings.Repeat("█", hi-lo+1)) +
+			strings.Repeat(" ", barW-hi-1)
+
+		sid := s.SessionID
+		if len(sid) > 8 {
+			sid = sid[:8]
+		}
+		sid = fmt.Sprintf("%-8s", sid)
+		dur := textDim.Render(formatDuration(s.StartedAt, s.EndedAt))
+		if i == selected {
+			lines = append(lines, cursorActive.Render("> ")+textPrimary.Render(sid)+" "+bar+" "+dur)
+		} else {
+			lines = append(lines, "  "+textDim.Render(sid)+" "+bar+" "+dur)
+		}
+	}
+
+	// Axis: start clock on the left, end clock on the right.
+	left, right := from.Local().Format("15:04"), to.Local().Format("15:04")
+	if to.Equal(now) {
+		right = "now"
+	}
+	gap := max(barW-len(left)-len(right), 1)
+	lines = append(lines, strings.Repeat(" ", 11)+textDim.Render(left+strings.Repeat("─", gap)+right))
+	return lines
+}
+
+// sessionBarStyle colors a timeline bar by session status: ongoing
+// sessions green, ended ones dim, anything else (e.g. abandoned) yellow.
+func sessionBarStyle(s dash.ActivitySummary) lipgloss.Style {
+	switch {
+	case s.Status == "ended":
+		return textDim
+	case s.Status == "active" && s.EndedAt == nil:
+		return textSuccess
+	}
+	return textWarning
+}
+
+// sessionLabel is the selectable item label for a session.
+func sessionLabel(s dash.ActivitySummary) string {
+	label := s.SessionID
+	if s.ProjectPath != "" {
+		label += " " + filepath.Base(s.ProjectPath)
+	}
+	if s.Headline != "" {
+		label += " " + s.Headline
+	}
+	return label
+}
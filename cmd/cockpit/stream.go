@@ -45,6 +45,7 @@ type chatErrorMsg struct {
 type chatToolCallMsg struct {
 	owner string
 	calls []streamToolCall
+	usage *apiUsage // set when the provider reported usage before the tool call
 }
 
 type apiUsage struct {
@@ -184,7 +185,7 @@ func (c *chatClient) StreamWithTools(ctx context.Context, messages []dash.ChatMe
 				})
 				calls[i].ArgsBuf.WriteString(tc.Arguments)
 			}
-			ch <- chatToolCallMsg{calls: calls}
+			ch <- chatToolCallMsg{calls: calls, usage: lastUsage}
 		case dash.EventUsage:
 			if ev.Usage != nil {
 				lastUsage = &apiUsage{
@@ -153,4 +153,12 @@ var (
 				BorderForeground(cCyan).
 				Foreground(cText).
 				Padding(0, 1)
+
+	// Daily LLM budget banner
+	budgetBanner = lipgloss.NewStyle().
+			Foreground(cText).Background(cAlert).Bold(true).Padding(0, 1)
+
+	// In-chat search matches
+	searchMatch   = lipgloss.NewStyle().Foreground(cDark).Background(cWarning)
+	searchCurrent = lipgloss.NewStyle().Foreground(cDark).Background(cCyan).Bold(true)
 )
@@ -12,6 +12,7 @@ type tokenMeter struct {
 	completion int // completion tokens from latest API call
 	limit      int
 	exchanges  int // number of user→assistant exchanges
+	spent      int // cumulative tokens across all calls; survives rotation
 }
 
 func newTokenMeter(limit int) tokenMeter {
@@ -24,6 +25,13 @@ func (tm *tokenMeter) set(prompt, completion int) {
 	tm.prompt = prompt
 	tm.completion = completion
 	tm.totalUsed = prompt + completion
+	tm.spent += prompt + completion
+}
+
+// rotate resets per-context counters after a context rotation or handoff,
+// keeping the cumulative spend that agent budgets are checked against.
+func (tm *tokenMeter) rotate() {
+	*tm = tokenMeter{limit: tm.limit, spent: tm.spent}
 }
 
 func (tm *tokenMeter) addExchange() {
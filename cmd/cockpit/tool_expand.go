@@ -0,0 +1,173 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"sync"
+
+	"dash"
+)
+
+// maxToolMessageBytes is how much of a tool result goes into the
+// conversation sent back to the model.
+const maxToolMessageBytes = 4000
+
+// maxFullToolOutputBytes caps a result kept for the expanded view.
+const maxFullToolOutputBytes = 256 * 1024
+
+// toolOutputStore keeps the untruncated text of tool results that were cut
+// to maxToolMessageBytes, by tool call ID, so an expanded box can show all of
+// it. executeTools fills it from the goroutine running the tools.
+type toolOutputStore struct {
+	mu      sync.Mutex
+	outputs map[string]string
+}
+
+func (s *toolOutputStore) put(id, output string) {
+	if len(output) > maxFullToolOutputBytes {
+		output = output[:maxFullToolOutputBytes] + "\n... (truncated)"
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.outputs == nil {
+		s.outputs = make(map[string]string)
+	}
+	s.outputs[id] = output
+}
+
+func (s *toolOutputStore) get(id string) (string, bool) {
+	if s == nil {
+		return "", false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out, ok := s.outputs[id]
+	return out, ok
+}
+
+// truncateToolMessage cuts a tool result to maxToolMessageBytes, keeping the
+// full text in outputs when anything was cut.
+func truncateToolMessage(outputs *toolOutputStore, id, text string) string {
+	if len(text) <= maxToolMessageBytes {
+		return text
+	}
+	outputs.put(id, text)
+	return text[:maxToolMessageBytes] + "\n... (truncated)"
+}
+
+// completedToolCalls returns the IDs of tool calls that have a result, oldest first.
+func (m *chatModel) completedToolCalls() []string {
+	done := make(map[string]bool)
+	for _, msg := range m.messages {
+		if msg.Role == "tool" && msg.ToolCallID != "" {
+			done[msg.ToolCallID] = true
+		}
+	}
+	var ids []string
+	for _, msg := range m.messages {
+		for _, tc := range msg.ToolCalls {
+			if done[tc.ID] {
+				ids = append(ids, tc.ID)
+			}
+		}
+	}
+	return ids
+}
+
+// selectTool moves the tool result selection by delta. Moving up with
+// nothing selected picks the newest result; moving down past the newest
+// clears the selection and returns to following the conversation.
+func (m *chatModel) selectTool(delta int) {
+	ids := m.completedToolCalls()
+	if len(ids) == 0 {
+		m.selectedTool = ""
+		return
+	}
+	cur := -1
+	for i, id := range ids {
+		if id == m.selectedTool {
+			cur = i
+			break
+		}
+	}
+	next := cur + delta
+	if cur == -1 {
+		if delta > 0 {
+			return
+		}
+		next = len(ids) - 1
+	}
+	if next < 0 {
+		next = 0
+	}
+	if next >= len(ids) {
+		m.selectedTool = ""
+		m.viewport.GotoBottom()
+		return
+	}
+	m.selectedTool = ids[next]
+	m.scrollToSelected = true
+}
+
+// toggleToolExpand flips full output for the selected tool result,
+// selecting the newest one first if nothing is selected. A read result
+// with a file preview steps collapsed → preview → full → collapsed.
+func (m *chatModel) toggleToolExpand() {
+	if m.selectedTool == "" {
+		m.selectTool(-1)
+		if m.selectedTool == "" {
+			return
+		}
+	}
+	if m.expandedTools == nil {
+		m.expandedTools = make(map[string]bool)
+	}
+	if m.fullTools == nil {
+		m.fullTools = make(map[string]bool)
+	}
+	id := m.selectedTool
+	switch {
+	case !m.expandedTools[id]:
+		m.expandedTools[id] = true
+	case m.previews.get(id) != nil && !m.fullTools[id]:
+		m.fullTools[id] = true
+	default:
+		delete(m.expandedTools, id)
+		delete(m.fullTools, id)
+	}
+	m.scrollToSelected = true
+}
+
+// renderToolBoxFull renders a tool call with its untruncated result.
+// JSON results are pretty-printed; long lines wrap to the box width.
+func renderToolBoxFull(tc dash.ToolCallRef, result string, boxWidth int) string {
+	innerWidth := boxWidth - 4
+	if innerWidth < 20 {
+		innerWidth = 20
+	}
+
+	var lines []string
+	icon := toolIcon[tc.Function.Name]
+	if icon == "" {
+		icon = "•"
+	}
+	lines = append(lines, toolBoxHeader.Render(icon+" "+tc.Function.Name+" (full)"))
+
+	argSummary := formatToolArgs(tc.Function.Name, tc.Function.Arguments)
+	if argSummary != "" {
+		lines = append(lines, toolBoxArg.Render(argSummary))
+	}
+
+	body := result
+	var pretty bytes.Buffer
+	if json.Indent(&pretty, []byte(result), "", "  ") == nil {
+		body = pretty.String()
+	}
+	lines = append(lines, "")
+	for _, line := range strings.Split(body, "\n") {
+		lines = append(lines, wrapText(line, innerWidth-2))
+	}
+
+	return toolBox.Width(innerWidth).Render(strings.Join(lines, "\n"))
+}
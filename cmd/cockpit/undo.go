@@ -0,0 +1,185 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"dash"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/google/uuid"
+)
+
+// maxUndoDepth bounds the undo stack so snapshots don't grow memory unbounded.
+const maxUndoDepth = 5
+
+// undoEntry is one reversible destructive action: either a chat clear
+// (snapshot of the conversation) or a forget (soft-deleted node IDs).
+type undoEntry struct {
+	kind string // "clear" | "forget"
+
+	messages   []dash.ChatMessage
+	uiMessages []uiMessage
+	renderLog  []renderEntry
+
+	nodeIDs []uuid.UUID
+}
+
+// pushUndo adds an entry, dropping the oldest when the stack is full.
+func (m *chatModel) pushUndo(e undoEntry) {
+	m.undoStack = append(m.undoStack, e)
+	if len(m.undoStack) > maxUndoDepth {
+		m.undoStack = m.undoStack[len(m.undoStack)-maxUndoDepth:]
+	}
+}
+
+// snapshotForClear records the current conversation before it is wiped.
+// Empty conversations are not recorded.
+func (m *chatModel) snapshotForClear() {
+	if len(m.renderLog) == 0 {
+		return
+	}
+	m.pushUndo(undoEntry{
+		kind:       "clear",
+		messages:   m.messages,
+		uiMessages: m.uiMessages,
+		renderLog:  m.renderLog,
+	})
+}
+
+// forgottenStore keeps the node IDs each forget call deleted, by tool call
+// ID. executeTools fills it from the structured result because the tool
+// message text is truncated and may no longer parse.
+type forgottenStore struct {
+	mu  sync.Mutex
+	ids map[string][]uuid.UUID
+}
+
+func (s *forgottenStore) put(id string, ids []uuid.UUID) {
+	if len(ids) == 0 {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.ids == nil {
+		s.ids = make(map[string][]uuid.UUID)
+	}
+	s.ids[id] = ids
+}
+
+// take returns and forgets the IDs recorded for a tool call.
+func (s *forgottenStore) take(id string) []uuid.UUID {
+	if s == nil {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ids := s.ids[id]
+	delete(s.ids, id)
+	return ids
+}
+
+// forgetDeletedIDs reads deleted_ids from a forget tool's result data.
+func forgetDeletedIDs(data any) []uuid.UUID {
+	res, ok := data.(map[string]any)
+	if !ok {
+		return nil
+	}
+	var raw []string
+	switch v := res["deleted_ids"].(type) {
+	case []string:
+		raw = v
+	case []any:
+		for _, x := range v {
+			if s, ok := x.(string); ok {
+				raw = append(raw, s)
+			}
+		}
+	}
+	var ids []uuid.UUID
+	for _, s := range raw {
+		if id, err := uuid.Parse(s); err == nil {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// trackForgetResults pushes an undo entry for every successful forget call in
+// results and announces the deleted IDs in the chat.
+func (m *chatModel) trackForgetResults(results []dash.ChatMessage) {
+	for _, r := range results {
+		if r.Name != "forget" || r.ToolError {
+			continue
+		}
+		ids := m.forgotten.take(r.ToolCallID)
+		if len(ids) == 0 {
+			continue
+		}
+		m.pushUndo(undoEntry{kind: "forget", nodeIDs: ids})
+		m.appendUI("system-marker", fmt.Sprintf("forgot %d node(s): %s  [ctrl+z] undo", len(ids), shortIDs(ids)))
+	}
+}
+
+// undoForgetMsg reports how many nodes a forget undo restored.
+type undoForgetMsg struct {
+	owner    string // scopedAgent of the chat
+	restored int
+	total    int
+}
+
+// undo reverts the most recent destructive action. A forget is restored in
+// the background; the result arrives as an undoForgetMsg.
+func (m *chatModel) undo() tea.Cmd {
+	if len(m.undoStack) == 0 {
+		m.errMsg = "nothing to undo"
+		return nil
+	}
+	e := m.undoStack[len(m.undoStack)-1]
+	m.undoStack = m.undoStack[:len(m.undoStack)-1]
+
+	switch e.kind {
+	case "clear":
+		m.messages = e.messages
+		m.uiMessages = e.uiMessages
+		m.renderLog = e.renderLog
+		m.errMsg = ""
+		m.scrollToBottom()
+	case "forget":
+		if m.d == nil {
+			m.errMsg = "undo forget: dash client not available"
+			return nil
+		}
+		d, owner, ids := m.d, m.scopedAgent, e.nodeIDs
+		return func() tea.Msg {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			restored := 0
+			for _, id := range ids {
+				if err := d.RestoreNode(ctx, id); err == nil {
+					restored++
+				}
+			}
+			return undoForgetMsg{owner: owner, restored: restored, total: len(ids)}
+		}
+	}
+	return nil
+}
+
+// handleUndoForget announces the outcome of a forget undo.
+func (m *chatModel) handleUndoForget(msg undoForgetMsg) {
+	m.appendUI("system-marker", fmt.Sprintf("restored %d of %d forgotten node(s)", msg.restored, msg.total))
+	m.scrollToBottom()
+}
+
+// shortIDs renders UUIDs as their first 8 characters, comma separated.
+func shortIDs(ids []uuid.UUID) string {
+	parts := make([]string, len(ids))
+	for i, id := range ids {
+		parts[i] = id.String()[:8]
+	}
+	return strings.Join(parts, ", ")
+}
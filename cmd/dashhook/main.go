@@ -7,6 +7,8 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
+	"strconv"
 
 	"dash"
 )
@@ -35,11 +37,16 @@ func main() {
 	// Create router
 	router := dash.NewLLMRouter(dash.DefaultRouterConfig())
 
+	// Unset or invalid means the library default
+	maxInput, _ := strconv.Atoi(os.Getenv("DASH_HOOK_MAX_INPUT_BYTES"))
+
 	// Create Dash client
 	d, err := dash.New(dash.Config{
-		DB:              db,
-		FileAllowedRoot: dash.EnvOr("DASH_FILE_ROOT", "/"),
-		Router:          router,
+		DB:                db,
+		FileAllowedRoot:   dash.EnvOr("DASH_FILE_ROOT", "/"),
+		Router:            router,
+		HookAllowedRoots:  filepath.SplitList(os.Getenv("DASH_HOOK_ALLOWED_ROOTS")),
+		HookMaxInputBytes: maxInput,
 	})
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "dashhook: failed to create dash client: %v\n", err)
@@ -5,13 +5,19 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
+	"os/signal"
+	"regexp"
+	"sort"
 	"strings"
+	"syscall"
 	"time"
 
 	"dash"
 
+	"github.com/google/uuid"
 	_ "github.com/lib/pq"
 )
 
@@ -28,46 +34,129 @@ func main() {
 	}
 	defer db.Close()
 
+	cmd := os.Args[1]
+	args, page, err := parsePaging(os.Args[2:])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "dashquery %s: %v\n", cmd, err)
+		os.Exit(1)
+	}
+
+	// watch runs until interrupted, so it gets its own context.
+	if cmd == "watch" {
+		if err := watchObservations(db, args); err != nil {
+			fmt.Fprintf(os.Stderr, "dashquery: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
-	cmd := os.Args[1]
-	args := os.Args[2:]
-
 	var result any
 	switch cmd {
 	case "sessions":
-		result, err = querySessions(ctx, db, args)
+		result, err = querySessions(ctx, db, args, page)
 	case "files":
-		result, err = queryFiles(ctx, db, args)
+		result, err = queryFiles(ctx, db, args, page)
 	case "tools":
 		result, err = queryTools(ctx, db, args)
 	case "failures":
-		result, err = queryFailures(ctx, db, args)
+		result, err = queryFailures(ctx, db, args, page)
+	case "failures-by-tool":
+		result, err = failuresByTool(ctx, db, args)
+	case "risky":
+		result, err = queryRisky(ctx, db, args, page)
+	case "timings":
+		result, err = queryTimings(ctx, db, args)
+	case "hotspots":
+		result, err = queryHotspots(ctx, db, args)
+	case "stats":
+		result, err = graphStats(ctx, db)
 	case "search":
 		if len(args) < 1 {
 			fmt.Fprintln(os.Stderr, "dashquery search: missing search term")
 			os.Exit(1)
 		}
-		result, err = searchNodes(ctx, db, args[0])
+		result, err = searchNodes(ctx, db, args[0], page)
+	case "ft":
+		if len(args) < 1 {
+			fmt.Fprintln(os.Stderr, "dashquery ft: missing query")
+			os.Exit(1)
+		}
+		result, err = fullTextSearch(ctx, db, args, page)
+	case "related":
+		if len(args) < 1 {
+			fmt.Fprintln(os.Stderr, "dashquery related: missing node ID")
+			os.Exit(1)
+		}
+		result, err = relatedNodes(ctx, db, args, page)
+	case "promote":
+		if len(args) < 1 {
+			fmt.Fprintln(os.Stderr, "dashquery promote: missing session ID")
+			os.Exit(1)
+		}
+		result, err = promoteSession(ctx, db, args[0])
+	case "pack-explain":
+		if len(args) == 0 {
+			fmt.Fprintln(os.Stderr, "dashquery pack-explain: missing query")
+			os.Exit(1)
+		}
+		result, err = packExplain(ctx, db, args)
+	case "pipeline-check":
+		if len(args) < 1 {
+			fmt.Fprintln(os.Stderr, "dashquery pipeline-check: missing profile name")
+			os.Exit(1)
+		}
+		result, err = pipelineCheck(ctx, db, args[0])
 	case "sql":
 		if len(args) < 1 {
 			fmt.Fprintln(os.Stderr, "dashquery sql: missing query")
 			os.Exit(1)
 		}
-		result, err = executeSQL(ctx, db, strings.Join(args, " "))
+		result, err = executeSQL(ctx, db, strings.Join(args, " "), page)
 	case "node":
 		if len(args) < 1 {
 			fmt.Fprintln(os.Stderr, "dashquery node: missing node ID or name")
 			os.Exit(1)
 		}
 		result, err = getNode(ctx, db, args[0])
+	case "diff-nodes":
+		if len(args) < 2 {
+			fmt.Fprintln(os.Stderr, "dashquery diff-nodes: usage: diff-nodes <id1> <id2>")
+			os.Exit(1)
+		}
+		result, err = diffNodes(ctx, db, args[0], args[1])
+	case "intent":
+		if len(args) < 1 {
+			fmt.Fprintln(os.Stderr, "dashquery intent: missing intent name")
+			os.Exit(1)
+		}
+		result, err = intentSubtree(ctx, db, args[0])
+	case "tag":
+		if len(args) < 2 {
+			fmt.Fprintln(os.Stderr, "dashquery tag: usage: tag <id|name> <tag>...")
+			os.Exit(1)
+		}
+		result, err = tagNode(ctx, db, args[0], args[1:])
+	case "bytag":
+		if len(args) < 1 {
+			fmt.Fprintln(os.Stderr, "dashquery bytag: missing tag")
+			os.Exit(1)
+		}
+		result, err = nodesByTag(ctx, db, args, page)
+	case "creator":
+		if len(args) < 1 {
+			fmt.Fprintln(os.Stderr, "dashquery creator: missing creator")
+			os.Exit(1)
+		}
+		result, err = nodesByCreator(ctx, db, args[0], page)
 	case "history":
 		if len(args) < 1 {
 			fmt.Fprintln(os.Stderr, "dashquery history: missing file path")
 			os.Exit(1)
 		}
-		result, err = fileHistory(ctx, db, args[0])
+		result, err = fileHistory(ctx, db, args[0], page)
 	case "check":
 		if len(args) < 2 {
 			fmt.Fprintln(os.Stderr, "dashquery check: usage: check <tool> <pattern>")
@@ -117,41 +206,165 @@ func main() {
 func printUsage() {
 	fmt.Println(`dashquery - Query the Dash graph database
 
-Usage: dashquery <command> [args]
+Usage: dashquery <command> [args] [--limit N] [--offset N]
 
 Commands:
   sessions [limit]       List recent Claude Code sessions
   files [hours]          List recently touched files (default: 24h)
   tools [hours]          Tool usage statistics (default: 24h)
   failures [limit]       Recent tool failures
+  failures-by-tool [hours]
+                         Failures grouped by tool, similar errors clustered
+                         (default: 168h)
+  risky [limit]          Recent high-risk shell commands
+  timings [hours]        Tool latency percentiles (default: 24h)
+  hotspots [hours]       Most frequently modified files (default: 168h)
+  stats                  Graph size: nodes, edges, observations, embeddings
   search <term>          Search nodes by name
+  ft <query> [limit]     Full-text search in node names and data, with snippets
   node <id|name>         Get node details by ID or name
+  diff-nodes <id1> <id2> Field-by-field diff of two nodes' metadata and data
+  related <id> [limit]   Semantically similar nodes (nearest embeddings)
+  intent <id|name>       An intent with its linked plans, tasks, suggestions,
+                         work orders and overall progress
+  tag <id|name> <tag>... Add tags to a node (lowercased, deduplicated)
+  bytag <tag> [layer] [type]
+                         List nodes carrying a tag
+  creator <name>         List nodes created by an agent or caller
+                         (e.g. mcp, cli, auto-promotion)
   history <filepath>     Get history for a file
   check <tool> <pattern> Check if similar operation failed before
   warn <tool> <pattern>  Like check, but exits 1 if failures found
+  promote <session>      Promote a session's suggested insights
+  pipeline-check <name>  Validate a profile's pipeline and dry-render it
+  pack-explain <query> [task|plan|default]
+                         Context pack with every scored candidate, kept or dropped
   sql <query>            Execute raw SQL (SELECT only)
+  watch [type]           Stream new observations as NDJSON until Ctrl+C
   help                   Show this help
 
+Paging:
+  --limit N, --offset N  Page through sessions, files, failures, risky, search,
+                         ft, related, bytag, creator, history and sql results.
+                         --limit overrides a positional limit. Results report
+                         has_more and the --offset of the next page. sql is
+                         capped at 100 rows unless the query has its own LIMIT.
+
 Examples:
   dashquery sessions 5
   dashquery files 2
   dashquery tools
   dashquery failures 10
+  dashquery failures-by-tool 48
+  dashquery risky 20
+  dashquery timings 48
+  dashquery hotspots 72
+  dashquery stats
   dashquery search "CLAUDE.md"
+  dashquery ft "token budget" 10
   dashquery node "d18a7ca7-80e6-410a-bad3-31bd6942bc36"
+  dashquery diff-nodes "d18a7ca7-80e6-410a-bad3-31bd6942bc36" "5b0e2f4c-9a1d-4c7e-8f3a-6d2b1e9c0a47"
+  dashquery related "d18a7ca7-80e6-410a-bad3-31bd6942bc36" 5
+  dashquery intent "automation"
+  dashquery tag "d18a7ca7-80e6-410a-bad3-31bd6942bc36" wip
+  dashquery bytag reviewed CONTEXT task
+  dashquery creator auto-promotion
   dashquery history "/dash/CLAUDE.md"
-  dashquery sql "SELECT COUNT(*) FROM nodes"`)
+  dashquery promote "8f3c2a1e-5b7d-4e9a-a6c0-2d1f4b8e9c7a"
+  dashquery pipeline-check agent-continuous
+  dashquery pack-explain "token budget enforcement" task
+  dashquery sql "SELECT COUNT(*) FROM nodes"
+  dashquery sql "SELECT name FROM nodes ORDER BY name" --limit 50 --offset 50
+  dashquery watch work_order_event`)
+}
+
+const (
+	watchPollInterval = 2 * time.Second
+	watchBatchSize    = 200 // max rows printed per poll; the rest follow next poll
+)
+
+// watchObservations polls for observations newer than the last one printed
+// and writes each as a JSON line. The cursor is (observed_at, id), so rows
+// sharing a timestamp are neither skipped nor repeated. Only rows arriving
+// after the command starts are shown.
+func watchObservations(db *sql.DB, args []string) error {
+	obsType := ""
+	if len(args) > 0 {
+		obsType = args[0]
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	var lastAt time.Time
+	if err := db.QueryRowContext(ctx, `SELECT NOW()`).Scan(&lastAt); err != nil {
+		return err
+	}
+	lastID := "00000000-0000-0000-0000-000000000000"
+
+	enc := json.NewEncoder(os.Stdout)
+	ticker := time.NewTicker(watchPollInterval)
+	defer ticker.Stop()
+
+	for {
+		rows, err := db.QueryContext(ctx, `
+			SELECT o.id, o.type, o.observed_at, o.data, o.node_id, COALESCE(n.name, '')
+			FROM observations o
+			LEFT JOIN nodes n ON n.id = o.node_id
+			WHERE (o.observed_at, o.id) > ($1, $2::uuid)
+			  AND ($3 = '' OR o.type = $3)
+			ORDER BY o.observed_at, o.id
+			LIMIT $4
+		`, lastAt, lastID, obsType, watchBatchSize)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+
+		for rows.Next() {
+			var id, typ, nodeID, nodeName string
+			var observedAt time.Time
+			var data []byte
+			if err := rows.Scan(&id, &typ, &observedAt, &data, &nodeID, &nodeName); err != nil {
+				rows.Close()
+				return err
+			}
+			lastAt, lastID = observedAt, id
+			enc.Encode(map[string]any{
+				"id":          id,
+				"type":        typ,
+				"observed_at": observedAt.Format(time.RFC3339Nano),
+				"node_id":     nodeID,
+				"node":        nodeName,
+				"data":        json.RawMessage(data),
+			})
+		}
+		err = rows.Err()
+		rows.Close()
+		if err != nil && ctx.Err() == nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
 }
 
 func connectDB() (*sql.DB, error) {
 	return dash.ConnectDB()
 }
 
-func querySessions(ctx context.Context, db *sql.DB, args []string) (any, error) {
+func querySessions(ctx context.Context, db *sql.DB, args []string, page paging) (any, error) {
 	limit := 10
 	if len(args) > 0 {
 		fmt.Sscanf(args[0], "%d", &limit)
 	}
+	limit = page.limitOr(limit)
 
 	rows, err := db.QueryContext(ctx, `
 		SELECT
@@ -164,8 +377,8 @@ func querySessions(ctx context.Context, db *sql.DB, args []string) (any, error)
 		FROM nodes
 		WHERE layer = 'CONTEXT' AND type = 'session' AND deleted_at IS NULL
 		ORDER BY created_at DESC
-		LIMIT $1
-	`, limit)
+		LIMIT $1 OFFSET $2
+	`, limit+1, page.offset)
 	if err != nil {
 		return nil, err
 	}
@@ -192,17 +405,19 @@ func querySessions(ctx context.Context, db *sql.DB, args []string) (any, error)
 		})
 	}
 
-	return map[string]any{
+	sessions, more := trimPage(sessions, limit)
+	return addPage(map[string]any{
 		"count":    len(sessions),
 		"sessions": sessions,
-	}, nil
+	}, limit, page.offset, more), nil
 }
 
-func queryFiles(ctx context.Context, db *sql.DB, args []string) (any, error) {
+func queryFiles(ctx context.Context, db *sql.DB, args []string, page paging) (any, error) {
 	hours := 24
 	if len(args) > 0 {
 		fmt.Sscanf(args[0], "%d", &hours)
 	}
+	limit := page.limitOr(50)
 
 	rows, err := db.QueryContext(ctx, `
 		SELECT
@@ -217,8 +432,8 @@ func queryFiles(ctx context.Context, db *sql.DB, args []string) (any, error) {
 		  AND s.type = 'session'
 		  AND ee.occurred_at > NOW() - $1::interval
 		ORDER BY ee.occurred_at DESC
-		LIMIT 50
-	`, fmt.Sprintf("%d hours", hours))
+		LIMIT $2 OFFSET $3
+	`, fmt.Sprintf("%d hours", hours), limit+1, page.offset)
 	if err != nil {
 		return nil, err
 	}
@@ -242,11 +457,12 @@ func queryFiles(ctx context.Context, db *sql.DB, args []string) (any, error) {
 		})
 	}
 
-	return map[string]any{
-		"hours":  hours,
-		"count":  len(files),
-		"files":  files,
-	}, nil
+	files, more := trimPage(files, limit)
+	return addPage(map[string]any{
+		"hours": hours,
+		"count": len(files),
+		"files": files,
+	}, limit, page.offset, more), nil
 }
 
 func queryTools(ctx context.Context, db *sql.DB, args []string) (any, error) {
@@ -301,11 +517,220 @@ func queryTools(ctx context.Context, db *sql.DB, args []string) (any, error) {
 	}, nil
 }
 
-func queryFailures(ctx context.Context, db *sql.DB, args []string) (any, error) {
+func queryHotspots(ctx context.Context, db *sql.DB, args []string) (any, error) {
+	hours := 168
+	if len(args) > 0 {
+		fmt.Sscanf(args[0], "%d", &hours)
+	}
+
+	d, err := dash.New(dash.Config{DB: db})
+	if err != nil {
+		return nil, err
+	}
+
+	hotspots, err := d.FileHotspots(ctx, time.Now().Add(-time.Duration(hours)*time.Hour), 20)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]any{
+		"hours": hours,
+		"count": len(hotspots),
+		"files": hotspots,
+	}, nil
+}
+
+func queryTimings(ctx context.Context, db *sql.DB, args []string) (any, error) {
+	hours := 24
+	if len(args) > 0 {
+		fmt.Sscanf(args[0], "%d", &hours)
+	}
+
+	d, err := dash.New(dash.Config{DB: db})
+	if err != nil {
+		return nil, err
+	}
+
+	timings, err := d.ToolTimingStats(ctx, hours)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]any{
+		"hours": hours,
+		"count": len(timings),
+		"tools": timings,
+	}, nil
+}
+
+// statsGroupLimit caps the rows of each breakdown in graphStats.
+const statsGroupLimit = 200
+
+// graphStats summarizes the size and shape of the graph. All queries run in
+// one read-only transaction so the counts agree with each other.
+func graphStats(ctx context.Context, db *sql.DB) (any, error) {
+	tx, err := db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	nodes, nodeTotal, err := countGroups(ctx, tx, []string{"layer", "type"}, `
+		SELECT layer::text, type, COUNT(*), SUM(COUNT(*)) OVER ()
+		FROM nodes
+		WHERE deleted_at IS NULL
+		GROUP BY layer, type
+		ORDER BY COUNT(*) DESC
+		LIMIT $1`)
+	if err != nil {
+		return nil, fmt.Errorf("nodes: %w", err)
+	}
+	edges, edgeTotal, err := countGroups(ctx, tx, []string{"relation"}, `
+		SELECT relation::text, COUNT(*), SUM(COUNT(*)) OVER ()
+		FROM edges
+		WHERE deprecated_at IS NULL
+		GROUP BY relation
+		ORDER BY COUNT(*) DESC
+		LIMIT $1`)
+	if err != nil {
+		return nil, fmt.Errorf("edges: %w", err)
+	}
+	observations, obsTotal, err := countGroups(ctx, tx, []string{"type"}, `
+		SELECT type, COUNT(*), SUM(COUNT(*)) OVER ()
+		FROM observations
+		GROUP BY type
+		ORDER BY COUNT(*) DESC
+		LIMIT $1`)
+	if err != nil {
+		return nil, fmt.Errorf("observations: %w", err)
+	}
+
+	var withEmbedding, withoutEmbedding int
+	var oldest, newest sql.NullTime
+	err = tx.QueryRowContext(ctx, `
+		SELECT
+			COUNT(*) FILTER (WHERE embedding IS NOT NULL),
+			COUNT(*) FILTER (WHERE embedding IS NULL),
+			MIN(created_at),
+			MAX(created_at)
+		FROM nodes
+		WHERE deleted_at IS NULL
+	`).Scan(&withEmbedding, &withoutEmbedding, &oldest, &newest)
+	if err != nil {
+		return nil, fmt.Errorf("embeddings: %w", err)
+	}
+
+	result := map[string]any{
+		"nodes":        map[string]any{"total": nodeTotal, "by_layer_type": nodes},
+		"edges":        map[string]any{"total": edgeTotal, "by_relation": edges},
+		"observations": map[string]any{"total": obsTotal, "by_type": observations},
+		"embeddings":   map[string]any{"present": withEmbedding, "missing": withoutEmbedding},
+	}
+	if oldest.Valid {
+		result["oldest_node"] = oldest.Time.Format(time.RFC3339)
+		result["newest_node"] = newest.Time.Format(time.RFC3339)
+	}
+	return result, nil
+}
+
+// countGroups runs a GROUP BY query whose columns are keys followed by the
+// group count and the total over all groups, limited to statsGroupLimit rows.
+// It returns one map per group and the total.
+func countGroups(ctx context.Context, tx *sql.Tx, keys []string, query string) ([]map[string]any, int, error) {
+	rows, err := tx.QueryContext(ctx, query, statsGroupLimit)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	groups := []map[string]any{}
+	total := 0
+	for rows.Next() {
+		vals := make([]string, len(keys))
+		var count int
+		dest := make([]any, 0, len(keys)+2)
+		for i := range vals {
+			dest = append(dest, &vals[i])
+		}
+		if err := rows.Scan(append(dest, &count, &total)...); err != nil {
+			return nil, 0, err
+		}
+		group := map[string]any{"count": count}
+		for i, k := range keys {
+			group[k] = vals[i]
+		}
+		groups = append(groups, group)
+	}
+	return groups, total, rows.Err()
+}
+
+func promoteSession(ctx context.Context, db *sql.DB, sessionID string) (any, error) {
+	d, err := dash.New(dash.Config{DB: db})
+	if err != nil {
+		return nil, err
+	}
+	return d.PromoteSessionInsights(ctx, sessionID)
+}
+
+func relatedNodes(ctx context.Context, db *sql.DB, args []string, page paging) (any, error) {
+	id, err := uuid.Parse(args[0])
+	if err != nil {
+		return nil, fmt.Errorf("invalid node ID: %w", err)
+	}
+	limit := 10
+	if len(args) > 1 {
+		fmt.Sscanf(args[1], "%d", &limit)
+	}
+	limit = page.limitOr(limit)
+
+	d, err := dash.New(dash.Config{
+		DB:     db,
+		Router: dash.NewLLMRouter(dash.DefaultRouterConfig()),
+	})
+	if err != nil {
+		return nil, err
+	}
+	results, err := d.RelatedNodes(ctx, id, page.offset+limit+1)
+	if err != nil {
+		return nil, err
+	}
+	results, more := pageSlice(results, page, limit)
+	return addPage(map[string]any{
+		"node_id": id,
+		"count":   len(results),
+		"results": results,
+	}, limit, page.offset, more), nil
+}
+
+func packExplain(ctx context.Context, db *sql.DB, args []string) (any, error) {
+	profile := dash.ProfileDefault
+	if len(args) > 1 {
+		profile = dash.RetrievalProfile(args[1])
+	}
+	d, err := dash.New(dash.Config{
+		DB:     db,
+		Router: dash.NewLLMRouter(dash.DefaultRouterConfig()),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return d.AssembleContextPackExplained(ctx, args[0], profile, nil, dash.PackOptions{})
+}
+
+func pipelineCheck(ctx context.Context, db *sql.DB, name string) (any, error) {
+	d, err := dash.New(dash.Config{DB: db})
+	if err != nil {
+		return nil, err
+	}
+	return d.CheckProfilePipeline(ctx, name)
+}
+
+func queryFailures(ctx context.Context, db *sql.DB, args []string, page paging) (any, error) {
 	limit := 10
 	if len(args) > 0 {
 		fmt.Sscanf(args[0], "%d", &limit)
 	}
+	limit = page.limitOr(limit)
 
 	rows, err := db.QueryContext(ctx, `
 		SELECT
@@ -317,8 +742,8 @@ func queryFailures(ctx context.Context, db *sql.DB, args []string) (any, error)
 		WHERE type = 'tool_event'
 		  AND data->'normalized'->>'event' = 'tool.failure'
 		ORDER BY observed_at DESC
-		LIMIT $1
-	`, limit)
+		LIMIT $1 OFFSET $2
+	`, limit+1, page.offset)
 	if err != nil {
 		return nil, err
 	}
@@ -346,21 +771,204 @@ func queryFailures(ctx context.Context, db *sql.DB, args []string) (any, error)
 		})
 	}
 
-	return map[string]any{
+	failures, more := trimPage(failures, limit)
+	return addPage(map[string]any{
 		"count":    len(failures),
 		"failures": failures,
+	}, limit, page.offset, more), nil
+}
+
+// Error normalization for failures-by-tool: volatile parts of a message are
+// replaced by placeholders so similar errors share one template.
+var (
+	errUUIDRe   = regexp.MustCompile(`(?i)\b[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}\b`)
+	errPathRe   = regexp.MustCompile(`(?:[A-Za-z]:)?(?:\.{0,2}/)?(?:[\w.@-]+/)+[\w.@-]*`)
+	errHexRe    = regexp.MustCompile(`(?i)\b(?:0x)?[0-9a-f]{7,}\b`)
+	errNumberRe = regexp.MustCompile(`\d+`)
+	errSpaceRe  = regexp.MustCompile(`\s+`)
+)
+
+const maxErrorTemplate = 160
+
+// errorTemplate reduces an error message to its first non-empty line with
+// UUIDs, paths, hashes and numbers replaced by placeholders.
+func errorTemplate(msg string) string {
+	line := ""
+	for _, l := range strings.Split(msg, "\n") {
+		if l = strings.TrimSpace(l); l != "" {
+			line = l
+			break
+		}
+	}
+	if line == "" {
+		return "(no error message)"
+	}
+	line = errUUIDRe.ReplaceAllString(line, "<uuid>")
+	line = errPathRe.ReplaceAllString(line, "<path>")
+	line = errHexRe.ReplaceAllString(line, "<hex>")
+	line = errNumberRe.ReplaceAllString(line, "<n>")
+	line = errSpaceRe.ReplaceAllString(line, " ")
+	if len(line) > maxErrorTemplate {
+		line = line[:maxErrorTemplate] + "..."
+	}
+	return line
+}
+
+type failureCluster struct {
+	Template string `json:"template"`
+	Count    int    `json:"count"`
+	Example  string `json:"example"`
+	LastSeen string `json:"last_seen"`
+}
+
+type toolFailures struct {
+	Tool     string            `json:"tool"`
+	Count    int               `json:"count"`
+	Clusters []*failureCluster `json:"clusters"`
+}
+
+func failuresByTool(ctx context.Context, db *sql.DB, args []string) (any, error) {
+	hours := 168
+	if len(args) > 0 {
+		fmt.Sscanf(args[0], "%d", &hours)
+	}
+
+	rows, err := db.QueryContext(ctx, `
+		SELECT
+			COALESCE(data->'claude_code'->>'tool_name', '') as tool,
+			COALESCE(data->'normalized'->'outcome'->>'error', data->'claude_code'->>'error', '') as error,
+			observed_at
+		FROM observations
+		WHERE type = 'tool_event'
+		  AND data->'normalized'->>'event' = 'tool.failure'
+		  AND observed_at > NOW() - make_interval(hours => $1)
+		ORDER BY observed_at DESC
+		LIMIT 5000
+	`, hours)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	// Rows arrive newest first, so each cluster's first row is its example.
+	byTool := make(map[string]*toolFailures)
+	clusters := make(map[[2]string]*failureCluster)
+	total := 0
+	for rows.Next() {
+		var tool, msg string
+		var observedAt time.Time
+		if err := rows.Scan(&tool, &msg, &observedAt); err != nil {
+			return nil, err
+		}
+		total++
+
+		tf := byTool[tool]
+		if tf == nil {
+			tf = &toolFailures{Tool: tool}
+			byTool[tool] = tf
+		}
+		tf.Count++
+
+		tmpl := errorTemplate(msg)
+		c := clusters[[2]string{tool, tmpl}]
+		if c == nil {
+			example := strings.TrimSpace(msg)
+			if len(example) > 300 {
+				example = example[:300] + "..."
+			}
+			c = &failureCluster{Template: tmpl, Example: example, LastSeen: observedAt.Format(time.RFC3339)}
+			clusters[[2]string{tool, tmpl}] = c
+			tf.Clusters = append(tf.Clusters, c)
+		}
+		c.Count++
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	tools := make([]*toolFailures, 0, len(byTool))
+	for _, tf := range byTool {
+		sort.SliceStable(tf.Clusters, func(i, j int) bool { return tf.Clusters[i].Count > tf.Clusters[j].Count })
+		tools = append(tools, tf)
+	}
+	sort.Slice(tools, func(i, j int) bool {
+		if tools[i].Count != tools[j].Count {
+			return tools[i].Count > tools[j].Count
+		}
+		return tools[i].Tool < tools[j].Tool
+	})
+
+	return map[string]any{
+		"hours":    hours,
+		"failures": total,
+		"tools":    tools,
 	}, nil
 }
 
-func searchNodes(ctx context.Context, db *sql.DB, term string) (any, error) {
+func queryRisky(ctx context.Context, db *sql.DB, args []string, page paging) (any, error) {
+	limit := 10
+	if len(args) > 0 {
+		fmt.Sscanf(args[0], "%d", &limit)
+	}
+	limit = page.limitOr(limit)
+
+	rows, err := db.QueryContext(ctx, `
+		SELECT
+			data->'normalized'->'subject'->>'ref' as command,
+			data->'normalized'->'risk_reasons' as reasons,
+			data->'claude_code'->>'session_id' as session,
+			observed_at
+		FROM observations
+		WHERE type = 'tool_event'
+		  AND data->'normalized'->>'event' = 'tool.pre'
+		  AND data->'normalized'->>'risk' = $1
+		ORDER BY observed_at DESC
+		LIMIT $2 OFFSET $3
+	`, dash.RiskHigh, limit+1, page.offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var commands []map[string]any
+	for rows.Next() {
+		var command, session sql.NullString
+		var reasons json.RawMessage
+		var observedAt time.Time
+
+		if err := rows.Scan(&command, &reasons, &session, &observedAt); err != nil {
+			return nil, err
+		}
+
+		var reasonsParsed []string
+		json.Unmarshal(reasons, &reasonsParsed)
+
+		commands = append(commands, map[string]any{
+			"command": command.String,
+			"reasons": reasonsParsed,
+			"session": session.String,
+			"when":    observedAt.Format(time.RFC3339),
+			"age":     time.Since(observedAt).Round(time.Second).String(),
+		})
+	}
+
+	commands, more := trimPage(commands, limit)
+	return addPage(map[string]any{
+		"count":    len(commands),
+		"commands": commands,
+	}, limit, page.offset, more), nil
+}
+
+func searchNodes(ctx context.Context, db *sql.DB, term string, page paging) (any, error) {
+	limit := page.limitOr(20)
 	rows, err := db.QueryContext(ctx, `
 		SELECT id, layer, type, name, created_at
 		FROM nodes
 		WHERE deleted_at IS NULL
 		  AND (name ILIKE $1 OR type ILIKE $1)
 		ORDER BY updated_at DESC
-		LIMIT 20
-	`, "%"+term+"%")
+		LIMIT $2 OFFSET $3
+	`, "%"+term+"%", limit+1, page.offset)
 	if err != nil {
 		return nil, err
 	}
@@ -384,48 +992,304 @@ func searchNodes(ctx context.Context, db *sql.DB, term string) (any, error) {
 		})
 	}
 
-	return map[string]any{
+	nodes, more := trimPage(nodes, limit)
+	return addPage(map[string]any{
 		"term":  term,
 		"count": len(nodes),
 		"nodes": nodes,
-	}, nil
+	}, limit, page.offset, more), nil
 }
 
-func getNode(ctx context.Context, db *sql.DB, idOrName string) (any, error) {
-	// Try by ID first, then by name
-	var id, layer, typ, name string
-	var data json.RawMessage
-	var createdAt, updatedAt time.Time
+func fullTextSearch(ctx context.Context, db *sql.DB, args []string, page paging) (any, error) {
+	query := args[0]
+	limit := 20
+	if len(args) > 1 {
+		fmt.Sscanf(args[1], "%d", &limit)
+	}
+	limit = page.limitOr(limit)
 
-	err := db.QueryRowContext(ctx, `
-		SELECT id, layer, type, name, data, created_at, updated_at
-		FROM nodes
-		WHERE deleted_at IS NULL AND (id::text = $1 OR name = $1)
-		LIMIT 1
-	`, idOrName).Scan(&id, &layer, &typ, &name, &data, &createdAt, &updatedAt)
+	d, err := dash.New(dash.Config{DB: db})
+	if err != nil {
+		return nil, err
+	}
+	results, err := d.FullTextSearch(ctx, query, page.offset+limit+1)
+	if err != nil {
+		return nil, err
+	}
+	results, more := pageSlice(results, page, limit)
+	return addPage(map[string]any{
+		"query":   query,
+		"count":   len(results),
+		"results": results,
+	}, limit, page.offset, more), nil
+}
 
-	if err == sql.ErrNoRows {
+// resolveNode looks a node up by ID first, then by name across all layers
+// and types. A UUID that matches no node ID is still tried as a name, since
+// nodes such as Claude sessions are named by UUID.
+func resolveNode(ctx context.Context, d *dash.Dash, idOrName string) (*dash.Node, error) {
+	var node *dash.Node
+	err := dash.ErrNodeNotFound
+	if id, parseErr := uuid.Parse(idOrName); parseErr == nil {
+		node, err = d.GetNodeActive(ctx, id)
+	}
+	if errors.Is(err, dash.ErrNodeNotFound) || errors.Is(err, dash.ErrNodeDeleted) {
+		node, err = d.FindNodeByName(ctx, idOrName)
+	}
+	if errors.Is(err, dash.ErrNodeNotFound) || errors.Is(err, dash.ErrNodeDeleted) {
 		return nil, fmt.Errorf("node not found: %s", idOrName)
 	}
+	return node, err
+}
+
+func getNode(ctx context.Context, db *sql.DB, idOrName string) (any, error) {
+	d, err := dash.New(dash.Config{DB: db})
+	if err != nil {
+		return nil, err
+	}
+
+	node, err := resolveNode(ctx, d, idOrName)
 	if err != nil {
 		return nil, err
 	}
 
 	var dataParsed any
-	json.Unmarshal(data, &dataParsed)
+	json.Unmarshal(node.Data, &dataParsed)
 
 	return map[string]any{
-		"id":         id,
-		"layer":      layer,
-		"type":       typ,
-		"name":       name,
+		"id":         node.ID.String(),
+		"layer":      node.Layer,
+		"type":       node.Type,
+		"name":       node.Name,
 		"data":       dataParsed,
-		"created_at": createdAt.Format(time.RFC3339),
-		"updated_at": updatedAt.Format(time.RFC3339),
+		"created_at": node.CreatedAt.Format(time.RFC3339),
+		"updated_at": node.UpdatedAt.Format(time.RFC3339),
 	}, nil
 }
 
-func fileHistory(ctx context.Context, db *sql.DB, filepath string) (any, error) {
+// fieldDiff is one field whose value differs between two nodes.
+type fieldDiff struct {
+	Field string `json:"field"`
+	A     any    `json:"a"`
+	B     any    `json:"b"`
+}
+
+// diffNodes compares two nodes: top-level metadata, then their data JSON
+// key by key. Keys are reported in sorted order; values are compared by
+// their JSON encoding, so nested objects differ only if their content does.
+func diffNodes(ctx context.Context, db *sql.DB, idA, idB string) (any, error) {
+	d, err := dash.New(dash.Config{DB: db})
+	if err != nil {
+		return nil, err
+	}
+	a, err := resolveNode(ctx, d, idA)
+	if err != nil {
+		return nil, err
+	}
+	b, err := resolveNode(ctx, d, idB)
+	if err != nil {
+		return nil, err
+	}
+
+	metaA := map[string]any{
+		"layer":      string(a.Layer),
+		"type":       a.Type,
+		"name":       a.Name,
+		"created_at": a.CreatedAt.Format(time.RFC3339),
+		"updated_at": a.UpdatedAt.Format(time.RFC3339),
+	}
+	metaB := map[string]any{
+		"layer":      string(b.Layer),
+		"type":       b.Type,
+		"name":       b.Name,
+		"created_at": b.CreatedAt.Format(time.RFC3339),
+		"updated_at": b.UpdatedAt.Format(time.RFC3339),
+	}
+	metadata, _, _ := diffFields(metaA, metaB)
+
+	var dataA, dataB map[string]any
+	json.Unmarshal(a.Data, &dataA)
+	json.Unmarshal(b.Data, &dataB)
+	changed, onlyA, onlyB := diffFields(dataA, dataB)
+
+	return map[string]any{
+		"a":         a.ID.String(),
+		"b":         b.ID.String(),
+		"metadata":  metadata,
+		"only_in_a": onlyA,
+		"only_in_b": onlyB,
+		"changed":   changed,
+		"identical": len(metadata) == 0 && len(changed) == 0 && len(onlyA) == 0 && len(onlyB) == 0,
+	}, nil
+}
+
+// diffFields returns the keys of a and b whose values differ, and the keys
+// present in only one of them, all sorted.
+func diffFields(a, b map[string]any) (changed []fieldDiff, onlyA, onlyB []string) {
+	changed, onlyA, onlyB = []fieldDiff{}, []string{}, []string{}
+	keys := make([]string, 0, len(a)+len(b))
+	for k := range a {
+		keys = append(keys, k)
+	}
+	for k := range b {
+		if _, ok := a[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		va, inA := a[k]
+		vb, inB := b[k]
+		switch {
+		case !inB:
+			onlyA = append(onlyA, k)
+		case !inA:
+			onlyB = append(onlyB, k)
+		default:
+			ja, _ := json.Marshal(va)
+			jb, _ := json.Marshal(vb)
+			if string(ja) != string(jb) {
+				changed = append(changed, fieldDiff{Field: k, A: va, B: vb})
+			}
+		}
+	}
+	return changed, onlyA, onlyB
+}
+
+func intentSubtree(ctx context.Context, db *sql.DB, idOrName string) (any, error) {
+	d, err := dash.New(dash.Config{DB: db})
+	if err != nil {
+		return nil, err
+	}
+
+	var node *dash.Node
+	if id, parseErr := uuid.Parse(idOrName); parseErr == nil {
+		node, err = d.GetNodeActive(ctx, id)
+	} else {
+		node, err = d.GetNodeByName(ctx, dash.LayerContext, "intent", idOrName)
+	}
+	if errors.Is(err, dash.ErrNodeNotFound) || errors.Is(err, dash.ErrNodeDeleted) {
+		return nil, fmt.Errorf("intent not found: %s", idOrName)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	tree, err := d.GetIntentSubtree(ctx, node.ID)
+	if err != nil {
+		return nil, err
+	}
+	p := tree.Progress
+	return map[string]any{
+		"intent":      tree.Intent.Name,
+		"id":          tree.Intent.ID.String(),
+		"status":      tree.Status,
+		"progress":    fmt.Sprintf("%d/%d tasks done, %d/%d plans approved, %d/%d work orders merged", p.TasksDone, p.TasksTotal, p.PlansApproved, p.PlansTotal, p.WorkOrdersMerged, p.WorkOrdersTotal),
+		"plans":       tree.Plans,
+		"tasks":       tree.Tasks,
+		"suggestions": tree.Suggestions,
+		"work_orders": tree.WorkOrders,
+		"other":       tree.Other,
+	}, nil
+}
+
+func tagNode(ctx context.Context, db *sql.DB, idOrName string, tags []string) (any, error) {
+	d, err := dash.New(dash.Config{DB: db})
+	if err != nil {
+		return nil, err
+	}
+
+	node, err := resolveNode(ctx, d, idOrName)
+	if err != nil {
+		return nil, err
+	}
+	all, err := d.AddTags(ctx, node.ID, tags...)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]any{
+		"id":   node.ID.String(),
+		"name": node.Name,
+		"tags": all,
+	}, nil
+}
+
+func nodesByTag(ctx context.Context, db *sql.DB, args []string, page paging) (any, error) {
+	d, err := dash.New(dash.Config{DB: db})
+	if err != nil {
+		return nil, err
+	}
+
+	var layer dash.Layer
+	var nodeType string
+	if len(args) > 1 {
+		layer = dash.Layer(strings.ToUpper(args[1]))
+	}
+	if len(args) > 2 {
+		nodeType = args[2]
+	}
+
+	nodes, err := d.NodesByTag(ctx, args[0], layer, nodeType)
+	if err != nil {
+		return nil, err
+	}
+	limit := page.limitOr(len(nodes))
+	nodes, more := pageSlice(nodes, page, limit)
+
+	results := make([]map[string]any, 0, len(nodes))
+	for _, n := range nodes {
+		results = append(results, map[string]any{
+			"id":         n.ID.String(),
+			"layer":      n.Layer,
+			"type":       n.Type,
+			"name":       n.Name,
+			"tags":       dash.NodeTags(n),
+			"updated_at": n.UpdatedAt.Format(time.RFC3339),
+		})
+	}
+
+	return addPage(map[string]any{
+		"tag":   strings.ToLower(strings.TrimSpace(args[0])),
+		"count": len(results),
+		"nodes": results,
+	}, limit, page.offset, more), nil
+}
+
+func nodesByCreator(ctx context.Context, db *sql.DB, creator string, page paging) (any, error) {
+	d, err := dash.New(dash.Config{DB: db})
+	if err != nil {
+		return nil, err
+	}
+
+	nodes, err := d.NodesByCreator(ctx, creator)
+	if err != nil {
+		return nil, err
+	}
+	limit := page.limitOr(len(nodes))
+	nodes, more := pageSlice(nodes, page, limit)
+
+	results := make([]map[string]any, 0, len(nodes))
+	for _, n := range nodes {
+		results = append(results, map[string]any{
+			"id":         n.ID.String(),
+			"layer":      n.Layer,
+			"type":       n.Type,
+			"name":       n.Name,
+			"created_at": n.CreatedAt.Format(time.RFC3339),
+		})
+	}
+
+	return addPage(map[string]any{
+		"creator": creator,
+		"count":   len(results),
+		"nodes":   results,
+	}, limit, page.offset, more), nil
+}
+
+func fileHistory(ctx context.Context, db *sql.DB, filepath string, page paging) (any, error) {
+	limit := page.limitOr(30)
 	rows, err := db.QueryContext(ctx, `
 		SELECT
 			ee.relation,
@@ -437,8 +1301,8 @@ func fileHistory(ctx context.Context, db *sql.DB, filepath string) (any, error)
 		JOIN nodes s ON ee.source_id = s.id
 		WHERE n.name = $1 AND n.type = 'file'
 		ORDER BY ee.occurred_at DESC
-		LIMIT 30
-	`, filepath)
+		LIMIT $2 OFFSET $3
+	`, filepath, limit+1, page.offset)
 	if err != nil {
 		return nil, err
 	}
@@ -466,21 +1330,30 @@ func fileHistory(ctx context.Context, db *sql.DB, filepath string) (any, error)
 		})
 	}
 
-	return map[string]any{
+	events, more := trimPage(events, limit)
+	return addPage(map[string]any{
 		"file":   filepath,
 		"count":  len(events),
 		"events": events,
-	}, nil
+	}, limit, page.offset, more), nil
 }
 
-func executeSQL(ctx context.Context, db *sql.DB, query string) (any, error) {
-	// Safety: only allow SELECT/WITH
-	normalized := strings.TrimSpace(strings.ToUpper(query))
-	if !strings.HasPrefix(normalized, "SELECT") && !strings.HasPrefix(normalized, "WITH") {
-		return nil, fmt.Errorf("only SELECT queries allowed")
+func executeSQL(ctx context.Context, db *sql.DB, query string, page paging) (any, error) {
+	// Safety check: exactly one SELECT/WITH statement, no write keywords
+	if err := dash.CheckReadOnlySQL(query); err != nil {
+		return nil, err
+	}
+	query, paged := pagedSQL(query, page)
+
+	// The guard is a tokenizer, not a parser; a read-only transaction makes
+	// the database refuse any write that slips past it.
+	tx, err := db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, err
 	}
+	defer tx.Rollback()
 
-	rows, err := db.QueryContext(ctx, query)
+	rows, err := tx.QueryContext(ctx, query)
 	if err != nil {
 		return nil, err
 	}
@@ -516,11 +1389,18 @@ func executeSQL(ctx context.Context, db *sql.DB, query string) (any, error) {
 		results = append(results, row)
 	}
 
-	return map[string]any{
+	result := map[string]any{
 		"columns": columns,
 		"count":   len(results),
 		"rows":    results,
-	}, nil
+	}
+	if !paged {
+		return result, nil
+	}
+	limit := page.limitOr(sqlDefaultLimit)
+	results, more := trimPage(results, limit)
+	result["count"], result["rows"] = len(results), results
+	return addPage(result, limit, page.offset, more), nil
 }
 
 func checkFailures(ctx context.Context, db *sql.DB, tool, pattern string) (any, error) {
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"testing"
+
+	"dash"
+
+	"github.com/google/uuid"
+)
+
+func testDash(t *testing.T) *dash.Dash {
+	t.Helper()
+	dbURL := os.Getenv("DASH_TEST_DATABASE_URL")
+	if dbURL == "" {
+		t.Skip("DASH_TEST_DATABASE_URL not set")
+	}
+	db, err := sql.Open("postgres", dbURL)
+	if err != nil {
+		t.Fatalf("db open: %v", err)
+	}
+	if err := db.Ping(); err != nil {
+		db.Close()
+		t.Skipf("db ping: %v", err)
+	}
+	d, err := dash.New(dash.Config{DB: db})
+	if err != nil {
+		db.Close()
+		t.Fatalf("new dash: %v", err)
+	}
+	t.Cleanup(func() { d.Close() })
+	return d
+}
+
+func TestResolveNodeByUUIDName(t *testing.T) {
+	d := testDash(t)
+	ctx := context.Background()
+
+	name := uuid.New().String()
+	n := &dash.Node{Layer: dash.LayerContext, Type: "test_node", Name: name}
+	if err := d.CreateNode(ctx, n); err != nil {
+		t.Fatalf("create node: %v", err)
+	}
+	t.Cleanup(func() { d.SoftDeleteNode(ctx, n.ID) })
+
+	got, err := resolveNode(ctx, d, name)
+	if err != nil || got.ID != n.ID {
+		t.Fatalf("resolveNode(name) = %v, %v; want %s", got, err, n.ID)
+	}
+	got, err = resolveNode(ctx, d, n.ID.String())
+	if err != nil || got.ID != n.ID {
+		t.Fatalf("resolveNode(id) = %v, %v; want %s", got, err, n.ID)
+	}
+	if _, err := resolveNode(ctx, d, uuid.New().String()); err == nil {
+		t.Error("unknown UUID should not resolve")
+	}
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// sqlDefaultLimit caps `sql` results when no --limit is given.
+const sqlDefaultLimit = 100
+
+// paging holds the --limit and --offset flags shared by the list commands.
+// A zero limit means the command's own default.
+type paging struct {
+	limit  int
+	offset int
+}
+
+// parsePaging removes --limit/--offset (also -limit, --limit=N) from args
+// wherever they appear and returns the remaining positional args.
+func parsePaging(args []string) ([]string, paging, error) {
+	var p paging
+	var rest []string
+	for i := 0; i < len(args); i++ {
+		name, value, hasValue := strings.Cut(strings.TrimLeft(args[i], "-"), "=")
+		if !strings.HasPrefix(args[i], "-") || (name != "limit" && name != "offset") {
+			rest = append(rest, args[i])
+			continue
+		}
+		if !hasValue {
+			if i+1 >= len(args) {
+				return nil, p, fmt.Errorf("--%s needs a value", name)
+			}
+			i++
+			value = args[i]
+		}
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			return nil, p, fmt.Errorf("--%s: invalid value %q", name, value)
+		}
+		if name == "limit" {
+			p.limit = n
+		} else {
+			p.offset = n
+		}
+	}
+	return rest, p, nil
+}
+
+// limitOr returns the --limit value, or def when none was given.
+func (p paging) limitOr(def int) int {
+	if p.limit > 0 {
+		return p.limit
+	}
+	return def
+}
+
+// pageSlice returns the page of items at p.offset, limit items long, and
+// whether items continue past it.
+func pageSlice[T any](items []T, p paging, limit int) ([]T, bool) {
+	if p.offset >= len(items) {
+		return nil, false
+	}
+	items = items[p.offset:]
+	if len(items) > limit {
+		return items[:limit], true
+	}
+	return items, false
+}
+
+// trimPage drops the extra row fetched to detect a following page.
+// Queries fetch limit+1 rows; more is true when that row came back.
+func trimPage[T any](items []T, limit int) ([]T, bool) {
+	if len(items) > limit {
+		return items[:limit], true
+	}
+	return items, false
+}
+
+// addPage records the page bounds in a command result, with a hint for
+// fetching the next page when more rows are likely available.
+func addPage(result map[string]any, limit, offset int, more bool) map[string]any {
+	result["limit"] = limit
+	result["offset"] = offset
+	result["has_more"] = more
+	if more {
+		result["next"] = fmt.Sprintf("--offset %d", offset+limit)
+	}
+	return result
+}
+
+var sqlPagingRe = regexp.MustCompile(`(?i)\b(limit|offset|fetch)\b`)
+
+// pagedSQL wraps a read-only query so that it returns at most limit+1 rows
+// from offset. Queries with their own LIMIT, OFFSET or FETCH are left alone
+// unless paging was asked for explicitly, so a deliberate bound is never
+// silently changed. The bool reports whether the query was wrapped.
+func pagedSQL(query string, p paging) (string, bool) {
+	query = strings.TrimRight(strings.TrimSpace(query), "; \t\n")
+	if sqlPagingRe.MatchString(query) && p.limit == 0 && p.offset == 0 {
+		return query, false
+	}
+	limit := p.limitOr(sqlDefaultLimit)
+	return fmt.Sprintf("SELECT * FROM (\n%s\n) sub LIMIT %d OFFSET %d", query, limit+1, p.offset), true
+}
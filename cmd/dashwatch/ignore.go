@@ -0,0 +1,143 @@
+package main
+
+import (
+	"bufio"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// ignoreFileName is the gitignore-style file read from the watch root.
+const ignoreFileName = ".dashignore"
+
+// ignoreRule is one non-comment line of a .dashignore file.
+type ignoreRule struct {
+	pattern  string // slash-separated, without leading "/", trailing "/" or "!"
+	negate   bool   // "!pattern" re-includes a previously ignored path
+	dirOnly  bool   // "pattern/" only matches directories
+	anchored bool   // pattern contains "/" and is matched from the root
+}
+
+// ignoreRules holds the parsed .dashignore of a watch root. A nil
+// *ignoreRules ignores nothing.
+type ignoreRules struct {
+	root  string
+	rules []ignoreRule
+}
+
+// loadIgnoreRules reads root/.dashignore. A missing or unreadable file
+// yields an empty rule set.
+func loadIgnoreRules(root string) *ignoreRules {
+	ir := &ignoreRules{root: root}
+	f, err := os.Open(filepath.Join(root, ignoreFileName))
+	if err != nil {
+		return ir
+	}
+	defer f.Close()
+
+	var lines []string
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		lines = append(lines, sc.Text())
+	}
+	ir.rules = parseIgnoreRules(lines)
+	return ir
+}
+
+// parseIgnoreRules parses gitignore-style lines: blank lines and "#"
+// comments are skipped, "!" negates, a trailing "/" restricts the rule to
+// directories, and a "/" anywhere else anchors it to the root. "*", "?",
+// "[...]" and "**" (any number of directories) are supported.
+func parseIgnoreRules(lines []string) []ignoreRule {
+	var rules []ignoreRule
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		var r ignoreRule
+		if strings.HasPrefix(line, "!") {
+			r.negate = true
+			line = line[1:]
+		}
+		if strings.HasSuffix(line, "/") {
+			r.dirOnly = true
+			line = strings.TrimRight(line, "/")
+		}
+		if strings.Contains(line, "/") {
+			r.anchored = true
+			line = strings.TrimPrefix(line, "/")
+		}
+		if line == "" {
+			continue
+		}
+		r.pattern = line
+		rules = append(rules, r)
+	}
+	return rules
+}
+
+// ignored reports whether path (absolute, under the root) is excluded. As in
+// git, a path inside an ignored directory is ignored regardless of later
+// negations.
+func (ir *ignoreRules) ignored(p string, isDir bool) bool {
+	if ir == nil || len(ir.rules) == 0 {
+		return false
+	}
+	rel, err := filepath.Rel(ir.root, p)
+	if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+		return false
+	}
+	parts := strings.Split(filepath.ToSlash(rel), "/")
+	for i := range parts {
+		last := i == len(parts)-1
+		if ir.matchRules(parts[:i+1], isDir || !last) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchRules applies the rules in order to one path; the last match wins.
+func (ir *ignoreRules) matchRules(parts []string, isDir bool) bool {
+	ignored := false
+	for _, r := range ir.rules {
+		if r.dirOnly && !isDir {
+			continue
+		}
+		var ok bool
+		if r.anchored {
+			ok = matchSegments(strings.Split(r.pattern, "/"), parts)
+		} else {
+			ok, _ = path.Match(r.pattern, parts[len(parts)-1])
+		}
+		if ok {
+			ignored = !r.negate
+		}
+	}
+	return ignored
+}
+
+// matchSegments matches path segments against pattern segments, where a
+// "**" segment matches zero or more path segments.
+func matchSegments(pattern, parts []string) bool {
+	if len(pattern) == 0 {
+		return len(parts) == 0
+	}
+	if pattern[0] == "**" {
+		for i := 0; i <= len(parts); i++ {
+			if matchSegments(pattern[1:], parts[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(parts) == 0 {
+		return false
+	}
+	if ok, _ := path.Match(pattern[0], parts[0]); !ok {
+		return false
+	}
+	return matchSegments(pattern[1:], parts[1:])
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestIgnoreRulesMatch(t *testing.T) {
+	ir := &ignoreRules{root: "/repo", rules: parseIgnoreRules([]string{
+		"# generated code",
+		"",
+		"gen/",
+		"*.pb.go",
+		"/cmd/legacy",
+		"docs/**/draft.md",
+		"*.md",
+		"!README.md",
+	})}
+
+	cases := []struct {
+		path  string
+		isDir bool
+		want  bool
+	}{
+		{"/repo/gen", true, true},
+		{"/repo/gen/types.go", false, true},
+		{"/repo/internal/gen/types.go", false, true},
+		{"/repo/gen", false, false}, // dir-only rule, plain file
+		{"/repo/api/service.pb.go", false, true},
+		{"/repo/api/service.go", false, false},
+		{"/repo/cmd/legacy/main.go", false, true},
+		{"/repo/other/cmd/legacy/main.go", false, false}, // anchored to root
+		{"/repo/docs/draft.md", false, true},
+		{"/repo/docs/a/b/draft.md", false, true},
+		{"/repo/notes.md", false, true},
+		{"/repo/README.md", false, false}, // negated
+		{"/repo/gen/README.md", false, true},
+		{"/elsewhere/gen/types.go", false, false},
+	}
+	for _, c := range cases {
+		if got := ir.ignored(c.path, c.isDir); got != c.want {
+			t.Errorf("ignored(%q, %v) = %v, want %v", c.path, c.isDir, got, c.want)
+		}
+	}
+}
+
+func TestIgnoreRulesOverrideEmbeddableExts(t *testing.T) {
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, ignoreFileName), []byte("vendor.go\nthird_party/\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	ir := loadIgnoreRules(root)
+
+	for _, p := range []string{"vendor.go", "third_party/lib.go", "third_party/x/y.ts"} {
+		path := filepath.Join(root, p)
+		if !isEmbeddable(path) {
+			t.Fatalf("%s should have an embeddable extension", p)
+		}
+		if !ir.ignored(path, false) {
+			t.Errorf("%s should be ignored despite its embeddable extension", p)
+		}
+	}
+	if ir.ignored(filepath.Join(root, "main.go"), false) {
+		t.Error("main.go should not be ignored")
+	}
+}
+
+func TestLoadIgnoreRulesMissingFile(t *testing.T) {
+	ir := loadIgnoreRules(t.TempDir())
+	if len(ir.rules) != 0 || ir.ignored(filepath.Join(ir.root, "a.go"), false) {
+		t.Fatalf("missing %s should ignore nothing", ignoreFileName)
+	}
+	var nilRules *ignoreRules
+	if nilRules.ignored("/x/a.go", false) {
+		t.Fatal("nil rules should ignore nothing")
+	}
+}
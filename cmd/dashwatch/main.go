@@ -4,12 +4,14 @@ import (
 	"context"
 	"crypto/sha256"
 	"encoding/hex"
+	"flag"
 	"io"
 	"log"
 	"os"
 	"path/filepath"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"dash"
@@ -18,11 +20,22 @@ import (
 )
 
 const (
-	debounceInterval = 2 * time.Second
-	maxFileSize      = 64 * 1024 // 64KB
+	debounceInterval  = 2 * time.Second
+	renameWindow      = 500 * time.Millisecond
+	heartbeatInterval = time.Minute
+	maxFileSize       = 64 * 1024 // 64KB
 )
 
-// projectDirs are the directories under watchDir we actually watch for embedding.
+// stats are counters reported in each heartbeat. embedded and errors are
+// reset after every beat; watched is a running total of directories.
+var stats struct {
+	watched  atomic.Int64
+	embedded atomic.Int64
+	errors   atomic.Int64
+}
+
+// projectDirs are the directories under a root we actually watch for
+// embedding. Roots without any of them are watched in full.
 var projectDirs = []string{
 	"dash",       // Go package
 	"cmd",        // binaries
@@ -44,9 +57,18 @@ var embeddableExts = map[string]bool{
 }
 
 func main() {
-	watchDir := "/dash"
-	if len(os.Args) > 1 {
-		watchDir = os.Args[1]
+	rescan := flag.Bool("rescan", false, "report file nodes whose stored hash no longer matches the file on disk, then exit")
+	gcOrphans := flag.Duration("gc-orphans", 0, "report file nodes with no embedding and no events, not updated within this window (e.g. 720h), then exit")
+	fix := flag.Bool("fix", false, "with -rescan: re-embed changed files and soft-delete nodes of missing files; with -gc-orphans: soft-delete the reported nodes")
+	flag.Parse()
+
+	args := flag.Args()
+	if len(args) == 0 {
+		args = []string{"/dash"}
+	}
+	roots, err := newWatchRoots(args)
+	if err != nil {
+		log.Fatalf("roots: %v", err)
 	}
 
 	// Connect to database
@@ -60,13 +82,31 @@ func main() {
 	// Create router for embeddings
 	router := dash.NewLLMRouter(dash.DefaultRouterConfig())
 
-	d, err := dash.New(dash.Config{
-		DB:              db,
-		FileAllowedRoot: "/",
-		Router:          router,
-	})
-	if err != nil {
-		log.Fatalf("dash: %v", err)
+	for _, r := range roots {
+		r.d, err = dash.New(dash.Config{
+			DB:              db,
+			FileAllowedRoot: r.dir,
+			Router:          router,
+		})
+		if err != nil {
+			log.Fatalf("dash %s: %v", r.dir, err)
+		}
+	}
+
+	if *rescan {
+		for _, r := range roots {
+			if err := rescanStale(r, *fix); err != nil {
+				log.Fatalf("rescan %s: %v", r.dir, err)
+			}
+		}
+		return
+	}
+
+	if *gcOrphans > 0 {
+		if err := gcOrphanFiles(roots[0].d, *gcOrphans, *fix); err != nil {
+			log.Fatalf("gc-orphans: %v", err)
+		}
+		return
 	}
 
 	watcher, err := fsnotify.NewWatcher()
@@ -75,32 +115,43 @@ func main() {
 	}
 	defer watcher.Close()
 
-	// Only watch project directories + root for top-level files
-	watched := 0
-	watcher.Add(watchDir)
-	watched++
+	for _, r := range roots {
+		r.ignore = loadIgnoreRules(r.dir)
+		if n := len(r.ignore.rules); n > 0 {
+			log.Printf("dashwatch: %d ignore rules from %s", n, filepath.Join(r.dir, ignoreFileName))
+		}
 
-	for _, sub := range projectDirs {
-		dir := filepath.Join(watchDir, sub)
-		filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
-			if err != nil {
-				return nil
-			}
-			if info.IsDir() {
-				if skipDirs[filepath.Base(path)] {
-					return filepath.SkipDir
+		// Only watch project directories + root for top-level files
+		watcher.Add(r.dir)
+		r.watched++
+
+		for _, dir := range r.walkDirs() {
+			filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+				if err != nil {
+					return nil
 				}
-				watcher.Add(path)
-				watched++
-			}
-			return nil
-		})
+				if info.IsDir() && path != r.dir {
+					if skipDirs[filepath.Base(path)] || r.ignore.ignored(path, true) {
+						return filepath.SkipDir
+					}
+					watcher.Add(path)
+					r.watched++
+				}
+				return nil
+			})
+		}
+		stats.watched.Add(int64(r.watched))
+		log.Printf("dashwatch: watching %d directories under %s (project %s)", r.watched, r.dir, r.project)
+	}
+	if len(roots) > 1 {
+		log.Printf("dashwatch: watching %d directories in %d roots", stats.watched.Load(), len(roots))
 	}
 
-	log.Printf("dashwatch: watching %d directories under %s", watched, watchDir)
+	go heartbeatLoop(roots[0].d)
 
 	// Debounce
 	pending := &sync.Map{}
+	renames := &renameTracker{}
 	var mu sync.Mutex
 	processing := make(map[string]bool)
 
@@ -131,7 +182,9 @@ func main() {
 						delete(processing, path)
 						mu.Unlock()
 					}()
-					processFile(d, path)
+					if r := rootFor(roots, path); r != nil {
+						processFile(r, path)
+					}
 				}()
 				return true
 			})
@@ -145,8 +198,26 @@ func main() {
 			if !ok {
 				return
 			}
+			r := rootFor(roots, event.Name)
+			if r == nil {
+				continue
+			}
+			if event.Name == filepath.Join(r.dir, ignoreFileName) {
+				r.ignore = loadIgnoreRules(r.dir)
+				log.Printf("reloaded %s: %d rules", event.Name, len(r.ignore.rules))
+				continue
+			}
+			embeddable := isEmbeddable(event.Name) && !r.ignore.ignored(event.Name, false)
+			if event.Has(fsnotify.Rename) && embeddable {
+				renames.from(event.Name)
+			}
+			if event.Has(fsnotify.Create) && embeddable {
+				if oldPath, ok := renames.take(event.Name); ok {
+					renameFileNode(r, oldPath, event.Name)
+				}
+			}
 			if event.Has(fsnotify.Write) || event.Has(fsnotify.Create) {
-				if isEmbeddable(event.Name) {
+				if embeddable {
 					pending.Store(event.Name, time.Now())
 				} else if !isEmbeddable(event.Name) && (event.Has(fsnotify.Create) || event.Has(fsnotify.Write)) {
 					// Log non-embeddable changes for visibility
@@ -158,8 +229,9 @@ func main() {
 			// Auto-watch new subdirectories
 			if event.Has(fsnotify.Create) {
 				if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
-					if !skipDirs[filepath.Base(event.Name)] {
+					if !skipDirs[filepath.Base(event.Name)] && !r.ignore.ignored(event.Name, true) {
 						watcher.Add(event.Name)
+						stats.watched.Add(1)
 					}
 				}
 			}
@@ -169,11 +241,86 @@ func main() {
 				return
 			}
 			log.Printf("error: %v", err)
+			stats.errors.Add(1)
+		}
+	}
+}
+
+// heartbeatLoop records a watcher_heartbeat observation every minute so the
+// dashboard can tell a live watcher from a hung one. It runs on its own
+// goroutine and never touches the debounce state.
+func heartbeatLoop(d *dash.Dash) {
+	beat := func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		hb := dash.WatcherHeartbeat{
+			DirsWatched: int(stats.watched.Load()),
+			Embedded:    int(stats.embedded.Swap(0)),
+			Errors:      int(stats.errors.Swap(0)),
 		}
+		if err := d.RecordWatcherHeartbeat(ctx, hb); err != nil {
+			log.Printf("heartbeat error: %v", err)
+		}
+	}
+
+	beat()
+	ticker := time.NewTicker(heartbeatInterval)
+	defer ticker.Stop()
+	for range ticker.C {
+		beat()
 	}
 }
 
-func processFile(d *dash.Dash, path string) {
+// renameTracker pairs a Rename event (the old path) with the Create event
+// fsnotify emits for the new path, so a moved file keeps its node.
+type renameTracker struct {
+	mu   sync.Mutex
+	path string
+	at   time.Time
+}
+
+func (r *renameTracker) from(path string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.path, r.at = path, time.Now()
+}
+
+// take returns the pending old path if it was renamed within renameWindow
+// and has the same extension as newPath.
+func (r *renameTracker) take(newPath string) (string, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	old := r.path
+	if old == "" || old == newPath || time.Since(r.at) > renameWindow || filepath.Ext(old) != filepath.Ext(newPath) {
+		return "", false
+	}
+	r.path = ""
+	return old, true
+}
+
+// renameFileNode moves the SYSTEM.file node for oldPath to newPath, keeping
+// its embedding and history. processFile then finds the hash unchanged.
+func renameFileNode(r *watchRoot, oldPath, newPath string) {
+	if _, err := os.Stat(oldPath); err == nil {
+		return // old file still exists: a copy, not a move
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	node, err := r.d.GetNodeByName(ctx, dash.LayerSystem, "file", oldPath)
+	if err != nil {
+		return
+	}
+	if err := r.d.RenameNode(ctx, node.ID, newPath); err != nil {
+		log.Printf("rename error %s: %v", filepath.Base(newPath), err)
+		return
+	}
+	log.Printf("renamed: %s -> %s", oldPath, newPath)
+}
+
+// processFile embeds path if its content changed, tagging its SYSTEM.file
+// node with the root and project it belongs to.
+func processFile(r *watchRoot, path string) {
 	info, err := os.Stat(path)
 	if err != nil || !info.Mode().IsRegular() || info.Size() == 0 || info.Size() > maxFileSize {
 		return
@@ -187,16 +334,22 @@ func processFile(d *dash.Dash, path string) {
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
+	d := r.d
 	fileNode, err := d.GetOrCreateNode(ctx, dash.LayerSystem, "file", path, map[string]any{
-		"path": path,
+		"path":    path,
+		"root":    r.dir,
+		"project": r.project,
 	})
 	if err != nil {
 		log.Printf("node error %s: %v", filepath.Base(path), err)
+		stats.errors.Add(1)
 		return
 	}
+	if data := dash.NodeDataOf(fileNode); data.String("project") != r.project || data.String("root") != r.dir {
+		d.UpdateNodeData(ctx, fileNode, map[string]any{"root": r.dir, "project": r.project})
+	}
 
-	existingHash, _ := d.GetNodeContentHash(ctx, fileNode.ID)
-	if existingHash == hash {
+	if d.EmbeddingUpToDate(ctx, fileNode.ID, hash) {
 		return
 	}
 
@@ -208,13 +361,53 @@ func processFile(d *dash.Dash, path string) {
 	embedding, err := d.EmbedText(ctx, content)
 	if err != nil {
 		log.Printf("embed error %s: %v", filepath.Base(path), err)
+		stats.errors.Add(1)
 		return
 	}
 
-	d.UpdateNodeEmbedding(ctx, fileNode.ID, embedding, hash)
+	if err := d.UpdateNodeEmbedding(ctx, fileNode.ID, embedding, hash); err != nil {
+		log.Printf("store embedding error %s: %v", filepath.Base(path), err)
+		stats.errors.Add(1)
+		return
+	}
+	stats.embedded.Add(1)
 	log.Printf("embedded: %s", path)
 }
 
+// rescanStale reports file nodes under r whose embedding is stale
+// because the file changed or disappeared. With fix, changed files are
+// re-embedded and nodes of missing files are soft-deleted.
+func rescanStale(r *watchRoot, fix bool) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+
+	d, root := r.d, r.dir
+	stale, err := d.ListStaleEmbeddings(ctx, root)
+	if err != nil {
+		return err
+	}
+	var changed, missing int
+	for _, sf := range stale {
+		if sf.Missing {
+			missing++
+			log.Printf("missing: %s", sf.Path)
+			if fix {
+				if err := d.SoftDeleteNode(ctx, sf.NodeID); err != nil {
+					log.Printf("delete error %s: %v", filepath.Base(sf.Path), err)
+				}
+			}
+			continue
+		}
+		changed++
+		log.Printf("changed: %s", sf.Path)
+		if fix {
+			processFile(r, sf.Path)
+		}
+	}
+	log.Printf("rescan: %d changed, %d missing under %s", changed, missing, root)
+	return nil
+}
+
 func isEmbeddable(path string) bool {
 	ext := strings.ToLower(filepath.Ext(path))
 	if ext == ".log" {
@@ -247,3 +440,27 @@ func readFile(path string) (string, error) {
 	return string(data), nil
 }
 
+// gcOrphanFiles reports orphaned file nodes (see dash.ListOrphanFiles) and,
+// with fix, soft-deletes them. Orphans are found across all roots.
+func gcOrphanFiles(d *dash.Dash, olderThan time.Duration, fix bool) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	orphans, err := d.ListOrphanFiles(ctx, olderThan)
+	if err != nil {
+		return err
+	}
+	for _, o := range orphans {
+		log.Printf("orphan: %s (untouched %s)", o.Name, o.Age)
+	}
+	if !fix {
+		log.Printf("gc-orphans: %d orphaned file nodes; rerun with -fix to delete", len(orphans))
+		return nil
+	}
+	pruned, err := d.GCOrphanFiles(ctx, olderThan)
+	if err != nil {
+		return err
+	}
+	log.Printf("gc-orphans: deleted %d orphaned file nodes", pruned)
+	return nil
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"dash"
+)
+
+// watchRoot is one project tree watched by this process. Each root has its
+// own Dash client scoped to the root (FileAllowedRoot) and its own ignore
+// rules; file nodes under it are tagged with its project name.
+type watchRoot struct {
+	dir     string // absolute, cleaned
+	project string // base name of dir
+	d       *dash.Dash
+	ignore  *ignoreRules
+	watched int // directories added to the watcher
+}
+
+// newWatchRoots resolves the root arguments to absolute, cleaned paths,
+// dropping duplicates. Project names are the base names of the roots.
+func newWatchRoots(args []string) ([]*watchRoot, error) {
+	var roots []*watchRoot
+	seen := make(map[string]bool)
+	for _, arg := range args {
+		dir, err := filepath.Abs(arg)
+		if err != nil {
+			return nil, err
+		}
+		if seen[dir] {
+			continue
+		}
+		seen[dir] = true
+		roots = append(roots, &watchRoot{dir: dir, project: filepath.Base(dir)})
+	}
+	return roots, nil
+}
+
+// rootFor returns the root containing path, preferring the deepest one when
+// roots are nested, or nil when path is under none of them.
+func rootFor(roots []*watchRoot, path string) *watchRoot {
+	var best *watchRoot
+	for _, r := range roots {
+		if path != r.dir && !strings.HasPrefix(path, r.dir+string(filepath.Separator)) {
+			continue
+		}
+		if best == nil || len(r.dir) > len(best.dir) {
+			best = r
+		}
+	}
+	return best
+}
+
+// walkDirs returns the directories of r to walk: the projectDirs that exist
+// under it, or the whole root for projects without that layout.
+func (r *watchRoot) walkDirs() []string {
+	var dirs []string
+	for _, sub := range projectDirs {
+		dir := filepath.Join(r.dir, sub)
+		if info, err := os.Stat(dir); err == nil && info.IsDir() {
+			dirs = append(dirs, dir)
+		}
+	}
+	if len(dirs) == 0 {
+		return []string{r.dir}
+	}
+	return dirs
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestNewWatchRootsDedup(t *testing.T) {
+	roots, err := newWatchRoots([]string{"/srv/a", "/srv/a/", "/srv/b/../a", "/srv/b"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(roots) != 2 {
+		t.Fatalf("got %d roots, want 2", len(roots))
+	}
+	if roots[0].dir != "/srv/a" || roots[0].project != "a" {
+		t.Errorf("roots[0] = %s (%s), want /srv/a (a)", roots[0].dir, roots[0].project)
+	}
+	if roots[1].dir != "/srv/b" || roots[1].project != "b" {
+		t.Errorf("roots[1] = %s (%s), want /srv/b (b)", roots[1].dir, roots[1].project)
+	}
+}
+
+func TestRootForNested(t *testing.T) {
+	outer := &watchRoot{dir: "/srv/mono"}
+	inner := &watchRoot{dir: "/srv/mono/svc"}
+	other := &watchRoot{dir: "/srv/other"}
+	roots := []*watchRoot{outer, inner, other}
+
+	cases := []struct {
+		path string
+		want *watchRoot
+	}{
+		{"/srv/mono/main.go", outer},
+		{"/srv/mono/svc/main.go", inner},
+		{"/srv/mono/svc", inner},
+		{"/srv/mono/svcx/main.go", outer},
+		{"/srv/other/x.go", other},
+		{"/srv/otherwise/x.go", nil},
+		{"/tmp/x.go", nil},
+	}
+	for _, c := range cases {
+		if got := rootFor(roots, c.path); got != c.want {
+			t.Errorf("rootFor(%s) = %v, want %v", c.path, got, c.want)
+		}
+	}
+}
+
+func TestWalkDirsFallsBackToRoot(t *testing.T) {
+	dir := t.TempDir()
+	r := &watchRoot{dir: dir}
+	if got := r.walkDirs(); len(got) != 1 || got[0] != dir {
+		t.Errorf("walkDirs() = %v, want [%s]", got, dir)
+	}
+
+	if err := os.Mkdir(filepath.Join(dir, "cmd"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if got := r.walkDirs(); len(got) != 1 || got[0] != filepath.Join(dir, "cmd") {
+		t.Errorf("walkDirs() = %v, want [%s/cmd]", got, dir)
+	}
+}
@@ -98,7 +98,7 @@ func main() {
 	fmt.Println("\n=== TESTS COMPLETE ===")
 	fmt.Println("\nTo actually delete nodes, use either:")
 	fmt.Println("  1. dry_run=true first, then confirm_ids with specific IDs")
-	fmt.Println("  2. Or the TUI/MCP layer handles confirm=true after challenge")
+	fmt.Println("  2. Or a live call: the challenge lists confirm_ids to re-call with")
 }
 
 func printResult(result *dash.ToolResult) {
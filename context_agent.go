@@ -1,6 +1,7 @@
 package dash
 
 import (
+	"context"
 	"fmt"
 	"strings"
 	"time"
@@ -39,7 +40,7 @@ func srcAgentEnvelope(p SourceParams) string {
 	}
 
 	// Active tasks (compact form)
-	tasks, err := p.D.GetActiveTasksWithDeps(p.Ctx)
+	tasks, err := p.activeTasks()
 	if err == nil && len(tasks) > 0 {
 		b.WriteString("\nAKTIVA TASKS:\n")
 		max := 10
@@ -64,17 +65,13 @@ func srcAgentEnvelope(p SourceParams) string {
 
 // srcRecentDecisions shows recent decisions so the agent doesn't re-propose decided things.
 func srcRecentDecisions(p SourceParams) string {
-	nodes, err := p.D.queryMultipleNodes(p.Ctx, queryGetRecentDecisions, 2*time.Second)
-	if err != nil || len(nodes) == 0 {
-		return "\nRECENT DECISIONS: inga\n"
-	}
-
 	max := 5
 	if p.MaxItems > 0 {
 		max = p.MaxItems
 	}
-	if len(nodes) > max {
-		nodes = nodes[:max]
+	nodes, err := p.D.recentContextNodes(p.Ctx, max, "decision")
+	if err != nil || len(nodes) == 0 {
+		return "\nRECENT DECISIONS: inga\n"
 	}
 
 	var b strings.Builder
@@ -144,6 +141,43 @@ func srcPendingDecisions(p SourceParams) string {
 	return b.String()
 }
 
+// srcOpenQuestions shows open questions and pending decisions that block
+// progress, oldest first, with who raised them and when.
+func srcOpenQuestions(p SourceParams) string {
+	max := 5
+	if p.MaxItems > 0 {
+		max = p.MaxItems
+	}
+	qCtx, cancel := context.WithTimeout(p.Ctx, 2*time.Second)
+	defer cancel()
+	nodes, err := p.D.ListOpenQuestions(qCtx, max)
+	if err != nil || len(nodes) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("\nOPEN QUESTIONS (%d) — besvara eller lös med resolve_question innan du går vidare:\n", len(nodes)))
+	for _, n := range nodes {
+		data := NodeDataOf(n)
+		text := data.String("text")
+		if text == "" {
+			text = n.Name
+		}
+		if len(text) > 160 {
+			text = text[:157] + "..."
+		}
+		by := data.String("raised_by")
+		if by == "" {
+			by = data.String("created_by")
+		}
+		if by == "" {
+			by = "okänd"
+		}
+		b.WriteString(fmt.Sprintf("- %s (%s, %s, id=%s)\n", text, by, formatTimeAgo(n.CreatedAt), n.ID))
+	}
+	return b.String()
+}
+
 // srcActiveAgents shows what other agents are currently running to avoid duplication.
 func srcActiveAgents(p SourceParams) string {
 	// Query for recent agent sessions (observations of type "agent_spawn")
@@ -189,3 +223,37 @@ func srcActiveAgents(p SourceParams) string {
 	}
 	return b.String()
 }
+
+// srcScratchpad shows the latest notes on the scratchpads of this agent's
+// active work order and of the current task, so peers' notes reach it each turn.
+func srcScratchpad(p SourceParams) string {
+	var keys []string
+	if p.AgentKey != "" {
+		if wo, err := p.D.GetActiveWorkOrderForAgent(p.Ctx, p.AgentKey); err == nil && wo != nil {
+			keys = append(keys, wo.Node.Name)
+		}
+	}
+	if p.TaskName != "" {
+		keys = append(keys, p.TaskName)
+	}
+	limit := p.MaxItems
+	if limit <= 0 {
+		limit = 10
+	}
+
+	var b strings.Builder
+	for _, key := range keys {
+		notes, err := p.D.ReadScratchpad(p.Ctx, key)
+		if err != nil || len(notes) == 0 {
+			continue
+		}
+		if len(notes) > limit {
+			notes = notes[len(notes)-limit:]
+		}
+		b.WriteString(fmt.Sprintf("\nSCRATCHPAD %s (%d):\n", key, len(notes)))
+		for _, n := range notes {
+			b.WriteString(fmt.Sprintf("- [%s] %s: %s\n", n.At.Format("15:04"), n.Agent, n.Note))
+		}
+	}
+	return b.String()
+}
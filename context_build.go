@@ -69,6 +69,23 @@ const (
 		  AND deleted_at IS NULL
 		ORDER BY updated_at DESC
 		LIMIT 1`
+
+	// Changes made by the current session itself are not intervening work.
+	queryGetFilesChangedSince = `
+		SELECT tn.name,
+		       COUNT(*) as modifications,
+		       COUNT(DISTINCT ee.source_id) as sessions,
+		       MAX(ee.occurred_at) as last_modified
+		FROM edge_events ee
+		JOIN nodes sn ON sn.id = ee.source_id
+		JOIN nodes tn ON tn.id = ee.target_id AND tn.deleted_at IS NULL
+		WHERE ee.relation = 'modified'
+		  AND ee.occurred_at > $1
+		  AND tn.layer = 'SYSTEM' AND tn.type = 'file'
+		  AND NOT (sn.layer = 'CONTEXT' AND sn.type = 'session' AND sn.name = $2)
+		GROUP BY tn.name
+		ORDER BY last_modified DESC
+		LIMIT $3`
 )
 
 func (d *Dash) getRecentFileActivity(ctx context.Context) ([]FileActivity, error) {
@@ -118,6 +135,26 @@ func (d *Dash) getPreviousSession(ctx context.Context, currentSessionID string)
 	return summary, nil
 }
 
+// getFilesChangedSince returns files modified after since by anyone other
+// than the session currentSessionID, most recently modified first.
+func (d *Dash) getFilesChangedSince(ctx context.Context, since time.Time, currentSessionID string, limit int) ([]FileChurn, error) {
+	rows, err := d.db.QueryContext(ctx, queryGetFilesChangedSince, since, currentSessionID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var changes []FileChurn
+	for rows.Next() {
+		var fc FileChurn
+		if err := rows.Scan(&fc.FilePath, &fc.ModifyCount, &fc.SessionCount, &fc.LastModified); err != nil {
+			continue
+		}
+		changes = append(changes, fc)
+	}
+	return changes, rows.Err()
+}
+
 const querySuggestRelatedFiles = `
 	SELECT DISTINCT n2.name
 	FROM edge_events ee1
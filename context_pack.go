@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"math"
+	"path/filepath"
 	"sort"
 	"strings"
 	"time"
@@ -36,6 +37,7 @@ type PackItem struct {
 	Frequency      float64   `json:"frequency"`           // 0-1, log-normalized
 	GraphProximity float64   `json:"graph_proximity"`     // 0-1, connected to task?
 	WhySelected    string    `json:"why_selected"`        // top signal explanation
+	Queries        []string  `json:"queries,omitempty"`   // queries that surfaced it, see AssembleMultiQueryPack
 }
 
 // ConstraintItem holds a constraint for inclusion in a context pack.
@@ -54,12 +56,84 @@ type ContextPack struct {
 	CreatedAt   time.Time        `json:"created_at"`
 }
 
+// PackExclude drops unwanted nodes from a context pack before scoring.
+// The zero value excludes nothing.
+type PackExclude struct {
+	Patterns []string // globs matched against the trailing components of Path and Name (e.g. "*_test.go", "sql/migrations/*")
+	Types    []string // "LAYER.type" pairs, e.g. "CONTEXT.session"
+}
+
+// defaultPackConstraints is how many constraints a pack includes when
+// PackOptions.MaxConstraints is unset.
+const defaultPackConstraints = 5
+
+// PackOptions tunes AssembleContextPackWithOptions. The zero value matches
+// AssembleContextPack.
+type PackOptions struct {
+	Exclude        PackExclude
+	MaxConstraints int // top-K constraints by similarity to the query; 0 = defaultPackConstraints
+}
+
+// IsEmpty reports whether the filter excludes nothing.
+func (e PackExclude) IsEmpty() bool {
+	return len(e.Patterns) == 0 && len(e.Types) == 0
+}
+
+// excludes reports whether a search result matches any pattern or type pair.
+func (e PackExclude) excludes(sr *SearchResult) bool {
+	for _, t := range e.Types {
+		if t == sr.Layer+"."+sr.Type {
+			return true
+		}
+	}
+	for _, pat := range e.Patterns {
+		for _, target := range []string{sr.Path, sr.Name} {
+			if target == "" || target == "." {
+				continue
+			}
+			if ok, _ := filepath.Match(pat, pathTail(target, pat)); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// pathTail returns the trailing path components of target that line up with
+// pat, so a relative pattern such as "sql/migrations/*" matches the absolute
+// node path "/dash/sql/migrations/010.sql". Absolute patterns get the whole
+// target.
+func pathTail(target, pat string) string {
+	if filepath.IsAbs(pat) {
+		return target
+	}
+	parts := strings.Split(target, "/")
+	if n := strings.Count(pat, "/") + 1; n < len(parts) {
+		parts = parts[len(parts)-n:]
+	}
+	return strings.Join(parts, "/")
+}
+
+// filterExcluded returns the results not matched by exclude.
+func filterExcluded(results []*SearchResult, exclude PackExclude) []*SearchResult {
+	if exclude.IsEmpty() {
+		return results
+	}
+	kept := results[:0:0]
+	for _, sr := range results {
+		if !exclude.excludes(sr) {
+			kept = append(kept, sr)
+		}
+	}
+	return kept
+}
+
 // RerankWeights controls how signals are combined into a unified score.
 type RerankWeights struct {
-	Similarity float64
-	Recency    float64
-	Frequency  float64
-	GraphProx  float64
+	Similarity float64 `json:"similarity"`
+	Recency    float64 `json:"recency"`
+	Frequency  float64 `json:"frequency"`
+	GraphProx  float64 `json:"graph_proximity"`
 }
 
 // profileWeights returns the reranking weights for a profile.
@@ -175,7 +249,7 @@ func (d *Dash) BatchGetPackActivity(ctx context.Context, results []*SearchResult
 }
 
 // GetTaskProximity finds nodes connected to a task via direct edges (multiple relation types,
-// bidirectional) and shared session activity.
+// bidirectional) and shared session activity. Direct edge scores are scaled by the edge weight.
 func (d *Dash) GetTaskProximity(ctx context.Context, taskID uuid.UUID, nodeIDs []uuid.UUID) (map[uuid.UUID]float64, error) {
 	if len(nodeIDs) == 0 {
 		return nil, nil
@@ -191,7 +265,7 @@ func (d *Dash) GetTaskProximity(ctx context.Context, taskID uuid.UUID, nodeIDs [
 					WHEN 'implements' THEN 0.9
 					WHEN 'owns' THEN 0.8
 					ELSE 0.6
-				END as score
+				END * weight as score
 			FROM edges
 			WHERE source_id = $1
 			AND target_id = ANY($2)
@@ -206,7 +280,7 @@ func (d *Dash) GetTaskProximity(ctx context.Context, taskID uuid.UUID, nodeIDs [
 					WHEN 'implements' THEN 0.7
 					WHEN 'owns' THEN 0.6
 					ELSE 0.5
-				END as score
+				END * weight as score
 			FROM edges
 			WHERE target_id = $1
 			AND source_id = ANY($2)
@@ -249,8 +323,51 @@ func (d *Dash) GetTaskProximity(ctx context.Context, taskID uuid.UUID, nodeIDs [
 	return result, rows.Err()
 }
 
+const queryTaskEditRecency = `
+	SELECT target_id, MAX(occurred_at)
+	FROM edge_events
+	WHERE source_id = $1 AND relation = 'modified'
+	  AND target_id = ANY($2)
+	  AND occurred_at > NOW() - INTERVAL '30 days'
+	GROUP BY target_id`
+
+// GetTaskEditRecency scores the nodes the task itself modified in the last
+// 30 days by how recently it last touched them (0-1, same decay as
+// Recency). Unlike GetTaskProximity it ignores edges and shared sessions.
+func (d *Dash) GetTaskEditRecency(ctx context.Context, taskID uuid.UUID, nodeIDs []uuid.UUID) (map[uuid.UUID]float64, error) {
+	if len(nodeIDs) == 0 {
+		return nil, nil
+	}
+	rows, err := d.db.QueryContext(ctx, queryTaskEditRecency, taskID, pq.Array(nodeIDs))
+	if err != nil {
+		return nil, fmt.Errorf("task edit recency: %w", err)
+	}
+	defer rows.Close()
+
+	result := make(map[uuid.UUID]float64)
+	for rows.Next() {
+		var id uuid.UUID
+		var last time.Time
+		if err := rows.Scan(&id, &last); err != nil {
+			return nil, err
+		}
+		result[id] = computeRecency(&last)
+	}
+	return result, rows.Err()
+}
+
+// mergeMaxScores raises each score in dst to the one in src where src is
+// higher.
+func mergeMaxScores(dst, src map[uuid.UUID]float64) {
+	for id, score := range src {
+		if score > dst[id] {
+			dst[id] = score
+		}
+	}
+}
+
 // BatchGetGraphNeighbors finds nodes connected to the given set via edges.
-// Returns neighbor IDs with scores based on relation type.
+// Returns neighbor IDs with scores based on relation type, scaled by edge weight.
 // Excludes nodes already in the input set.
 func (d *Dash) BatchGetGraphNeighbors(ctx context.Context, nodeIDs []uuid.UUID, limit int) (map[uuid.UUID]float64, error) {
 	if len(nodeIDs) == 0 {
@@ -261,14 +378,14 @@ func (d *Dash) BatchGetGraphNeighbors(ctx context.Context, nodeIDs []uuid.UUID,
 	}
 
 	rows, err := d.db.QueryContext(ctx, `
-		SELECT neighbor_id, relation FROM (
-			SELECT target_id as neighbor_id, relation
+		SELECT neighbor_id, relation, weight FROM (
+			SELECT target_id as neighbor_id, relation, weight
 			FROM edges
 			WHERE source_id = ANY($1)
 			  AND target_id != ALL($1)
 			  AND deprecated_at IS NULL
 			UNION
-			SELECT source_id as neighbor_id, relation
+			SELECT source_id as neighbor_id, relation, weight
 			FROM edges
 			WHERE target_id = ANY($1)
 			  AND source_id != ALL($1)
@@ -285,24 +402,11 @@ func (d *Dash) BatchGetGraphNeighbors(ctx context.Context, nodeIDs []uuid.UUID,
 	for rows.Next() {
 		var id uuid.UUID
 		var relation string
-		if err := rows.Scan(&id, &relation); err != nil {
+		var weight float64
+		if err := rows.Scan(&id, &relation, &weight); err != nil {
 			return nil, err
 		}
-		var score float64
-		switch relation {
-		case "affects":
-			score = 0.5
-		case "depends_on":
-			score = 0.5
-		case "uses":
-			score = 0.4
-		case "implements":
-			score = 0.5
-		case "owns":
-			score = 0.4
-		default:
-			score = 0.3
-		}
+		score := neighborScore(relation, weight)
 		if existing, ok := scores[id]; !ok || score > existing {
 			scores[id] = score
 		}
@@ -311,40 +415,127 @@ func (d *Dash) BatchGetGraphNeighbors(ctx context.Context, nodeIDs []uuid.UUID,
 		return nil, err
 	}
 
-	// Cap at limit — keep highest-scored neighbors
-	if len(scores) > limit {
-		type entry struct {
-			id    uuid.UUID
-			score float64
-		}
-		entries := make([]entry, 0, len(scores))
-		for id, s := range scores {
-			entries = append(entries, entry{id, s})
-		}
-		sort.Slice(entries, func(i, j int) bool { return entries[i].score > entries[j].score })
-		trimmed := make(map[uuid.UUID]float64, limit)
-		for i := 0; i < limit; i++ {
-			trimmed[entries[i].id] = entries[i].score
-		}
-		scores = trimmed
+	return topNeighbors(scores, limit), nil
+}
+
+// neighborScore is the proximity a neighbor earns through one edge: a base
+// score for the relation scaled by the edge's weight.
+func neighborScore(relation string, weight float64) float64 {
+	var score float64
+	switch relation {
+	case "affects":
+		score = 0.5
+	case "depends_on":
+		score = 0.5
+	case "uses":
+		score = 0.4
+	case "implements":
+		score = 0.5
+	case "owns":
+		score = 0.4
+	default:
+		score = 0.3
 	}
+	return score * weight
+}
 
-	return scores, nil
+// topNeighbors caps scores at limit, keeping the highest-scored neighbors.
+func topNeighbors(scores map[uuid.UUID]float64, limit int) map[uuid.UUID]float64 {
+	if len(scores) <= limit {
+		return scores
+	}
+	type entry struct {
+		id    uuid.UUID
+		score float64
+	}
+	entries := make([]entry, 0, len(scores))
+	for id, s := range scores {
+		entries = append(entries, entry{id, s})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].score != entries[j].score {
+			return entries[i].score > entries[j].score
+		}
+		return entries[i].id.String() < entries[j].id.String()
+	})
+	trimmed := make(map[uuid.UUID]float64, limit)
+	for i := 0; i < limit; i++ {
+		trimmed[entries[i].id] = entries[i].score
+	}
+	return trimmed
 }
 
-// fetchPackConstraints retrieves CONTEXT.constraint nodes for inclusion in context packs.
-func (d *Dash) fetchPackConstraints(ctx context.Context) ([]ConstraintItem, error) {
-	rows, err := d.db.QueryContext(ctx, queryGetConstraints)
+const queryPackConstraints = `
+	SELECT id, layer, type, name, data, created_at, updated_at, deleted_at
+	FROM nodes
+	WHERE layer = 'CONTEXT' AND type = 'constraint'
+	  AND deleted_at IS NULL
+	ORDER BY created_at ASC`
+
+// fetchPackConstraints retrieves up to k CONTEXT.constraint nodes for a
+// context pack. With more than k constraints, the ones most similar to query
+// are kept; without an embedder the oldest k are.
+func (d *Dash) fetchPackConstraints(ctx context.Context, query string, k int) ([]ConstraintItem, error) {
+	rows, err := d.db.QueryContext(ctx, queryPackConstraints)
 	if err != nil {
 		return nil, fmt.Errorf("fetch constraints: %w", err)
 	}
-	defer rows.Close()
-
 	nodes, err := scanNodes(rows)
+	rows.Close()
 	if err != nil {
 		return nil, err
 	}
+	all := constraintItems(nodes)
+	if len(all) <= k {
+		return all, nil
+	}
 
+	var ranked []uuid.UUID
+	if results, err := d.SearchSimilarByType(ctx, query, LayerContext, "constraint", k); err == nil {
+		for _, r := range results {
+			ranked = append(ranked, r.ID)
+		}
+	}
+	return selectPackConstraints(all, ranked, k), nil
+}
+
+// selectPackConstraints returns the constraints named in ranked, in that
+// order, then fills up to k from the rest of all in its original order.
+// With no ranking it falls back to the first k of all.
+func selectPackConstraints(all []ConstraintItem, ranked []uuid.UUID, k int) []ConstraintItem {
+	if len(all) <= k {
+		return all
+	}
+	if len(ranked) == 0 {
+		return all[:k]
+	}
+	byID := make(map[uuid.UUID]ConstraintItem, len(all))
+	for _, c := range all {
+		byID[c.ID] = c
+	}
+	var selected []ConstraintItem
+	chosen := make(map[uuid.UUID]bool, k)
+	for _, id := range ranked {
+		if c, ok := byID[id]; ok && !chosen[id] && len(selected) < k {
+			selected = append(selected, c)
+			chosen[id] = true
+		}
+	}
+	for _, c := range all {
+		if len(selected) >= k {
+			break
+		}
+		if !chosen[c.ID] {
+			selected = append(selected, c)
+			chosen[c.ID] = true
+		}
+	}
+	return selected
+}
+
+// constraintItems converts constraint nodes to pack items, skipping nodes
+// without text or description.
+func constraintItems(nodes []*Node) []ConstraintItem {
 	var constraints []ConstraintItem
 	for _, n := range nodes {
 		var data map[string]any
@@ -364,7 +555,7 @@ func (d *Dash) fetchPackConstraints(ctx context.Context) ([]ConstraintItem, erro
 			Text: text,
 		})
 	}
-	return constraints, nil
+	return constraints
 }
 
 // extractSummary extracts a human-readable summary from a CONTEXT node's data.
@@ -456,16 +647,39 @@ func generateWhySelected(item PackItem) string {
 
 // AssembleContextPack builds a ranked context pack from search + activity + graph signals.
 func (d *Dash) AssembleContextPack(ctx context.Context, query string, profile RetrievalProfile, taskID *uuid.UUID) (*ContextPack, error) {
+	return d.AssembleContextPackExcluding(ctx, query, profile, taskID, PackExclude{})
+}
+
+// AssembleContextPackExcluding is AssembleContextPack with an exclusion filter.
+// Excluded nodes are dropped after search and neighbor expansion but before
+// scoring, so they never consume result slots.
+func (d *Dash) AssembleContextPackExcluding(ctx context.Context, query string, profile RetrievalProfile, taskID *uuid.UUID, exclude PackExclude) (*ContextPack, error) {
+	return d.AssembleContextPackWithOptions(ctx, query, profile, taskID, PackOptions{Exclude: exclude})
+}
+
+// AssembleContextPackWithOptions is AssembleContextPack with an exclusion
+// filter and a cap on query-relevant constraints.
+func (d *Dash) AssembleContextPackWithOptions(ctx context.Context, query string, profile RetrievalProfile, taskID *uuid.UUID, opts PackOptions) (*ContextPack, error) {
+	pack, _, err := d.assembleContextPack(ctx, query, profile, taskID, opts, false)
+	return pack, err
+}
+
+// assembleContextPack builds the pack. With explain set it also returns every
+// candidate it considered, and widens the neighbor expansion so neighbors cut
+// by the cap are scored too (they are never selected).
+func (d *Dash) assembleContextPack(ctx context.Context, query string, profile RetrievalProfile, taskID *uuid.UUID, opts PackOptions, explain bool) (*ContextPack, []PackCandidate, error) {
+	exclude := opts.Exclude
 	limit := profileLimit(profile)
 	weights := profileWeights(profile)
+	var candidates []PackCandidate
 
 	// 1. Over-fetch: get 2x results from vector search across ALL node types
 	searchResults, err := d.SearchSimilar(ctx, query, limit*2)
 	if err != nil {
-		return nil, fmt.Errorf("search: %w", err)
+		return nil, nil, fmt.Errorf("search: %w", err)
 	}
 	if len(searchResults) == 0 {
-		return &ContextPack{Profile: profile, Query: query, CreatedAt: time.Now()}, nil
+		return &ContextPack{Profile: profile, Query: query, CreatedAt: time.Now()}, nil, nil
 	}
 
 	// Build ID set for deduplication
@@ -480,10 +694,23 @@ func (d *Dash) AssembleContextPack(ctx context.Context, query string, profile Re
 		nodeIDs[i] = sr.ID
 	}
 
-	neighborScores, err := d.BatchGetGraphNeighbors(ctx, nodeIDs, limit)
+	neighborLimit := limit
+	if explain {
+		neighborLimit = explainNeighborLimit
+	}
+	neighborScores, err := d.BatchGetGraphNeighbors(ctx, nodeIDs, neighborLimit)
 	if err != nil {
 		neighborScores = make(map[uuid.UUID]float64)
 	}
+	capped := make(map[uuid.UUID]bool)
+	if explain {
+		kept := topNeighbors(neighborScores, limit)
+		for id := range neighborScores {
+			if _, ok := kept[id]; !ok {
+				capped[id] = true
+			}
+		}
+	}
 
 	// Fetch metadata for neighbors not already in results
 	var newNeighborIDs []uuid.UUID
@@ -517,6 +744,19 @@ func (d *Dash) AssembleContextPack(ctx context.Context, query string, profile Re
 		}
 	}
 
+	// Drop excluded nodes before enrichment and scoring
+	if explain {
+		for _, sr := range searchResults {
+			if exclude.excludes(sr) {
+				candidates = append(candidates, packCandidate(sr, nodeIDs, PackItem{Similarity: normalizeDistance(sr.Distance)}, DropExcluded))
+			}
+		}
+	}
+	searchResults = filterExcluded(searchResults, exclude)
+	if len(searchResults) == 0 {
+		return &ContextPack{Profile: profile, Query: query, CreatedAt: time.Now()}, candidates, nil
+	}
+
 	// Rebuild full ID list after expansion
 	allIDs := make([]uuid.UUID, len(searchResults))
 	for i, sr := range searchResults {
@@ -541,20 +781,22 @@ func (d *Dash) AssembleContextPack(ctx context.Context, query string, profile Re
 		proximity = make(map[uuid.UUID]float64)
 	}
 
-	// 5. Merge neighbor proximity scores into graph proximity
-	for id, nScore := range neighborScores {
-		if existing, ok := proximity[id]; ok {
-			if nScore > existing {
-				proximity[id] = nScore
-			}
-		} else {
-			proximity[id] = nScore
+	// For task packs, files the task itself edited recently count as
+	// close to it
+	if taskID != nil && profile == ProfileTask {
+		if edits, err := d.GetTaskEditRecency(ctx, *taskID, allIDs); err == nil {
+			mergeMaxScores(proximity, edits)
 		}
 	}
 
+	// 5. Merge neighbor proximity scores into graph proximity
+	mergeMaxScores(proximity, neighborScores)
+
 	// 6. Build PackItems with all normalized signals
 	items := make([]PackItem, 0, len(searchResults))
+	bySearchResult := make(map[uuid.UUID]*SearchResult, len(searchResults))
 	for _, sr := range searchResults {
+		bySearchResult[sr.ID] = sr
 		fa := activity[sr.ID]
 		item := PackItem{
 			ID:             sr.ID,
@@ -575,18 +817,39 @@ func (d *Dash) AssembleContextPack(ctx context.Context, query string, profile Re
 	// 7. Sort by unified score (descending)
 	sort.Slice(items, func(i, j int) bool { return items[i].Score > items[j].Score })
 
-	// 8. Trim to profile limit
-	if len(items) > limit {
-		items = items[:limit]
+	// 8. Trim to profile limit, skipping neighbors only fetched to explain
+	// and items duplicating a higher-ranked one
+	sims, err := d.batchPackSimilarities(ctx, allIDs, d.packDedupThreshold)
+	if err != nil {
+		sims = make(map[packPair]float64)
+	}
+	var selected []PackItem
+	for _, item := range items {
+		// 9. Generate WhySelected for each item
+		item.WhySelected = generateWhySelected(item)
+		drop := ""
+		switch {
+		case capped[item.ID]:
+			drop = DropNeighborCap
+		case isDuplicate(item, selected, sims, d.packDedupThreshold):
+			drop = DropDuplicate
+		case len(selected) >= limit:
+			drop = DropBelowCutoff
+		default:
+			selected = append(selected, item)
+		}
+		if explain {
+			candidates = append(candidates, packCandidate(bySearchResult[item.ID], nodeIDs, item, drop))
+		}
 	}
+	items = selected
 
-	// 9. Generate WhySelected for each item
-	for i := range items {
-		items[i].WhySelected = generateWhySelected(items[i])
+	// 10. Fetch the constraints most relevant to the query
+	maxConstraints := opts.MaxConstraints
+	if maxConstraints <= 0 {
+		maxConstraints = defaultPackConstraints
 	}
-
-	// 10. Fetch constraints
-	constraints, err := d.fetchPackConstraints(ctx)
+	constraints, err := d.fetchPackConstraints(ctx, query, maxConstraints)
 	if err != nil {
 		constraints = nil
 	}
@@ -597,7 +860,7 @@ func (d *Dash) AssembleContextPack(ctx context.Context, query string, profile Re
 		Items:       items,
 		Constraints: constraints,
 		CreatedAt:   time.Now(),
-	}, nil
+	}, candidates, nil
 }
 
 // RenderForPrompt produces a human-readable text block for system prompts.
@@ -619,7 +882,11 @@ func (cp *ContextPack) RenderForPrompt() string {
 		} else {
 			label = fmt.Sprintf("[%s.%s] %s", item.Layer, item.Type, item.Name)
 		}
-		b.WriteString(fmt.Sprintf("  - %-50s score:%.2f  \"%s\"\n", label, item.Score, item.WhySelected))
+		b.WriteString(fmt.Sprintf("  - %-50s score:%.2f  \"%s\"", label, item.Score, item.WhySelected))
+		if len(item.Queries) > 0 {
+			b.WriteString(fmt.Sprintf("  [%s]", strings.Join(item.Queries, ", ")))
+		}
+		b.WriteString("\n")
 		if item.Summary != "" {
 			b.WriteString(fmt.Sprintf("    %s\n", item.Summary))
 		}
@@ -636,6 +903,52 @@ func (cp *ContextPack) RenderForPrompt() string {
 	return b.String()
 }
 
+// RenderMarkdown produces a Markdown section for MCP clients and docs: a
+// table of items linked by node id, followed by the constraints. Use
+// RenderForPrompt for LLM prompts.
+func (cp *ContextPack) RenderMarkdown() string {
+	if cp == nil || len(cp.Items) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString("## Context Pack\n\n")
+	b.WriteString(fmt.Sprintf("_%s profile, %d results", cp.Profile, len(cp.Items)))
+	if cp.Query != "" {
+		b.WriteString(fmt.Sprintf(" for %q", cp.Query))
+	}
+	b.WriteString("_\n\n")
+
+	b.WriteString("| Name | Layer.Type | Score | Why |\n")
+	b.WriteString("|------|------------|------:|-----|\n")
+	for _, item := range cp.Items {
+		label := item.Name
+		if item.Layer == "SYSTEM" && item.Type == "file" && item.Path != "" {
+			label = item.Path
+		}
+		b.WriteString(fmt.Sprintf("| [%s](%s) | %s.%s | %.2f | %s |\n",
+			markdownCell(label), nodeLink(item.ID), item.Layer, item.Type, item.Score, markdownCell(item.WhySelected)))
+	}
+
+	if len(cp.Constraints) > 0 {
+		b.WriteString("\n### Constraints\n\n")
+		for _, c := range cp.Constraints {
+			b.WriteString(fmt.Sprintf("- [%s](%s): %s\n", markdownCell(c.Name), nodeLink(c.ID), c.Text))
+		}
+	}
+	return b.String()
+}
+
+// nodeLink is the link target used for nodes in Markdown output.
+func nodeLink(id uuid.UUID) string {
+	return "dash://node/" + id.String()
+}
+
+// markdownCell makes s safe inside a Markdown table cell or link text.
+func markdownCell(s string) string {
+	return strings.NewReplacer("\n", " ", "|", "\\|", "[", "\\[", "]", "\\]").Replace(s)
+}
+
 // ToMap returns a structured map for JSON/MCP output.
 func (cp *ContextPack) ToMap() map[string]any {
 	items := make([]map[string]any, len(cp.Items))
@@ -656,6 +969,9 @@ func (cp *ContextPack) ToMap() map[string]any {
 		if item.Summary != "" {
 			m["summary"] = item.Summary
 		}
+		if len(item.Queries) > 0 {
+			m["queries"] = item.Queries
+		}
 		items[i] = m
 	}
 
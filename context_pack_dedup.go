@@ -0,0 +1,99 @@
+package dash
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// defaultPackDedupThreshold is the embedding similarity above which two
+// same-named pack items are treated as the same concept.
+const defaultPackDedupThreshold = 0.95
+
+// packPair is an unordered pair of node IDs.
+type packPair [2]uuid.UUID
+
+func newPackPair(a, b uuid.UUID) packPair {
+	if b.String() < a.String() {
+		a, b = b, a
+	}
+	return packPair{a, b}
+}
+
+const queryPackSimilarities = `
+	SELECT a.id, b.id, 1 - (a.embedding <=> b.embedding)
+	FROM nodes a
+	JOIN nodes b ON a.id < b.id
+	WHERE a.id = ANY($1) AND b.id = ANY($1)
+	  AND a.embedding IS NOT NULL AND b.embedding IS NOT NULL
+	  AND 1 - (a.embedding <=> b.embedding) >= $2`
+
+// batchPackSimilarities returns the cosine similarity of every pair of ids
+// whose embeddings are at least minSimilarity alike. Pairs missing from the
+// map are below the threshold or lack an embedding.
+func (d *Dash) batchPackSimilarities(ctx context.Context, ids []uuid.UUID, minSimilarity float64) (map[packPair]float64, error) {
+	sims := make(map[packPair]float64)
+	if len(ids) < 2 || minSimilarity > 1 {
+		return sims, nil
+	}
+	rows, err := d.db.QueryContext(ctx, queryPackSimilarities, pq.Array(ids), minSimilarity)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var a, b uuid.UUID
+		var sim float64
+		if err := rows.Scan(&a, &b, &sim); err != nil {
+			continue
+		}
+		sims[newPackPair(a, b)] = sim
+	}
+	return sims, rows.Err()
+}
+
+// isDuplicate reports whether item duplicates one of kept: an item is a
+// duplicate only when its embedding similarity to a kept item reaches
+// threshold and both name the same thing (see samePackSubject). Either
+// signal alone is too weak: a summary is alike but adds content, and
+// unrelated nodes can share a name.
+func isDuplicate(item PackItem, kept []PackItem, sims map[packPair]float64, threshold float64) bool {
+	for i := range kept {
+		sim, ok := sims[newPackPair(item.ID, kept[i].ID)]
+		if ok && sim >= threshold && samePackSubject(item, kept[i]) {
+			return true
+		}
+	}
+	return false
+}
+
+// samePackSubject reports whether two items are near-identical by path or
+// name: the same file path, or keys equal once case and separators are
+// ignored, using a file's base name without extension ("context_pack.go"
+// vs an insight named "Context pack"). Two files at different paths are
+// never the same subject.
+func samePackSubject(a, b PackItem) bool {
+	if a.Path != "" && b.Path != "" {
+		return a.Path == b.Path
+	}
+	na, nb := subjectKey(a), subjectKey(b)
+	return na != "" && na == nb
+}
+
+func subjectKey(item PackItem) string {
+	base := item.Name
+	if item.Path != "" {
+		base = filepath.Base(item.Path)
+		base = strings.TrimSuffix(base, filepath.Ext(base))
+	}
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '-', '_', ' ', '.':
+			return -1
+		}
+		return r
+	}, strings.ToLower(base))
+}
@@ -0,0 +1,73 @@
+package dash
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// explainNeighborLimit is how many graph neighbors an explained pack scores,
+// so neighbors cut by the normal cap still show up as candidates.
+const explainNeighborLimit = 50
+
+// Reasons a candidate was left out of a context pack.
+const (
+	DropExcluded    = "excluded"     // matched PackOptions.Exclude
+	DropNeighborCap = "neighbor_cap" // graph neighbor beyond the expansion cap
+	DropDuplicate   = "duplicate"    // near-identical to a higher-ranked kept item
+	DropBelowCutoff = "below_cutoff" // scored below the profile's top-K
+)
+
+// PackCandidate is a node considered for a context pack, selected or not,
+// with every signal that went into its score.
+type PackCandidate struct {
+	PackItem
+	Source   string `json:"source"` // "search" or "neighbor"
+	Selected bool   `json:"selected"`
+	Dropped  string `json:"dropped,omitempty"` // one of the Drop* reasons
+}
+
+// ContextPackExplanation is a context pack together with the full scored
+// candidate list, ordered by score (excluded candidates first, unscored).
+type ContextPackExplanation struct {
+	Pack       *ContextPack    `json:"pack"`
+	Weights    RerankWeights   `json:"weights"`
+	Limit      int             `json:"limit"`
+	Candidates []PackCandidate `json:"candidates"`
+}
+
+// AssembleContextPackExplained builds the same pack as
+// AssembleContextPackWithOptions and also reports every candidate it
+// considered, including excluded nodes, neighbors past the expansion cap and
+// items that scored below the cutoff.
+func (d *Dash) AssembleContextPackExplained(ctx context.Context, query string, profile RetrievalProfile, taskID *uuid.UUID, opts PackOptions) (*ContextPackExplanation, error) {
+	pack, candidates, err := d.assembleContextPack(ctx, query, profile, taskID, opts, true)
+	if err != nil {
+		return nil, err
+	}
+	return &ContextPackExplanation{
+		Pack:       pack,
+		Weights:    profileWeights(profile),
+		Limit:      profileLimit(profile),
+		Candidates: candidates,
+	}, nil
+}
+
+// packCandidate describes sr with its scored item. searchIDs are the nodes
+// found by vector search; anything else came from graph expansion.
+func packCandidate(sr *SearchResult, searchIDs []uuid.UUID, item PackItem, dropped string) PackCandidate {
+	item.ID, item.Name, item.Path, item.Layer, item.Type = sr.ID, sr.Name, sr.Path, sr.Layer, sr.Type
+	source := "neighbor"
+	for _, id := range searchIDs {
+		if id == sr.ID {
+			source = "search"
+			break
+		}
+	}
+	return PackCandidate{
+		PackItem: item,
+		Source:   source,
+		Selected: dropped == "",
+		Dropped:  dropped,
+	}
+}
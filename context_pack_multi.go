@@ -0,0 +1,124 @@
+package dash
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AssembleMultiQueryPack builds one context pack for a task spanning several
+// concerns. Each query runs through the pack pipeline on its own, so one
+// embedding never blurs the concerns together. The candidates are merged
+// keeping each node's best per-query score, re-ranked, deduplicated and
+// capped at the profile limit. Items record in Queries which queries
+// surfaced them.
+func (d *Dash) AssembleMultiQueryPack(ctx context.Context, queries []string, profile RetrievalProfile, taskID *uuid.UUID) (*ContextPack, error) {
+	queries = uniqueQueries(queries)
+	if len(queries) == 0 {
+		return nil, fmt.Errorf("at least one query is required")
+	}
+
+	perQuery := make([][]PackCandidate, len(queries))
+	var constraints []ConstraintItem
+	seenConstraint := make(map[uuid.UUID]bool)
+	for i, q := range queries {
+		pack, candidates, err := d.assembleContextPack(ctx, q, profile, taskID, PackOptions{}, true)
+		if err != nil {
+			return nil, fmt.Errorf("query %q: %w", q, err)
+		}
+		perQuery[i] = candidates
+		for _, c := range pack.Constraints {
+			if !seenConstraint[c.ID] {
+				seenConstraint[c.ID] = true
+				constraints = append(constraints, c)
+			}
+		}
+	}
+	if len(constraints) > defaultPackConstraints {
+		constraints = constraints[:defaultPackConstraints]
+	}
+
+	merged := mergeQueryCandidates(queries, perQuery)
+	ids := make([]uuid.UUID, len(merged))
+	for i, item := range merged {
+		ids[i] = item.ID
+	}
+	sims, err := d.batchPackSimilarities(ctx, ids, d.packDedupThreshold)
+	if err != nil {
+		sims = make(map[packPair]float64)
+	}
+
+	limit := profileLimit(profile)
+	var items []PackItem
+	for _, item := range merged {
+		if len(items) >= limit {
+			break
+		}
+		if isDuplicate(item, items, sims, d.packDedupThreshold) {
+			continue
+		}
+		items = append(items, item)
+	}
+
+	return &ContextPack{
+		Profile:     profile,
+		Query:       strings.Join(queries, " + "),
+		Items:       items,
+		Constraints: constraints,
+		CreatedAt:   time.Now(),
+	}, nil
+}
+
+// uniqueQueries trims queries and drops blanks and repeats.
+func uniqueQueries(queries []string) []string {
+	var out []string
+	seen := make(map[string]bool)
+	for _, q := range queries {
+		q = strings.TrimSpace(q)
+		if q == "" || seen[q] {
+			continue
+		}
+		seen[q] = true
+		out = append(out, q)
+	}
+	return out
+}
+
+// mergeQueryCandidates unions the scored candidates of each query, keeping
+// the highest-scoring version of every node, and sorts by score. Candidates
+// that were never scored (excluded) or could never be selected (past the
+// neighbor cap) are skipped.
+func mergeQueryCandidates(queries []string, perQuery [][]PackCandidate) []PackItem {
+	best := make(map[uuid.UUID]PackItem)
+	surfaced := make(map[uuid.UUID][]string)
+	for i, candidates := range perQuery {
+		for _, c := range candidates {
+			if c.Dropped == DropExcluded || c.Dropped == DropNeighborCap {
+				continue
+			}
+			if qs := surfaced[c.ID]; len(qs) == 0 || qs[len(qs)-1] != queries[i] {
+				surfaced[c.ID] = append(qs, queries[i])
+			}
+			if prev, ok := best[c.ID]; !ok || c.Score > prev.Score {
+				best[c.ID] = c.PackItem
+			}
+		}
+	}
+
+	items := make([]PackItem, 0, len(best))
+	for id, item := range best {
+		item.Queries = surfaced[id]
+		items = append(items, item)
+	}
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].Score != items[j].Score {
+			return items[i].Score > items[j].Score
+		}
+		return items[i].ID.String() < items[j].ID.String()
+	})
+	return items
+}
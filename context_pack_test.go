@@ -0,0 +1,376 @@
+package dash
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestFilterExcludedTestFiles(t *testing.T) {
+	file := func(path string) *SearchResult {
+		return &SearchResult{ID: uuid.New(), Layer: "SYSTEM", Type: "file", Name: path, Path: path}
+	}
+	results := []*SearchResult{
+		file("/dash/nodes.go"),
+		file("/dash/nodes_test.go"),
+		file("/dash/cmd/cockpit/model.go"),
+		file("/dash/context_pack_test.go"),
+		{ID: uuid.New(), Layer: "CONTEXT", Type: "insight", Name: "testing is good"},
+	}
+
+	got := filterExcluded(results, PackExclude{Patterns: []string{"*_test.go"}})
+
+	want := []string{"/dash/nodes.go", "/dash/cmd/cockpit/model.go", "testing is good"}
+	if len(got) != len(want) {
+		t.Fatalf("got %d results, want %d", len(got), len(want))
+	}
+	for i, sr := range got {
+		if sr.Name != want[i] {
+			t.Errorf("result[%d] = %q, want %q", i, sr.Name, want[i])
+		}
+	}
+}
+
+func TestFilterExcludedTypes(t *testing.T) {
+	results := []*SearchResult{
+		{Layer: "CONTEXT", Type: "session", Name: "s1"},
+		{Layer: "CONTEXT", Type: "insight", Name: "i1"},
+		{Layer: "SYSTEM", Type: "file", Name: "/dash/sql/migrations/010.sql", Path: "/dash/sql/migrations/010.sql"},
+	}
+
+	got := filterExcluded(results, PackExclude{
+		Types:    []string{"CONTEXT.session"},
+		Patterns: []string{"sql/migrations/*"},
+	})
+	if len(got) != 1 || got[0].Name != "i1" {
+		t.Errorf("got %v, want only i1", got)
+	}
+
+	if got := filterExcluded(results, PackExclude{}); len(got) != len(results) {
+		t.Errorf("empty exclude dropped results: %d of %d kept", len(got), len(results))
+	}
+}
+
+func TestPackExcludePathPatterns(t *testing.T) {
+	sr := &SearchResult{Layer: "SYSTEM", Type: "file", Name: "/dash/sql/migrations/010.sql", Path: "/dash/sql/migrations/010.sql"}
+	cases := []struct {
+		pattern string
+		want    bool
+	}{
+		{"sql/migrations/*", true},
+		{"migrations/*.sql", true},
+		{"*.sql", true},
+		{"/dash/sql/migrations/*", true},
+		{"/sql/migrations/*", false},
+		{"cmd/migrations/*", false},
+		{"sql/*", false},
+	}
+	for _, c := range cases {
+		if got := (PackExclude{Patterns: []string{c.pattern}}).excludes(sr); got != c.want {
+			t.Errorf("excludes(%q) = %v, want %v", c.pattern, got, c.want)
+		}
+	}
+}
+
+func TestSelectPackConstraints(t *testing.T) {
+	var all []ConstraintItem
+	for i := 0; i < 12; i++ {
+		all = append(all, ConstraintItem{ID: uuid.New(), Name: fmt.Sprintf("c%d", i), Text: "rule"})
+	}
+
+	// Query-relevant constraints come back in similarity order, capped at k.
+	ranked := []uuid.UUID{all[7].ID, uuid.New(), all[2].ID, all[9].ID, all[4].ID}
+	got := selectPackConstraints(all, ranked, 3)
+	if len(got) != 3 || got[0].Name != "c7" || got[1].Name != "c2" || got[2].Name != "c9" {
+		t.Errorf("ranked selection = %v, want c7, c2, c9", got)
+	}
+
+	// No ranking (no embedder): first k.
+	got = selectPackConstraints(all, nil, 3)
+	if len(got) != 3 || got[0].Name != "c0" || got[2].Name != "c2" {
+		t.Errorf("fallback selection = %v, want c0..c2", got)
+	}
+
+	// Ranking that covers fewer than k: the rest fill in, in original order.
+	got = selectPackConstraints(all[:4], []uuid.UUID{all[2].ID}, 3)
+	if len(got) != 3 || got[0].Name != "c2" || got[1].Name != "c0" || got[2].Name != "c1" {
+		t.Errorf("partial ranking = %v, want c2, c0, c1", got)
+	}
+
+	// Few constraints: all of them, regardless of ranking.
+	if got := selectPackConstraints(all[:4], ranked, 5); len(got) != 4 {
+		t.Errorf("few constraints returned %d, want 4", len(got))
+	}
+}
+
+func TestRenderMarkdown(t *testing.T) {
+	cp := &ContextPack{
+		Profile: ProfileTask,
+		Query:   "render",
+		Items: []PackItem{
+			{ID: uuid.New(), Name: "context_pack.go", Path: "/dash/context_pack.go", Layer: "SYSTEM", Type: "file", Score: 0.91, WhySelected: "high similarity"},
+			{ID: uuid.New(), Name: "use a|b tables", Layer: "CONTEXT", Type: "decision", Score: 0.42, WhySelected: "recent"},
+		},
+		Constraints: []ConstraintItem{{ID: uuid.New(), Name: "no-cgo", Text: "Builds must not need cgo"}},
+	}
+
+	md := cp.RenderMarkdown()
+	for _, want := range []string{
+		"## Context Pack",
+		"| Name | Layer.Type | Score | Why |",
+		"/dash/context_pack.go",
+		`use a\|b tables`,
+		"CONTEXT.decision",
+		"dash://node/" + cp.Items[0].ID.String(),
+		"no-cgo",
+	} {
+		if !strings.Contains(md, want) {
+			t.Errorf("markdown missing %q:\n%s", want, md)
+		}
+	}
+	if (&ContextPack{}).RenderMarkdown() != "" {
+		t.Error("empty pack should render nothing")
+	}
+}
+
+func TestTopNeighborsKeepsHighestScores(t *testing.T) {
+	a, b, c := uuid.New(), uuid.New(), uuid.New()
+	scores := map[uuid.UUID]float64{a: 0.3, b: 0.5, c: 0.4}
+
+	got := topNeighbors(scores, 2)
+	if len(got) != 2 || got[b] != 0.5 || got[c] != 0.4 {
+		t.Errorf("topNeighbors = %v, want b and c", got)
+	}
+	if got := topNeighbors(scores, 5); len(got) != 3 {
+		t.Errorf("under the limit should keep all, got %d", len(got))
+	}
+}
+
+func TestNeighborScoreScalesWithWeight(t *testing.T) {
+	for _, rel := range []string{"affects", "uses", "part_of"} {
+		full := neighborScore(rel, 1.0)
+		for _, w := range []float64{0.25, 0.5, 0.8} {
+			if got := neighborScore(rel, w); math.Abs(got-full*w) > 1e-9 {
+				t.Errorf("neighborScore(%s, %v) = %v, want %v", rel, w, got, full*w)
+			}
+		}
+	}
+}
+
+func TestTaskProximityScalesWithEdgeWeight(t *testing.T) {
+	d := testDash(t)
+	ctx := context.Background()
+	prefix := fmt.Sprintf("test-weight-%d", time.Now().UnixNano())
+
+	var nodes []*Node
+	for _, name := range []string{"task", "certain", "probable"} {
+		n := &Node{Layer: LayerContext, Type: "test_node", Name: prefix + "-" + name}
+		if err := d.CreateNode(ctx, n); err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { d.SoftDeleteNode(ctx, n.ID) })
+		nodes = append(nodes, n)
+	}
+	task, certain, probable := nodes[0], nodes[1], nodes[2]
+	for _, e := range []*Edge{
+		{SourceID: task.ID, TargetID: certain.ID, Relation: RelationDependsOn},
+		{SourceID: task.ID, TargetID: probable.ID, Relation: RelationDependsOn, Weight: 0.4},
+	} {
+		if err := d.CreateEdge(ctx, e); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	prox, err := d.GetTaskProximity(ctx, task.ID, []uuid.UUID{certain.ID, probable.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if prox[certain.ID] == 0 {
+		t.Fatalf("certain edge should give proximity, got %v", prox)
+	}
+	if ratio := prox[probable.ID] / prox[certain.ID]; math.Abs(ratio-0.4) > 1e-3 {
+		t.Errorf("proximity ratio = %v, want 0.4 (%v)", ratio, prox)
+	}
+
+	neighbors, err := d.BatchGetGraphNeighbors(ctx, []uuid.UUID{task.ID}, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ratio := neighbors[probable.ID] / neighbors[certain.ID]; math.Abs(ratio-0.4) > 1e-3 {
+		t.Errorf("neighbor ratio = %v, want 0.4 (%v)", ratio, neighbors)
+	}
+}
+
+func TestTaskEditRecency(t *testing.T) {
+	d := testDash(t)
+	ctx := context.Background()
+	prefix := fmt.Sprintf("test-task-edits-%d", time.Now().UnixNano())
+
+	var nodes []*Node
+	for _, n := range []*Node{
+		{Layer: LayerContext, Type: "task", Name: prefix + "-task"},
+		{Layer: LayerSystem, Type: "file", Name: "/tmp/" + prefix + "-edited.go"},
+		{Layer: LayerSystem, Type: "file", Name: "/tmp/" + prefix + "-other.go"},
+	} {
+		if err := d.CreateNode(ctx, n); err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { d.SoftDeleteNode(ctx, n.ID) })
+		nodes = append(nodes, n)
+	}
+	task, edited, other := nodes[0], nodes[1], nodes[2]
+	if err := d.CreateEdgeEvent(ctx, &EdgeEvent{
+		SourceID: task.ID, TargetID: edited.ID,
+		Relation: EventRelationModified, Success: true, OccurredAt: time.Now(),
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	edits, err := d.GetTaskEditRecency(ctx, task.ID, []uuid.UUID{edited.ID, other.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if edits[edited.ID] < 0.99 || edits[other.ID] != 0 {
+		t.Errorf("edit recency = %v, want ~1 for the edited file only", edits)
+	}
+}
+
+func TestTaskEditedFileOutranksSemanticMatch(t *testing.T) {
+	edited, semantic := uuid.New(), uuid.New()
+	w := profileWeights(ProfileTask)
+
+	// Both files were modified recently by some session; the semantic match
+	// is the closer one and wins on similarity alone.
+	semItem := PackItem{ID: semantic, Similarity: 0.9, Recency: 1, Frequency: 0.2}
+	editItem := PackItem{ID: edited, Similarity: 0.6, Recency: 1, Frequency: 0.2}
+	if computePackScore(editItem, w) >= computePackScore(semItem, w) {
+		t.Fatal("without the task edit signal the semantic match should rank first")
+	}
+
+	// The task itself edited one of them an hour ago.
+	lastEdit := time.Now().Add(-time.Hour)
+	proximity := map[uuid.UUID]float64{semantic: 0.1}
+	mergeMaxScores(proximity, map[uuid.UUID]float64{edited: computeRecency(&lastEdit)})
+	semItem.GraphProximity = proximity[semantic]
+	editItem.GraphProximity = proximity[edited]
+	if got, sem := computePackScore(editItem, w), computePackScore(semItem, w); got <= sem {
+		t.Errorf("task-edited file should outrank the semantic match: %.3f vs %.3f", got, sem)
+	}
+}
+
+func TestCreateEdgeRejectsWeightOutOfRange(t *testing.T) {
+	d := &Dash{}
+	err := d.CreateEdge(context.Background(), &Edge{SourceID: uuid.New(), TargetID: uuid.New(), Weight: 1.5})
+	if err == nil || !strings.Contains(err.Error(), "out of range") {
+		t.Errorf("CreateEdge with weight 1.5: err = %v, want out of range", err)
+	}
+}
+
+func TestPackCandidateSource(t *testing.T) {
+	searched := &SearchResult{ID: uuid.New(), Layer: "SYSTEM", Type: "file", Name: "/a.go", Path: "/a.go"}
+	neighbor := &SearchResult{ID: uuid.New(), Layer: "CONTEXT", Type: "task", Name: "t"}
+	ids := []uuid.UUID{searched.ID}
+
+	c := packCandidate(searched, ids, PackItem{Score: 0.8}, "")
+	if c.Source != "search" || !c.Selected || c.Path != "/a.go" || c.Score != 0.8 {
+		t.Errorf("search candidate = %+v", c)
+	}
+	c = packCandidate(neighbor, ids, PackItem{}, DropNeighborCap)
+	if c.Source != "neighbor" || c.Selected || c.Dropped != DropNeighborCap {
+		t.Errorf("neighbor candidate = %+v", c)
+	}
+}
+
+func TestPackDedupKeepsOneOfEachDuplicate(t *testing.T) {
+	file := PackItem{ID: uuid.New(), Name: "/dash/context_pack.go", Path: "/dash/context_pack.go", Type: "file"}
+	summary := PackItem{ID: uuid.New(), Name: "context pack summary", Type: "insight"}
+	sameFile := PackItem{ID: uuid.New(), Name: "/dash/context_pack.go", Path: "/dash/context_pack.go", Type: "file"}
+	namesake := PackItem{ID: uuid.New(), Name: "Context-Pack", Type: "insight"}
+	otherFile := PackItem{ID: uuid.New(), Name: "/dash/cmd/context_pack.go", Path: "/dash/cmd/context_pack.go", Type: "file"}
+	unrelated := PackItem{ID: uuid.New(), Name: "token budgets", Type: "insight"}
+	lookalike := PackItem{ID: uuid.New(), Name: "context pack", Type: "decision"}
+
+	sims := map[packPair]float64{
+		newPackPair(sameFile.ID, file.ID):  0.99, // alike and same path
+		newPackPair(namesake.ID, file.ID):  0.96, // alike and same name
+		newPackPair(summary.ID, file.ID):   0.97, // alike, but a different name
+		newPackPair(otherFile.ID, file.ID): 0.98, // alike, but a different path
+		newPackPair(unrelated.ID, file.ID): 0.90,
+		// lookalike shares the name but not the embedding
+	}
+
+	ranked := []PackItem{file, summary, sameFile, namesake, otherFile, unrelated, lookalike}
+	var kept []PackItem
+	for _, item := range ranked {
+		if !isDuplicate(item, kept, sims, defaultPackDedupThreshold) {
+			kept = append(kept, item)
+		}
+	}
+
+	want := []PackItem{file, summary, otherFile, unrelated, lookalike}
+	if len(kept) != len(want) {
+		t.Fatalf("kept %d items, want %d: %+v", len(kept), len(want), kept)
+	}
+	for i, w := range want {
+		if kept[i].ID != w.ID {
+			t.Errorf("kept[%d] = %s, want %s", i, kept[i].Name, w.Name)
+		}
+	}
+
+	// A threshold above 1 disables dedup: a name match alone is not enough.
+	kept = kept[:0]
+	for _, item := range ranked {
+		if !isDuplicate(item, kept, sims, 1.1) {
+			kept = append(kept, item)
+		}
+	}
+	if len(kept) != len(ranked) {
+		t.Errorf("with embedding dedup disabled kept %d items, want %d", len(kept), len(ranked))
+	}
+}
+
+func TestMergeQueryCandidatesKeepsBestScore(t *testing.T) {
+	shared, authOnly, rateOnly, capped := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	queries := []string{"auth refactor", "rate limiting"}
+	perQuery := [][]PackCandidate{
+		{
+			{PackItem: PackItem{ID: shared, Name: "middleware.go", Score: 0.4}},
+			{PackItem: PackItem{ID: authOnly, Name: "auth.go", Score: 0.7}},
+			{PackItem: PackItem{ID: capped, Name: "far.go", Score: 0.9}, Dropped: DropNeighborCap},
+		},
+		{
+			{PackItem: PackItem{ID: shared, Name: "middleware.go", Score: 0.8}},
+			{PackItem: PackItem{ID: rateOnly, Name: "limiter.go", Score: 0.5}, Dropped: DropBelowCutoff},
+		},
+	}
+
+	got := mergeQueryCandidates(queries, perQuery)
+	if len(got) != 3 {
+		t.Fatalf("got %d items, want 3 (neighbor-capped dropped): %+v", len(got), got)
+	}
+	if got[0].ID != shared || got[0].Score != 0.8 {
+		t.Errorf("first = %s %.2f, want middleware.go at its best score 0.8", got[0].Name, got[0].Score)
+	}
+	if !reflect.DeepEqual(got[0].Queries, queries) {
+		t.Errorf("shared queries = %v, want %v", got[0].Queries, queries)
+	}
+	if got[1].ID != authOnly || !reflect.DeepEqual(got[1].Queries, []string{"auth refactor"}) {
+		t.Errorf("second = %s %v, want auth.go from auth refactor", got[1].Name, got[1].Queries)
+	}
+	if got[2].ID != rateOnly {
+		t.Errorf("third = %s, want limiter.go", got[2].Name)
+	}
+}
+
+func TestUniqueQueries(t *testing.T) {
+	got := uniqueQueries([]string{" auth ", "", "rate", "auth"})
+	if want := []string{"auth", "rate"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("uniqueQueries = %v, want %v", got, want)
+	}
+}
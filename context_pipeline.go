@@ -29,6 +29,8 @@ type SourceParams struct {
 	AgentMission string // why this agent was spawned
 	MaxItems     int
 	Format       string // "rich" | "compact"
+
+	cache *sourceCache // shared query results for one pipeline run
 }
 
 // Pipeline declares what a system prompt should contain.
@@ -42,6 +44,7 @@ type PipelineSource struct {
 	Name     string `json:"name"`
 	MaxItems int    `json:"max_items,omitempty"`
 	Format   string `json:"format,omitempty"`
+	When     string `json:"when,omitempty"` // condition gating the source, see conditionRegistry
 }
 
 // sourceRegistry maps source names to their implementations.
@@ -62,11 +65,16 @@ var sourceRegistry = map[string]SourceFunc{
 	"sibling_tasks":     srcSiblingTasks,
 	"context_pack":      srcContextPack,
 	"plan_execution":    srcPlanExecution,
+	"intent":            srcIntent,
+	// Session-resume sources
+	"diff_since_last_session": srcDiffSinceLastSession,
 	// Agent-continuous sources
 	"agent_envelope":     srcAgentEnvelope,
 	"recent_decisions":   srcRecentDecisions,
 	"pending_decisions":  srcPendingDecisions,
+	"open_questions":     srcOpenQuestions,
 	"active_agents":      srcActiveAgents,
+	"scratchpad":         srcScratchpad,
 	// Orchestrator sources
 	"work_orders":        srcWorkOrders,
 	"pipeline_status":    srcPipelineStatus,
@@ -75,30 +83,75 @@ var sourceRegistry = map[string]SourceFunc{
 
 // RunPipeline executes a pipeline and returns the concatenated text.
 func (d *Dash) RunPipeline(ctx context.Context, p Pipeline, params SourceParams) string {
+	return d.RunPipelineWithBudget(ctx, p, params, 0)
+}
+
+// ValidatePipeline returns one error per source name that is not in
+// sourceRegistry and per When condition that cannot be evaluated.
+// RunPipeline skips unknown sources silently and treats unknown conditions
+// as false.
+func ValidatePipeline(p Pipeline) []error {
+	var errs []error
+	for i, src := range p.Sources {
+		if _, ok := sourceRegistry[src.Name]; !ok {
+			errs = append(errs, fmt.Errorf("source %d: unknown source %q", i+1, src.Name))
+		}
+		if src.When != "" {
+			if err := validateCondition(src.When); err != nil {
+				errs = append(errs, fmt.Errorf("source %d: %w", i+1, err))
+			}
+		}
+	}
+	return errs
+}
+
+// pipelineTruncatedMarker is appended when RunPipelineWithBudget cuts output short.
+const pipelineTruncatedMarker = "…(context truncated)\n"
+
+// RunPipelineWithBudget executes a pipeline like RunPipeline but stops appending
+// once maxBytes is reached. Sources listed first get priority; the section that
+// crosses the budget is cut at a line boundary and followed by a truncation marker,
+// which counts against the budget. A maxBytes of zero or less means no limit.
+func (d *Dash) RunPipelineWithBudget(ctx context.Context, p Pipeline, params SourceParams, maxBytes int) string {
 	params.Ctx = ctx
 	params.D = d
+	run := newPipelineRun(params)
 	var b strings.Builder
 	for _, src := range p.Sources {
-		fn := sourceRegistry[src.Name]
-		if fn == nil {
+		section := run.render(src)
+		if section == "" {
 			continue
 		}
-		// Apply per-source overrides
-		sp := params
-		if src.MaxItems > 0 {
-			sp.MaxItems = src.MaxItems
-		}
-		if src.Format != "" {
-			sp.Format = src.Format
-		}
-		if section := fn(sp); section != "" {
+		section += "\n"
+
+		remaining := maxBytes - b.Len()
+		if maxBytes <= 0 || len(section) <= remaining {
 			b.WriteString(section)
-			b.WriteString("\n")
+			continue
 		}
+		b.WriteString(truncateAtLine(section, remaining-len(pipelineTruncatedMarker)))
+		b.WriteString(pipelineTruncatedMarker)
+		break
 	}
 	return b.String()
 }
 
+// truncateAtLine returns the longest prefix of s that fits in max bytes and
+// ends on a line boundary. Returns "" if no complete line fits.
+func truncateAtLine(s string, max int) string {
+	if max <= 0 {
+		return ""
+	}
+	if len(s) <= max {
+		return s
+	}
+	cut := strings.LastIndexByte(s[:max], '\n')
+	if cut < 0 {
+		return ""
+	}
+	return s[:cut+1]
+}
+
 // --- Source functions ---
 
 func srcHeader(p SourceParams) string {
@@ -139,12 +192,12 @@ func srcMission(p SourceParams) string {
 	if err != nil || node == nil {
 		return ""
 	}
-	data := extractNodeData(node)
+	data := NodeDataOf(node)
 	// Try statement first (mission nodes), then description
-	if stmt, ok := data["statement"].(string); ok && stmt != "" {
+	if stmt := data.String("statement"); stmt != "" {
 		return fmt.Sprintf("\nMISSION: %s\n", stmt)
 	}
-	if desc, ok := data["description"].(string); ok && desc != "" {
+	if desc := data.String("description"); desc != "" {
 		return fmt.Sprintf("\nMISSION: %s\n", desc)
 	}
 	return ""
@@ -155,33 +208,17 @@ func srcNow(p SourceParams) string {
 	if err != nil || node == nil {
 		return ""
 	}
-	data := extractNodeData(node)
+	data := NodeDataOf(node)
 
 	var b strings.Builder
-	if focus, ok := data["current_focus"].(string); ok && focus != "" {
+	if focus := data.String("current_focus"); focus != "" {
 		b.WriteString(fmt.Sprintf("\nNOW: %s\n", focus))
 	}
-	if nextSteps, ok := data["next_steps"].([]any); ok && len(nextSteps) > 0 {
-		parts := make([]string, 0, len(nextSteps))
-		for _, s := range nextSteps {
-			if str, ok := s.(string); ok {
-				parts = append(parts, str)
-			}
-		}
-		if len(parts) > 0 {
-			b.WriteString(fmt.Sprintf("NEXT: %s\n", strings.Join(parts, ", ")))
-		}
+	if next := data.StringSlice("next_steps"); len(next) > 0 {
+		b.WriteString(fmt.Sprintf("NEXT: %s\n", strings.Join(next, ", ")))
 	}
-	if blockers, ok := data["blockers"].([]any); ok && len(blockers) > 0 {
-		parts := make([]string, 0, len(blockers))
-		for _, s := range blockers {
-			if str, ok := s.(string); ok {
-				parts = append(parts, str)
-			}
-		}
-		if len(parts) > 0 {
-			b.WriteString(fmt.Sprintf("BLOCKERS: %s\n", strings.Join(parts, ", ")))
-		}
+	if blockers := data.StringSlice("blockers"); len(blockers) > 0 {
+		b.WriteString(fmt.Sprintf("BLOCKERS: %s\n", strings.Join(blockers, ", ")))
 	} else {
 		b.WriteString("BLOCKERS: none\n")
 	}
@@ -189,7 +226,7 @@ func srcNow(p SourceParams) string {
 }
 
 func srcTasks(p SourceParams) string {
-	tasks, err := p.D.GetActiveTasksWithDeps(p.Ctx)
+	tasks, err := p.activeTasks()
 	if err != nil || len(tasks) == 0 {
 		return ""
 	}
@@ -271,16 +308,11 @@ func srcConstraints(p SourceParams) string {
 }
 
 func srcInsights(p SourceParams) string {
-	nodes, err := p.D.queryMultipleNodes(p.Ctx, queryGetRecentInsights, 2*time.Second)
+	nodes, err := p.D.recentContextNodes(p.Ctx, p.MaxItems, "insight")
 	if err != nil || len(nodes) == 0 {
 		return ""
 	}
 
-	maxItems := p.MaxItems
-	if maxItems > 0 && len(nodes) > maxItems {
-		nodes = nodes[:maxItems]
-	}
-
 	var b strings.Builder
 	b.WriteString("\nINSIGHTS (recent):\n")
 	for _, n := range nodes {
@@ -298,16 +330,11 @@ func srcInsights(p SourceParams) string {
 }
 
 func srcDecisions(p SourceParams) string {
-	nodes, err := p.D.queryMultipleNodes(p.Ctx, queryGetRecentDecisions, 2*time.Second)
+	nodes, err := p.D.recentContextNodes(p.Ctx, p.MaxItems, "decision")
 	if err != nil || len(nodes) == 0 {
 		return ""
 	}
 
-	maxItems := p.MaxItems
-	if maxItems > 0 && len(nodes) > maxItems {
-		nodes = nodes[:maxItems]
-	}
-
 	var b strings.Builder
 	b.WriteString("\nDECISIONS:\n")
 	for _, n := range nodes {
@@ -370,10 +397,9 @@ func srcPromote(p SourceParams) string {
 	var b strings.Builder
 	b.WriteString("\n")
 	for _, pc := range nodes {
-		data := extractNodeData(pc)
-		eventCount, _ := data["event_count"].(float64)
+		eventCount := NodeDataOf(pc).Int("event_count")
 		ago := formatTimeAgo(pc.UpdatedAt)
-		b.WriteString(fmt.Sprintf("PROMOTE? session %s (%d events, %s)\n", pc.Name, int(eventCount), ago))
+		b.WriteString(fmt.Sprintf("PROMOTE? session %s (%d events, %s)\n", pc.Name, eventCount, ago))
 	}
 	return b.String()
 }
@@ -389,12 +415,44 @@ func srcSession(p SourceParams) string {
 	return fmt.Sprintf("\nLAST SESSION: %s, %s, %d files\n", ago, duration, prev.FilesCount)
 }
 
+// srcDiffSinceLastSession lists files modified since the previous session
+// ended, by other sessions or by hand, so a resuming agent knows what moved.
+func srcDiffSinceLastSession(p SourceParams) string {
+	prev, err := p.D.getPreviousSession(p.Ctx, p.SessionID)
+	if err != nil || prev == nil || prev.EndedAt.IsZero() {
+		return ""
+	}
+	limit := p.MaxItems
+	if limit <= 0 {
+		limit = 10
+	}
+	changes, err := p.D.getFilesChangedSince(p.Ctx, prev.EndedAt, p.SessionID, limit)
+	if err != nil || len(changes) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString("\nCHANGED SINCE LAST SESSION:\n")
+	for _, c := range changes {
+		displayPath := c.FilePath
+		if len(displayPath) > 50 {
+			displayPath = "..." + filepath.Base(displayPath)
+		}
+		line := fmt.Sprintf("- %s (%d edits, %s", displayPath, c.ModifyCount, formatTimeAgo(c.LastModified))
+		if c.SessionCount > 1 {
+			line += fmt.Sprintf(", %d sessions", c.SessionCount)
+		}
+		b.WriteString(line + ")\n")
+	}
+	return b.String()
+}
+
 func srcTaskDetail(p SourceParams) string {
 	if p.TaskName == "" {
 		return ""
 	}
 
-	allTasks, err := p.D.GetActiveTasksWithDeps(p.Ctx)
+	allTasks, err := p.activeTasks()
 	if err != nil {
 		return ""
 	}
@@ -411,8 +469,7 @@ func srcTaskDetail(p SourceParams) string {
 	}
 
 	var b strings.Builder
-	data := extractNodeData(task.Node)
-	desc, _ := data["description"].(string)
+	desc := NodeDataOf(task.Node).String("description")
 	b.WriteString(fmt.Sprintf("TASK: %s [%s]\n", task.Node.Name, task.Status))
 	if desc != "" {
 		b.WriteString(fmt.Sprintf("  %s\n", desc))
@@ -474,12 +531,70 @@ func srcSuggestionDetail(p SourceParams) string {
 	return b.String()
 }
 
+// srcIntent shows the intent governing the current task, plan, suggestion
+// or (for agents) active work order, with its alignment when known.
+func srcIntent(p SourceParams) string {
+	var subject *Node
+	switch {
+	case p.TaskName != "":
+		subject, _ = p.D.GetNodeByName(p.Ctx, LayerContext, "task", p.TaskName)
+	case p.PlanName != "":
+		subject, _ = p.D.GetNodeByName(p.Ctx, LayerContext, "plan", p.PlanName)
+	case p.SuggName != "":
+		subject, _ = p.D.GetNodeByName(p.Ctx, LayerContext, "suggestion", p.SuggName)
+	case p.AgentKey != "":
+		if wo, err := p.D.GetActiveWorkOrderForAgent(p.Ctx, p.AgentKey); err == nil && wo != nil {
+			subject = wo.Node
+		}
+	}
+	if subject == nil {
+		return ""
+	}
+	data := extractNodeData(subject)
+
+	// Suggestions carry their intent and alignment inline; others are linked by edge.
+	var intent *Node
+	alignment := -1
+	if subject.Type == "suggestion" {
+		if name := pipelineGetString(data, "intent"); name != "" {
+			intent, _ = p.D.GetNodeByName(p.Ctx, LayerContext, "intent", name)
+		}
+		if pct, ok := data["alignment_pct"].(float64); ok {
+			alignment = int(pct)
+		}
+	} else if n, err := p.D.LinkedIntent(p.Ctx, subject.ID); err == nil {
+		intent = n
+		desc := pipelineGetString(data, "description")
+		if desc == "" {
+			desc = pipelineGetString(data, "goal")
+		}
+		alignment = p.D.IntentAlignment(p.Ctx, subject.Name, desc, intent.ID)
+	}
+	if intent == nil {
+		return ""
+	}
+
+	intentData := extractNodeData(intent)
+	statement := pipelineGetString(intentData, "statement")
+	if statement == "" {
+		statement = pipelineGetString(intentData, "description")
+	}
+	if statement == "" {
+		statement = intent.Name
+	}
+
+	if alignment >= 0 {
+		return fmt.Sprintf("\nINTENT: %s (alignment: %d%%)\n", statement, alignment)
+	}
+	return fmt.Sprintf("\nINTENT: %s\n", statement)
+}
+
 func srcSiblingTasks(p SourceParams) string {
 	// Determine the intent to search for
 	var targetIntent string
 
 	if p.TaskName != "" {
-		allTasks, err := p.D.GetActiveTasksWithDeps(p.Ctx)
+		allTasks, err := p.activeTasks()
 		if err != nil {
 			return ""
 		}
@@ -503,7 +618,7 @@ func srcSiblingTasks(p SourceParams) string {
 		return ""
 	}
 
-	allTasks, err := p.D.GetActiveTasksWithDeps(p.Ctx)
+	allTasks, err := p.activeTasks()
 	if err != nil {
 		return ""
 	}
@@ -642,7 +757,11 @@ func srcPlanExecution(p SourceParams) string {
 	if len(ps.AcceptanceCriteria) > 0 {
 		b.WriteString("ACCEPTANCE CRITERIA:\n")
 		for _, ac := range ps.AcceptanceCriteria {
-			b.WriteString(fmt.Sprintf("- %s\n", ac))
+			b.WriteString(fmt.Sprintf("- %s", ac.Text))
+			if ac.TestRef != "" {
+				b.WriteString(fmt.Sprintf(" (test: %s)", ac.TestRef))
+			}
+			b.WriteString("\n")
 		}
 		b.WriteString("\n")
 	}
@@ -685,7 +804,7 @@ func srcPlanExecution(p SourceParams) string {
 
 // srcWorkOrders lists active work orders for the orchestrator.
 func srcWorkOrders(p SourceParams) string {
-	orders, err := p.D.ListActiveWorkOrders(p.Ctx)
+	orders, err := p.activeWorkOrders()
 	if err != nil || len(orders) == 0 {
 		return "\nWORK ORDERS: inga aktiva\n"
 	}
@@ -711,7 +830,7 @@ func srcWorkOrders(p SourceParams) string {
 
 // srcPipelineStatus aggregates active work orders per status as a health indicator.
 func srcPipelineStatus(p SourceParams) string {
-	orders, err := p.D.ListActiveWorkOrders(p.Ctx)
+	orders, err := p.activeWorkOrders()
 	if err != nil || len(orders) == 0 {
 		return "\nPIPELINE: idle\n"
 	}
@@ -0,0 +1,247 @@
+package dash
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+// withTestSources registers fixed-output sources for the duration of a test.
+func withTestSources(t *testing.T, sources map[string]string) {
+	t.Helper()
+	for name, out := range sources {
+		out := out
+		sourceRegistry[name] = func(SourceParams) string { return out }
+	}
+	t.Cleanup(func() {
+		for name := range sources {
+			delete(sourceRegistry, name)
+		}
+	})
+}
+
+func TestRunPipelineWithBudgetFits(t *testing.T) {
+	withTestSources(t, map[string]string{
+		"test_a": "AAA\n",
+		"test_b": "BBB\n",
+	})
+	p := Pipeline{Sources: []PipelineSource{{Name: "test_a"}, {Name: "test_b"}}}
+	d := &Dash{}
+
+	got := d.RunPipelineWithBudget(context.Background(), p, SourceParams{}, 1000)
+	want := d.RunPipeline(context.Background(), p, SourceParams{})
+	if got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestRunPipelineWithBudgetTruncates(t *testing.T) {
+	withTestSources(t, map[string]string{
+		"test_first":  "first-1\nfirst-2\n",
+		"test_second": "second-1\nsecond-2\nsecond-3\nsecond-4\nsecond-5\n",
+		"test_third":  "third\n",
+	})
+	p := Pipeline{Sources: []PipelineSource{
+		{Name: "test_first"}, {Name: "test_second"}, {Name: "test_third"},
+	}}
+	d := &Dash{}
+
+	// First section is 17 bytes (with separator); 50 leaves room for "second-1\n"
+	// plus the 23-byte marker only.
+	got := d.RunPipelineWithBudget(context.Background(), p, SourceParams{}, 50)
+
+	if !strings.HasPrefix(got, "first-1\nfirst-2\n\n") {
+		t.Errorf("first source should be kept in full, got %q", got)
+	}
+	if !strings.Contains(got, "second-1\n") || strings.Contains(got, "second-2") {
+		t.Errorf("second source should be cut after its first line, got %q", got)
+	}
+	if strings.Contains(got, "third") {
+		t.Errorf("sources after the budget should be dropped, got %q", got)
+	}
+	if !strings.HasSuffix(got, pipelineTruncatedMarker) {
+		t.Errorf("missing truncation marker, got %q", got)
+	}
+	if len(got) > 50 {
+		t.Errorf("output is %d bytes, over the 50-byte budget", len(got))
+	}
+}
+
+func TestRunPipelineWithBudgetTooSmallForLine(t *testing.T) {
+	withTestSources(t, map[string]string{
+		"test_long": "a-very-long-single-line\n",
+	})
+	p := Pipeline{Sources: []PipelineSource{{Name: "test_long"}}}
+	d := &Dash{}
+
+	got := d.RunPipelineWithBudget(context.Background(), p, SourceParams{}, 5)
+	if got != pipelineTruncatedMarker {
+		t.Errorf("got %q, want only the marker", got)
+	}
+}
+
+func TestTruncateAtLine(t *testing.T) {
+	tests := []struct {
+		in   string
+		max  int
+		want string
+	}{
+		{"abc\ndef\n", 100, "abc\ndef\n"},
+		{"abc\ndef\n", 6, "abc\n"},
+		{"abc\ndef\n", 4, "abc\n"},
+		{"abc\ndef\n", 3, ""},
+		{"abc", 0, ""},
+	}
+	for _, tt := range tests {
+		if got := truncateAtLine(tt.in, tt.max); got != tt.want {
+			t.Errorf("truncateAtLine(%q, %d) = %q, want %q", tt.in, tt.max, got, tt.want)
+		}
+	}
+}
+
+func TestValidatePipeline(t *testing.T) {
+	p := Pipeline{Sources: []PipelineSource{{Name: "header"}, {Name: "taks"}, {Name: "now"}, {Name: "insigths"}}}
+	errs := ValidatePipeline(p)
+	if len(errs) != 2 {
+		t.Fatalf("got %d errors, want 2: %v", len(errs), errs)
+	}
+	if !strings.Contains(errs[0].Error(), `"taks"`) || !strings.Contains(errs[1].Error(), `"insigths"`) {
+		t.Errorf("errors = %v", errs)
+	}
+	if errs := ValidatePipeline(Pipeline{Sources: []PipelineSource{{Name: "header"}}}); errs != nil {
+		t.Errorf("valid pipeline errors = %v", errs)
+	}
+}
+
+func TestRunPipelineWhen(t *testing.T) {
+	calls := 0
+	sourceRegistry["test_counted"] = func(SourceParams) string { calls++; return "COUNTED\n" }
+	t.Cleanup(func() { delete(sourceRegistry, "test_counted") })
+	withTestSources(t, map[string]string{
+		"test_empty":  "",
+		"test_header": "HEADER\n",
+		"test_body":   "BODY\n",
+	})
+	p := Pipeline{Sources: []PipelineSource{
+		{Name: "test_header", When: "output:test_empty"},
+		{Name: "test_body", When: "!output:test_empty"},
+		{Name: "test_header", When: "output:test_counted"},
+		{Name: "test_counted"},
+		{Name: "test_body", When: "has_session"},
+		{Name: "test_body", When: "no_such_condition"},
+	}}
+
+	got := (&Dash{}).RunPipeline(context.Background(), p, SourceParams{})
+	want := "BODY\n\nHEADER\n\nCOUNTED\n\n"
+	if got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if calls != 1 {
+		t.Errorf("source used in a condition ran %d times, want 1", calls)
+	}
+
+	got = (&Dash{}).RunPipeline(context.Background(), Pipeline{Sources: []PipelineSource{{Name: "test_body", When: "has_session"}}}, SourceParams{SessionID: "s1"})
+	if got != "BODY\n\n" {
+		t.Errorf("has_session with a session: got %q", got)
+	}
+}
+
+func TestValidatePipelineConditions(t *testing.T) {
+	p := Pipeline{Sources: []PipelineSource{
+		{Name: "tasks", When: "has_tasks"},
+		{Name: "tasks", When: "!output:session"},
+		{Name: "tasks", When: "has_taks"},
+		{Name: "tasks", When: "output:nope"},
+	}}
+	errs := ValidatePipeline(p)
+	if len(errs) != 2 {
+		t.Fatalf("got %d errors, want 2: %v", len(errs), errs)
+	}
+	if !strings.Contains(errs[0].Error(), `"has_taks"`) || !strings.Contains(errs[1].Error(), `"nope"`) {
+		t.Errorf("errors = %v", errs)
+	}
+}
+
+func TestRunPipelineCachesActiveTasks(t *testing.T) {
+	queries := 0
+	tasks := []TaskWithDeps{
+		{Node: &Node{Name: "task-a", Data: []byte(`{"description": "first"}`)}, Status: "active", Intent: "speed"},
+		{Node: &Node{Name: "task-b", Data: []byte(`{}`)}, Status: "pending", Intent: "speed"},
+	}
+	cache := &sourceCache{loadTasks: func(context.Context) ([]TaskWithDeps, error) {
+		queries++
+		return tasks, nil
+	}}
+	p := Pipeline{Sources: []PipelineSource{
+		{Name: "tasks", When: "has_tasks"},
+		{Name: "task_detail"},
+		{Name: "sibling_tasks"},
+	}}
+
+	got := (&Dash{}).RunPipeline(context.Background(), p, SourceParams{TaskName: "task-a", cache: cache})
+	for _, want := range []string{"ACTIVE:", "TASK: task-a", "SIBLING TASKS:", "task-b"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("output missing %q:\n%s", want, got)
+		}
+	}
+	// has_tasks, tasks, task_detail and sibling_tasks (twice) would otherwise
+	// issue five queries.
+	if queries != 1 {
+		t.Errorf("active tasks queried %d times, want 1", queries)
+	}
+}
+
+func TestFilesChangedSinceSkipsCurrentSession(t *testing.T) {
+	d := testDash(t)
+	ctx := context.Background()
+	prefix := fmt.Sprintf("test-changed-since-%d", time.Now().UnixNano())
+
+	create := func(n *Node) *Node {
+		t.Helper()
+		if err := d.CreateNode(ctx, n); err != nil {
+			t.Fatalf("create %s: %v", n.Name, err)
+		}
+		t.Cleanup(func() { d.SoftDeleteNode(ctx, n.ID) })
+		return n
+	}
+	modify := func(session, file *Node) {
+		t.Helper()
+		if err := d.CreateEdgeEvent(ctx, &EdgeEvent{
+			SourceID: session.ID, TargetID: file.ID,
+			Relation: EventRelationModified, Success: true, OccurredAt: time.Now(),
+		}); err != nil {
+			t.Fatalf("create edge event: %v", err)
+		}
+	}
+
+	current := create(&Node{Layer: LayerContext, Type: "session", Name: prefix + "-current"})
+	other := create(&Node{Layer: LayerContext, Type: "session", Name: prefix + "-other"})
+	mine := create(&Node{Layer: LayerSystem, Type: "file", Name: "/tmp/" + prefix + "-mine.go"})
+	theirs := create(&Node{Layer: LayerSystem, Type: "file", Name: "/tmp/" + prefix + "-theirs.go"})
+	since := time.Now().Add(-time.Second)
+	modify(current, mine)
+	modify(other, theirs)
+	modify(other, theirs)
+
+	changes, err := d.getFilesChangedSince(ctx, since, current.Name, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var found bool
+	for _, c := range changes {
+		switch c.FilePath {
+		case mine.Name:
+			t.Errorf("file changed by the current session listed: %+v", c)
+		case theirs.Name:
+			found = true
+			if c.ModifyCount != 2 || c.SessionCount != 1 {
+				t.Errorf("got %+v, want 2 edits from 1 session", c)
+			}
+		}
+	}
+	if !found {
+		t.Errorf("%s missing from %+v", theirs.Name, changes)
+	}
+}
@@ -2,11 +2,23 @@ package dash
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"path/filepath"
+	"sort"
 
 	"github.com/google/uuid"
 )
 
+// buildGateResultType is the observation type holding a work order's latest gate result.
+const buildGateResultType = "build_gate_result"
+
+// lintResultType is the per-file observation type for AST policy violations.
+const lintResultType = "lint_result"
+
+// maxDivergenceOffenders caps the worst-offender list in a DivergenceSummary.
+const maxDivergenceOffenders = 5
+
 // DivergenceCheck represents a single claim-vs-artifact verification.
 type DivergenceCheck struct {
 	Claim    string `json:"claim"`
@@ -100,12 +112,7 @@ func (d *Dash) CheckClaims(ctx context.Context, woID uuid.UUID, buildResult *Bui
 		return nil, fmt.Errorf("get work order %s: %w", woID, err)
 	}
 
-	checks := []DivergenceCheck{
-		checkTestsPass(buildResult),
-		checkFilesCreated(wo),
-		checkMerged(wo),
-		checkNoViolations(buildResult),
-	}
+	checks := claimChecks(wo, buildResult)
 
 	allMatch := true
 	for _, c := range checks {
@@ -121,3 +128,232 @@ func (d *Dash) CheckClaims(ctx context.Context, woID uuid.UUID, buildResult *Bui
 		Checks:      checks,
 	}, nil
 }
+
+// claimChecks runs all claim checks for a work order.
+func claimChecks(wo *WorkOrder, buildResult *BuildGateResult) []DivergenceCheck {
+	return []DivergenceCheck{
+		checkTestsPass(buildResult),
+		checkFilesCreated(wo),
+		checkMerged(wo),
+		checkNoViolations(buildResult),
+	}
+}
+
+// RecordBuildGateResult stores a gate result so later divergence checks can
+// verify claims against it. Best-effort: errors are ignored.
+func (d *Dash) RecordBuildGateResult(ctx context.Context, woID uuid.UUID, result *BuildGateResult) {
+	if result == nil {
+		return
+	}
+	data, err := json.Marshal(result)
+	if err != nil {
+		return
+	}
+	d.CreateObservation(ctx, &Observation{NodeID: woID, Type: buildGateResultType, Data: data})
+	d.recordFileLint(ctx, woID, result.AST)
+}
+
+// recordFileLint attaches AST violations to the SYSTEM.file node of each
+// offending file, so a file's lint history can be read without the work order.
+func (d *Dash) recordFileLint(ctx context.Context, woID uuid.UUID, ast ASTValidationResult) {
+	byFile := map[string][]ASTViolation{}
+	for _, v := range ast.Violations {
+		if v.File == "" || v.Kind == "warning" {
+			continue
+		}
+		byFile[v.File] = append(byFile[v.File], v)
+	}
+	if len(byFile) == 0 {
+		return
+	}
+
+	repoRoot := ""
+	if wo, err := d.GetWorkOrder(ctx, woID); err == nil {
+		repoRoot = wo.RepoRoot
+	}
+	for file, violations := range byFile {
+		path := file
+		if repoRoot != "" && !filepath.IsAbs(path) {
+			path = filepath.Join(repoRoot, path)
+		}
+		d.StoreObservationForNode(ctx, LayerSystem, "file", path, lintResultType, map[string]any{
+			"work_order_id": woID.String(),
+			"violations":    violations,
+		})
+	}
+}
+
+// DivergenceCheckStats counts outcomes for one claim across work orders.
+type DivergenceCheckStats struct {
+	Claim      string `json:"claim"`
+	Passed     int    `json:"passed"`
+	Diverged   int    `json:"diverged"`
+	Unverified int    `json:"unverified,omitempty"` // no build gate result recorded
+}
+
+// DivergenceOffender is a work order with one or more diverged claims.
+type DivergenceOffender struct {
+	WorkOrderID uuid.UUID `json:"work_order_id"`
+	Name        string    `json:"name"`
+	AgentKey    string    `json:"agent_key,omitempty"`
+	Diverged    []string  `json:"diverged"`
+}
+
+// DivergenceSummary aggregates claim checks across merged work orders.
+type DivergenceSummary struct {
+	Period         TimeRange              `json:"period"`
+	WorkOrders     int                    `json:"work_orders"`
+	FullyPassed    int                    `json:"fully_passed"`
+	Checks         []DivergenceCheckStats `json:"checks"`
+	WorstOffenders []DivergenceOffender   `json:"worst_offenders,omitempty"`
+}
+
+// woDivergence holds the checks run for one work order. Gate-based claims are
+// listed in unverified when no build gate result was recorded.
+type woDivergence struct {
+	wo         *WorkOrder
+	checks     []DivergenceCheck
+	unverified []string
+}
+
+// divergenceFor runs the claim checks that can be verified for wo.
+func divergenceFor(wo *WorkOrder, buildResult *BuildGateResult) woDivergence {
+	if buildResult != nil {
+		return woDivergence{wo: wo, checks: claimChecks(wo, buildResult)}
+	}
+	return woDivergence{
+		wo:         wo,
+		checks:     []DivergenceCheck{checkFilesCreated(wo), checkMerged(wo)},
+		unverified: []string{checkTestsPass(nil).Claim, checkNoViolations(nil).Claim},
+	}
+}
+
+// queryMergedWorkOrdersWithGate lists the work orders with a merged event in
+// a period, most recently merged first, each with its latest build gate result.
+const queryMergedWorkOrdersWithGate = `
+	WITH merged AS (
+		SELECT node_id, MAX(observed_at) AS merged_at
+		FROM observations
+		WHERE type = 'work_order_event'
+		  AND data->>'status' = 'merged'
+		  AND observed_at >= $1
+		  AND observed_at < $2
+		GROUP BY node_id
+	)
+	SELECT n.id, n.layer, n.type, n.name, n.data, n.created_at, n.updated_at, n.deleted_at, gate.data
+	FROM merged m
+	JOIN nodes n ON n.id = m.node_id AND n.deleted_at IS NULL
+	LEFT JOIN LATERAL (
+		SELECT o.data
+		FROM observations o
+		WHERE o.node_id = m.node_id AND o.type = 'build_gate_result'
+		ORDER BY o.observed_at DESC
+		LIMIT 1
+	) gate ON true
+	ORDER BY m.merged_at DESC
+	LIMIT 1000`
+
+// AggregateDivergence runs the claim checks over every work order merged in
+// period and reports pass/diverge counts per claim plus the worst offenders.
+func (d *Dash) AggregateDivergence(ctx context.Context, period TimeRange) (*DivergenceSummary, error) {
+	rows, err := d.db.QueryContext(ctx, queryMergedWorkOrdersWithGate, period.Start, period.End)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []woDivergence
+	for rows.Next() {
+		var gateData []byte
+		node, err := scanNode(scannerFunc(func(dest ...any) error {
+			return rows.Scan(append(dest, &gateData)...)
+		}))
+		if err != nil {
+			return nil, err
+		}
+		wo, err := parseWorkOrder(node)
+		if err != nil {
+			continue
+		}
+		var buildResult *BuildGateResult
+		if gateData != nil {
+			var r BuildGateResult
+			if json.Unmarshal(gateData, &r) == nil {
+				buildResult = &r
+			}
+		}
+		results = append(results, divergenceFor(wo, buildResult))
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	summary := summarizeDivergence(results, maxDivergenceOffenders)
+	summary.Period = period
+	return summary, nil
+}
+
+// scannerFunc adapts a function to the Scan interface taken by scanNode, so a
+// query can select node columns followed by extra ones.
+type scannerFunc func(dest ...any) error
+
+func (f scannerFunc) Scan(dest ...any) error { return f(dest...) }
+
+// summarizeDivergence tallies per-claim outcomes and ranks offenders by the
+// number of diverged claims.
+func summarizeDivergence(results []woDivergence, maxOffenders int) *DivergenceSummary {
+	summary := &DivergenceSummary{WorkOrders: len(results)}
+	stats := make(map[string]*DivergenceCheckStats)
+	var order []string
+	stat := func(claim string) *DivergenceCheckStats {
+		if s, ok := stats[claim]; ok {
+			return s
+		}
+		s := &DivergenceCheckStats{Claim: claim}
+		stats[claim] = s
+		order = append(order, claim)
+		return s
+	}
+	for _, c := range claimChecks(&WorkOrder{}, nil) {
+		stat(c.Claim)
+	}
+
+	var offenders []DivergenceOffender
+	for _, r := range results {
+		var diverged []string
+		for _, c := range r.checks {
+			if c.Match {
+				stat(c.Claim).Passed++
+			} else {
+				stat(c.Claim).Diverged++
+				diverged = append(diverged, c.Claim)
+			}
+		}
+		for _, claim := range r.unverified {
+			stat(claim).Unverified++
+		}
+		if len(diverged) == 0 {
+			summary.FullyPassed++
+			continue
+		}
+		off := DivergenceOffender{AgentKey: r.wo.AgentKey, Diverged: diverged}
+		if r.wo.Node != nil {
+			off.WorkOrderID = r.wo.Node.ID
+			off.Name = r.wo.Node.Name
+		}
+		offenders = append(offenders, off)
+	}
+
+	for _, claim := range order {
+		summary.Checks = append(summary.Checks, *stats[claim])
+	}
+
+	sort.SliceStable(offenders, func(i, j int) bool {
+		return len(offenders[i].Diverged) > len(offenders[j].Diverged)
+	})
+	if len(offenders) > maxOffenders {
+		offenders = offenders[:maxOffenders]
+	}
+	summary.WorstOffenders = offenders
+	return summary
+}
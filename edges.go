@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"encoding/json"
 	"errors"
+	"fmt"
 
 	"github.com/google/uuid"
 )
@@ -19,42 +20,42 @@ var (
 
 const (
 	queryGetEdge = `
-		SELECT id, source_id, target_id, relation, data, created_at, deprecated_at
+		SELECT id, source_id, target_id, relation, data, weight, created_at, deprecated_at
 		FROM edges
 		WHERE id = $1`
 
 	queryGetEdgeActive = `
-		SELECT id, source_id, target_id, relation, data, created_at, deprecated_at
+		SELECT id, source_id, target_id, relation, data, weight, created_at, deprecated_at
 		FROM edges
 		WHERE id = $1 AND deprecated_at IS NULL`
 
 	queryListEdgesBySource = `
-		SELECT id, source_id, target_id, relation, data, created_at, deprecated_at
+		SELECT id, source_id, target_id, relation, data, weight, created_at, deprecated_at
 		FROM edges
 		WHERE source_id = $1 AND deprecated_at IS NULL
 		ORDER BY created_at DESC`
 
 	queryListEdgesByTarget = `
-		SELECT id, source_id, target_id, relation, data, created_at, deprecated_at
+		SELECT id, source_id, target_id, relation, data, weight, created_at, deprecated_at
 		FROM edges
 		WHERE target_id = $1 AND deprecated_at IS NULL
 		ORDER BY created_at DESC`
 
 	queryListEdgesBySourceRelation = `
-		SELECT id, source_id, target_id, relation, data, created_at, deprecated_at
+		SELECT id, source_id, target_id, relation, data, weight, created_at, deprecated_at
 		FROM edges
 		WHERE source_id = $1 AND relation = $2 AND deprecated_at IS NULL
 		ORDER BY created_at DESC`
 
 	queryListEdgesBetween = `
-		SELECT id, source_id, target_id, relation, data, created_at, deprecated_at
+		SELECT id, source_id, target_id, relation, data, weight, created_at, deprecated_at
 		FROM edges
 		WHERE source_id = $1 AND target_id = $2 AND deprecated_at IS NULL
 		ORDER BY created_at DESC`
 
 	queryInsertEdge = `
-		INSERT INTO edges (source_id, target_id, relation, data)
-		VALUES ($1, $2, $3, $4)
+		INSERT INTO edges (source_id, target_id, relation, data, weight)
+		VALUES ($1, $2, $3, $4, $5)
 		RETURNING id, created_at`
 
 	queryDeprecateEdge = `
@@ -133,15 +134,22 @@ func (d *Dash) ListEdgesBetween(ctx context.Context, sourceID, targetID uuid.UUI
 	return scanEdges(rows)
 }
 
-// CreateEdge creates a new edge between two nodes.
+// CreateEdge creates a new edge between two nodes. A zero Weight is stored
+// as 1.0, a certain relation.
 func (d *Dash) CreateEdge(ctx context.Context, edge *Edge) error {
 	if edge.SourceID == edge.TargetID {
 		return ErrSelfLoop
 	}
+	if edge.Weight < 0 || edge.Weight > 1 {
+		return fmt.Errorf("edge weight %v out of range [0, 1]", edge.Weight)
+	}
 
 	if edge.Data == nil {
 		edge.Data = json.RawMessage(`{}`)
 	}
+	if edge.Weight == 0 {
+		edge.Weight = 1.0
+	}
 
 	err := d.db.QueryRowContext(
 		ctx,
@@ -150,6 +158,7 @@ func (d *Dash) CreateEdge(ctx context.Context, edge *Edge) error {
 		edge.TargetID,
 		edge.Relation,
 		edge.Data,
+		edge.Weight,
 	).Scan(&edge.ID, &edge.CreatedAt)
 
 	return err
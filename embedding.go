@@ -5,6 +5,27 @@ import (
 	"fmt"
 )
 
+// reembedBatchSize caps how many stale nodes one ReembedStale call processes.
+const reembedBatchSize = 100
+
+const (
+	queryStaleEmbeddings = `
+		SELECT id, layer, type, name, data
+		FROM nodes
+		WHERE embedding IS NOT NULL
+		  AND deleted_at IS NULL
+		  AND embedding_model IS DISTINCT FROM $1
+		ORDER BY updated_at DESC
+		LIMIT $2`
+
+	queryCountStaleEmbeddings = `
+		SELECT COUNT(*)
+		FROM nodes
+		WHERE embedding IS NOT NULL
+		  AND deleted_at IS NULL
+		  AND embedding_model IS DISTINCT FROM $1`
+)
+
 // EmbeddingClient generates vector embeddings from text.
 type EmbeddingClient interface {
 	// Embed generates a vector embedding for the given text.
@@ -12,6 +33,12 @@ type EmbeddingClient interface {
 	Embed(ctx context.Context, text string) ([]float32, error)
 }
 
+// EmbeddingModeler is implemented by embedding clients that can report the
+// model they embed with, so stored vectors can be invalidated on model change.
+type EmbeddingModeler interface {
+	EmbeddingModel() string
+}
+
 // MaxEmbeddingTextSize is the maximum text size for embedding (in bytes).
 // OpenAI embeddings have max 8191 tokens (~32KB text).
 // We use 32KB as a safe limit.
@@ -135,3 +162,80 @@ func (d *Dash) EmbedNode(ctx context.Context, node *Node) error {
 	return d.UpdateNodeEmbedding(ctx, node.ID, embedding, hash)
 }
 
+// ReembedResult summarizes a ReembedStale run.
+type ReembedResult struct {
+	Model      string   `json:"model"`
+	Reembedded int      `json:"reembedded"`
+	Skipped    int      `json:"skipped"`
+	Errors     []string `json:"errors,omitempty"`
+	Remaining  int      `json:"remaining"`
+}
+
+// ReembedStale re-embeds up to reembedBatchSize nodes whose stored embedding
+// model differs from model. An empty model means the active embedder's model;
+// a model other than the active one is rejected since vectors would be mislabeled.
+func (d *Dash) ReembedStale(ctx context.Context, model string) (*ReembedResult, error) {
+	if !d.HasRealEmbedder() {
+		return nil, ErrNoEmbedder
+	}
+	active := d.EmbeddingModel()
+	if model == "" {
+		model = active
+	}
+	if model == "" {
+		return nil, fmt.Errorf("active embedder does not report a model")
+	}
+	if model != active {
+		return nil, fmt.Errorf("model %q is not the active embedding model %q", model, active)
+	}
+
+	rows, err := d.db.QueryContext(ctx, queryStaleEmbeddings, model, reembedBatchSize)
+	if err != nil {
+		return nil, err
+	}
+	var stale []*Node
+	for rows.Next() {
+		var n Node
+		if err := rows.Scan(&n.ID, &n.Layer, &n.Type, &n.Name, &n.Data); err != nil {
+			continue
+		}
+		stale = append(stale, &n)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	result := &ReembedResult{Model: model}
+	for _, n := range stale {
+		var text string
+		if n.Layer == LayerSystem && n.Type == "file" {
+			text, _ = readFileForEmbedding(n.Name)
+		} else {
+			text = extractEmbeddableText(n)
+		}
+		if text == "" {
+			result.Skipped++
+			continue
+		}
+
+		embedding, err := d.embedder.Embed(ctx, text)
+		if err != nil || embedding == nil {
+			if err != nil {
+				result.Errors = append(result.Errors, fmt.Sprintf("%s: %v", n.Name, err))
+			}
+			result.Skipped++
+			continue
+		}
+		if err := d.UpdateNodeEmbedding(ctx, n.ID, embedding, hashContent(text)); err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("%s: %v", n.Name, err))
+			continue
+		}
+		result.Reembedded++
+	}
+
+	if err := d.db.QueryRowContext(ctx, queryCountStaleEmbeddings, model).Scan(&result.Remaining); err != nil {
+		return result, err
+	}
+	return result, nil
+}
@@ -102,10 +102,9 @@ func (e *SQLExecutor) Execute(ctx context.Context, args map[string]any) (any, er
 }
 
 func (e *SQLExecutor) executeQuery(ctx context.Context, query string, args map[string]any) (any, error) {
-	// Safety check: only allow SELECT queries
-	normalized := strings.TrimSpace(strings.ToUpper(query))
-	if !strings.HasPrefix(normalized, "SELECT") && !strings.HasPrefix(normalized, "WITH") {
-		return nil, ErrReadOnlyViolation
+	// Safety check: exactly one SELECT/WITH statement, no write keywords
+	if err := CheckReadOnlySQL(query); err != nil {
+		return nil, err
 	}
 
 	// Get timeout
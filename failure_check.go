@@ -137,6 +137,14 @@ func extractSearchPatterns(toolName string, input json.RawMessage) []string {
 		if path, ok := data["path"].(string); ok {
 			patterns = append(patterns, extractFilename(path))
 		}
+	case "WebFetch":
+		if url, ok := data["url"].(string); ok && url != "" {
+			patterns = append(patterns, url)
+		}
+	case "WebSearch":
+		if query, ok := data["query"].(string); ok && query != "" {
+			patterns = append(patterns, query)
+		}
 	}
 
 	return patterns
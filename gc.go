@@ -12,6 +12,13 @@ type GCPolicy struct {
 	SessionRetentionDays    int  `json:"session_retention_days"`    // Default 14
 	CompressedRetentionDays int  `json:"compressed_retention_days"` // Default 30
 	DryRun                  bool `json:"dry_run"`
+
+	// MinEdgeWeight deprecates active edges weighted below it. 0 disables pruning.
+	MinEdgeWeight float64 `json:"min_edge_weight,omitempty"`
+
+	// OrphanFileDays soft-deletes orphaned SYSTEM.file nodes untouched for
+	// this many days (see ListOrphanFiles). 0 disables the pass.
+	OrphanFileDays int `json:"orphan_file_days,omitempty"`
 }
 
 // GCResult contains the results of a garbage collection run.
@@ -20,6 +27,8 @@ type GCResult struct {
 	ExpiredSessions     []GCTarget  `json:"expired_sessions"`
 	ExpiredCompressed   []GCTarget  `json:"expired_compressed"`
 	TotalSoftDeleted    int         `json:"total_soft_deleted"`
+	PrunedEdges         int         `json:"pruned_edges"`
+	OrphanFiles         []GCTarget  `json:"orphan_files,omitempty"`
 }
 
 // GCTarget represents a node that was or would be garbage collected.
@@ -30,8 +39,11 @@ type GCTarget struct {
 }
 
 // RunGC performs garbage collection on old sessions.
-// It NEVER touches: insights, decisions, tasks, mission, context_frame, constraints, SYSTEM.*, AUTOMATION.*
-// It only soft-deletes sessions that are past their retention period.
+// It NEVER touches: insights, decisions, tasks, mission, context_frame, constraints, AUTOMATION.*,
+// or any SYSTEM node other than orphaned SYSTEM.file nodes.
+// It only soft-deletes sessions that are past their retention period,
+// deprecates edges below policy.MinEdgeWeight and soft-deletes orphaned
+// SYSTEM.file nodes (see ListOrphanFiles) when policy.OrphanFileDays is set.
 func (d *Dash) RunGC(ctx context.Context, policy GCPolicy) (*GCResult, error) {
 	if policy.SessionRetentionDays <= 0 {
 		policy.SessionRetentionDays = 14
@@ -125,5 +137,105 @@ func (d *Dash) RunGC(ctx context.Context, policy GCPolicy) (*GCResult, error) {
 		result.TotalSoftDeleted = len(result.ExpiredSessions) + len(result.ExpiredCompressed)
 	}
 
+	// 4. Prune low-confidence edges
+	if policy.MinEdgeWeight > 0 {
+		query := queryPruneWeakEdges
+		if policy.DryRun {
+			query = queryCountWeakEdges
+		}
+		if err := d.db.QueryRowContext(ctx, query, policy.MinEdgeWeight).Scan(&result.PrunedEdges); err != nil {
+			return nil, err
+		}
+	}
+
+	// 5. Orphaned file nodes
+	if policy.OrphanFileDays > 0 {
+		orphans, err := d.ListOrphanFiles(ctx, time.Duration(policy.OrphanFileDays)*24*time.Hour)
+		if err != nil {
+			return nil, err
+		}
+		result.OrphanFiles = orphans
+		if !policy.DryRun {
+			for _, target := range orphans {
+				if err := d.SoftDeleteNode(ctx, target.ID); err == nil {
+					result.TotalSoftDeleted++
+				}
+			}
+		} else {
+			result.TotalSoftDeleted += len(orphans)
+		}
+	}
+
 	return result, nil
 }
+
+// ListOrphanFiles returns the SYSTEM.file nodes that GCOrphanFiles would
+// delete: never embedded, never the target or source of an edge event, with
+// no active edge, and not updated within olderThan. These are left behind by files deleted
+// outside dashwatch and by paths that never got embedded.
+func (d *Dash) ListOrphanFiles(ctx context.Context, olderThan time.Duration) ([]GCTarget, error) {
+	rows, err := d.db.QueryContext(ctx, queryListOrphanFiles, time.Now().Add(-olderThan))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var targets []GCTarget
+	for rows.Next() {
+		var id uuid.UUID
+		var name string
+		var updatedAt time.Time
+		if err := rows.Scan(&id, &name, &updatedAt); err != nil {
+			continue
+		}
+		targets = append(targets, GCTarget{
+			ID:   id,
+			Name: name,
+			Age:  formatDuration(time.Since(updatedAt)),
+		})
+	}
+	return targets, rows.Err()
+}
+
+// GCOrphanFiles soft-deletes the file nodes listed by ListOrphanFiles and
+// returns how many were deleted. Use ListOrphanFiles (or RunGC with DryRun)
+// to see what would go first.
+func (d *Dash) GCOrphanFiles(ctx context.Context, olderThan time.Duration) (pruned int, err error) {
+	orphans, err := d.ListOrphanFiles(ctx, olderThan)
+	if err != nil {
+		return 0, err
+	}
+	for _, target := range orphans {
+		if err := d.SoftDeleteNode(ctx, target.ID); err == nil {
+			pruned++
+		}
+	}
+	return pruned, nil
+}
+
+const queryListOrphanFiles = `
+	SELECT n.id, n.name, n.updated_at
+	FROM nodes n
+	WHERE n.layer = 'SYSTEM' AND n.type = 'file'
+	  AND n.deleted_at IS NULL
+	  AND n.embedding IS NULL
+	  AND n.updated_at < $1
+	  AND NOT EXISTS (SELECT 1 FROM edge_events ee WHERE ee.target_id = n.id)
+	  AND NOT EXISTS (SELECT 1 FROM edge_events ee WHERE ee.source_id = n.id)
+	  AND NOT EXISTS (SELECT 1 FROM edges e WHERE e.source_id = n.id AND e.deprecated_at IS NULL)
+	  AND NOT EXISTS (SELECT 1 FROM edges e WHERE e.target_id = n.id AND e.deprecated_at IS NULL)
+	ORDER BY n.updated_at ASC`
+
+const (
+	queryCountWeakEdges = `
+		SELECT COUNT(*) FROM edges
+		WHERE deprecated_at IS NULL AND weight < $1`
+
+	queryPruneWeakEdges = `
+		WITH pruned AS (
+			UPDATE edges SET deprecated_at = NOW()
+			WHERE deprecated_at IS NULL AND weight < $1
+			RETURNING id
+		)
+		SELECT COUNT(*) FROM pruned`
+)
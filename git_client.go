@@ -28,6 +28,7 @@ type GitClient interface {
 	CheckoutBranch(name string) error
 	CommitAll(message string) error
 	CommitAllIn(dir, message string) error
+	RevertCommit(commitHash string) error
 	CurrentHash() (string, error)
 	ChangedFiles(baseBranch string) ([]string, error)
 	UnifiedDiff(baseBranch string) (string, error)
@@ -47,10 +48,17 @@ type GitClient interface {
 // ExecGitClient -- real implementation using os/exec
 // ---------------------------------------------------------------------------
 
+// dryRunMarker is passed as stderr to the logger for commands skipped in dry-run mode.
+const dryRunMarker = "[dry-run]"
+
 // ExecGitClient implements GitClient by shelling out to git and gh.
 type ExecGitClient struct {
 	repoRoot string
 	logger   func(cmd string, args []string, exitCode int, stderr string)
+
+	// DryRun skips mutating commands (branch, commit, push, merge, worktree, PR
+	// creation) and reports them to the logger instead. Reads still execute.
+	DryRun bool
 }
 
 // NewExecGitClient returns an ExecGitClient rooted at repoRoot.
@@ -67,9 +75,52 @@ func (g *ExecGitClient) SetLogger(fn func(cmd string, args []string, exitCode in
 	g.logger = fn
 }
 
+// isMutatingCommand reports whether a git/gh invocation changes repository or
+// remote state.
+func isMutatingCommand(name string, args []string) bool {
+	if name == "git" && len(args) > 2 && args[0] == "-C" {
+		args = args[2:]
+	}
+	if len(args) == 0 {
+		return false
+	}
+	switch name {
+	case "git":
+		switch args[0] {
+		case "branch", "checkout", "add", "commit", "push", "merge", "worktree",
+			"reset", "rebase", "tag", "stash", "revert":
+			return true
+		}
+	case "gh":
+		if args[0] == "pr" && len(args) > 1 {
+			switch args[1] {
+			case "create", "merge", "close", "edit":
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// skipDryRun logs and reports true when the command must not run in dry-run mode.
+func (g *ExecGitClient) skipDryRun(name string, args ...string) bool {
+	if !g.DryRun || !isMutatingCommand(name, args) {
+		return false
+	}
+	if g.logger != nil {
+		g.logger(name, args, 0, dryRunMarker)
+	}
+	return true
+}
+
 // run executes a command with Dir=repoRoot, captures stdout+stderr, logs, and
 // returns stdout bytes on success or an error containing stderr.
+// In dry-run mode, mutating commands return empty output without executing.
 func (g *ExecGitClient) run(name string, args ...string) ([]byte, error) {
+	if g.skipDryRun(name, args...) {
+		return nil, nil
+	}
+
 	cmd := exec.Command(name, args...)
 	cmd.Dir = g.repoRoot
 
@@ -127,6 +178,10 @@ func (g *ExecGitClient) CommitAll(message string) error {
 
 // CommitAllIn stages all changes and commits with the given message in the specified directory.
 func (g *ExecGitClient) CommitAllIn(dir, message string) error {
+	if g.skipDryRun("git", "-C", dir, "commit", "-m", message) {
+		return nil
+	}
+
 	addCmd := exec.Command("git", "add", "-A")
 	addCmd.Dir = dir
 	var stderr bytes.Buffer
@@ -144,6 +199,21 @@ func (g *ExecGitClient) CommitAllIn(dir, message string) error {
 	return nil
 }
 
+// RevertCommit commits the inverse of commitHash on the current branch.
+// Merge commits are reverted against their first parent.
+func (g *ExecGitClient) RevertCommit(commitHash string) error {
+	out, err := g.run("git", "cat-file", "-p", commitHash)
+	if err != nil {
+		return err
+	}
+	args := []string{"revert", "--no-edit"}
+	if strings.Count(string(out), "\nparent ") > 1 {
+		args = append(args, "-m", "1")
+	}
+	_, err = g.run("git", append(args, commitHash)...)
+	return err
+}
+
 // ShowFileAtRef returns the contents of a file at a given git ref (e.g. "main:path/to/file.go").
 func (g *ExecGitClient) ShowFileAtRef(ref, filePath string) ([]byte, error) {
 	return g.run("git", "show", ref+":"+filePath)
@@ -151,6 +221,10 @@ func (g *ExecGitClient) ShowFileAtRef(ref, filePath string) ([]byte, error) {
 
 // UpdateBranchRef force-updates a branch to point at HEAD, executed from dir.
 func (g *ExecGitClient) UpdateBranchRef(branch, dir string) error {
+	if g.skipDryRun("git", "-C", dir, "branch", "-f", branch, "HEAD") {
+		return nil
+	}
+
 	cmd := exec.Command("git", "branch", "-f", branch, "HEAD")
 	cmd.Dir = dir
 	var stderr bytes.Buffer
@@ -185,7 +259,13 @@ func (g *ExecGitClient) ChangedFiles(baseBranch string) ([]string, error) {
 
 // UnifiedDiff returns the unified diff between baseBranch and HEAD, capped at 500KB.
 func (g *ExecGitClient) UnifiedDiff(baseBranch string) (string, error) {
-	out, err := g.run("git", "diff", baseBranch+"...HEAD")
+	return g.BranchDiff(baseBranch, "HEAD")
+}
+
+// BranchDiff returns the unified diff between baseBranch and branch, capped at 500KB.
+// Unlike UnifiedDiff it does not depend on what the checkout has checked out.
+func (g *ExecGitClient) BranchDiff(baseBranch, branch string) (string, error) {
+	out, err := g.run("git", "diff", baseBranch+"..."+branch)
 	if err != nil {
 		return "", err
 	}
@@ -213,6 +293,9 @@ func (g *ExecGitClient) CreatePR(title, body, base string) (int, string, error)
 	if err != nil {
 		return 0, "", err
 	}
+	if g.DryRun {
+		return 0, "", nil
+	}
 
 	var result struct {
 		Number int    `json:"number"`
@@ -300,6 +383,7 @@ type FakeGitClient struct {
 	Files         map[string]string // filename -> content
 	BaseFiles     map[string]string // "ref:path" -> content, for ShowFileAtRef
 	Commits       []string
+	Reverted      []string          // commit hashes passed to RevertCommit
 	Worktrees     map[string]string // path -> branch
 	PRs           map[int]FakePR
 	NextPRNum     int
@@ -361,6 +445,18 @@ func (f *FakeGitClient) CommitAllIn(dir, message string) error {
 	return nil
 }
 
+func (f *FakeGitClient) RevertCommit(commitHash string) error {
+	if f.Err != nil {
+		return f.Err
+	}
+	if commitHash == "" {
+		return fmt.Errorf("no commit to revert")
+	}
+	f.Reverted = append(f.Reverted, commitHash)
+	f.Commits = append(f.Commits, fmt.Sprintf("Revert %s", commitHash))
+	return nil
+}
+
 func (f *FakeGitClient) ShowFileAtRef(ref, filePath string) ([]byte, error) {
 	if f.Err != nil {
 		return nil, f.Err
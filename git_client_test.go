@@ -2,6 +2,9 @@ package dash
 
 import (
 	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
 	"strings"
 	"testing"
 )
@@ -328,3 +331,157 @@ func containsAll(s string, subs ...string) bool {
 	}
 	return true
 }
+
+// TestExecGitClientDryRun runs a create→assign→commit sequence against a real
+// repository in dry-run mode and verifies nothing changed.
+func TestExecGitClientDryRun(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	repo := t.TempDir()
+	setup := [][]string{
+		{"init", "-q", "-b", "main"},
+		{"config", "user.email", "test@example.com"},
+		{"config", "user.name", "test"},
+		{"commit", "-q", "--allow-empty", "-m", "initial"},
+	}
+	for _, args := range setup {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = repo
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+
+	gc := NewExecGitClient(repo)
+	gc.DryRun = true
+	var skipped []string
+	gc.SetLogger(func(cmd string, args []string, exitCode int, stderr string) {
+		if stderr == dryRunMarker {
+			skipped = append(skipped, cmd+" "+strings.Join(args, " "))
+		}
+	})
+
+	before, err := gc.CurrentHash()
+	if err != nil {
+		t.Fatalf("CurrentHash: %v", err)
+	}
+
+	wtPath := filepath.Join(t.TempDir(), "wt")
+	if err := gc.CreateBranch("agent/test/dry"); err != nil {
+		t.Fatalf("CreateBranch: %v", err)
+	}
+	if err := gc.AddWorktree(wtPath, "agent/test/dry"); err != nil {
+		t.Fatalf("AddWorktree: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(repo, "new.go"), []byte("package main\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := gc.CommitAll("dry commit"); err != nil {
+		t.Fatalf("CommitAll: %v", err)
+	}
+	if err := gc.CommitAllIn(repo, "dry commit in"); err != nil {
+		t.Fatalf("CommitAllIn: %v", err)
+	}
+	if err := gc.UpdateBranchRef("agent/test/dry", repo); err != nil {
+		t.Fatalf("UpdateBranchRef: %v", err)
+	}
+	if err := gc.PushBranch("agent/test/dry"); err != nil {
+		t.Fatalf("PushBranch: %v", err)
+	}
+
+	after, err := gc.CurrentHash()
+	if err != nil {
+		t.Fatalf("CurrentHash after: %v", err)
+	}
+	if after != before {
+		t.Errorf("HEAD moved: %s → %s", before, after)
+	}
+	if _, err := os.Stat(wtPath); !os.IsNotExist(err) {
+		t.Errorf("worktree %s should not exist", wtPath)
+	}
+	out, err := exec.Command("git", "-C", repo, "branch", "--list", "agent/test/dry").Output()
+	if err != nil {
+		t.Fatalf("git branch --list: %v", err)
+	}
+	if strings.TrimSpace(string(out)) != "" {
+		t.Errorf("branch should not exist, got %q", out)
+	}
+	status, err := exec.Command("git", "-C", repo, "status", "--porcelain").Output()
+	if err != nil {
+		t.Fatalf("git status: %v", err)
+	}
+	if !strings.Contains(string(status), "?? new.go") {
+		t.Errorf("new.go should remain untracked, status = %q", status)
+	}
+
+	// branch, worktree, add+commit, commit-in, branch -f, push
+	if len(skipped) != 7 {
+		t.Errorf("skipped %d commands, want 7: %v", len(skipped), skipped)
+	}
+}
+
+// TestRevertCommit verifies the fake RevertCommit records the hash and a commit.
+func TestRevertCommit(t *testing.T) {
+	gc := NewFakeGitClient()
+	if err := gc.RevertCommit(""); err == nil {
+		t.Fatal("RevertCommit should fail without a hash")
+	}
+	if err := gc.RevertCommit("abc123"); err != nil {
+		t.Fatalf("RevertCommit: %v", err)
+	}
+	if len(gc.Reverted) != 1 || gc.Reverted[0] != "abc123" {
+		t.Errorf("Reverted = %v, want [abc123]", gc.Reverted)
+	}
+	if len(gc.Commits) != 1 || gc.Commits[0] != "Revert abc123" {
+		t.Errorf("Commits = %v, want [\"Revert abc123\"]", gc.Commits)
+	}
+}
+
+// TestWorkOrderDiffUsesBranch verifies that a work order's diff comes from
+// its branch even when the repo root has the base branch checked out.
+func TestWorkOrderDiffUsesBranch(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	repo := t.TempDir()
+	setup := [][]string{
+		{"init", "-q", "-b", "main"},
+		{"config", "user.email", "test@example.com"},
+		{"config", "user.name", "test"},
+		{"commit", "-q", "--allow-empty", "-m", "initial"},
+		{"checkout", "-q", "-b", "agent/test/diff"},
+		{"add", "feature.go"},
+		{"commit", "-q", "-m", "feature"},
+		{"checkout", "-q", "main"},
+	}
+	for _, args := range setup {
+		if args[0] == "add" {
+			if err := os.WriteFile(filepath.Join(repo, "feature.go"), []byte("package feature\n"), 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+		cmd := exec.Command("git", args...)
+		cmd.Dir = repo
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+
+	wo := &WorkOrder{
+		Node:         &Node{Name: "wo-diff"},
+		BranchName:   "agent/test/diff",
+		BaseBranch:   "main",
+		RepoRoot:     repo,
+		WorktreePath: filepath.Join(repo, "removed-worktree"),
+	}
+	diff, err := workOrderDiff(wo)
+	if err != nil {
+		t.Fatalf("workOrderDiff: %v", err)
+	}
+	if !strings.Contains(diff, "feature.go") {
+		t.Errorf("diff should contain the branch's change, got:\n%s", diff)
+	}
+}
@@ -0,0 +1,114 @@
+package dash
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+const (
+	// handoffTranscriptMax caps the transcript sent to the summarizer.
+	handoffTranscriptMax = 24000
+	// handoffTailMax caps the fallback summary when no summarizer is configured.
+	handoffTailMax = 3000
+)
+
+const handoffSummaryPrompt = `You compress an agent's conversation so a fresh session can continue the work.
+Write a compact "state so far" in the conversation's language with exactly these sections:
+DECISIONS: decisions made and facts established (bullets)
+FOCUS: what is being worked on right now, including files and next step
+OPEN QUESTIONS: unresolved questions or blockers (bullets, or "none")
+Keep it under 300 words. Do not invent anything that is not in the conversation.`
+
+// SummarizeSessionForHandoff compresses a chat session into a "state so far"
+// (decisions, current focus, open questions) to seed the next session after
+// a rotation. Without a summarizer it returns a truncated tail of the
+// conversation instead.
+func (d *Dash) SummarizeSessionForHandoff(ctx context.Context, messages []ChatMessage) (string, error) {
+	if !d.HasRealSummarizer() {
+		return handoffTail(messages, handoffTailMax), nil
+	}
+	transcript, _ := handoffTranscript(messages, handoffTranscriptMax)
+	if transcript == "" {
+		return "", nil
+	}
+	tmpl := d.GetPromptTemplate(ctx, PromptHandoffSummary)
+	summary, err := d.summarizer.Complete(withPromptTemplate(ctx, tmpl), tmpl.Text, transcript)
+	if err != nil {
+		return "", fmt.Errorf("summarize session: %w", err)
+	}
+	summary = strings.TrimSpace(summary)
+	if summary == "" {
+		return handoffTail(messages, handoffTailMax), nil
+	}
+	return summary, nil
+}
+
+// handoffLine renders one message for a transcript, or "" for messages that
+// carry nothing worth keeping.
+func handoffLine(msg ChatMessage) string {
+	switch msg.Role {
+	case "user":
+		return "USER: " + truncateString(msg.Content, 1000)
+	case "assistant":
+		text := msg.Content
+		if len(msg.ToolCalls) > 0 {
+			var names []string
+			for _, tc := range msg.ToolCalls {
+				names = append(names, tc.Function.Name)
+			}
+			text += " [tools: " + strings.Join(names, ", ") + "]"
+		}
+		if strings.TrimSpace(text) == "" {
+			return ""
+		}
+		return "ASSISTANT: " + truncateString(text, 1500)
+	case "tool":
+		prefix := "TOOL(" + msg.Name + ")"
+		if msg.ToolError {
+			prefix += " ERROR"
+		}
+		return prefix + ": " + truncateString(msg.Content, 300)
+	}
+	return ""
+}
+
+// handoffTranscript renders the messages oldest first, dropping the oldest
+// lines when the result would exceed max bytes. It also returns how many
+// lines were dropped.
+func handoffTranscript(messages []ChatMessage, max int) (string, int) {
+	var lines []string
+	size, omitted := 0, 0
+	for i := len(messages) - 1; i >= 0; i-- {
+		line := handoffLine(messages[i])
+		if line == "" {
+			continue
+		}
+		if omitted > 0 || size+len(line)+1 > max {
+			omitted++
+			continue
+		}
+		size += len(line) + 1
+		lines = append(lines, line)
+	}
+	for i, j := 0, len(lines)-1; i < j; i, j = i+1, j-1 {
+		lines[i], lines[j] = lines[j], lines[i]
+	}
+	return strings.Join(lines, "\n"), omitted
+}
+
+// handoffTail is the no-summarizer fallback: the most recent user and
+// assistant messages that fit in max bytes.
+func handoffTail(messages []ChatMessage, max int) string {
+	var convo []ChatMessage
+	for _, msg := range messages {
+		if msg.Role == "user" || msg.Role == "assistant" {
+			convo = append(convo, msg)
+		}
+	}
+	tail, omitted := handoffTranscript(convo, max)
+	if omitted > 0 {
+		tail = fmt.Sprintf("... (%d earlier messages omitted)\n", omitted) + tail
+	}
+	return tail
+}
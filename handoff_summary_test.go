@@ -0,0 +1,76 @@
+package dash
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+// fakeSummaryClient is a SummaryClient whose Complete is a fakeCompleter.
+type fakeSummaryClient struct{ fakeCompleter }
+
+func (f *fakeSummaryClient) Summarize(ctx context.Context, content, filePath string) (string, error) {
+	return "", nil
+}
+
+func handoffTestMessages() []ChatMessage {
+	return []ChatMessage{
+		{Role: "user", Content: "refactor the parser"},
+		{Role: "assistant", Content: "looking at it", ToolCalls: []ToolCallRef{{Function: ToolCallFunc{Name: "read"}}}},
+		{Role: "tool", Name: "read", Content: "package parser"},
+		{Role: "assistant", Content: "decided to split lexer.go"},
+		{Role: "user", Content: "ok, go ahead"},
+	}
+}
+
+func TestSummarizeSessionForHandoffUsesSummarizer(t *testing.T) {
+	fake := &fakeSummaryClient{fakeCompleter{responses: []string{"  DECISIONS: split lexer.go\nFOCUS: lexer\nOPEN QUESTIONS: none  "}}}
+	d := &Dash{summarizer: fake}
+
+	got, err := d.SummarizeSessionForHandoff(context.Background(), handoffTestMessages())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(got, "DECISIONS: split lexer.go") || strings.HasSuffix(got, " ") {
+		t.Errorf("summary = %q", got)
+	}
+	prompt := fake.prompts[0]
+	for _, want := range []string{"USER: refactor the parser", "[tools: read]", "TOOL(read): package parser", "USER: ok, go ahead"} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("transcript missing %q:\n%s", want, prompt)
+		}
+	}
+}
+
+func TestSummarizeSessionForHandoffFallsBackToTail(t *testing.T) {
+	d := &Dash{summarizer: &NoOpSummarizer{}}
+	got, err := d.SummarizeSessionForHandoff(context.Background(), handoffTestMessages())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(got, "TOOL(") {
+		t.Errorf("tail should skip tool results: %q", got)
+	}
+	if !strings.HasSuffix(got, "USER: ok, go ahead") {
+		t.Errorf("tail should end with the last message: %q", got)
+	}
+}
+
+func TestHandoffTailTruncates(t *testing.T) {
+	var msgs []ChatMessage
+	for i := 0; i < 50; i++ {
+		msgs = append(msgs, ChatMessage{Role: "user", Content: strings.Repeat("x", 100)})
+	}
+	msgs = append(msgs, ChatMessage{Role: "assistant", Content: "latest"})
+
+	got := handoffTail(msgs, 500)
+	if !strings.HasPrefix(got, "... (") || !strings.Contains(got, "earlier messages omitted") {
+		t.Errorf("expected omission marker, got %q", got[:min(len(got), 60)])
+	}
+	if !strings.HasSuffix(got, "ASSISTANT: latest") {
+		t.Errorf("expected newest message last, got %q", got)
+	}
+	if len(got) > 600 {
+		t.Errorf("tail too long: %d bytes", len(got))
+	}
+}
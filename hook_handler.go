@@ -3,7 +3,9 @@ package dash
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 	"time"
 
@@ -18,6 +20,15 @@ func (d *Dash) ProcessHookEvent(ctx context.Context, input []byte) (*HookOutput,
 		return nil, err
 	}
 
+	// Sessions outside the configured project roots are not recorded.
+	if !cwdAllowed(cc.Cwd, d.hookAllowedRoots) {
+		return nil, nil
+	}
+
+	if cc.SessionID != "" {
+		ctx = WithLLMSession(ctx, cc.SessionID)
+	}
+
 	switch cc.HookEventName {
 	case HookSessionStart:
 		return d.handleSessionStart(ctx, &cc)
@@ -35,6 +46,28 @@ func (d *Dash) ProcessHookEvent(ctx context.Context, input []byte) (*HookOutput,
 	}
 }
 
+// cwdAllowed reports whether cwd is one of roots or below one of them. An
+// empty roots list allows everything.
+func cwdAllowed(cwd string, roots []string) bool {
+	if len(roots) == 0 {
+		return true
+	}
+	if cwd == "" {
+		return false
+	}
+	cwd = filepath.Clean(cwd)
+	for _, root := range roots {
+		if root == "" {
+			continue
+		}
+		root = filepath.Clean(root)
+		if cwd == root || strings.HasPrefix(cwd, strings.TrimSuffix(root, string(filepath.Separator))+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
+
 func (d *Dash) handleSessionStart(ctx context.Context, cc *ClaudeCodeInput) (*HookOutput, error) {
 	now := time.Now()
 
@@ -79,11 +112,15 @@ func (d *Dash) handleSessionStart(ctx context.Context, cc *ClaudeCodeInput) (*Ho
 		return nil, err
 	}
 
-	// Generate the default prompt context and inject to stdout
-	content, _ := d.GetPrompt(ctx, "default", PromptOptions{
-		Cwd:       cc.Cwd,
-		SessionID: cc.SessionID,
-	})
+	// Drop pending tool calls that never completed (best-effort)
+	_, _ = d.SweepPendingToolUses(ctx, pendingToolUseTTL)
+
+	// Generate the project's prompt context and inject to stdout
+	opts := PromptOptions{Cwd: cc.Cwd, SessionID: cc.SessionID}
+	content, err := d.GetPrompt(ctx, d.sessionStartProfile(ctx, cc.Cwd), opts)
+	if err != nil {
+		content, _ = d.GetPrompt(ctx, "default", opts)
+	}
 	if content != "" {
 		return &HookOutput{Content: content}, nil
 	}
@@ -91,6 +128,19 @@ func (d *Dash) handleSessionStart(ctx context.Context, cc *ClaudeCodeInput) (*Ho
 	return nil, nil
 }
 
+// sessionStartProfile returns the prompt profile injected at session start
+// for cwd: the prompt_profile of the project containing cwd, or "default".
+func (d *Dash) sessionStartProfile(ctx context.Context, cwd string) string {
+	project, err := d.ProjectForCwd(ctx, cwd)
+	if err != nil {
+		return "default"
+	}
+	if profile := NodeDataOf(project).String("prompt_profile"); profile != "" {
+		return profile
+	}
+	return "default"
+}
+
 func (d *Dash) handlePreToolUse(ctx context.Context, cc *ClaudeCodeInput) (*HookOutput, error) {
 	now := time.Now()
 
@@ -121,11 +171,28 @@ func (d *Dash) handlePreToolUse(ctx context.Context, cc *ClaudeCodeInput) (*Hook
 		return nil, err
 	}
 
-	// Check for past failures (non-blocking - errors don't stop the tool)
+	// Mark the call in flight so PostToolUse can compute its duration (best-effort)
+	if cc.ToolUseID != "" {
+		_ = d.RecordPendingToolUse(ctx, cc.ToolUseID, cc.SessionID, cc.ToolName, now)
+	}
+
+	// Risk warning comes first; past failures are appended (non-blocking - errors don't stop the tool)
+	var warnings []string
+	if envelope.Normalized.Risk == RiskHigh {
+		warnings = append(warnings, riskWarning(envelope.Normalized.RiskReasons))
+	}
 	failureCheck, checkErr := d.CheckPastFailures(ctx, cc.ToolName, cc.ToolInput)
 	if checkErr == nil && failureCheck != nil && failureCheck.HasFailures {
+		warnings = append(warnings, failureCheck.Warning)
+	}
+	if cc.ToolName == "WebFetch" {
+		if note := d.priorWebFetchNotice(ctx, cc.ToolInput); note != "" {
+			warnings = append(warnings, note)
+		}
+	}
+	if len(warnings) > 0 {
 		return &HookOutput{
-			SystemMessage: failureCheck.Warning,
+			SystemMessage: strings.Join(warnings, "\n"),
 			IsJSON:        true,
 		}, nil
 	}
@@ -136,14 +203,11 @@ func (d *Dash) handlePreToolUse(ctx context.Context, cc *ClaudeCodeInput) (*Hook
 func (d *Dash) handlePostToolUse(ctx context.Context, cc *ClaudeCodeInput) error {
 	now := time.Now()
 
-	// Calculate duration from PreToolUse event
+	// Calculate duration from the pending PreToolUse entry
 	var durationMs *int
-	if cc.ToolUseID != "" {
-		preTime, _ := d.GetPreToolUseTime(ctx, cc.ToolUseID)
-		if !preTime.IsZero() {
-			ms := int(now.Sub(preTime).Milliseconds())
-			durationMs = &ms
-		}
+	if preTime := d.toolUseStartTime(ctx, cc.ToolUseID); !preTime.IsZero() {
+		ms := int(now.Sub(preTime).Milliseconds())
+		durationMs = &ms
 	}
 
 	// Capture system state
@@ -212,6 +276,14 @@ func (d *Dash) handlePostToolUse(ctx context.Context, cc *ClaudeCodeInput) error
 	envelope.SystemState = sysState
 	envelope.ProcessContext = procCtx
 	envelope.FileMetadata = fileMeta
+
+	// Summarize web results so later sessions can recall prior lookups
+	if wc := captureWebResult(cc.ToolName, cc.ToolInput, cc.ToolResponse); wc != nil {
+		envelope.Normalized.Web = wc
+		if cc.ToolName == "WebFetch" {
+			d.recordWebFetch(ctx, session, wc, now)
+		}
+	}
 	envelopeJSON, err := json.Marshal(envelope)
 	if err != nil {
 		return err
@@ -229,6 +301,13 @@ func (d *Dash) handlePostToolUse(ctx context.Context, cc *ClaudeCodeInput) error
 func (d *Dash) handlePostToolUseFailure(ctx context.Context, cc *ClaudeCodeInput) error {
 	now := time.Now()
 
+	// Calculate duration from the pending PreToolUse entry
+	var durationMs *int
+	if preTime := d.toolUseStartTime(ctx, cc.ToolUseID); !preTime.IsZero() {
+		ms := int(now.Sub(preTime).Milliseconds())
+		durationMs = &ms
+	}
+
 	// Get or create session node
 	session, err := d.GetOrCreateNode(ctx, LayerContext, "session", cc.SessionID, map[string]any{
 		"status": "active",
@@ -255,6 +334,7 @@ func (d *Dash) handlePostToolUseFailure(ctx context.Context, cc *ClaudeCodeInput
 					TargetID:   fileNode.ID,
 					Relation:   EventRelationFailedWith,
 					Success:    false,
+					DurationMs: durationMs,
 					Data:       eventData,
 					OccurredAt: now,
 				})
@@ -266,8 +346,9 @@ func (d *Dash) handlePostToolUseFailure(ctx context.Context, cc *ClaudeCodeInput
 	envelope := d.buildEnvelope(cc, "tool.failure")
 	envelope.Normalized.Subject = d.extractSubject(cc)
 	envelope.Normalized.Outcome = &Outcome{
-		Success: boolPtr(false),
-		Error:   cc.Error,
+		Success:    boolPtr(false),
+		Error:      cc.Error,
+		DurationMs: durationMs,
 	}
 	envelopeJSON, err := json.Marshal(envelope)
 	if err != nil {
@@ -335,13 +416,13 @@ func (d *Dash) handleSessionEnd(ctx context.Context, cc *ClaudeCodeInput) error
 			"score_breakdown": breakdown,
 		}
 		// Generate and auto-promote insights for high-value sessions
-		if score >= 40 {
+		if score >= autoPromoteMinRichness {
 			updates["promotion_candidate"] = true
 			if suggestions, err := d.SuggestInsights(scoreCtx, session.ID); err == nil && len(suggestions) > 0 {
 				updates["suggested_insights"] = suggestions
 				// Auto-promote: create permanent CONTEXT.insight nodes
 				promoted := d.autoPromoteInsights(scoreCtx, session.ID, suggestions)
-				updates["auto_promoted"] = promoted
+				updates["auto_promoted"] = len(promoted)
 			}
 		}
 		_ = d.UpdateNodeData(scoreCtx, session, updates)
@@ -365,10 +446,12 @@ func (d *Dash) handleSessionEnd(ctx context.Context, cc *ClaudeCodeInput) error
 
 // buildEnvelope creates a DashHookEnvelope from Claude Code input.
 func (d *Dash) buildEnvelope(cc *ClaudeCodeInput, event string) *DashHookEnvelope {
+	// Store a copy so compacting the input never affects the caller's cc.
+	stored := *cc
 	envelope := &DashHookEnvelope{
 		EnvelopeVersion: "dashhook/v1",
 		ReceivedAt:      time.Now(),
-		ClaudeCode:      cc,
+		ClaudeCode:      &stored,
 		Normalized: &NormalizedEvent{
 			Event:         event,
 			CorrelationID: cc.ToolUseID,
@@ -381,6 +464,9 @@ func (d *Dash) buildEnvelope(cc *ClaudeCodeInput, event string) *DashHookEnvelop
 			Name: cc.ToolName,
 			Kind: getToolKind(cc.ToolName),
 		}
+		envelope.Normalized.Risk, envelope.Normalized.RiskReasons = classifyToolInput(cc.ToolName, cc.ToolInput)
+		envelope.Normalized.InputBytes = len(cc.ToolInput)
+		stored.ToolInput, envelope.Normalized.InputTruncated = compactToolInput(cc.ToolName, cc.ToolInput, d.hookMaxInputBytes)
 	}
 
 	return envelope
@@ -540,10 +626,9 @@ func getToolKind(toolName string) ToolKind {
 // maybeUpdateEmbedding checks if embedding needs update and generates it async.
 // This is called in a goroutine and must not block the hook response.
 func (d *Dash) maybeUpdateEmbedding(fileNode *Node, filePath, newHash string) {
-	// Check if hash has changed
-	existingHash, _ := d.GetNodeContentHash(context.Background(), fileNode.ID)
-	if existingHash == newHash {
-		// Hash unchanged, no need to regenerate embedding
+	// Check if hash or embedding model has changed
+	if d.EmbeddingUpToDate(context.Background(), fileNode.ID, newHash) {
+		// Unchanged, no need to regenerate embedding
 		return
 	}
 
@@ -690,20 +775,85 @@ func readFileContent(path string, maxBytes int) (string, error) {
 	return string(buf[:n]), nil
 }
 
+// autoPromoteMinRichness is the richness score at which session end promotes insights.
+const autoPromoteMinRichness = 40
+
+// InsightPromotion reports a manual PromoteSessionInsights run.
+type InsightPromotion struct {
+	Session        string         `json:"session"`
+	RichnessScore  int            `json:"richness_score"`
+	AutoThreshold  int            `json:"auto_threshold"`
+	ScoreBreakdown map[string]any `json:"score_breakdown,omitempty"`
+	Suggestions    int            `json:"suggestions"`
+	Created        []string       `json:"created"`
+	Existing       []string       `json:"existing,omitempty"`
+}
+
+// PromoteSessionInsights runs insight promotion for a session regardless of
+// its richness score. sessionID is the session node's name or UUID. Safe to
+// repeat: insights whose name already exists are reported as Existing.
+func (d *Dash) PromoteSessionInsights(ctx context.Context, sessionID string) (*InsightPromotion, error) {
+	session, err := d.GetNodeByName(ctx, LayerContext, "session", sessionID)
+	if err != nil {
+		id, perr := uuid.Parse(sessionID)
+		if perr != nil {
+			return nil, fmt.Errorf("session %q not found", sessionID)
+		}
+		if session, err = d.GetNodeActive(ctx, id); err != nil || session.Type != "session" {
+			return nil, fmt.Errorf("session %q not found", sessionID)
+		}
+	}
+
+	score, breakdown, err := d.CalculateRichnessScore(ctx, session.ID)
+	if err != nil {
+		return nil, fmt.Errorf("richness score: %w", err)
+	}
+	suggestions, err := d.SuggestInsights(ctx, session.ID)
+	if err != nil {
+		return nil, fmt.Errorf("suggest insights: %w", err)
+	}
+
+	result := &InsightPromotion{
+		Session:        session.Name,
+		RichnessScore:  score,
+		AutoThreshold:  autoPromoteMinRichness,
+		ScoreBreakdown: breakdown,
+		Suggestions:    len(suggestions),
+		Created:        []string{},
+	}
+	created := make(map[string]bool)
+	for _, n := range d.autoPromoteInsights(ctx, session.ID, suggestions) {
+		result.Created = append(result.Created, n.Name)
+		created[n.Name] = true
+	}
+	for _, s := range suggestions {
+		name := insightName(s["text"])
+		if name != "" && !created[name] {
+			result.Existing = append(result.Existing, name)
+		}
+	}
+	return result, nil
+}
+
+// insightName derives the CONTEXT.insight node name from suggestion text.
+func insightName(text string) string {
+	if len(text) > 255 {
+		return text[:252] + "..."
+	}
+	return text
+}
+
 // autoPromoteInsights creates permanent CONTEXT.insight nodes from session suggestions.
-// Returns the number of successfully promoted insights.
-func (d *Dash) autoPromoteInsights(ctx context.Context, sessionID uuid.UUID, suggestions []map[string]string) int {
-	promoted := 0
+// Returns the successfully promoted insight nodes.
+func (d *Dash) autoPromoteInsights(ctx context.Context, sessionID uuid.UUID, suggestions []map[string]string) []*Node {
+	var promoted []*Node
 	for _, s := range suggestions {
 		text := s["text"]
 		if text == "" {
 			continue
 		}
 
-		name := text
-		if len(name) > 255 {
-			name = name[:252] + "..."
-		}
+		name := insightName(text)
 
 		// Skip if insight with this name already exists
 		if existing, _ := d.GetNodeByName(ctx, LayerContext, "insight", name); existing != nil {
@@ -740,7 +890,7 @@ func (d *Dash) autoPromoteInsights(ctx context.Context, sessionID uuid.UUID, sug
 			TargetID: sessionID,
 			Relation: RelationDerivedFrom,
 		})
-		promoted++
+		promoted = append(promoted, node)
 	}
 	return promoted
 }
@@ -0,0 +1,201 @@
+package dash
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCwdAllowed(t *testing.T) {
+	roots := []string{"/work/dash", "/srv/projects/"}
+	tests := []struct {
+		cwd  string
+		want bool
+	}{
+		{"/work/dash", true},
+		{"/work/dash/cmd/cockpit", true},
+		{"/srv/projects/x", true},
+		{"/work/dashboard", false},
+		{"/work", false},
+		{"/tmp", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := cwdAllowed(tt.cwd, roots); got != tt.want {
+			t.Errorf("cwdAllowed(%q) = %v, want %v", tt.cwd, got, tt.want)
+		}
+	}
+
+	if !cwdAllowed("/anywhere", nil) || !cwdAllowed("", nil) {
+		t.Error("empty allowlist should allow every cwd")
+	}
+	if !cwdAllowed("/tmp/x", []string{"/"}) {
+		t.Error("root allowlist should allow every absolute cwd")
+	}
+}
+
+func TestProcessHookEventOutsideAllowlist(t *testing.T) {
+	d := testDash(t)
+	ctx := context.Background()
+	d.hookAllowedRoots = []string{"/allowed/project"}
+
+	sessionID := fmt.Sprintf("test-hook-allowlist-%d", time.Now().UnixNano())
+	input, _ := json.Marshal(ClaudeCodeInput{
+		SessionID:     sessionID,
+		HookEventName: HookSessionStart,
+		Cwd:           "/elsewhere/project",
+	})
+
+	out, err := d.ProcessHookEvent(ctx, input)
+	if err != nil || out != nil {
+		t.Fatalf("ProcessHookEvent = %v, %v; want nil, nil", out, err)
+	}
+	if node, err := d.GetNodeByName(ctx, LayerContext, "session", sessionID); err == nil {
+		d.SoftDeleteNode(ctx, node.ID)
+		t.Fatal("session outside the allowlist was recorded")
+	}
+}
+
+func TestSessionStartProfileFromProject(t *testing.T) {
+	d := testDash(t)
+	ctx := context.Background()
+	root := fmt.Sprintf("/tmp/test-project-profile-%d", time.Now().UnixNano())
+
+	for _, p := range []struct{ name, path, profile string }{
+		{"outer", root, ""},
+		{"inner", root + "/svc", "agent-continuous"},
+	} {
+		node, err := d.GetOrCreateNode(ctx, LayerContext, "project", filepath.Base(root)+"-"+p.name, map[string]any{
+			"path":           p.path,
+			"prompt_profile": p.profile,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { d.SoftDeleteNode(ctx, node.ID) })
+	}
+
+	cases := []struct{ cwd, want string }{
+		{root + "/svc", "agent-continuous"},
+		{root + "/svc/cmd/api", "agent-continuous"},
+		{root + "/svcx", "default"}, // outer project without an override
+		{root, "default"},
+		{"/nowhere/" + filepath.Base(root), "default"},
+	}
+	for _, c := range cases {
+		if got := d.sessionStartProfile(ctx, c.cwd); got != c.want {
+			t.Errorf("sessionStartProfile(%s) = %q, want %q", c.cwd, got, c.want)
+		}
+	}
+}
+
+func TestBuildEnvelopeCompactsLargeInput(t *testing.T) {
+	d := &Dash{hookMaxInputBytes: 1024}
+
+	small := &ClaudeCodeInput{ToolName: "Bash", ToolInput: json.RawMessage(`{"command":"ls -la"}`)}
+	env := d.buildEnvelope(small, "tool.pre")
+	if env.Normalized.InputTruncated || string(env.ClaudeCode.ToolInput) != `{"command":"ls -la"}` {
+		t.Errorf("small input changed: %s", env.ClaudeCode.ToolInput)
+	}
+	if env.Normalized.InputBytes != len(small.ToolInput) {
+		t.Errorf("InputBytes = %d, want %d", env.Normalized.InputBytes, len(small.ToolInput))
+	}
+
+	body := strings.Repeat("package main\n", 500)
+	write, _ := json.Marshal(map[string]any{"file_path": "/dash/big.go", "content": body})
+	cc := &ClaudeCodeInput{ToolName: "Write", ToolInput: write}
+	env = d.buildEnvelope(cc, "tool.post")
+	if !env.Normalized.InputTruncated || env.Normalized.InputBytes != len(write) {
+		t.Fatalf("truncated=%v bytes=%d, want true and %d", env.Normalized.InputTruncated, env.Normalized.InputBytes, len(write))
+	}
+	if string(cc.ToolInput) != string(write) {
+		t.Error("caller's ToolInput was modified")
+	}
+	var stored map[string]any
+	if err := json.Unmarshal(env.ClaudeCode.ToolInput, &stored); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := stored["content"]; ok {
+		t.Error("Write content stored verbatim")
+	}
+	if stored["file_path"] != "/dash/big.go" || stored["content_bytes"] != float64(len(body)) || stored["content_sha256"] == "" {
+		t.Errorf("stored Write input = %v", stored)
+	}
+	if stored["truncated"] != true || stored["original_bytes"] != float64(len(write)) {
+		t.Errorf("missing truncation markers: %v", stored)
+	}
+
+	heredoc, _ := json.Marshal(map[string]any{"command": "cat <<EOF\n" + body + "EOF"})
+	env = d.buildEnvelope(&ClaudeCodeInput{ToolName: "Bash", ToolInput: heredoc}, "tool.post")
+	if len(env.ClaudeCode.ToolInput) > 1024 || !strings.HasPrefix(jsonStringField(t, env.ClaudeCode.ToolInput, "command"), "cat <<EOF") {
+		t.Errorf("Bash input not truncated to the cap: %d bytes", len(env.ClaudeCode.ToolInput))
+	}
+}
+
+func jsonStringField(t *testing.T, raw json.RawMessage, key string) string {
+	t.Helper()
+	var m map[string]any
+	if err := json.Unmarshal(raw, &m); err != nil {
+		t.Fatal(err)
+	}
+	s, _ := m[key].(string)
+	return s
+}
+
+func TestCaptureWebFetch(t *testing.T) {
+	input := json.RawMessage(`{"url":"https://go.dev/doc/effective_go","prompt":"summarize"}`)
+	resp, _ := json.Marshal(map[string]any{
+		"code":   200,
+		"result": "# Effective Go\n\nThis document gives tips   for writing\nclear, idiomatic Go code.",
+	})
+	wc := captureWebResult("WebFetch", input, resp)
+	if wc == nil {
+		t.Fatal("no capture for WebFetch")
+	}
+	if wc.URL != "https://go.dev/doc/effective_go" || wc.Title != "Effective Go" {
+		t.Errorf("url=%q title=%q", wc.URL, wc.Title)
+	}
+	if !strings.HasPrefix(wc.Summary, "# Effective Go This document gives tips for writing") {
+		t.Errorf("summary = %q", wc.Summary)
+	}
+
+	long, _ := json.Marshal(strings.Repeat("word ", webSummaryChars))
+	if wc := captureWebResult("WebFetch", input, long); len(wc.Summary) > webSummaryChars+3 {
+		t.Errorf("summary not capped: %d bytes", len(wc.Summary))
+	}
+	binary, _ := json.Marshal("PNG\x00\x01\x02")
+	if wc := captureWebResult("WebFetch", input, binary); wc.Skipped != "binary" || wc.Summary != "" {
+		t.Errorf("binary capture = %+v", wc)
+	}
+	huge := json.RawMessage(`"` + strings.Repeat("a", maxWebResponseBytes) + `"`)
+	if wc := captureWebResult("WebFetch", input, huge); wc.Skipped != "too_large" || wc.URL == "" {
+		t.Errorf("huge capture = %+v", wc)
+	}
+	if wc := captureWebResult("Read", json.RawMessage(`{"file_path":"/x"}`), nil); wc != nil {
+		t.Errorf("non-web tool captured: %+v", wc)
+	}
+}
+
+func TestCaptureWebSearchTopURLs(t *testing.T) {
+	input := json.RawMessage(`{"query":"pgvector hnsw"}`)
+	resp := json.RawMessage(`{"query":"pgvector hnsw","results":[{"content":[
+		{"title":"a","url":"https://github.com/pgvector/pgvector"},
+		{"title":"b","url":"https://example.com/hnsw."},
+		{"title":"a again","url":"https://github.com/pgvector/pgvector"}]}]}`)
+	wc := captureWebResult("WebSearch", input, resp)
+	want := []string{"https://github.com/pgvector/pgvector", "https://example.com/hnsw"}
+	if wc == nil || wc.Query != "pgvector hnsw" || !reflect.DeepEqual(wc.URLs, want) {
+		t.Errorf("capture = %+v, want urls %v", wc, want)
+	}
+}
+
+func TestWebTitleFromHTML(t *testing.T) {
+	if got := webTitle("<html><head><title> Docs \n</title></head>"); got != "Docs" {
+		t.Errorf("webTitle = %q, want Docs", got)
+	}
+}
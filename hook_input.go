@@ -0,0 +1,52 @@
+package dash
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+)
+
+// defaultHookMaxInputBytes is the tool input size above which hook events
+// store a compacted copy of the input instead of the verbatim one.
+const defaultHookMaxInputBytes = 8 * 1024
+
+// compactToolInput returns input unchanged when it fits in maxBytes.
+// Larger inputs are shrunk field by field so lookups on file_path, command
+// and path keep working: Write content is replaced by its sha256 and byte
+// length, and every other string longer than half the cap is truncated.
+// The result carries truncated: true and the original byte length.
+func compactToolInput(toolName string, input json.RawMessage, maxBytes int) (json.RawMessage, bool) {
+	if len(input) <= maxBytes {
+		return input, false
+	}
+
+	var fields map[string]any
+	if err := json.Unmarshal(input, &fields); err != nil || fields == nil {
+		out, _ := json.Marshal(map[string]any{
+			"truncated":      true,
+			"original_bytes": len(input),
+			"preview":        truncateString(string(input), maxBytes/2),
+		})
+		return out, true
+	}
+
+	if content, ok := fields["content"].(string); ok && toolName == "Write" {
+		sum := sha256.Sum256([]byte(content))
+		delete(fields, "content")
+		fields["content_sha256"] = hex.EncodeToString(sum[:])
+		fields["content_bytes"] = len(content)
+	}
+	for k, v := range fields {
+		if s, ok := v.(string); ok && len(s) > maxBytes/2 {
+			fields[k] = truncateString(s, maxBytes/2)
+		}
+	}
+	fields["truncated"] = true
+	fields["original_bytes"] = len(input)
+
+	out, err := json.Marshal(fields)
+	if err != nil {
+		return input, false
+	}
+	return out, true
+}
@@ -0,0 +1,99 @@
+package dash
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// RiskHigh tags a shell command as potentially destructive or irreversible.
+const RiskHigh = "high"
+
+// riskPattern is a named check that marks a shell command as high risk.
+type riskPattern struct {
+	name  string
+	match func(cmd string) bool
+}
+
+func reMatch(expr string) func(string) bool {
+	return regexp.MustCompile(expr).MatchString
+}
+
+// highRiskPatterns lists shell commands tagged RiskHigh. Add new entries here.
+var highRiskPatterns = []riskPattern{
+	{"rm -rf", isRecursiveForceRm},
+	{"git push --force", reMatch(`\bgit\s+(\S+\s+)*push\b.*\s(--force|-f|--force-with-lease)\b`)},
+	{"git reset --hard", reMatch(`\bgit\s+(\S+\s+)*reset\b.*\s--hard\b`)},
+	{"git clean -f", reMatch(`\bgit\s+(\S+\s+)*clean\b.*\s-[a-zA-Z]*f`)},
+	{"DROP TABLE", reMatch(`(?i)\bdrop\s+(table|database|schema)\b`)},
+	{"TRUNCATE TABLE", reMatch(`(?i)\btruncate\s+table\b`)},
+	{"DELETE without WHERE", reMatch(`(?i)\bdelete\s+from\s+[\w.]+\s*(;|$|"|')`)},
+	{"curl | sh", reMatch(`\b(curl|wget)\b[^|]*\|\s*(sudo\s+)?(sh|bash|zsh)\b`)},
+	{"mkfs/dd to device", reMatch(`\bmkfs(\.\w+)?\b|\bdd\b.*\bof=/dev/`)},
+	{"chmod -R 777", reMatch(`\bchmod\s+-R\s+0?777\b`)},
+}
+
+// isRecursiveForceRm reports whether any rm invocation in cmd has both a
+// recursive and a force flag, in any combination (-rf, -fr, -r -f, --recursive --force).
+func isRecursiveForceRm(cmd string) bool {
+	fields := strings.Fields(cmd)
+	for i, f := range fields {
+		if f != "rm" && !strings.HasSuffix(f, "/rm") {
+			continue
+		}
+		var recursive, force bool
+		for _, arg := range fields[i+1:] {
+			if !strings.HasPrefix(arg, "-") {
+				break
+			}
+			switch {
+			case arg == "--recursive":
+				recursive = true
+			case arg == "--force":
+				force = true
+			case !strings.HasPrefix(arg, "--"):
+				recursive = recursive || strings.ContainsAny(arg, "rR")
+				force = force || strings.Contains(arg, "f")
+			}
+		}
+		if recursive && force {
+			return true
+		}
+	}
+	return false
+}
+
+// classifyCommand returns RiskHigh and the matched pattern names when cmd
+// matches a high-risk pattern, or "" and nil otherwise.
+func classifyCommand(cmd string) (string, []string) {
+	var reasons []string
+	for _, p := range highRiskPatterns {
+		if p.match(cmd) {
+			reasons = append(reasons, p.name)
+		}
+	}
+	if len(reasons) == 0 {
+		return "", nil
+	}
+	return RiskHigh, reasons
+}
+
+// classifyToolInput classifies the command of a Bash tool call.
+func classifyToolInput(toolName string, input json.RawMessage) (string, []string) {
+	if toolName != "Bash" || input == nil {
+		return "", nil
+	}
+	var args struct {
+		Command string `json:"command"`
+	}
+	if err := json.Unmarshal(input, &args); err != nil || args.Command == "" {
+		return "", nil
+	}
+	return classifyCommand(args.Command)
+}
+
+// riskWarning formats the pre-hook warning for a high-risk command.
+func riskWarning(reasons []string) string {
+	return fmt.Sprintf("⚠ High-risk command (%s). Double-check before running.", strings.Join(reasons, ", "))
+}
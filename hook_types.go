@@ -79,6 +79,13 @@ type NormalizedEvent struct {
 	Subject       *SubjectRef `json:"subject,omitempty"`
 	Tool          *ToolRef    `json:"tool,omitempty"`
 	Outcome       *Outcome    `json:"outcome,omitempty"`
+	Risk          string      `json:"risk,omitempty"`         // RiskHigh for dangerous shell commands
+	RiskReasons   []string    `json:"risk_reasons,omitempty"` // matched risk pattern names
+
+	InputBytes     int  `json:"input_bytes,omitempty"`     // size of the tool input as received
+	InputTruncated bool `json:"input_truncated,omitempty"` // stored tool input was compacted, see compactToolInput
+
+	Web *WebCapture `json:"web,omitempty"` // WebFetch/WebSearch result summary, see captureWebResult
 }
 
 // SubjectRef references the subject of an operation.
@@ -2,70 +2,90 @@ package dash
 
 import (
 	"context"
+	"database/sql"
 	"encoding/json"
 )
 
 // GetOrCreateNode retrieves an existing node by layer/type/name or creates a new one.
-// This handles race conditions by catching unique constraint violations.
+// See GetOrCreateNodeWithStatus.
 func (d *Dash) GetOrCreateNode(ctx context.Context, layer Layer, nodeType, name string, data map[string]any) (*Node, error) {
+	node, _, err := d.GetOrCreateNodeWithStatus(ctx, layer, nodeType, name, data)
+	return node, err
+}
+
+const queryInsertNodeIfAbsent = `
+	INSERT INTO nodes (layer, type, name, data)
+	VALUES ($1, $2, $3, $4)
+	ON CONFLICT (layer, type, name) WHERE deleted_at IS NULL DO NOTHING
+	RETURNING id, created_at, updated_at`
+
+// maxGetOrCreateAttempts bounds the insert/read loop in
+// GetOrCreateNodeWithStatus; it only repeats when the conflicting node is
+// deleted between the insert and the read.
+const maxGetOrCreateAttempts = 3
+
+// GetOrCreateNodeWithStatus is GetOrCreateNode that also reports whether
+// the node was created by this call. The insert skips on the active-name
+// unique index instead of failing, so of several concurrent callers exactly
+// one creates the node and the others read it back.
+func (d *Dash) GetOrCreateNodeWithStatus(ctx context.Context, layer Layer, nodeType, name string, data map[string]any) (*Node, bool, error) {
 	// Try to get existing node first
 	node, err := d.GetNodeByName(ctx, layer, nodeType, name)
 	if err == nil {
-		return node, nil
+		return node, false, nil
 	}
 	if err != ErrNodeNotFound {
-		return nil, err
+		return nil, false, err
 	}
 
-	// Node doesn't exist, create it
-	var dataJSON json.RawMessage
+	dataJSON := json.RawMessage(`{}`)
 	if data != nil {
 		dataJSON, err = json.Marshal(data)
 		if err != nil {
-			return nil, err
+			return nil, false, err
 		}
-	} else {
-		dataJSON = json.RawMessage(`{}`)
 	}
+	dataJSON = stampCreated(dataJSON, CallerFromContext(ctx))
 
-	node = &Node{
-		Layer: layer,
-		Type:  nodeType,
-		Name:  name,
-		Data:  dataJSON,
-	}
+	for attempt := 0; attempt < maxGetOrCreateAttempts; attempt++ {
+		node = &Node{Layer: layer, Type: nodeType, Name: name, Data: dataJSON}
+		err = d.db.QueryRowContext(ctx, queryInsertNodeIfAbsent, layer, nodeType, name, dataJSON).
+			Scan(&node.ID, &node.CreatedAt, &node.UpdatedAt)
+		if err == nil {
+			return node, true, nil
+		}
+		if err != sql.ErrNoRows {
+			return nil, false, err
+		}
 
-	if err := d.CreateNode(ctx, node); err != nil {
-		// Handle race condition: another process may have created it
-		// Try to get the node again
-		existingNode, getErr := d.GetNodeByName(ctx, layer, nodeType, name)
-		if getErr == nil {
-			return existingNode, nil
+		// Another caller holds the name: return its node
+		node, err = d.GetNodeByName(ctx, layer, nodeType, name)
+		if err != ErrNodeNotFound {
+			return node, false, err
 		}
-		// Return the original create error
-		return nil, err
 	}
-
-	return node, nil
+	return nil, false, err
 }
 
+// maxNodeDataRetries bounds how often UpdateNodeData re-reads after a conflict.
+const maxNodeDataRetries = 5
+
 // UpdateNodeData updates the data field of an existing node by merging new data.
+// Concurrent writers are detected via updated_at; on conflict the node is
+// re-read and the updates are merged into the fresh data, so no write is lost.
 func (d *Dash) UpdateNodeData(ctx context.Context, node *Node, updates map[string]any) error {
-	var existing map[string]any
-	if err := json.Unmarshal(node.Data, &existing); err != nil {
-		existing = make(map[string]any)
-	}
-
-	// Merge updates into existing
-	for k, v := range updates {
-		existing[k] = v
-	}
+	var err error
+	for attempt := 0; attempt < maxNodeDataRetries; attempt++ {
+		err = d.UpdateNodeDataCAS(ctx, node, node.UpdatedAt, updates)
+		if err != ErrNodeConflict {
+			return err
+		}
 
-	dataJSON, err := json.Marshal(existing)
-	if err != nil {
-		return err
+		fresh, getErr := d.GetNodeActive(ctx, node.ID)
+		if getErr != nil {
+			return getErr
+		}
+		*node = *fresh
 	}
-
-	node.Data = dataJSON
-	return d.UpdateNode(ctx, node)
+	return err
 }
@@ -0,0 +1,194 @@
+package dash
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	// webSummaryChars caps the page text kept per WebFetch capture.
+	webSummaryChars = 1000
+	// maxWebResponseBytes is the largest tool response that is summarized;
+	// bigger responses only record the URL.
+	maxWebResponseBytes = 2 << 20
+	// webSearchTopURLs is how many result URLs a WebSearch capture keeps.
+	webSearchTopURLs = 5
+)
+
+// WebCapture is what a WebFetch or WebSearch returned, kept small enough to
+// store on every observation.
+type WebCapture struct {
+	URL     string   `json:"url,omitempty"`
+	Query   string   `json:"query,omitempty"`
+	Title   string   `json:"title,omitempty"`
+	Summary string   `json:"summary,omitempty"`
+	URLs    []string `json:"urls,omitempty"` // WebSearch top result URLs
+	Bytes   int      `json:"bytes"`
+	Skipped string   `json:"skipped,omitempty"` // "too_large" or "binary" when no summary was taken
+}
+
+// captureWebResult extracts a WebCapture from a web tool's input and
+// response, or nil for other tools.
+func captureWebResult(toolName string, input, response json.RawMessage) *WebCapture {
+	var in map[string]any
+	json.Unmarshal(input, &in)
+
+	switch toolName {
+	case "WebFetch":
+		wc := &WebCapture{URL: stringVal(in, "url"), Bytes: len(response)}
+		if wc.URL == "" {
+			return nil
+		}
+		if len(response) > maxWebResponseBytes {
+			wc.Skipped = "too_large"
+			return wc
+		}
+		text := webResponseText(response)
+		if !utf8.ValidString(text) || isBinaryContent(text) {
+			wc.Skipped = "binary"
+			return wc
+		}
+		wc.Title = webTitle(text)
+		wc.Summary = truncateString(strings.Join(strings.Fields(text), " "), webSummaryChars)
+		return wc
+	case "WebSearch":
+		wc := &WebCapture{Query: stringVal(in, "query"), Bytes: len(response)}
+		if wc.Query == "" {
+			return nil
+		}
+		if len(response) > maxWebResponseBytes {
+			wc.Skipped = "too_large"
+			return wc
+		}
+		wc.URLs = webResultURLs(response, webSearchTopURLs)
+		return wc
+	}
+	return nil
+}
+
+// webResponseText returns the text of a tool response that is either a JSON
+// string or an object carrying the text in result/content/text.
+func webResponseText(response json.RawMessage) string {
+	var s string
+	if json.Unmarshal(response, &s) == nil {
+		return s
+	}
+	var obj map[string]any
+	if json.Unmarshal(response, &obj) == nil {
+		for _, key := range []string{"result", "content", "text"} {
+			if v := stringVal(obj, key); v != "" {
+				return v
+			}
+		}
+	}
+	return string(response)
+}
+
+var htmlTitleRe = regexp.MustCompile(`(?is)<title[^>]*>(.*?)</title>`)
+
+// webTitle takes the page title: an HTML <title>, else the first Markdown
+// heading, else the first non-empty line.
+func webTitle(text string) string {
+	if m := htmlTitleRe.FindStringSubmatch(text); m != nil {
+		return truncateString(strings.TrimSpace(m[1]), 200)
+	}
+	first := ""
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if strings.HasPrefix(line, "#") {
+			return truncateString(strings.TrimSpace(strings.TrimLeft(line, "#")), 200)
+		}
+		if first == "" {
+			first = line
+		}
+	}
+	return truncateString(first, 200)
+}
+
+var webURLRe = regexp.MustCompile(`https?://[^\s"'<>)\]]+`)
+
+// webResultURLs returns the first n distinct URLs in a search response, in
+// the order they appear.
+func webResultURLs(response json.RawMessage, n int) []string {
+	var urls []string
+	seen := make(map[string]bool)
+	for _, u := range webURLRe.FindAllString(webResponseText(response), -1) {
+		u = strings.TrimRight(u, ".,;\\")
+		if seen[u] {
+			continue
+		}
+		seen[u] = true
+		urls = append(urls, u)
+		if len(urls) == n {
+			break
+		}
+	}
+	return urls
+}
+
+// recordWebFetch upserts the SYSTEM.url node for a fetched page with its
+// latest title and summary, and links the session to it with an observed
+// edge event so later sessions can see the page was already read.
+func (d *Dash) recordWebFetch(ctx context.Context, session *Node, wc *WebCapture, now time.Time) {
+	node, err := d.GetOrCreateNode(ctx, LayerSystem, "url", wc.URL, map[string]any{"url": wc.URL})
+	if err != nil || node == nil {
+		return
+	}
+	patch := map[string]any{
+		"last_fetched_at": now.UTC().Format(time.RFC3339),
+		"fetch_count":     NodeDataOf(node).Int("fetch_count") + 1,
+		"bytes":           wc.Bytes,
+	}
+	if wc.Title != "" {
+		patch["title"] = wc.Title
+	}
+	if wc.Summary != "" {
+		patch["summary"] = wc.Summary
+	}
+	d.UpdateNodeData(ctx, node, patch)
+
+	eventData, _ := json.Marshal(map[string]any{"tool_name": "WebFetch", "title": wc.Title})
+	d.CreateEdgeEvent(ctx, &EdgeEvent{
+		SourceID:   session.ID,
+		TargetID:   node.ID,
+		Relation:   EventRelationObserved,
+		Success:    true,
+		Data:       eventData,
+		OccurredAt: now,
+	})
+}
+
+// priorWebFetchNotice returns a note when the URL about to be fetched was
+// fetched before, or "" if it was not.
+func (d *Dash) priorWebFetchNotice(ctx context.Context, toolInput json.RawMessage) string {
+	var in map[string]any
+	if json.Unmarshal(toolInput, &in) != nil {
+		return ""
+	}
+	url := stringVal(in, "url")
+	if url == "" {
+		return ""
+	}
+	node, err := d.GetNodeByName(ctx, LayerSystem, "url", url)
+	if err != nil || node == nil {
+		return ""
+	}
+	data := NodeDataOf(node)
+	fetchedAt, err := time.Parse(time.RFC3339, data.String("last_fetched_at"))
+	if err != nil {
+		return ""
+	}
+	note := fmt.Sprintf("ℹ Already fetched %s (%s)", url, formatTimeAgo(fetchedAt))
+	if title := data.String("title"); title != "" {
+		note += ": " + title
+	}
+	return note
+}
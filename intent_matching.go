@@ -76,8 +76,78 @@ func (d *Dash) MatchTaskToIntents(ctx context.Context, taskName, taskDescription
 	return matches, rows.Err()
 }
 
-// AutoLinkTaskToIntent matches a task to its best intent and creates an implements edge.
-// Returns the matched intent name, or empty string if no match found.
+// alignmentPct normalizes an intent match score to a 0-100 percentage
+// (max observed score ~15-20).
+func alignmentPct(score int) int {
+	const maxScore = 20
+	pct := (score * 100) / maxScore
+	if pct > 100 {
+		pct = 100
+	}
+	return pct
+}
+
+// intentLinkWeight turns a match score into an edge weight in (0, 1]: the
+// alignment percentage, floored so a weak auto-link still counts a little.
+func intentLinkWeight(score int) float64 {
+	const minWeight = 0.1
+	w := float64(alignmentPct(score)) / 100
+	if w < minWeight {
+		w = minWeight
+	}
+	return w
+}
+
+// LinkedIntent follows implements edges from a node to the CONTEXT.intent it
+// serves, going through at most one intermediate node (e.g. work order →
+// task → intent). Returns ErrNodeNotFound when no intent is linked.
+func (d *Dash) LinkedIntent(ctx context.Context, nodeID uuid.UUID) (*Node, error) {
+	return d.linkedIntent(ctx, nodeID, 1)
+}
+
+func (d *Dash) linkedIntent(ctx context.Context, nodeID uuid.UUID, hops int) (*Node, error) {
+	edges, err := d.ListEdgesBySourceRelation(ctx, nodeID, RelationImplements)
+	if err != nil {
+		return nil, err
+	}
+	var via []uuid.UUID
+	for _, e := range edges {
+		target, err := d.GetNodeActive(ctx, e.TargetID)
+		if err != nil {
+			continue
+		}
+		if target.Layer == LayerContext && target.Type == "intent" {
+			return target, nil
+		}
+		via = append(via, target.ID)
+	}
+	if hops > 0 {
+		for _, id := range via {
+			if intent, err := d.linkedIntent(ctx, id, hops-1); err == nil {
+				return intent, nil
+			}
+		}
+	}
+	return nil, ErrNodeNotFound
+}
+
+// IntentAlignment scores how well a name and description match one intent,
+// as a 0-100 percentage. Returns 0 when the intent is not among the matches.
+func (d *Dash) IntentAlignment(ctx context.Context, name, description string, intentID uuid.UUID) int {
+	matches, err := d.MatchTaskToIntents(ctx, name, description)
+	if err != nil {
+		return 0
+	}
+	for _, m := range matches {
+		if m.IntentID == intentID {
+			return alignmentPct(m.Score)
+		}
+	}
+	return 0
+}
+
+// AutoLinkTaskToIntent matches a task to its best intent and creates an implements edge
+// weighted by how well the task matched. Returns the matched intent name, or empty string if no match found.
 func (d *Dash) AutoLinkTaskToIntent(ctx context.Context, taskID uuid.UUID, taskName, taskDescription string) (string, error) {
 	matches, err := d.MatchTaskToIntents(ctx, taskName, taskDescription)
 	if err != nil || len(matches) == 0 {
@@ -96,6 +166,7 @@ func (d *Dash) AutoLinkTaskToIntent(ctx context.Context, taskID uuid.UUID, taskN
 		SourceID: taskID,
 		TargetID: best.IntentID,
 		Relation: RelationImplements,
+		Weight:   intentLinkWeight(best.Score),
 	})
 	if err != nil {
 		return "", err
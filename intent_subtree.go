@@ -0,0 +1,140 @@
+package dash
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// intentSubtreeDepth bounds how many edges below the intent are followed,
+// enough for intent ← plan ← task ← work order.
+const intentSubtreeDepth = 3
+
+// IntentItem is one node linked beneath an intent.
+type IntentItem struct {
+	ID     uuid.UUID `json:"id"`
+	Type   string    `json:"type"`
+	Name   string    `json:"name"`
+	Status string    `json:"status,omitempty"` // status, or stage for plans
+	Via    string    `json:"via,omitempty"`    // name of the node it links through, empty when linked directly
+}
+
+// IntentProgress aggregates completion over an intent's subtree.
+type IntentProgress struct {
+	TasksDone        int `json:"tasks_done"`
+	TasksTotal       int `json:"tasks_total"`
+	PlansApproved    int `json:"plans_approved"`
+	PlansTotal       int `json:"plans_total"`
+	WorkOrdersMerged int `json:"work_orders_merged"`
+	WorkOrdersTotal  int `json:"work_orders_total"`
+}
+
+// IntentSubtree is an intent with everything that implements or affects it,
+// directly or through another linked node.
+type IntentSubtree struct {
+	Intent      *Node          `json:"intent"`
+	Status      string         `json:"status"`
+	Plans       []IntentItem   `json:"plans,omitempty"`
+	Tasks       []IntentItem   `json:"tasks,omitempty"`
+	Suggestions []IntentItem   `json:"suggestions,omitempty"`
+	WorkOrders  []IntentItem   `json:"work_orders,omitempty"`
+	Other       []IntentItem   `json:"other,omitempty"`
+	Progress    IntentProgress `json:"progress"`
+}
+
+// GetIntentSubtree collects the nodes linked to a CONTEXT.intent by incoming
+// implements/affects edges, following them breadth-first up to
+// intentSubtreeDepth hops so work orders of linked tasks are included.
+func (d *Dash) GetIntentSubtree(ctx context.Context, intentID uuid.UUID) (*IntentSubtree, error) {
+	intent, err := d.GetNodeActive(ctx, intentID)
+	if err != nil {
+		return nil, err
+	}
+	if intent.Layer != LayerContext || intent.Type != "intent" {
+		return nil, fmt.Errorf("node %s is not a CONTEXT.intent", intent.Name)
+	}
+
+	tree := &IntentSubtree{Intent: intent, Status: nodeStatus(intent)}
+	if tree.Status == "" {
+		tree.Status = "active"
+	}
+
+	seen := map[uuid.UUID]bool{intentID: true}
+	frontier := []*Node{intent}
+	for depth := 0; depth < intentSubtreeDepth && len(frontier) > 0; depth++ {
+		var next []*Node
+		for _, parent := range frontier {
+			edges, err := d.ListEdgesByTarget(ctx, parent.ID)
+			if err != nil {
+				return nil, err
+			}
+			for _, e := range edges {
+				if e.Relation != RelationImplements && e.Relation != RelationAffects {
+					continue
+				}
+				if seen[e.SourceID] {
+					continue
+				}
+				seen[e.SourceID] = true
+				child, err := d.GetNodeActive(ctx, e.SourceID)
+				if err != nil {
+					continue
+				}
+				item := IntentItem{ID: child.ID, Type: child.Type, Name: child.Name, Status: nodeStatus(child)}
+				if parent != intent {
+					item.Via = parent.Name
+				}
+				tree.add(item)
+				next = append(next, child)
+			}
+		}
+		frontier = next
+	}
+	return tree, nil
+}
+
+// add files an item under its type and counts it towards progress.
+func (t *IntentSubtree) add(item IntentItem) {
+	switch item.Type {
+	case "plan":
+		t.Plans = append(t.Plans, item)
+		t.Progress.PlansTotal++
+		if item.Status == string(StageApproved) {
+			t.Progress.PlansApproved++
+		}
+	case "task":
+		t.Tasks = append(t.Tasks, item)
+		t.Progress.TasksTotal++
+		if item.Status == "completed" || item.Status == "done" {
+			t.Progress.TasksDone++
+		}
+	case "suggestion":
+		t.Suggestions = append(t.Suggestions, item)
+	case "work_order":
+		t.WorkOrders = append(t.WorkOrders, item)
+		t.Progress.WorkOrdersTotal++
+		if item.Status == string(WOStatusMerged) {
+			t.Progress.WorkOrdersMerged++
+		}
+	default:
+		t.Other = append(t.Other, item)
+	}
+}
+
+// nodeStatus reads a node's status from its data: stage for plans, status
+// for everything else.
+func nodeStatus(n *Node) string {
+	var data map[string]any
+	if json.Unmarshal(n.Data, &data) != nil {
+		return ""
+	}
+	if n.Type == "plan" {
+		if stage := stringVal(data, "stage"); stage != "" {
+			return stage
+		}
+		return string(StageOutline)
+	}
+	return stringVal(data, "status")
+}
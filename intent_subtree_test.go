@@ -0,0 +1,86 @@
+package dash
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestIntentSubtreeAdd(t *testing.T) {
+	tree := &IntentSubtree{}
+	for _, item := range []IntentItem{
+		{Type: "task", Status: "completed"},
+		{Type: "task", Status: "pending"},
+		{Type: "task", Status: "done"},
+		{Type: "plan", Status: "approved"},
+		{Type: "plan", Status: "review"},
+		{Type: "work_order", Status: "merged"},
+		{Type: "work_order", Status: "mutating"},
+		{Type: "suggestion"},
+		{Type: "file"},
+	} {
+		tree.add(item)
+	}
+	want := IntentProgress{TasksDone: 2, TasksTotal: 3, PlansApproved: 1, PlansTotal: 2, WorkOrdersMerged: 1, WorkOrdersTotal: 2}
+	if tree.Progress != want {
+		t.Errorf("progress = %+v, want %+v", tree.Progress, want)
+	}
+	if len(tree.Suggestions) != 1 || len(tree.Other) != 1 {
+		t.Errorf("suggestions=%d other=%d, want 1 and 1", len(tree.Suggestions), len(tree.Other))
+	}
+}
+
+func TestGetIntentSubtree(t *testing.T) {
+	d := testDash(t)
+	ctx := context.Background()
+	prefix := fmt.Sprintf("test-intent-%d", time.Now().UnixNano())
+
+	create := func(typ, suffix, data string) *Node {
+		t.Helper()
+		n := &Node{Layer: LayerContext, Type: typ, Name: prefix + "-" + suffix, Data: []byte(data)}
+		if typ == "work_order" {
+			n.Layer = LayerAutomation
+		}
+		if err := d.CreateNode(ctx, n); err != nil {
+			t.Fatalf("create %s: %v", typ, err)
+		}
+		t.Cleanup(func() { d.SoftDeleteNode(ctx, n.ID) })
+		return n
+	}
+	link := func(from, to *Node) {
+		t.Helper()
+		if err := d.CreateEdge(ctx, &Edge{SourceID: from.ID, TargetID: to.ID, Relation: RelationImplements}); err != nil {
+			t.Fatalf("link %s → %s: %v", from.Name, to.Name, err)
+		}
+	}
+
+	intent := create("intent", "intent", `{}`)
+	done := create("task", "done", `{"status": "completed"}`)
+	open := create("task", "open", `{"status": "pending"}`)
+	plan := create("plan", "plan", `{"stage": "review"}`)
+	wo := create("work_order", "wo", `{"status": "merged"}`)
+	link(done, intent)
+	link(open, intent)
+	link(plan, intent)
+	link(wo, open)
+
+	tree, err := d.GetIntentSubtree(ctx, intent.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := IntentProgress{TasksDone: 1, TasksTotal: 2, PlansTotal: 1, WorkOrdersMerged: 1, WorkOrdersTotal: 1}
+	if tree.Progress != want {
+		t.Errorf("progress = %+v, want %+v", tree.Progress, want)
+	}
+	if len(tree.WorkOrders) != 1 || tree.WorkOrders[0].Via != open.Name {
+		t.Errorf("work orders = %+v, want one via %s", tree.WorkOrders, open.Name)
+	}
+	if tree.Status != "active" {
+		t.Errorf("status = %q, want active", tree.Status)
+	}
+
+	if _, err := d.GetIntentSubtree(ctx, done.ID); err == nil {
+		t.Error("expected error for a non-intent node")
+	}
+}
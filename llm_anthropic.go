@@ -117,8 +117,12 @@ func translateToAnthropic(messages []ChatMessage) (system string, out []anthropi
 				blocks = append(blocks, anthropicContentBlock{Type: "text", Text: m.Content})
 			}
 			for _, tc := range m.ToolCalls {
-				var input any
-				json.Unmarshal([]byte(tc.Function.Arguments), &input)
+				// Malformed arguments are replayed as an empty object: tool_use
+				// requires an input, and the tool result carries the parse error.
+				var input map[string]any
+				if json.Unmarshal([]byte(tc.Function.Arguments), &input) != nil || input == nil {
+					input = map[string]any{}
+				}
 				blocks = append(blocks, anthropicContentBlock{
 					Type:  "tool_use",
 					ID:    tc.ID,
@@ -235,6 +239,7 @@ func doAnthropicComplete(ctx context.Context, client *http.Client, prov Provider
 //	content_block_delta (thinking_delta) -> EventReasoning
 //	content_block_delta (input_json_delta) -> accumulate tool args
 //	content_block_stop  (tool_use block) -> EventToolCall
+//	message_start                        -> remember input tokens
 //	message_delta                        -> EventUsage
 //	message_stop                         -> EventDone
 func streamAnthropic(ctx context.Context, client *http.Client, prov ProviderConfig, model string, messages []ChatMessage, tools []map[string]any, ch chan<- StreamEvent) {
@@ -299,6 +304,7 @@ func streamAnthropic(ctx context.Context, client *http.Client, prov ProviderConf
 	blocks := make(map[int]*blockInfo)
 	var pendingToolCalls []StreamToolCall
 	var contentLen int
+	var inputTokens int
 
 	scanner := bufio.NewScanner(resp.Body)
 	scanner.Buffer(make([]byte, 0, 256*1024), 256*1024)
@@ -318,6 +324,18 @@ func streamAnthropic(ctx context.Context, client *http.Client, prov ProviderConf
 		data := strings.TrimPrefix(line, "data: ")
 
 		switch eventType {
+		case "message_start":
+			var ev struct {
+				Message struct {
+					Usage struct {
+						InputTokens int `json:"input_tokens"`
+					} `json:"usage"`
+				} `json:"message"`
+			}
+			if json.Unmarshal([]byte(data), &ev) == nil {
+				inputTokens = ev.Message.Usage.InputTokens
+			}
+
 		case "content_block_start":
 			var ev struct {
 				Index        int `json:"index"`
@@ -387,7 +405,9 @@ func streamAnthropic(ctx context.Context, client *http.Client, prov ProviderConf
 			}
 			if json.Unmarshal([]byte(data), &ev) == nil && ev.Usage != nil {
 				ch <- StreamEvent{Type: EventUsage, Usage: &TokenUsage{
+					PromptTokens:     inputTokens,
 					CompletionTokens: ev.Usage.OutputTokens,
+					TotalTokens:      inputTokens + ev.Usage.OutputTokens,
 				}}
 			}
 
@@ -0,0 +1,238 @@
+package dash
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrBudgetExceeded is returned when the router's daily spend cap is reached.
+var ErrBudgetExceeded = errors.New("daily LLM budget exceeded")
+
+// Fallback prices (USD per million tokens) for models without configured
+// pricing. Deliberately on the expensive side so the cap errs toward safety.
+const (
+	defaultInputUSDPerM  = 3.0
+	defaultOutputUSDPerM = 15.0
+)
+
+// BudgetStore persists daily spend so the cap holds across processes.
+// Day keys are local dates formatted as "2006-01-02".
+type BudgetStore interface {
+	LoadDailySpend(ctx context.Context, day string) (float64, error)
+	AddDailySpend(ctx context.Context, day string, usd float64) error
+}
+
+// budgetTracker accumulates estimated spend for the current local day.
+// Without a store, spend is tracked in memory only.
+type budgetTracker struct {
+	mu    sync.Mutex
+	day   string
+	spent float64
+	store BudgetStore
+	now   func() time.Time
+}
+
+func newBudgetTracker() *budgetTracker {
+	return &budgetTracker{now: time.Now}
+}
+
+// today returns the current local day key, resetting in-memory spend at midnight.
+func (b *budgetTracker) today() string {
+	day := b.now().Format("2006-01-02")
+	if day != b.day {
+		b.day = day
+		b.spent = 0
+	}
+	return day
+}
+
+// spentToday returns spend for the current day, preferring the store so other
+// processes' spend is included.
+func (b *budgetTracker) spentToday(ctx context.Context) float64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	day := b.today()
+	if b.store != nil {
+		if usd, err := b.store.LoadDailySpend(ctx, day); err == nil {
+			b.spent = usd
+		}
+	}
+	return b.spent
+}
+
+// add records spend for the current day.
+func (b *budgetTracker) add(ctx context.Context, usd float64) {
+	if usd <= 0 {
+		return
+	}
+	b.mu.Lock()
+	day := b.today()
+	b.spent += usd
+	store := b.store
+	b.mu.Unlock()
+
+	if store != nil {
+		// Spend already happened; persist it even if the request was cancelled.
+		store.AddDailySpend(context.WithoutCancel(ctx), day, usd)
+	}
+}
+
+// SetBudgetStore attaches persistent storage for daily spend.
+func (r *LLMRouter) SetBudgetStore(s BudgetStore) {
+	r.budget.mu.Lock()
+	defer r.budget.mu.Unlock()
+	r.budget.store = s
+}
+
+// BudgetStatus returns today's estimated spend and the configured cap (0 = unlimited).
+func (r *LLMRouter) BudgetStatus(ctx context.Context) (spent, limit float64) {
+	return r.budget.spentToday(ctx), r.Config().DailyBudgetUSD
+}
+
+// checkBudget returns ErrBudgetExceeded when the daily cap is reached and the
+// request is too large to be exempt.
+func (r *LLMRouter) checkBudget(ctx context.Context, estInputTokens int) error {
+	cfg := r.Config()
+	if cfg.DailyBudgetUSD <= 0 || estInputTokens <= cfg.BudgetExemptTokens {
+		return nil
+	}
+	spent := r.budget.spentToday(ctx)
+	if spent >= cfg.DailyBudgetUSD {
+		return fmt.Errorf("%w: spent $%.2f of $%.2f today", ErrBudgetExceeded, spent, cfg.DailyBudgetUSD)
+	}
+	return nil
+}
+
+// recordUsage adds the estimated cost of a request to today's spend and
+// reports it to the usage recorder under the context's agent.
+func (r *LLMRouter) recordUsage(ctx context.Context, model string, promptTokens, completionTokens int) {
+	cost := r.estimateCost(model, promptTokens, completionTokens)
+	r.budget.add(ctx, cost)
+	r.notifyUsage(ctx, UsageRecord{
+		AgentKey:         LLMAgentFromContext(ctx),
+		Model:            model,
+		PromptTokens:     promptTokens,
+		CompletionTokens: completionTokens,
+		CostUSD:          cost,
+		PromptTemplate:   promptTemplateFromContext(ctx),
+		At:               time.Now(),
+	})
+}
+
+// recordEmbedUsage records embedding cost only when the model has a configured
+// price; the completion fallback would grossly overstate embedding cost.
+func (r *LLMRouter) recordEmbedUsage(ctx context.Context, model string, tokens int) {
+	r.mu.RLock()
+	mc, ok := r.config.Models[model]
+	r.mu.RUnlock()
+	if ok && mc.InputUSDPerM > 0 {
+		r.budget.add(ctx, float64(tokens)*mc.InputUSDPerM/1e6)
+	}
+}
+
+// EstimateCost returns the estimated USD cost of a request to model with the
+// given token counts, priced the same way the budget is charged.
+func (r *LLMRouter) EstimateCost(model string, promptTokens, completionTokens int) float64 {
+	return r.estimateCost(model, promptTokens, completionTokens)
+}
+
+// estimateCost prices a request from the model config, falling back to defaults.
+func (r *LLMRouter) estimateCost(model string, promptTokens, completionTokens int) float64 {
+	in, out := defaultInputUSDPerM, defaultOutputUSDPerM
+	r.mu.RLock()
+	if mc, ok := r.config.Models[model]; ok {
+		if mc.InputUSDPerM > 0 {
+			in = mc.InputUSDPerM
+		}
+		if mc.OutputUSDPerM > 0 {
+			out = mc.OutputUSDPerM
+		}
+	}
+	r.mu.RUnlock()
+	return (float64(promptTokens)*in + float64(completionTokens)*out) / 1e6
+}
+
+// estimateTokens approximates token count as one token per four characters.
+func estimateTokens(chars int) int {
+	return (chars + 3) / 4
+}
+
+// messagesTokens estimates the prompt size of a message list.
+func messagesTokens(messages []ChatMessage) int {
+	chars := 0
+	for _, m := range messages {
+		chars += len(m.Content)
+	}
+	return estimateTokens(chars)
+}
+
+// meterStream forwards events from in to out, recording usage when the stream
+// ends. Provider-reported usage wins; otherwise usage is estimated from text.
+// A provider that reports only completion tokens keeps the prompt estimate,
+// so its prompt cost still counts against the budget.
+func (r *LLMRouter) meterStream(ctx context.Context, model string, promptTokens int, in <-chan StreamEvent, out chan<- StreamEvent) {
+	var usage *TokenUsage
+	completionChars := 0
+	for ev := range in {
+		switch ev.Type {
+		case EventContent:
+			completionChars += len(ev.Content)
+		case EventReasoning:
+			completionChars += len(ev.Reasoning)
+		case EventUsage:
+			if ev.Usage != nil {
+				usage = ev.Usage
+			}
+		}
+		out <- ev
+	}
+	if usage != nil {
+		prompt := usage.PromptTokens
+		if prompt == 0 {
+			prompt = promptTokens
+		}
+		r.recordUsage(ctx, model, prompt, usage.CompletionTokens)
+	} else {
+		r.recordUsage(ctx, model, promptTokens, estimateTokens(completionChars))
+	}
+}
+
+// --- Graph persistence ---
+
+const queryAddDailySpend = `
+	UPDATE nodes
+	SET data = jsonb_set(data, '{spent_usd}',
+		to_jsonb(COALESCE((data->>'spent_usd')::float8, 0) + $2))
+	WHERE id = $1`
+
+// LoadDailySpend returns the spend recorded on the SYSTEM.llm_budget node for day.
+func (d *Dash) LoadDailySpend(ctx context.Context, day string) (float64, error) {
+	node, err := d.GetNodeByName(ctx, LayerSystem, "llm_budget", day)
+	if err == ErrNodeNotFound {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	var data struct {
+		SpentUSD float64 `json:"spent_usd"`
+	}
+	if err := json.Unmarshal(node.Data, &data); err != nil {
+		return 0, err
+	}
+	return data.SpentUSD, nil
+}
+
+// AddDailySpend atomically increments the spend on the SYSTEM.llm_budget node for day.
+func (d *Dash) AddDailySpend(ctx context.Context, day string, usd float64) error {
+	node, err := d.GetOrCreateNode(ctx, LayerSystem, "llm_budget", day, map[string]any{"spent_usd": 0})
+	if err != nil {
+		return err
+	}
+	_, err = d.db.ExecContext(ctx, queryAddDailySpend, node.ID, usd)
+	return err
+}
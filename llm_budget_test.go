@@ -0,0 +1,113 @@
+package dash
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+type memBudgetStore struct {
+	spend map[string]float64
+}
+
+func (m *memBudgetStore) LoadDailySpend(ctx context.Context, day string) (float64, error) {
+	return m.spend[day], nil
+}
+
+func (m *memBudgetStore) AddDailySpend(ctx context.Context, day string, usd float64) error {
+	m.spend[day] += usd
+	return nil
+}
+
+func TestRouterBudgetExceeded(t *testing.T) {
+	cfg := RouterConfig{
+		DailyBudgetUSD:     1.0,
+		BudgetExemptTokens: 100,
+		Models: map[string]ModelConfig{
+			"m": {Name: "m", InputUSDPerM: 1_000_000, OutputUSDPerM: 0},
+		},
+	}
+	r := NewLLMRouter(cfg)
+	now := time.Date(2026, 3, 1, 23, 0, 0, 0, time.Local)
+	r.budget.now = func() time.Time { return now }
+	store := &memBudgetStore{spend: map[string]float64{}}
+	r.SetBudgetStore(store)
+	ctx := context.Background()
+
+	if err := r.checkBudget(ctx, 1000); err != nil {
+		t.Fatalf("fresh budget: %v", err)
+	}
+
+	// One prompt token at $1M/Mtok = $1, reaching the cap.
+	r.recordUsage(ctx, "m", 1, 0)
+	if got := store.spend["2026-03-01"]; got != 1.0 {
+		t.Errorf("persisted spend = %v, want 1.0", got)
+	}
+
+	err := r.checkBudget(ctx, 1000)
+	if !errors.Is(err, ErrBudgetExceeded) {
+		t.Fatalf("err = %v, want ErrBudgetExceeded", err)
+	}
+	if err := r.checkBudget(ctx, 50); err != nil {
+		t.Errorf("small request should be exempt: %v", err)
+	}
+
+	// Local midnight starts a fresh day.
+	now = now.Add(2 * time.Hour)
+	if err := r.checkBudget(ctx, 1000); err != nil {
+		t.Errorf("after midnight: %v", err)
+	}
+}
+
+func TestRouterBudgetUnlimited(t *testing.T) {
+	r := NewLLMRouter(RouterConfig{})
+	r.recordUsage(context.Background(), "unknown", 1_000_000, 1_000_000)
+	if err := r.checkBudget(context.Background(), 1_000_000); err != nil {
+		t.Errorf("no cap configured: %v", err)
+	}
+}
+
+func TestEstimateCostDefaults(t *testing.T) {
+	r := NewLLMRouter(RouterConfig{})
+	got := r.estimateCost("unknown", 1_000_000, 1_000_000)
+	if want := defaultInputUSDPerM + defaultOutputUSDPerM; got != want {
+		t.Errorf("cost = %v, want %v", got, want)
+	}
+}
+
+func TestMeterStreamRecordsUsage(t *testing.T) {
+	r := NewLLMRouter(RouterConfig{Models: map[string]ModelConfig{
+		"m": {Name: "m", InputUSDPerM: 1, OutputUSDPerM: 2},
+	}})
+	in := make(chan StreamEvent, 4)
+	out := make(chan StreamEvent, 4)
+	in <- StreamEvent{Type: EventContent, Content: "hello"}
+	in <- StreamEvent{Type: EventUsage, Usage: &TokenUsage{PromptTokens: 1_000_000, CompletionTokens: 1_000_000}}
+	in <- StreamEvent{Type: EventDone}
+	close(in)
+
+	r.meterStream(context.Background(), "m", 10, in, out)
+	if len(out) != 3 {
+		t.Errorf("forwarded %d events, want 3", len(out))
+	}
+	if got := r.budget.spentToday(context.Background()); got != 3 {
+		t.Errorf("spent = %v, want 3", got)
+	}
+}
+
+func TestMeterStreamKeepsPromptEstimate(t *testing.T) {
+	r := NewLLMRouter(RouterConfig{Models: map[string]ModelConfig{
+		"m": {Name: "m", InputUSDPerM: 1, OutputUSDPerM: 2},
+	}})
+	in := make(chan StreamEvent, 2)
+	out := make(chan StreamEvent, 2)
+	in <- StreamEvent{Type: EventUsage, Usage: &TokenUsage{CompletionTokens: 1_000_000}}
+	in <- StreamEvent{Type: EventDone}
+	close(in)
+
+	r.meterStream(context.Background(), "m", 1_000_000, in, out)
+	if got := r.budget.spentToday(context.Background()); got != 3 {
+		t.Errorf("spent = %v, want 3", got)
+	}
+}
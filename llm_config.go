@@ -4,12 +4,18 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"os"
+	"strconv"
 )
 
 // DefaultRouterConfig returns the hardcoded default router configuration.
+// The daily budget is read from DASH_LLM_DAILY_BUDGET_USD and
+// DASH_LLM_BUDGET_EXEMPT_TOKENS; unset means no cap.
 func DefaultRouterConfig() RouterConfig {
 	return RouterConfig{
-		EnvFile: "/dash/.mcp.json",
+		EnvFile:            "/dash/.mcp.json",
+		DailyBudgetUSD:     envFloat("DASH_LLM_DAILY_BUDGET_USD"),
+		BudgetExemptTokens: int(envFloat("DASH_LLM_BUDGET_EXEMPT_TOKENS")),
 		Providers: map[string]ProviderConfig{
 			"openrouter": {
 				Name:          "openrouter",
@@ -265,6 +271,15 @@ func parseProviderFromData(nodeName string, data json.RawMessage) ProviderConfig
 	if pc.Name == "" {
 		pc.Name = nodeName
 	}
+	pc.MockDefault = getString("mock_default")
+	if mr, ok := m["mock_responses"].(map[string]any); ok {
+		pc.MockResponses = make(map[string]string)
+		for k, v := range mr {
+			if s, ok := v.(string); ok {
+				pc.MockResponses[k] = s
+			}
+		}
+	}
 
 	// Parse extra_headers
 	if eh, ok := m["extra_headers"].(map[string]any); ok {
@@ -332,10 +347,25 @@ func parseModelFromData(nodeName string, data json.RawMessage) ModelConfig {
 	if cl, ok := m["context_length"].(float64); ok {
 		mc.ContextLength = int(cl)
 	}
+	if p, ok := m["input_usd_per_mtok"].(float64); ok {
+		mc.InputUSDPerM = p
+	}
+	if p, ok := m["output_usd_per_mtok"].(float64); ok {
+		mc.OutputUSDPerM = p
+	}
 
 	return mc
 }
 
+// envFloat parses a float environment variable, returning 0 when unset or invalid.
+func envFloat(key string) float64 {
+	v, err := strconv.ParseFloat(os.Getenv(key), 64)
+	if err != nil {
+		return 0
+	}
+	return v
+}
+
 // getNodesByLayerType returns all non-deleted nodes matching layer+type.
 func (d *Dash) getNodesByLayerType(ctx context.Context, layer Layer, nodeType string) ([]*Node, error) {
 	rows, err := d.db.QueryContext(ctx, `
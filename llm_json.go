@@ -0,0 +1,129 @@
+package dash
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// jsonOnlyInstruction is appended to the system prompt of CompleteJSON calls.
+const jsonOnlyInstruction = "\n\nRespond with a single JSON value only. No prose, no markdown code fences."
+
+// Completer is the completion half of SummaryClient.
+type Completer interface {
+	Complete(ctx context.Context, systemPrompt, userMsg string) (string, error)
+}
+
+// CompleteJSON asks c for a JSON response and unmarshals it into v. Fences,
+// surrounding prose and trailing commas are cleaned up locally; if the result
+// still does not parse, the model is re-prompted once with the parse error.
+func CompleteJSON(ctx context.Context, c Completer, systemPrompt, userPrompt string, v any) error {
+	sys := systemPrompt + jsonOnlyInstruction
+	response, err := c.Complete(ctx, sys, userPrompt)
+	if err != nil {
+		return err
+	}
+	parseErr := decodeJSONResponse(response, v)
+	if parseErr == nil {
+		return nil
+	}
+
+	repairPrompt := fmt.Sprintf("%s\n\nYour previous response could not be parsed as JSON (%v):\n%s\n\nReturn the corrected JSON only.",
+		userPrompt, parseErr, truncateString(response, 4000))
+	response, err = c.Complete(ctx, sys, repairPrompt)
+	if err != nil {
+		return fmt.Errorf("repair completion: %w", err)
+	}
+	if err := decodeJSONResponse(response, v); err != nil {
+		return fmt.Errorf("invalid JSON after repair: %w (response: %.200s)", err, response)
+	}
+	return nil
+}
+
+// CompleteJSON is CompleteJSON using the router's default completion role.
+func (r *LLMRouter) CompleteJSON(ctx context.Context, systemPrompt, userPrompt string, v any) error {
+	return CompleteJSON(ctx, r, systemPrompt, userPrompt, v)
+}
+
+// decodeJSONResponse extracts the JSON value from a model response and
+// unmarshals it into v, retrying once with trailing commas removed.
+func decodeJSONResponse(response string, v any) error {
+	raw := extractJSON(response)
+	err := json.Unmarshal([]byte(raw), v)
+	if err == nil {
+		return nil
+	}
+	if fixed := stripTrailingCommas(raw); fixed != raw {
+		if json.Unmarshal([]byte(fixed), v) == nil {
+			return nil
+		}
+	}
+	return err
+}
+
+// extractJSON strips markdown code fences and any prose around the outermost
+// JSON object or array in s. If no object or array is found, the trimmed
+// text is returned as is.
+func extractJSON(s string) string {
+	s = strings.TrimSpace(s)
+	if strings.HasPrefix(s, "```") {
+		if idx := strings.Index(s[3:], "\n"); idx >= 0 {
+			s = s[3+idx+1:]
+		}
+		if end := strings.LastIndex(s, "```"); end >= 0 {
+			s = s[:end]
+		}
+		s = strings.TrimSpace(s)
+	}
+
+	start := strings.IndexAny(s, "{[")
+	if start < 0 {
+		return s
+	}
+	closer := byte('}')
+	if s[start] == '[' {
+		closer = ']'
+	}
+	end := strings.LastIndexByte(s, closer)
+	if end < start {
+		return s
+	}
+	return s[start : end+1]
+}
+
+// stripTrailingCommas removes commas directly before a closing brace or
+// bracket, ignoring anything inside string literals.
+func stripTrailingCommas(s string) string {
+	var b strings.Builder
+	inString, escaped := false, false
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if inString {
+			b.WriteByte(c)
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\':
+				escaped = true
+			case c == '"':
+				inString = false
+			}
+			continue
+		}
+		if c == '"' {
+			inString = true
+		}
+		if c == ',' {
+			j := i + 1
+			for j < len(s) && strings.IndexByte(" \t\r\n", s[j]) >= 0 {
+				j++
+			}
+			if j < len(s) && (s[j] == '}' || s[j] == ']') {
+				continue
+			}
+		}
+		b.WriteByte(c)
+	}
+	return b.String()
+}
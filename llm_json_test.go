@@ -0,0 +1,80 @@
+package dash
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+// fakeCompleter returns canned responses in order and records the prompts.
+type fakeCompleter struct {
+	responses []string
+	prompts   []string
+}
+
+func (f *fakeCompleter) Complete(ctx context.Context, systemPrompt, userMsg string) (string, error) {
+	f.prompts = append(f.prompts, userMsg)
+	if len(f.prompts) > len(f.responses) {
+		return "", nil
+	}
+	return f.responses[len(f.prompts)-1], nil
+}
+
+func TestCompleteJSONExtraction(t *testing.T) {
+	tests := []struct {
+		name     string
+		response string
+	}{
+		{"plain", `{"name": "p", "steps": ["a", "b"]}`},
+		{"fenced", "```json\n{\"name\": \"p\", \"steps\": [\"a\", \"b\"]}\n```"},
+		{"trailing comma", `{"name": "p", "steps": ["a", "b",],}`},
+		{"prose around", "Here is the plan:\n{\"name\": \"p\", \"steps\": [\"a\", \"b\"]}\nLet me know if it needs changes."},
+		{"comma in string", `{"name": "p", "steps": ["a, ]", "b",]}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fc := &fakeCompleter{responses: []string{tt.response}}
+			var got struct {
+				Name  string   `json:"name"`
+				Steps []string `json:"steps"`
+			}
+			if err := CompleteJSON(context.Background(), fc, "sys", "user", &got); err != nil {
+				t.Fatalf("CompleteJSON: %v", err)
+			}
+			if got.Name != "p" || len(got.Steps) != 2 {
+				t.Errorf("got %+v", got)
+			}
+			if len(fc.prompts) != 1 {
+				t.Errorf("made %d calls, want 1 (no repair needed)", len(fc.prompts))
+			}
+		})
+	}
+}
+
+func TestCompleteJSONRepair(t *testing.T) {
+	fc := &fakeCompleter{responses: []string{
+		`{"name": p}`,
+		`{"name": "p"}`,
+	}}
+	var got map[string]any
+	if err := CompleteJSON(context.Background(), fc, "sys", "user", &got); err != nil {
+		t.Fatalf("CompleteJSON: %v", err)
+	}
+	if got["name"] != "p" {
+		t.Errorf("got %v", got)
+	}
+	if len(fc.prompts) != 2 || !strings.Contains(fc.prompts[1], "could not be parsed") {
+		t.Errorf("expected one repair prompt carrying the parse error, got %q", fc.prompts)
+	}
+}
+
+func TestCompleteJSONRepairFails(t *testing.T) {
+	fc := &fakeCompleter{responses: []string{"no json here", "still none"}}
+	var got map[string]any
+	if err := CompleteJSON(context.Background(), fc, "sys", "user", &got); err == nil {
+		t.Fatal("expected error after failed repair")
+	}
+	if len(fc.prompts) != 2 {
+		t.Errorf("made %d calls, want 2", len(fc.prompts))
+	}
+}
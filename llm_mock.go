@@ -0,0 +1,71 @@
+package dash
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MockRouterConfig returns a router config whose completion roles all use an
+// offline mock provider. Each reply in responses is returned when its key
+// occurs in the prompt; fallback answers everything else ("" = error). No
+// embed role is configured, so embeddings stay unavailable.
+func MockRouterConfig(responses map[string]string, fallback string) RouterConfig {
+	cfg := RouterConfig{
+		Providers: map[string]ProviderConfig{
+			"mock": {
+				Name:          "mock",
+				Format:        FormatMock,
+				Enabled:       true,
+				MockResponses: responses,
+				MockDefault:   fallback,
+			},
+		},
+		Roles:        map[string]RoleConfig{},
+		ModelAliases: map[string]string{},
+		Models:       map[string]ModelConfig{"mock": {Name: "mock", Provider: "mock"}},
+	}
+	for _, role := range []string{"summarize", "chat", "plan", "mutator", "synthesizer"} {
+		cfg.Roles[role] = RoleConfig{Role: role, Provider: "mock", Model: "mock"}
+	}
+	return cfg
+}
+
+// mockComplete answers from prov.MockResponses. Keys are matched against the
+// whole conversation; the longest matching key wins so a specific key can
+// override a general one, with ties broken alphabetically.
+func mockComplete(prov ProviderConfig, messages []ChatMessage) (string, error) {
+	var prompt strings.Builder
+	for _, m := range messages {
+		prompt.WriteString(m.Content)
+		prompt.WriteString("\n")
+	}
+	text := prompt.String()
+
+	best, found := "", false
+	for key := range prov.MockResponses {
+		if key == "" || !strings.Contains(text, key) {
+			continue
+		}
+		if !found || len(key) > len(best) || len(key) == len(best) && key < best {
+			best, found = key, true
+		}
+	}
+	if found {
+		return prov.MockResponses[best], nil
+	}
+	if prov.MockDefault != "" {
+		return prov.MockDefault, nil
+	}
+	return "", fmt.Errorf("mock provider %s: no response for prompt %.80q", prov.Name, text)
+}
+
+// streamMock sends the mock reply as a single content chunk.
+func streamMock(prov ProviderConfig, messages []ChatMessage, ch chan<- StreamEvent) {
+	out, err := mockComplete(prov, messages)
+	if err != nil {
+		ch <- StreamEvent{Type: EventError, Error: err}
+	} else {
+		ch <- StreamEvent{Type: EventContent, Content: out}
+	}
+	ch <- StreamEvent{Type: EventDone}
+}
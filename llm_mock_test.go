@@ -0,0 +1,110 @@
+package dash
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMockCompleteMatchesLongestKey(t *testing.T) {
+	r := NewLLMRouter(MockRouterConfig(map[string]string{
+		"plan":           "general",
+		"plan generator": "specific",
+	}, ""))
+	ctx := context.Background()
+
+	if got, err := r.Complete(ctx, "You are a plan generator", "hi"); err != nil || got != "specific" {
+		t.Errorf("Complete = %q, %v; want specific", got, err)
+	}
+	if got, err := r.CompleteWithRole(ctx, "chat", "sys", "make a plan"); err != nil || got != "general" {
+		t.Errorf("CompleteWithRole = %q, %v; want general", got, err)
+	}
+	if _, err := r.Complete(ctx, "sys", "nothing matches"); err == nil {
+		t.Error("expected an error without a matching key or fallback")
+	}
+
+	var content string
+	for ev := range r.Stream(ctx, "chat", []ChatMessage{{Role: "user", Content: "plan it"}}, nil) {
+		if ev.Type == EventError {
+			t.Fatalf("stream error: %v", ev.Error)
+		}
+		content += ev.Content
+	}
+	if content != "general" {
+		t.Errorf("streamed %q, want general", content)
+	}
+}
+
+func TestMockCompleteFallback(t *testing.T) {
+	r := NewLLMRouter(MockRouterConfig(nil, "fallback"))
+	if got, err := r.Complete(context.Background(), "sys", "anything"); err != nil || got != "fallback" {
+		t.Errorf("Complete = %q, %v; want fallback", got, err)
+	}
+	if _, err := r.Embed(context.Background(), "text"); err == nil {
+		t.Error("mock router should not embed")
+	}
+}
+
+// mockPlanJSON is a plan complete enough to pass review and the gate.
+const mockPlanJSON = `{
+  "name": "%s",
+  "goal": "Add retry backoff to work orders",
+  "scope": "work_order.go",
+  "non_goals": ["Changing the build gate"],
+  "assumptions": ["Builds fail transiently"],
+  "risks": [{"description": "Orders wait longer before retrying"}],
+  "milestones": [{"name": "backoff"}],
+  "steps": [
+    {"description": "Record next_attempt_at on failure", "files": ["/dash/work_order.go"], "estimated_lines": 30, "milestone": "backoff"},
+    {"description": "Test the backoff", "files": ["/dash/work_order_test.go"], "estimated_lines": 40, "milestone": "backoff"}
+  ],
+  "acceptance_criteria": ["Retries wait for the backoff"],
+  "test_strategy": "Unit tests with injected timestamps",
+  "blocked_by": [],
+  "required_modules": ["dash"],
+  "missing_apis": [],
+  "migrations": []
+}`
+
+func TestGeneratePlanFromChatWithMockProvider(t *testing.T) {
+	base := testDash(t)
+	ctx := context.Background()
+	name := fmt.Sprintf("test-mock-plan-%d", time.Now().UnixNano())
+
+	d, err := New(Config{
+		DB: base.DB(),
+		Router: NewLLMRouter(MockRouterConfig(map[string]string{
+			"You are a plan generator": fmt.Sprintf(mockPlanJSON, name),
+		}, "")),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !d.HasRealSummarizer() {
+		t.Fatal("mock router should count as a real summarizer")
+	}
+
+	node, err := d.GeneratePlanFromChat(ctx, []ChatMessage{
+		{Role: "user", Content: "Builds keep failing on a flaky runner, add backoff between retries"},
+	}, "")
+	if err != nil {
+		t.Fatalf("GeneratePlanFromChat: %v", err)
+	}
+	t.Cleanup(func() { d.SoftDeleteNode(ctx, node.ID) })
+
+	if node.Name != name {
+		t.Fatalf("plan name = %q, want %q (fallback plan used?)", node.Name, name)
+	}
+	ps, err := parsePlanData(node)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ps.Stage != StageApproved {
+		t.Errorf("stage = %s, want approved (review: %+v)", ps.Stage, ps.Review)
+	}
+	if ref := NodeDataOf(node).String("prompt_template"); !strings.HasPrefix(ref, PromptPlanGeneration+"@") {
+		t.Errorf("prompt_template = %q, want a %s version", ref, PromptPlanGeneration)
+	}
+}
@@ -14,6 +14,8 @@ import (
 type LLMRouter struct {
 	config     RouterConfig
 	httpClient *http.Client
+	budget     *budgetTracker
+	usage      UsageRecorder
 	mu         sync.RWMutex
 }
 
@@ -28,6 +30,7 @@ func NewLLMRouter(cfg RouterConfig) *LLMRouter {
 		httpClient: &http.Client{
 			Timeout: 120 * time.Second,
 		},
+		budget: newBudgetTracker(),
 	}
 }
 
@@ -76,15 +79,32 @@ func (r *LLMRouter) Embed(ctx context.Context, text string) ([]float32, error) {
 	if err != nil {
 		return nil, err
 	}
+	tokens := estimateTokens(len(text))
+	if err := r.checkBudget(ctx, tokens); err != nil {
+		return nil, err
+	}
 
 	switch prov.Format {
 	case FormatOpenAI:
-		return doOpenAIEmbed(ctx, r.httpClient, prov, role.Model, text)
+		emb, err := doOpenAIEmbed(ctx, r.httpClient, prov, role.Model, text)
+		if err == nil {
+			r.recordEmbedUsage(ctx, role.Model, tokens)
+		}
+		return emb, err
 	default:
 		return nil, fmt.Errorf("embeddings not supported for format %s", prov.Format)
 	}
 }
 
+// EmbeddingModel returns the model used by the "embed" role, or "" if unconfigured.
+func (r *LLMRouter) EmbeddingModel() string {
+	_, role, err := r.resolve("embed")
+	if err != nil {
+		return ""
+	}
+	return role.Model
+}
+
 // --- SummaryClient implementation ---
 
 const defaultSummaryPrompt = "Summarize this file in 1-2 sentences in English. Focus on what the file does, not implementation details."
@@ -115,14 +135,7 @@ func (r *LLMRouter) Complete(ctx context.Context, systemPrompt, userMsg string)
 		Temperature: role.Temperature,
 	}
 
-	switch prov.Format {
-	case FormatOpenAI:
-		return doOpenAIComplete(ctx, r.httpClient, prov, role.Model, messages, opts)
-	case FormatAnthropic:
-		return doAnthropicComplete(ctx, r.httpClient, prov, role.Model, messages, opts)
-	default:
-		return "", fmt.Errorf("unknown format: %s", prov.Format)
-	}
+	return r.completeMetered(ctx, prov, role.Model, messages, opts)
 }
 
 // CompleteWithRole sends a completion request using the specified role.
@@ -142,14 +155,33 @@ func (r *LLMRouter) CompleteWithRole(ctx context.Context, role, systemPrompt, us
 		Temperature: rc.Temperature,
 	}
 
+	return r.completeMetered(ctx, prov, rc.Model, messages, opts)
+}
+
+// completeMetered checks the daily budget, dispatches a completion by
+// provider format, and records its estimated cost.
+func (r *LLMRouter) completeMetered(ctx context.Context, prov ProviderConfig, model string, messages []ChatMessage, opts CompleteOpts) (string, error) {
+	promptTokens := messagesTokens(messages)
+	if err := r.checkBudget(ctx, promptTokens); err != nil {
+		return "", err
+	}
+
+	var out string
+	var err error
 	switch prov.Format {
 	case FormatOpenAI:
-		return doOpenAIComplete(ctx, r.httpClient, prov, rc.Model, messages, opts)
+		out, err = doOpenAIComplete(ctx, r.httpClient, prov, model, messages, opts)
 	case FormatAnthropic:
-		return doAnthropicComplete(ctx, r.httpClient, prov, rc.Model, messages, opts)
+		out, err = doAnthropicComplete(ctx, r.httpClient, prov, model, messages, opts)
+	case FormatMock:
+		out, err = mockComplete(prov, messages)
 	default:
 		return "", fmt.Errorf("unknown format: %s", prov.Format)
 	}
+	if err == nil {
+		r.recordUsage(ctx, model, promptTokens, estimateTokens(len(out)))
+	}
+	return out, err
 }
 
 // --- Streaming ---
@@ -167,15 +199,7 @@ func (r *LLMRouter) Stream(ctx context.Context, role string, messages []ChatMess
 			return
 		}
 
-		switch prov.Format {
-		case FormatOpenAI:
-			streamOpenAI(ctx, r.httpClient, prov, rc.Model, messages, tools, ch)
-		case FormatAnthropic:
-			streamAnthropic(ctx, r.httpClient, prov, rc.Model, messages, tools, ch)
-		default:
-			ch <- StreamEvent{Type: EventError, Error: fmt.Errorf("unknown format: %s", prov.Format)}
-			ch <- StreamEvent{Type: EventDone}
-		}
+		r.streamMetered(ctx, prov, rc.Model, messages, tools, ch)
 	}()
 	return ch
 }
@@ -198,17 +222,37 @@ func (r *LLMRouter) StreamWithModel(ctx context.Context, model string, messages
 			tools = nil
 		}
 
+		r.streamMetered(ctx, prov, model, messages, tools, ch)
+	}()
+	return ch
+}
+
+// streamMetered checks the daily budget, then streams by provider format,
+// recording the estimated cost once the stream finishes.
+func (r *LLMRouter) streamMetered(ctx context.Context, prov ProviderConfig, model string, messages []ChatMessage, tools []map[string]any, ch chan<- StreamEvent) {
+	promptTokens := messagesTokens(messages)
+	if err := r.checkBudget(ctx, promptTokens); err != nil {
+		ch <- StreamEvent{Type: EventError, Error: err}
+		ch <- StreamEvent{Type: EventDone}
+		return
+	}
+
+	inner := make(chan StreamEvent, 64)
+	go func() {
+		defer close(inner)
 		switch prov.Format {
 		case FormatOpenAI:
-			streamOpenAI(ctx, r.httpClient, prov, model, messages, tools, ch)
+			streamOpenAI(ctx, r.httpClient, prov, model, messages, tools, inner)
 		case FormatAnthropic:
-			streamAnthropic(ctx, r.httpClient, prov, model, messages, tools, ch)
+			streamAnthropic(ctx, r.httpClient, prov, model, messages, tools, inner)
+		case FormatMock:
+			streamMock(prov, messages, inner)
 		default:
-			ch <- StreamEvent{Type: EventError, Error: fmt.Errorf("unknown format: %s", prov.Format)}
-			ch <- StreamEvent{Type: EventDone}
+			inner <- StreamEvent{Type: EventError, Error: fmt.Errorf("unknown format: %s", prov.Format)}
+			inner <- StreamEvent{Type: EventDone}
 		}
 	}()
-	return ch
+	r.meterStream(ctx, model, promptTokens, inner, ch)
 }
 
 // findProviderForModel finds the best provider for a given model string.
@@ -9,7 +9,10 @@ import (
 
 type llmContextKey string
 
-const llmAgentKey llmContextKey = "llm-agent"
+const (
+	llmAgentKey   llmContextKey = "llm-agent"
+	llmSessionKey llmContextKey = "llm-session"
+)
 
 // WithLLMAgent attaches an agent name to the context for per-agent API logging.
 func WithLLMAgent(ctx context.Context, agent string) context.Context {
@@ -24,12 +27,25 @@ func LLMAgentFromContext(ctx context.Context) string {
 	return "default"
 }
 
+// WithLLMSession attaches a session ID to the context. Prompt template
+// versions are assigned per session (see GetPromptTemplate).
+func WithLLMSession(ctx context.Context, sessionID string) context.Context {
+	return context.WithValue(ctx, llmSessionKey, sessionID)
+}
+
+// LLMSessionFromContext extracts the session ID, or "" if none was set.
+func LLMSessionFromContext(ctx context.Context) string {
+	v, _ := ctx.Value(llmSessionKey).(string)
+	return v
+}
+
 // APIFormat represents the API wire format used by a provider.
 type APIFormat string
 
 const (
 	FormatOpenAI    APIFormat = "openai"
 	FormatAnthropic APIFormat = "anthropic"
+	FormatMock      APIFormat = "mock" // offline canned responses, for tests
 )
 
 // AuthStyle controls how the API key is sent to the provider.
@@ -52,6 +68,11 @@ type ProviderConfig struct {
 	ExtraHeaders  map[string]string `json:"extra_headers,omitempty"`
 	Enabled       bool              `json:"enabled"`
 	SupportsTools bool              `json:"supports_tools"` // Whether provider accepts tool definitions
+
+	// FormatMock only: replies keyed by a substring of the prompt, and the
+	// reply used when no key matches.
+	MockResponses map[string]string `json:"mock_responses,omitempty"`
+	MockDefault   string            `json:"mock_default,omitempty"`
 }
 
 // RoleConfig maps a logical role to a specific provider + model.
@@ -65,9 +86,11 @@ type RoleConfig struct {
 
 // ModelConfig describes a model available for chat/streaming.
 type ModelConfig struct {
-	Name          string `json:"name"`           // Display/API name, e.g. "anthropic/claude-opus-4"
-	Provider      string `json:"provider"`       // Provider key, e.g. "openrouter"
-	ContextLength int    `json:"context_length"` // Context window size in tokens
+	Name          string  `json:"name"`                          // Display/API name, e.g. "anthropic/claude-opus-4"
+	Provider      string  `json:"provider"`                      // Provider key, e.g. "openrouter"
+	ContextLength int     `json:"context_length"`                // Context window size in tokens
+	InputUSDPerM  float64 `json:"input_usd_per_mtok,omitempty"`  // Prompt price per million tokens (0 = default estimate)
+	OutputUSDPerM float64 `json:"output_usd_per_mtok,omitempty"` // Completion price per million tokens (0 = default estimate)
 }
 
 // RouterConfig is the full configuration for the LLM router.
@@ -77,6 +100,13 @@ type RouterConfig struct {
 	ModelAliases map[string]string         `json:"model_aliases,omitempty"` // model name → provider name
 	Models       map[string]ModelConfig    `json:"models,omitempty"`        // model name → config
 	EnvFile      string                    `json:"env_file,omitempty"`      // Path to .mcp.json for API key loading
+
+	// DailyBudgetUSD caps estimated spend per local calendar day (0 = unlimited).
+	// Once reached, completions fail with ErrBudgetExceeded until midnight.
+	DailyBudgetUSD float64 `json:"daily_budget_usd,omitempty"`
+	// BudgetExemptTokens lets requests with at most this many estimated input
+	// tokens through even when over budget (small context reads, embeddings).
+	BudgetExemptTokens int `json:"budget_exempt_tokens,omitempty"`
 }
 
 // StreamEventType classifies streaming events.
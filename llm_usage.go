@@ -0,0 +1,125 @@
+package dash
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+// UsageRecord is the token usage of one completed LLM request.
+type UsageRecord struct {
+	AgentKey         string    `json:"agent_key"`
+	Model            string    `json:"model"`
+	PromptTokens     int       `json:"prompt_tokens"`
+	CompletionTokens int       `json:"completion_tokens"`
+	CostUSD          float64   `json:"cost_usd"`
+	PromptTemplate   string    `json:"prompt_template,omitempty"` // "<template>@<version>" the request used
+	At               time.Time `json:"-"`
+}
+
+// UsageRecorder persists per-request token usage for attribution.
+type UsageRecorder interface {
+	RecordTokenUsage(ctx context.Context, rec UsageRecord) error
+}
+
+// SetUsageRecorder attaches a recorder that receives every request's usage.
+// Recording runs in its own goroutine so it never delays a response.
+func (r *LLMRouter) SetUsageRecorder(rec UsageRecorder) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.usage = rec
+}
+
+// notifyUsage hands rec to the usage recorder, if any, without blocking.
+func (r *LLMRouter) notifyUsage(ctx context.Context, rec UsageRecord) {
+	r.mu.RLock()
+	recorder := r.usage
+	r.mu.RUnlock()
+	if recorder == nil {
+		return
+	}
+	// The tokens were spent; record them even if the request was cancelled.
+	go recorder.RecordTokenUsage(context.WithoutCancel(ctx), rec)
+}
+
+// AgentUsage is the token usage summed over a period for one agent.
+type AgentUsage struct {
+	AgentKey         string                `json:"agent_key"`
+	Requests         int                   `json:"requests"`
+	PromptTokens     int                   `json:"prompt_tokens"`
+	CompletionTokens int                   `json:"completion_tokens"`
+	TotalTokens      int                   `json:"total_tokens"`
+	CostUSD          float64               `json:"cost_usd"`
+	ByModel          map[string]AgentUsage `json:"by_model,omitempty"`
+}
+
+func (u *AgentUsage) add(o AgentUsage) {
+	u.Requests += o.Requests
+	u.PromptTokens += o.PromptTokens
+	u.CompletionTokens += o.CompletionTokens
+	u.TotalTokens += o.TotalTokens
+	u.CostUSD += o.CostUSD
+}
+
+// Usage is recorded as llm_usage observations on a SYSTEM.llm_usage node
+// per agent, with the estimated cost as the observation value.
+const llmUsageType = "llm_usage"
+
+const queryAgentTokenUsage = `
+	SELECT o.data->>'model',
+	       COUNT(*),
+	       COALESCE(SUM((o.data->>'prompt_tokens')::bigint), 0),
+	       COALESCE(SUM((o.data->>'completion_tokens')::bigint), 0),
+	       COALESCE(SUM(o.value), 0)
+	FROM observations o
+	JOIN nodes n ON n.id = o.node_id
+	WHERE n.layer = 'SYSTEM' AND n.type = 'llm_usage' AND n.name = $1
+	  AND o.type = 'llm_usage'
+	  AND o.observed_at >= $2 AND o.observed_at < $3
+	GROUP BY 1
+	ORDER BY 1`
+
+// RecordTokenUsage stores rec as an llm_usage observation on the agent's
+// SYSTEM.llm_usage node.
+func (d *Dash) RecordTokenUsage(ctx context.Context, rec UsageRecord) error {
+	node, err := d.GetOrCreateNode(ctx, LayerSystem, llmUsageType, rec.AgentKey, map[string]any{"agent_key": rec.AgentKey})
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	cost := rec.CostUSD
+	return d.CreateObservation(ctx, &Observation{
+		NodeID:     node.ID,
+		Type:       llmUsageType,
+		Value:      &cost,
+		Data:       data,
+		ObservedAt: rec.At,
+	})
+}
+
+// AgentTokenUsage sums the tokens and estimated cost of agentKey's LLM
+// requests in period, in total and per model. The agent key is the one set
+// with WithLLMAgent ("default" when none was set).
+func (d *Dash) AgentTokenUsage(ctx context.Context, agentKey string, period TimeRange) (*AgentUsage, error) {
+	rows, err := d.db.QueryContext(ctx, queryAgentTokenUsage, agentKey, period.Start, period.End)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	total := &AgentUsage{AgentKey: agentKey, ByModel: make(map[string]AgentUsage)}
+	for rows.Next() {
+		var model string
+		var u AgentUsage
+		if err := rows.Scan(&model, &u.Requests, &u.PromptTokens, &u.CompletionTokens, &u.CostUSD); err != nil {
+			return nil, err
+		}
+		u.TotalTokens = u.PromptTokens + u.CompletionTokens
+		total.ByModel[model] = u
+		total.add(u)
+	}
+	return total, rows.Err()
+}
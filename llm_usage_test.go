@@ -0,0 +1,70 @@
+package dash
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"testing"
+	"time"
+)
+
+type chanUsageRecorder chan UsageRecord
+
+func (c chanUsageRecorder) RecordTokenUsage(ctx context.Context, rec UsageRecord) error {
+	c <- rec
+	return nil
+}
+
+func TestRouterRecordsUsagePerAgent(t *testing.T) {
+	r := NewLLMRouter(RouterConfig{Models: map[string]ModelConfig{
+		"m": {Name: "m", InputUSDPerM: 1, OutputUSDPerM: 2},
+	}})
+	rec := make(chanUsageRecorder, 1)
+	r.SetUsageRecorder(rec)
+
+	r.recordUsage(WithLLMAgent(context.Background(), "agent-a"), "m", 1_000_000, 1_000_000)
+
+	select {
+	case got := <-rec:
+		if got.AgentKey != "agent-a" || got.Model != "m" || got.PromptTokens != 1_000_000 || got.CostUSD != 3 {
+			t.Errorf("recorded %+v, want agent-a on m costing $3", got)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("usage was not recorded")
+	}
+}
+
+func TestAgentTokenUsage(t *testing.T) {
+	d := testDash(t)
+	ctx := context.Background()
+	agent := fmt.Sprintf("test-usage-%d", time.Now().UnixNano())
+	now := time.Now()
+
+	for _, rec := range []UsageRecord{
+		{AgentKey: agent, Model: "a", PromptTokens: 100, CompletionTokens: 10, CostUSD: 0.5, At: now},
+		{AgentKey: agent, Model: "a", PromptTokens: 200, CompletionTokens: 20, CostUSD: 0.25, At: now},
+		{AgentKey: agent, Model: "b", PromptTokens: 50, CompletionTokens: 5, CostUSD: 0.1, At: now},
+		{AgentKey: agent, Model: "b", PromptTokens: 999, CompletionTokens: 999, CostUSD: 9, At: now.Add(-48 * time.Hour)},
+	} {
+		if err := d.RecordTokenUsage(ctx, rec); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if node, err := d.GetNodeByName(ctx, LayerSystem, llmUsageType, agent); err == nil {
+		t.Cleanup(func() { d.SoftDeleteNode(ctx, node.ID) })
+	}
+
+	usage, err := d.AgentTokenUsage(ctx, agent, TimeRange{Start: now.Add(-time.Hour), End: now.Add(time.Hour)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if usage.Requests != 3 || usage.PromptTokens != 350 || usage.CompletionTokens != 35 || usage.TotalTokens != 385 {
+		t.Errorf("usage = %+v, want 3 requests, 350 prompt, 35 completion", usage)
+	}
+	if math.Abs(usage.CostUSD-0.85) > 1e-9 {
+		t.Errorf("cost = %v, want 0.85", usage.CostUSD)
+	}
+	if a := usage.ByModel["a"]; a.Requests != 2 || a.TotalTokens != 330 {
+		t.Errorf("model a = %+v, want 2 requests, 330 tokens", a)
+	}
+}
@@ -60,8 +60,9 @@ type mcpToolCallParams struct {
 }
 
 type mcpToolResult struct {
-	Content []mcpContent `json:"content"`
-	IsError bool         `json:"isError,omitempty"`
+	Content           []mcpContent `json:"content"`
+	StructuredContent any          `json:"structuredContent,omitempty"`
+	IsError           bool         `json:"isError,omitempty"`
 }
 
 type mcpContent struct {
@@ -174,6 +175,18 @@ func (s *MCPServer) handleToolsCall(ctx context.Context, req *jsonRPCRequest) {
 		return
 	}
 
+	// Challenge: nothing was executed. Surface it so the client can ask the
+	// user and re-call with explicit confirmation arguments.
+	if result.Challenge != nil {
+		payload := map[string]any{"challenge": result.Challenge}
+		challengeJSON, _ := json.MarshalIndent(payload, "", "  ")
+		s.sendResult(req.ID, mcpToolResult{
+			Content:           []mcpContent{{Type: "text", Text: string(challengeJSON)}},
+			StructuredContent: payload,
+		})
+		return
+	}
+
 	resultJSON, _ := json.MarshalIndent(result.Data, "", "  ")
 	s.sendResult(req.ID, mcpToolResult{
 		Content: []mcpContent{{Type: "text", Text: string(resultJSON)}},
@@ -187,6 +200,13 @@ func (s *MCPServer) CallTool(ctx context.Context, name string, args map[string]a
 	if !result.Success {
 		return "", fmt.Errorf("%s", result.Error)
 	}
+	if result.Challenge != nil {
+		challengeJSON, err := json.Marshal(map[string]any{"challenge": result.Challenge})
+		if err != nil {
+			return "", fmt.Errorf("marshal challenge: %w", err)
+		}
+		return string(challengeJSON), nil
+	}
 	resultJSON, err := json.Marshal(result.Data)
 	if err != nil {
 		return "", fmt.Errorf("marshal result: %w", err)
@@ -285,12 +305,14 @@ func registerBuiltinTools(d *Dash) {
 		d.registry.Register(defSession())
 		d.registry.Register(defFile())
 		d.registry.Register(defSearch())
+		d.registry.Register(defRelated())
 		d.registry.Register(defQuery())
 		d.registry.Register(defNode())
 		d.registry.Register(defLink())
 		d.registry.Register(defTraverse())
 		d.registry.Register(defSummary())
 		d.registry.Register(defRemember())
+		d.registry.Register(defResolveQuestion())
 		d.registry.Register(defForget())
 		d.registry.Register(defWorkingSet())
 		d.registry.Register(defPromote())
@@ -310,6 +332,7 @@ func registerBuiltinTools(d *Dash) {
 		// Prompt service
 		d.registry.Register(defPrompt())
 		d.registry.Register(defPromptProfile())
+		d.registry.Register(defPromptTemplate())
 		// Agent management
 		d.registry.Register(defSpawnAgent())
 		d.registry.Register(defAgentStatus())
@@ -317,8 +340,13 @@ func registerBuiltinTools(d *Dash) {
 		// Cross-agent communication
 		d.registry.Register(defAskAgent())
 		d.registry.Register(defAnswerQuery())
+		d.registry.Register(defBroadcastAgents())
+		d.registry.Register(defScratchpad())
 		// Planner delegation
 		d.registry.Register(defGiveToPlanner())
+		// Peer review of work orders
+		d.registry.Register(defRequestPeerReview())
+		d.registry.Register(defSubmitPeerReview())
 		// Filesystem
 		d.registry.Register(defRead())
 		d.registry.Register(defWrite())
@@ -0,0 +1,91 @@
+package dash
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+)
+
+// newTestMCPServer returns an MCP server with a single challenge-guarded tool
+// and a flag reporting whether the tool body ever ran.
+func newTestMCPServer(t *testing.T) (*MCPServer, *bytes.Buffer, *bool) {
+	t.Helper()
+	executed := false
+	d := &Dash{registry: NewToolRegistry()}
+	d.registry.Register(&ToolDef{
+		Name: "danger",
+		Fn: func(ctx context.Context, d *Dash, args map[string]any) (any, error) {
+			executed = true
+			return map[string]any{"deleted": 1}, nil
+		},
+		ChallengeFunc: func(ctx context.Context, d *Dash, args map[string]any) *Challenge {
+			if ids, ok := args["confirm_ids"].([]any); ok && len(ids) > 0 {
+				return nil
+			}
+			return &Challenge{
+				ID:       "danger-confirm",
+				Question: "Really?",
+				Data:     map[string]any{"confirm_ids": []string{"abc"}},
+			}
+		},
+	})
+	var out bytes.Buffer
+	return &MCPServer{dash: d, writer: &out}, &out, &executed
+}
+
+func callMCPTool(t *testing.T, s *MCPServer, out *bytes.Buffer, args map[string]any) map[string]any {
+	t.Helper()
+	params, _ := json.Marshal(mcpToolCallParams{Name: "danger", Arguments: args})
+	out.Reset()
+	s.handleToolsCall(context.Background(), &jsonRPCRequest{JSONRPC: "2.0", ID: 1, Method: "tools/call", Params: params})
+
+	var resp struct {
+		Result map[string]any `json:"result"`
+	}
+	if err := json.Unmarshal(out.Bytes(), &resp); err != nil {
+		t.Fatalf("decode response: %v (%s)", err, out.String())
+	}
+	return resp.Result
+}
+
+func TestMCPToolCallPropagatesChallenge(t *testing.T) {
+	s, out, executed := newTestMCPServer(t)
+
+	result := callMCPTool(t, s, out, map[string]any{"query": "x"})
+
+	if *executed {
+		t.Fatal("tool body must not run before confirmation")
+	}
+	structured, ok := result["structuredContent"].(map[string]any)
+	if !ok {
+		t.Fatalf("missing structuredContent: %v", result)
+	}
+	ch, ok := structured["challenge"].(map[string]any)
+	if !ok {
+		t.Fatalf("missing challenge: %v", structured)
+	}
+	if ch["id"] != "danger-confirm" {
+		t.Errorf("challenge id = %v, want danger-confirm", ch["id"])
+	}
+	data, _ := ch["data"].(map[string]any)
+	if ids, _ := data["confirm_ids"].([]any); len(ids) != 1 || ids[0] != "abc" {
+		t.Errorf("challenge confirm_ids = %v, want [abc]", data["confirm_ids"])
+	}
+	if isErr, _ := result["isError"].(bool); isErr {
+		t.Error("challenge should not be reported as an error")
+	}
+}
+
+func TestMCPToolCallConfirmedRuns(t *testing.T) {
+	s, out, executed := newTestMCPServer(t)
+
+	result := callMCPTool(t, s, out, map[string]any{"confirm_ids": []any{"abc"}})
+
+	if !*executed {
+		t.Fatal("tool body should run with confirm_ids")
+	}
+	if _, ok := result["structuredContent"]; ok {
+		t.Errorf("unexpected structuredContent on confirmed call: %v", result)
+	}
+}
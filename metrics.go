@@ -15,9 +15,11 @@ type EvolutionMetrics struct {
 	WOCreated         int                     `json:"wo_created"`
 	WOMerged          int                     `json:"wo_merged"`
 	WORejected        int                     `json:"wo_rejected"`
+	WOForceMerged     int                     `json:"wo_force_merged,omitempty"` // included in WOMerged, not in MeanTimeToMerge
 	BuildSuccessRate  float64                 `json:"build_success_rate"`
 	SynthesisAvgScore float64                 `json:"synthesis_avg_score"`
 	MeanTimeToMerge   time.Duration           `json:"mean_time_to_merge"`
+	MeanAttempts      float64                 `json:"mean_attempts,omitempty"` // per merged work order, from completion reports
 	Steps             StepDurations           `json:"steps"`
 	Agents            map[string]AgentMetrics `json:"agents,omitempty"`
 }
@@ -40,14 +42,16 @@ type AgentMetrics struct {
 
 // woEventData is the parsed JSON shape of a work_order_event observation's Data field.
 type woEventData struct {
-	Status   string `json:"status"`
-	Actor    string `json:"actor"`
-	Detail   string `json:"detail"`
-	Revision int    `json:"revision"`
-	Attempt  int    `json:"attempt"`
-	EventNum int    `json:"event_num"`
-	Branch   string `json:"branch"`
-	AgentKey string `json:"agent_key"`
+	Status   string   `json:"status"`
+	Actor    string   `json:"actor"`
+	Detail   string   `json:"detail"`
+	Revision int      `json:"revision"`
+	Attempt  int      `json:"attempt"`
+	EventNum int      `json:"event_num"`
+	Branch   string   `json:"branch"`
+	AgentKey string   `json:"agent_key"`
+	Score    *float64 `json:"score,omitempty"`  // synthesis score, once scored
+	Forced   bool     `json:"forced,omitempty"` // merged via ForceMergeWorkOrder
 }
 
 // parseWOEventData extracts woEventData from a raw JSON observation Data field.
@@ -189,6 +193,9 @@ func (d *Dash) ComputeEvolutionMetrics(ctx context.Context, period TimeRange) (*
 	var buildPassed, buildFailed int
 	var mergeTimesTotal time.Duration
 	var mergeTimesCount int
+	var scoreTotal float64
+	var scoreCount int
+	agentScores := make(map[string][]float64)
 
 	for _, events := range grouped {
 		if len(events) == 0 {
@@ -197,6 +204,8 @@ func (d *Dash) ComputeEvolutionMetrics(ctx context.Context, period TimeRange) (*
 
 		// Determine the agent for this work order from the first event with an agent_key.
 		var agentKey string
+		var score *float64
+		var forced bool
 		statusTimes := make(map[string]time.Time)
 		hasStatus := make(map[string]bool)
 
@@ -204,6 +213,12 @@ func (d *Dash) ComputeEvolutionMetrics(ctx context.Context, period TimeRange) (*
 			if te.Event.AgentKey != "" && agentKey == "" {
 				agentKey = te.Event.AgentKey
 			}
+			if te.Event.Score != nil {
+				score = te.Event.Score // latest wins
+			}
+			if te.Event.Forced {
+				forced = true
+			}
 			if !hasStatus[te.Event.Status] {
 				statusTimes[te.Event.Status] = te.At
 				hasStatus[te.Event.Status] = true
@@ -217,6 +232,9 @@ func (d *Dash) ComputeEvolutionMetrics(ctx context.Context, period TimeRange) (*
 		if hasStatus[string(WOStatusMerged)] {
 			m.WOMerged++
 		}
+		if forced {
+			m.WOForceMerged++
+		}
 		if hasStatus[string(WOStatusRejected)] {
 			m.WORejected++
 		}
@@ -229,13 +247,22 @@ func (d *Dash) ComputeEvolutionMetrics(ctx context.Context, period TimeRange) (*
 			buildFailed++
 		}
 
-		// Time to merge: created -> merged.
-		if hasStatus[string(WOStatusCreated)] && hasStatus[string(WOStatusMerged)] {
+		// Time to merge: created -> merged. Force merges skipped the pipeline.
+		if hasStatus[string(WOStatusCreated)] && hasStatus[string(WOStatusMerged)] && !forced {
 			ttm := statusTimes[string(WOStatusMerged)].Sub(statusTimes[string(WOStatusCreated)])
 			mergeTimesTotal += ttm
 			mergeTimesCount++
 		}
 
+		// Synthesis score.
+		if score != nil {
+			scoreTotal += *score
+			scoreCount++
+			if agentKey != "" {
+				agentScores[agentKey] = append(agentScores[agentKey], *score)
+			}
+		}
+
 		// Per-agent accumulation.
 		if agentKey != "" {
 			am := m.Agents[agentKey]
@@ -256,6 +283,20 @@ func (d *Dash) ComputeEvolutionMetrics(ctx context.Context, period TimeRange) (*
 		m.BuildSuccessRate = float64(buildPassed) / float64(buildTotal)
 	}
 
+	// Synthesis scores.
+	if scoreCount > 0 {
+		m.SynthesisAvgScore = scoreTotal / float64(scoreCount)
+	}
+	for agent, scores := range agentScores {
+		var sum float64
+		for _, s := range scores {
+			sum += s
+		}
+		am := m.Agents[agent]
+		am.AvgScore = sum / float64(len(scores))
+		m.Agents[agent] = am
+	}
+
 	// Mean time to merge.
 	if mergeTimesCount > 0 {
 		m.MeanTimeToMerge = mergeTimesTotal / time.Duration(mergeTimesCount)
@@ -264,5 +305,30 @@ func (d *Dash) ComputeEvolutionMetrics(ctx context.Context, period TimeRange) (*
 	// Step durations.
 	m.Steps = computeStepDurations(grouped)
 
+	// Attempts per merge, from completion reports.
+	completions, err := d.ListObservationsByType(ctx, "work_order_completion", period, 1000)
+	if err != nil {
+		return nil, err
+	}
+	m.MeanAttempts = meanCompletionAttempts(completions)
+
 	return m, nil
 }
+
+// meanCompletionAttempts averages the attempts of work_order_completion
+// observations, skipping unparseable ones.
+func meanCompletionAttempts(observations []*Observation) float64 {
+	var total, count int
+	for _, obs := range observations {
+		var r CompletionReport
+		if err := json.Unmarshal(obs.Data, &r); err != nil {
+			continue
+		}
+		total += r.Attempts
+		count++
+	}
+	if count == 0 {
+		return 0
+	}
+	return float64(total) / float64(count)
+}
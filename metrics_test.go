@@ -2,6 +2,7 @@ package dash
 
 import (
 	"encoding/json"
+	"math"
 	"testing"
 	"time"
 
@@ -443,3 +444,171 @@ func TestAgentMetricsOmitEmptyScore(t *testing.T) {
 		t.Error("expected avg_score to be omitted when zero")
 	}
 }
+
+// --- AggregateDivergence summary ---
+
+func TestSummarizeDivergence(t *testing.T) {
+	gate := &BuildGateResult{
+		Test:  BuildResult{Passed: true},
+		AST:   ASTValidationResult{Passed: true},
+		Scope: ScopeCheckResult{Passed: true},
+	}
+	failingGate := &BuildGateResult{Test: BuildResult{Passed: false}}
+
+	good := &WorkOrder{Node: &Node{Name: "wo-good"}, Status: WOStatusMerged, ChecksStatus: "pass", FilesChanged: []string{"a.go"}}
+	noFiles := &WorkOrder{Node: &Node{Name: "wo-nofiles"}, Status: WOStatusMerged, ChecksStatus: "pass"}
+	bad := &WorkOrder{Node: &Node{Name: "wo-bad"}, AgentKey: "agent-x", Status: WOStatusMerged, ChecksStatus: "fail"}
+
+	results := []woDivergence{
+		divergenceFor(good, gate),
+		divergenceFor(noFiles, nil),
+		divergenceFor(bad, failingGate),
+	}
+	s := summarizeDivergence(results, 5)
+
+	if s.WorkOrders != 3 || s.FullyPassed != 1 {
+		t.Errorf("WorkOrders=%d FullyPassed=%d, want 3,1", s.WorkOrders, s.FullyPassed)
+	}
+
+	want := map[string]DivergenceCheckStats{
+		"tests pass":    {Claim: "tests pass", Passed: 1, Diverged: 1, Unverified: 1},
+		"files created": {Claim: "files created", Passed: 1, Diverged: 2},
+		"merged":        {Claim: "merged", Passed: 2, Diverged: 1},
+		"no violations": {Claim: "no violations", Passed: 1, Diverged: 1, Unverified: 1},
+	}
+	if len(s.Checks) != len(want) {
+		t.Fatalf("got %d check stats, want %d", len(s.Checks), len(want))
+	}
+	for _, c := range s.Checks {
+		if c != want[c.Claim] {
+			t.Errorf("stats for %q = %+v, want %+v", c.Claim, c, want[c.Claim])
+		}
+	}
+	if s.Checks[0].Claim != "tests pass" {
+		t.Errorf("checks not in canonical order: first is %q", s.Checks[0].Claim)
+	}
+
+	if len(s.WorstOffenders) != 2 || s.WorstOffenders[0].Name != "wo-bad" || len(s.WorstOffenders[0].Diverged) != 4 {
+		t.Errorf("worst offenders = %+v, want wo-bad first with 4 diverged", s.WorstOffenders)
+	}
+
+	if s := summarizeDivergence(results, 1); len(s.WorstOffenders) != 1 {
+		t.Errorf("maxOffenders=1 returned %d offenders", len(s.WorstOffenders))
+	}
+}
+
+func TestMeanCompletionAttempts(t *testing.T) {
+	obs := func(data string) *Observation { return &Observation{Data: json.RawMessage(data)} }
+	got := meanCompletionAttempts([]*Observation{
+		obs(`{"attempts": 1}`),
+		obs(`{"attempts": 3}`),
+		obs(`not json`),
+	})
+	if got != 2 {
+		t.Errorf("meanCompletionAttempts = %v, want 2", got)
+	}
+	if got := meanCompletionAttempts(nil); got != 0 {
+		t.Errorf("meanCompletionAttempts(nil) = %v, want 0", got)
+	}
+}
+
+func TestAgentLeaderboard(t *testing.T) {
+	base := time.Date(2026, 2, 1, 9, 0, 0, 0, time.UTC)
+	score := func(v float64) *float64 { return &v }
+	grouped := map[uuid.UUID][]timestampedEvent{}
+	// order adds one work order for agent, merged after mergeAfter (or
+	// rejected when mergeAfter is zero).
+	order := func(agent string, mergeAfter time.Duration, s *float64) {
+		evs := []timestampedEvent{{Event: woEventData{Status: "created", AgentKey: agent}, At: base}}
+		if mergeAfter > 0 {
+			evs = append(evs, timestampedEvent{Event: woEventData{Status: "merged", AgentKey: agent, Score: s}, At: base.Add(mergeAfter)})
+		} else {
+			evs = append(evs, timestampedEvent{Event: woEventData{Status: "rejected", AgentKey: agent, Score: s}, At: base.Add(time.Hour)})
+		}
+		grouped[uuid.New()] = evs
+	}
+	for i := 0; i < 4; i++ {
+		order("fast", 10*time.Minute, score(0.9))
+	}
+	order("slow", 40*time.Minute, score(0.8))
+	order("slow", 40*time.Minute, score(0.8))
+	order("slow", 0, score(0.4))
+	order("new", 5*time.Minute, score(1))
+	// A force merge is not counted for slow.
+	grouped[uuid.New()] = []timestampedEvent{
+		{Event: woEventData{Status: "created", AgentKey: "slow"}, At: base},
+		{Event: woEventData{Status: "merged", AgentKey: "slow", Forced: true}, At: base.Add(time.Minute)},
+	}
+
+	ranks := agentLeaderboard(grouped, DefaultLeaderboardWeights)
+	if len(ranks) != 3 {
+		t.Fatalf("ranks = %+v", ranks)
+	}
+	fast, slow, fresh := ranks[0], ranks[1], ranks[2]
+	if fast.AgentKey != "fast" || fast.Rank != 1 || slow.AgentKey != "slow" || slow.Rank != 2 {
+		t.Errorf("order = %s(%d), %s(%d)", fast.AgentKey, fast.Rank, slow.AgentKey, slow.Rank)
+	}
+	if fast.Speed != 1 || fast.Volume != 1 || fast.MergeRate != 1 {
+		t.Errorf("fast components = %+v", fast)
+	}
+	if math.Abs(slow.Speed-0.25) > 1e-9 || math.Abs(slow.Volume-0.5) > 1e-9 || math.Abs(slow.MergeRate-2.0/3) > 1e-9 {
+		t.Errorf("slow components = %+v", slow)
+	}
+	if slow.WOCount != 3 || slow.MeanTimeToMerge != 40*time.Minute {
+		t.Errorf("slow WOCount = %d, MeanTimeToMerge = %v", slow.WOCount, slow.MeanTimeToMerge)
+	}
+	if fresh.AgentKey != "new" || !fresh.Insufficient || fresh.Rank != 0 || fresh.Composite != 0 {
+		t.Errorf("agent with one order = %+v, want insufficient data", fresh)
+	}
+
+	// With all weight on volume the composite is the volume component.
+	ranks = agentLeaderboard(grouped, LeaderboardWeights{Volume: 1})
+	if ranks[0].Composite != 1 || math.Abs(ranks[1].Composite-0.5) > 1e-9 {
+		t.Errorf("volume-only composites = %v, %v", ranks[0].Composite, ranks[1].Composite)
+	}
+}
+
+func TestWorkOrderFunnel(t *testing.T) {
+	events := func(statuses ...string) []timestampedEvent {
+		base := time.Date(2026, 1, 15, 10, 0, 0, 0, time.UTC)
+		out := make([]timestampedEvent, len(statuses))
+		for i, s := range statuses {
+			out[i] = timestampedEvent{Event: woEventData{Status: s}, At: base.Add(time.Duration(i) * time.Minute)}
+		}
+		return out
+	}
+	grouped := map[uuid.UUID][]timestampedEvent{
+		uuid.New(): events("created", "assigned", "mutating", "build_passed", "synthesis_pending", "merge_pending", "merged"),
+		uuid.New(): events("created", "assigned", "mutating", "build_failed", "mutating", "build_passed", "synthesis_pending", "rejected"),
+		uuid.New(): events("created", "assigned", "mutating", "build_failed"),
+		uuid.New(): events("created", "merge_pending", "merged"), // force merge skips stages
+		uuid.New(): events("mutating", "build_passed"),           // created before the period
+	}
+
+	f := workOrderFunnel(grouped)
+	want := []int{4, 4, 4, 3, 3, 2, 2}
+	for i, st := range f.Stages {
+		if st.Status != funnelStages[i] {
+			t.Fatalf("stage %d = %s, want %s", i, st.Status, funnelStages[i])
+		}
+		if st.Reached != want[i] {
+			t.Errorf("%s reached = %d, want %d", st.Status, st.Reached, want[i])
+		}
+	}
+	if got := f.Stages[3].DropOff; got != 0.25 {
+		t.Errorf("build_passed drop-off = %v, want 0.25", got)
+	}
+	if got := f.Stages[6].Pct; got != 0.5 {
+		t.Errorf("merged pct = %v, want 0.5", got)
+	}
+	if f.WorstStage != WOStatusMergePending {
+		t.Errorf("worst stage = %s, want merge_pending", f.WorstStage)
+	}
+}
+
+func TestWorkOrderFunnelEmpty(t *testing.T) {
+	f := workOrderFunnel(nil)
+	if len(f.Stages) != len(funnelStages) || f.Stages[0].Reached != 0 || f.WorstStage != "" {
+		t.Errorf("empty funnel = %+v", f)
+	}
+}
@@ -0,0 +1,240 @@
+package dash
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// MissionDriftThreshold is the drift score above which an agent is
+// considered to have left its mission. Drift is 1 - cosine similarity between
+// the mission and the agent's activity, so 0.75 means a similarity below 0.25.
+const MissionDriftThreshold = 0.75
+
+const (
+	driftActivityWindow = 2 * time.Hour
+	driftMaxEvents      = 200
+	driftMaxFiles       = 20
+	driftMaxSearches    = 10
+)
+
+const queryFileMissionSimilarity = `
+	SELECT name, 1 - (embedding <=> $1)
+	FROM nodes
+	WHERE layer = 'SYSTEM' AND type = 'file'
+	  AND name = ANY($2)
+	  AND embedding IS NOT NULL
+	  AND deleted_at IS NULL`
+
+// AgentActivity is what an agent has recently done: files it touched with
+// how often, and the searches it ran.
+type AgentActivity struct {
+	Files    map[string]int
+	Searches []string
+}
+
+// DriftReport compares an agent's activity with its mission.
+type DriftReport struct {
+	AgentKey        string    `json:"agent_key"`
+	Mission         string    `json:"mission"`
+	Activity        string    `json:"activity"`
+	Similarity      float64   `json:"similarity"`
+	Score           float64   `json:"score"` // 0 = on mission, 1 = unrelated
+	Drifting        bool      `json:"drifting"`
+	OffMissionFiles []string  `json:"off_mission_files,omitempty"`
+	CheckedAt       time.Time `json:"checked_at"`
+}
+
+// DetectMissionDrift measures how far the agent's latest session has strayed
+// from its mission. The mission and the tool activity are read from the
+// graph: the newest CONTEXT.agent_session for agentKey and the tool_event
+// observations of its session over the last two hours.
+func (d *Dash) DetectMissionDrift(ctx context.Context, agentKey string) (*DriftReport, error) {
+	session, err := scanNode(d.db.QueryRowContext(ctx, queryLatestAgentSessionByKey, agentKey))
+	if err != nil {
+		return nil, fmt.Errorf("no agent session for %q: %w", agentKey, err)
+	}
+	mission := stringVal(parseNodeData(session), "mission")
+
+	act, err := d.sessionActivity(ctx, session.Name)
+	if err != nil {
+		return nil, err
+	}
+	return d.MeasureMissionDrift(ctx, agentKey, mission, act)
+}
+
+// MeasureMissionDrift scores act against mission. Callers that already track
+// activity themselves, like the cockpit's per-tab file counts, use this
+// directly. It returns ErrNoEmbedder when no real embedder is configured.
+func (d *Dash) MeasureMissionDrift(ctx context.Context, agentKey, mission string, act AgentActivity) (*DriftReport, error) {
+	if strings.TrimSpace(mission) == "" {
+		return nil, fmt.Errorf("agent %q has no mission", agentKey)
+	}
+	report := &DriftReport{
+		AgentKey:  agentKey,
+		Mission:   mission,
+		Activity:  activitySummary(act),
+		CheckedAt: time.Now(),
+	}
+	if report.Activity == "" {
+		return report, nil // nothing done yet, nothing to drift from
+	}
+
+	if d.embedder == nil {
+		return nil, ErrNoEmbedder
+	}
+	if _, isNoOp := d.embedder.(*NoOpEmbedder); isNoOp {
+		return nil, ErrNoEmbedder
+	}
+	missionVec, err := d.embedder.Embed(ctx, mission)
+	if err != nil {
+		return nil, fmt.Errorf("embed mission: %w", err)
+	}
+	activityVec, err := d.embedder.Embed(ctx, report.Activity)
+	if err != nil {
+		return nil, fmt.Errorf("embed activity: %w", err)
+	}
+	if missionVec == nil || activityVec == nil {
+		return nil, ErrNoEmbedder
+	}
+
+	report.Similarity = cosineSimilarity(missionVec, activityVec)
+	report.Score = 1 - report.Similarity
+	report.Drifting = report.Score > MissionDriftThreshold
+	report.OffMissionFiles, _ = d.offMissionFiles(ctx, missionVec, act.Files)
+	return report, nil
+}
+
+// sessionActivity collects files and searches from the tool_event
+// observations of the CONTEXT.session named sessionID.
+func (d *Dash) sessionActivity(ctx context.Context, sessionID string) (AgentActivity, error) {
+	act := AgentActivity{Files: make(map[string]int)}
+	session, err := d.GetNodeByName(ctx, LayerContext, "session", sessionID)
+	if err != nil {
+		return act, nil // no tool calls logged yet
+	}
+	now := time.Now()
+	events, err := d.ListObservationsByNodeType(ctx, session.ID, "tool_event", TimeRange{Start: now.Add(-driftActivityWindow), End: now})
+	if err != nil {
+		return act, err
+	}
+	if len(events) > driftMaxEvents {
+		events = events[:driftMaxEvents] // newest first
+	}
+	for i := len(events) - 1; i >= 0; i-- {
+		obs := events[i]
+		var ev struct {
+			Phase string         `json:"phase"`
+			Args  map[string]any `json:"args"`
+		}
+		if json.Unmarshal(obs.Data, &ev) != nil || ev.Phase != "tool.post" {
+			continue
+		}
+		if p := extractFilePathFromArgs(ev.Args); p != "" {
+			act.Files[p]++
+		}
+		for _, key := range []string{"query", "pattern"} {
+			if q, ok := ev.Args[key].(string); ok && q != "" {
+				act.Searches = append(act.Searches, q)
+			}
+		}
+	}
+	return act, nil
+}
+
+// offMissionFiles returns the touched files whose stored embedding is too far
+// from the mission, most touched first. Files without embeddings are skipped.
+func (d *Dash) offMissionFiles(ctx context.Context, missionVec []float32, files map[string]int) ([]string, error) {
+	if len(files) == 0 {
+		return nil, nil
+	}
+	paths := make([]string, 0, len(files))
+	for p := range files {
+		paths = append(paths, p)
+	}
+	rows, err := d.db.QueryContext(ctx, queryFileMissionSimilarity, float32SliceToVector(missionVec), pq.Array(paths))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var off []string
+	for rows.Next() {
+		var path string
+		var sim float64
+		if err := rows.Scan(&path, &sim); err != nil {
+			return nil, err
+		}
+		if 1-sim > MissionDriftThreshold {
+			off = append(off, path)
+		}
+	}
+	sort.Slice(off, func(i, j int) bool {
+		if files[off[i]] != files[off[j]] {
+			return files[off[i]] > files[off[j]]
+		}
+		return off[i] < off[j]
+	})
+	return off, rows.Err()
+}
+
+// activitySummary renders act as text for embedding: the most touched files
+// and the latest searches.
+func activitySummary(act AgentActivity) string {
+	paths := make([]string, 0, len(act.Files))
+	for p := range act.Files {
+		paths = append(paths, p)
+	}
+	sort.Slice(paths, func(i, j int) bool {
+		if act.Files[paths[i]] != act.Files[paths[j]] {
+			return act.Files[paths[i]] > act.Files[paths[j]]
+		}
+		return paths[i] < paths[j]
+	})
+	if len(paths) > driftMaxFiles {
+		paths = paths[:driftMaxFiles]
+	}
+	searches := act.Searches
+	if len(searches) > driftMaxSearches {
+		searches = searches[len(searches)-driftMaxSearches:]
+	}
+
+	var b strings.Builder
+	if len(paths) > 0 {
+		b.WriteString("Files touched:\n")
+		for _, p := range paths {
+			fmt.Fprintf(&b, "- %s (%d)\n", p, act.Files[p])
+		}
+	}
+	if len(searches) > 0 {
+		b.WriteString("Searches:\n")
+		for _, q := range searches {
+			fmt.Fprintf(&b, "- %s\n", q)
+		}
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// cosineSimilarity returns the cosine of the angle between a and b, or 0 if
+// either is empty or zero or their lengths differ.
+func cosineSimilarity(a, b []float32) float64 {
+	if len(a) == 0 || len(a) != len(b) {
+		return 0
+	}
+	var dot, na, nb float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		na += float64(a[i]) * float64(a[i])
+		nb += float64(b[i]) * float64(b[i])
+	}
+	if na == 0 || nb == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(na) * math.Sqrt(nb))
+}
@@ -0,0 +1,62 @@
+package dash
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestCosineSimilarity(t *testing.T) {
+	tests := []struct {
+		a, b []float32
+		want float64
+	}{
+		{[]float32{1, 0}, []float32{1, 0}, 1},
+		{[]float32{1, 0}, []float32{0, 1}, 0},
+		{[]float32{1, 1}, []float32{-1, -1}, -1},
+		{[]float32{1, 0}, []float32{1, 0, 0}, 0},
+		{[]float32{0, 0}, []float32{1, 0}, 0},
+		{nil, nil, 0},
+	}
+	for _, tt := range tests {
+		if got := cosineSimilarity(tt.a, tt.b); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("cosineSimilarity(%v, %v) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestActivitySummary(t *testing.T) {
+	act := AgentActivity{
+		Files:    map[string]int{"/a/rare.go": 1, "/a/hot.go": 5},
+		Searches: []string{"old query", "new query"},
+	}
+	got := activitySummary(act)
+	if strings.Index(got, "/a/hot.go (5)") > strings.Index(got, "/a/rare.go (1)") {
+		t.Errorf("most touched file should come first:\n%s", got)
+	}
+	if !strings.HasSuffix(got, "- new query") {
+		t.Errorf("latest search should come last:\n%s", got)
+	}
+	if activitySummary(AgentActivity{}) != "" {
+		t.Error("empty activity should give empty summary")
+	}
+}
+
+func TestMeasureMissionDriftWithoutEmbedder(t *testing.T) {
+	d := &Dash{embedder: &NoOpEmbedder{}}
+	ctx := context.Background()
+
+	if _, err := d.MeasureMissionDrift(ctx, "coder", "", AgentActivity{}); err == nil {
+		t.Error("expected error for empty mission")
+	}
+	report, err := d.MeasureMissionDrift(ctx, "coder", "fix the parser", AgentActivity{})
+	if err != nil || report.Drifting || report.Score != 0 {
+		t.Errorf("no activity should not drift: %+v, %v", report, err)
+	}
+	_, err = d.MeasureMissionDrift(ctx, "coder", "fix the parser", AgentActivity{Files: map[string]int{"/a/b.go": 1}})
+	if !errors.Is(err, ErrNoEmbedder) {
+		t.Errorf("err = %v, want ErrNoEmbedder", err)
+	}
+}
@@ -0,0 +1,148 @@
+package dash
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// NodeData is a node's JSON data decoded once, with typed getters that
+// tolerate missing keys and wrong types by returning the zero value. Use it
+// instead of re-unmarshaling node.Data and type-asserting at each call site.
+type NodeData map[string]any
+
+// NewNodeData decodes node's data. Empty or null data gives an empty
+// NodeData; only malformed JSON is an error.
+func NewNodeData(node *Node) (NodeData, error) {
+	if node == nil {
+		return nil, fmt.Errorf("nil node")
+	}
+	data := NodeData{}
+	if len(node.Data) == 0 {
+		return data, nil
+	}
+	if err := json.Unmarshal(node.Data, &data); err != nil {
+		return nil, err
+	}
+	if data == nil {
+		data = NodeData{}
+	}
+	return data, nil
+}
+
+// NodeDataOf is NewNodeData for call sites that treat malformed data as
+// empty.
+func NodeDataOf(node *Node) NodeData {
+	data, err := NewNodeData(node)
+	if err != nil {
+		return NodeData{}
+	}
+	return data
+}
+
+// String returns key as a string, or "".
+func (d NodeData) String(key string) string {
+	v, _ := d[key].(string)
+	return v
+}
+
+// Int returns key as an int. JSON numbers decode as float64 and are
+// truncated; 0 if missing.
+func (d NodeData) Int(key string) int {
+	switch v := d[key].(type) {
+	case float64:
+		return int(v)
+	case int:
+		return v
+	case int64:
+		return int(v)
+	}
+	return 0
+}
+
+// Float returns key as a float64, or 0.
+func (d NodeData) Float(key string) float64 {
+	switch v := d[key].(type) {
+	case float64:
+		return v
+	case int:
+		return float64(v)
+	case int64:
+		return float64(v)
+	}
+	return 0
+}
+
+// Bool returns key as a bool, or false.
+func (d NodeData) Bool(key string) bool {
+	v, _ := d[key].(bool)
+	return v
+}
+
+// StringSlice returns the string elements of the array at key, skipping
+// anything that is not a string.
+func (d NodeData) StringSlice(key string) []string {
+	switch raw := d[key].(type) {
+	case []string:
+		return raw
+	case []any:
+		var out []string
+		for _, v := range raw {
+			if s, ok := v.(string); ok {
+				out = append(out, s)
+			}
+		}
+		return out
+	}
+	return nil
+}
+
+// Names reads an array whose elements are either strings or objects with
+// a string field, e.g. milestones: ["foo"] or [{"name":"foo"}].
+func (d NodeData) Names(key, field string) []string {
+	raw, ok := d[key].([]any)
+	if !ok {
+		return nil
+	}
+	var out []string
+	for _, v := range raw {
+		switch val := v.(type) {
+		case string:
+			out = append(out, val)
+		case map[string]any:
+			if s, ok := val[field].(string); ok && s != "" {
+				out = append(out, s)
+			}
+		}
+	}
+	return out
+}
+
+// Map returns the object at key, or nil if key is not an object.
+func (d NodeData) Map(key string) NodeData {
+	m, _ := d[key].(map[string]any)
+	return m
+}
+
+// List returns the array at key, or nil.
+func (d NodeData) List(key string) []any {
+	v, _ := d[key].([]any)
+	return v
+}
+
+// Set stores v under key.
+func (d NodeData) Set(key string, v any) {
+	d[key] = v
+}
+
+// Merge applies patch with the same shallow semantics as UpdateNodeData:
+// keys in patch replace existing ones.
+func (d NodeData) Merge(patch map[string]any) {
+	for k, v := range patch {
+		d[k] = v
+	}
+}
+
+// JSON encodes the data for storing back on a node.
+func (d NodeData) JSON() (json.RawMessage, error) {
+	return json.Marshal(map[string]any(d))
+}
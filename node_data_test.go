@@ -0,0 +1,107 @@
+package dash
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestNodeDataGetters(t *testing.T) {
+	node := &Node{Data: json.RawMessage(`{
+		"goal": "ship it",
+		"count": 3,
+		"ratio": 0.5,
+		"done": true,
+		"tags": ["a", 1, "b"],
+		"milestones": ["m1", {"name": "m2"}, {"name": ""}],
+		"gate": {"decision": "go", "risk_score": 40}
+	}`)}
+	data, err := NewNodeData(node)
+	if err != nil {
+		t.Fatalf("NewNodeData: %v", err)
+	}
+
+	if got := data.String("goal"); got != "ship it" {
+		t.Errorf("String(goal) = %q", got)
+	}
+	if got := data.String("count"); got != "" {
+		t.Errorf("String(count) = %q, want empty for wrong type", got)
+	}
+	if got := data.Int("count"); got != 3 {
+		t.Errorf("Int(count) = %d, want 3", got)
+	}
+	if got := data.Float("ratio"); got != 0.5 {
+		t.Errorf("Float(ratio) = %v, want 0.5", got)
+	}
+	if !data.Bool("done") || data.Bool("missing") {
+		t.Errorf("Bool(done)/Bool(missing) wrong")
+	}
+	if got := data.StringSlice("tags"); !reflect.DeepEqual(got, []string{"a", "b"}) {
+		t.Errorf("StringSlice(tags) = %v", got)
+	}
+	if got := data.Names("milestones", "name"); !reflect.DeepEqual(got, []string{"m1", "m2"}) {
+		t.Errorf("Names(milestones) = %v", got)
+	}
+	gate := data.Map("gate")
+	if gate.String("decision") != "go" || gate.Int("risk_score") != 40 {
+		t.Errorf("Map(gate) = %v", gate)
+	}
+	if data.Map("goal") != nil || data.Map("missing").String("x") != "" {
+		t.Errorf("Map on non-object should be nil and safe to read")
+	}
+}
+
+func TestNodeDataEmptyAndInvalid(t *testing.T) {
+	for _, raw := range []string{"", "null", "{}"} {
+		data, err := NewNodeData(&Node{Data: json.RawMessage(raw)})
+		if err != nil || data == nil || len(data) != 0 {
+			t.Errorf("NewNodeData(%q) = %v, %v; want empty, nil", raw, data, err)
+		}
+	}
+	if _, err := NewNodeData(&Node{Data: json.RawMessage(`{bad`)}); err == nil {
+		t.Error("NewNodeData should fail on malformed JSON")
+	}
+	if data := NodeDataOf(&Node{Data: json.RawMessage(`{bad`)}); data == nil || len(data) != 0 {
+		t.Errorf("NodeDataOf(malformed) = %v, want empty", data)
+	}
+}
+
+func TestNodeDataSetMergeJSON(t *testing.T) {
+	data := NodeDataOf(&Node{Data: json.RawMessage(`{"a": "1", "b": "2"}`)})
+	data.Set("c", 3)
+	data.Merge(map[string]any{"a": "x"})
+
+	raw, err := data.JSON()
+	if err != nil {
+		t.Fatalf("JSON: %v", err)
+	}
+	back := NodeDataOf(&Node{Data: raw})
+	if back.String("a") != "x" || back.String("b") != "2" || back.Int("c") != 3 {
+		t.Errorf("round trip = %v", back)
+	}
+}
+
+func TestParsePlanDataSteps(t *testing.T) {
+	node := &Node{Data: json.RawMessage(`{
+		"goal": "g",
+		"steps": ["plain", {"description": "typed", "estimated_lines": 20, "done": true, "files": ["a.go"]}],
+		"gate": {"decision": "approve", "risk_score": 12}
+	}`)}
+	ps, err := parsePlanData(node)
+	if err != nil {
+		t.Fatalf("parsePlanData: %v", err)
+	}
+	if ps.Stage != StageOutline || ps.Goal != "g" {
+		t.Errorf("stage/goal = %s/%s", ps.Stage, ps.Goal)
+	}
+	want := []PlanStep{
+		{Order: 1, Description: "plain"},
+		{Order: 2, Description: "typed", Files: []string{"a.go"}, EstimatedLines: 20, Done: true},
+	}
+	if !reflect.DeepEqual(ps.Steps, want) {
+		t.Errorf("steps = %+v, want %+v", ps.Steps, want)
+	}
+	if ps.Gate == nil || ps.Gate.Decision != "approve" || ps.Gate.RiskScore != 12 {
+		t.Errorf("gate = %+v", ps.Gate)
+	}
+}
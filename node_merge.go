@@ -0,0 +1,141 @@
+package dash
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Merge queries take $1 = keep, $2 = drop.
+const (
+	// Edges between the two nodes would become self-loops.
+	queryMergeDeprecateBetween = `
+		UPDATE edges
+		SET deprecated_at = NOW()
+		WHERE deprecated_at IS NULL
+		  AND ((source_id = $1 AND target_id = $2) OR (source_id = $2 AND target_id = $1))`
+
+	// Active edges of drop that keep already has are deprecated, not moved.
+	queryMergeDeprecateDuplicates = `
+		UPDATE edges e
+		SET deprecated_at = NOW()
+		WHERE e.deprecated_at IS NULL
+		  AND ((e.source_id = $2 AND EXISTS (
+		          SELECT 1 FROM edges k
+		          WHERE k.source_id = $1 AND k.target_id = e.target_id
+		            AND k.relation = e.relation AND k.deprecated_at IS NULL))
+		    OR (e.target_id = $2 AND EXISTS (
+		          SELECT 1 FROM edges k
+		          WHERE k.target_id = $1 AND k.source_id = e.source_id
+		            AND k.relation = e.relation AND k.deprecated_at IS NULL)))`
+
+	queryMergeEdgeSources = `
+		UPDATE edges SET source_id = $1
+		WHERE source_id = $2 AND target_id <> $1`
+
+	queryMergeEdgeTargets = `
+		UPDATE edges SET target_id = $1
+		WHERE target_id = $2 AND source_id <> $1`
+
+	queryMergeEventSources = `UPDATE edge_events SET source_id = $1 WHERE source_id = $2`
+	queryMergeEventTargets = `UPDATE edge_events SET target_id = $1 WHERE target_id = $2`
+	queryMergeObservations = `UPDATE observations SET node_id = $1 WHERE node_id = $2`
+
+	queryMergeNodeData = `
+		UPDATE nodes SET data = $2
+		WHERE id = $1 AND deleted_at IS NULL`
+)
+
+// MergeNodes collapses the duplicate dropID into keepID. Edges, edge events
+// and observations of dropID are moved to keepID, data is merged with keepID
+// winning on conflicting keys, and dropID is soft-deleted. Both nodes must be
+// active and share layer and type. The merge is recorded as a node_merge
+// observation on keepID.
+func (d *Dash) MergeNodes(ctx context.Context, keepID, dropID uuid.UUID) error {
+	if keepID == dropID {
+		return fmt.Errorf("cannot merge a node into itself")
+	}
+	keep, err := d.GetNodeActive(ctx, keepID)
+	if err != nil {
+		return fmt.Errorf("keep: %w", err)
+	}
+	drop, err := d.GetNodeActive(ctx, dropID)
+	if err != nil {
+		return fmt.Errorf("drop: %w", err)
+	}
+	if keep.Layer != drop.Layer || keep.Type != drop.Type {
+		return fmt.Errorf("cannot merge %s.%s into %s.%s", drop.Layer, drop.Type, keep.Layer, keep.Type)
+	}
+
+	data, err := mergeDuplicateData(keep.Data, drop.Data)
+	if err != nil {
+		return err
+	}
+
+	moved := map[string]int64{}
+	err = d.WithTx(ctx, func(tx *sql.Tx) error {
+		steps := []struct {
+			name  string
+			query string
+		}{
+			{"edges_deprecated", queryMergeDeprecateBetween},
+			{"edges_deprecated", queryMergeDeprecateDuplicates},
+			{"edges_moved", queryMergeEdgeSources},
+			{"edges_moved", queryMergeEdgeTargets},
+			{"events_moved", queryMergeEventSources},
+			{"events_moved", queryMergeEventTargets},
+			{"observations_moved", queryMergeObservations},
+		}
+		for _, s := range steps {
+			res, err := tx.ExecContext(ctx, s.query, keepID, dropID)
+			if err != nil {
+				return fmt.Errorf("%s: %w", s.name, err)
+			}
+			n, _ := res.RowsAffected()
+			moved[s.name] += n
+		}
+		if _, err := tx.ExecContext(ctx, queryMergeNodeData, keepID, data); err != nil {
+			return fmt.Errorf("merge data: %w", err)
+		}
+		res, err := tx.ExecContext(ctx, querySoftDeleteNode, dropID)
+		if err != nil {
+			return fmt.Errorf("delete dropped node: %w", err)
+		}
+		if n, _ := res.RowsAffected(); n == 0 {
+			return ErrNodeNotFound
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	obsData, _ := json.Marshal(map[string]any{
+		"dropped_id":         dropID.String(),
+		"dropped_name":       drop.Name,
+		"edges_moved":        moved["edges_moved"],
+		"edges_deprecated":   moved["edges_deprecated"],
+		"events_moved":       moved["events_moved"],
+		"observations_moved": moved["observations_moved"],
+	})
+	_ = d.CreateObservation(ctx, &Observation{
+		NodeID:     keepID,
+		Type:       "node_merge",
+		Data:       obsData,
+		ObservedAt: time.Now(),
+	})
+	return nil
+}
+
+// mergeDuplicateData combines two node data objects. Keys from keep win.
+func mergeDuplicateData(keep, drop json.RawMessage) (json.RawMessage, error) {
+	var keepMap map[string]any
+	if err := json.Unmarshal(keep, &keepMap); err != nil || keepMap == nil {
+		keepMap = make(map[string]any)
+	}
+	return mergeNodeData(drop, keepMap)
+}
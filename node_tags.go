@@ -0,0 +1,114 @@
+package dash
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// Tags are stored as a sorted, lowercase string array in data.tags, so
+// tag lookups are served by the GIN index on nodes.data.
+
+// NodeTags returns the tags stored on a node.
+func NodeTags(node *Node) []string {
+	var data struct {
+		Tags []string `json:"tags"`
+	}
+	if json.Unmarshal(node.Data, &data) != nil {
+		return nil
+	}
+	return data.Tags
+}
+
+// AddTags adds tags to an active node and returns its resulting tags.
+// Tags are trimmed, lowercased and deduplicated.
+func (d *Dash) AddTags(ctx context.Context, id uuid.UUID, tags ...string) ([]string, error) {
+	add := normalizeTags(tags)
+	if len(add) == 0 {
+		return nil, fmt.Errorf("at least one tag is required")
+	}
+	return d.updateNodeTags(ctx, id, func(current []string) []string {
+		return normalizeTags(append(current, add...))
+	})
+}
+
+// RemoveTags removes tags from an active node and returns its remaining tags.
+func (d *Dash) RemoveTags(ctx context.Context, id uuid.UUID, tags ...string) ([]string, error) {
+	drop := make(map[string]bool)
+	for _, t := range normalizeTags(tags) {
+		drop[t] = true
+	}
+	return d.updateNodeTags(ctx, id, func(current []string) []string {
+		kept := []string{}
+		for _, t := range normalizeTags(current) {
+			if !drop[t] {
+				kept = append(kept, t)
+			}
+		}
+		return kept
+	})
+}
+
+// NodesByTag returns active nodes carrying tag, newest first. An empty layer
+// or nodeType matches any.
+func (d *Dash) NodesByTag(ctx context.Context, tag string, layer Layer, nodeType string) ([]*Node, error) {
+	norm := normalizeTags([]string{tag})
+	if len(norm) == 0 {
+		return nil, fmt.Errorf("tag is required")
+	}
+	filter := NodeFilter{
+		DataFilter: map[string]any{"tags": norm},
+		Limit:      1000,
+	}
+	if layer != "" {
+		filter.Layer = &layer
+	}
+	if nodeType != "" {
+		filter.Type = &nodeType
+	}
+	return d.SearchNodes(ctx, filter)
+}
+
+// updateNodeTags applies fn to the node's current tags and writes the result,
+// re-reading and reapplying on concurrent modification.
+func (d *Dash) updateNodeTags(ctx context.Context, id uuid.UUID, fn func([]string) []string) ([]string, error) {
+	node, err := d.GetNodeActive(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	for attempt := 0; attempt < maxNodeDataRetries; attempt++ {
+		tags := fn(NodeTags(node))
+		err = d.UpdateNodeDataCAS(ctx, node, node.UpdatedAt, map[string]any{"tags": tags})
+		if err == nil {
+			return tags, nil
+		}
+		if err != ErrNodeConflict {
+			return nil, err
+		}
+		if node, err = d.GetNodeActive(ctx, id); err != nil {
+			return nil, err
+		}
+	}
+	return nil, ErrNodeConflict
+}
+
+// normalizeTags trims and lowercases tags, drops empty ones and returns the
+// distinct tags sorted.
+func normalizeTags(tags []string) []string {
+	seen := make(map[string]bool)
+	out := []string{}
+	for _, t := range tags {
+		t = strings.ToLower(strings.TrimSpace(t))
+		if t == "" || seen[t] {
+			continue
+		}
+		seen[t] = true
+		out = append(out, t)
+	}
+	sort.Strings(out)
+	return out
+}
@@ -7,8 +7,11 @@ import (
 	"errors"
 	"fmt"
 	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
 var (
@@ -17,6 +20,15 @@ var (
 
 	// ErrNodeDeleted is returned when attempting to access a soft-deleted node.
 	ErrNodeDeleted = errors.New("node has been deleted")
+
+	// ErrNodeConflict is returned when a node changed since it was read.
+	ErrNodeConflict = errors.New("node was modified concurrently")
+
+	// ErrNodeNameTaken is returned when a rename target already exists in the same layer and type.
+	ErrNodeNameTaken = errors.New("node name already exists")
+
+	// ErrNodeAmbiguous is returned when a name matches active nodes in more than one layer or type.
+	ErrNodeAmbiguous = errors.New("node name is ambiguous")
 )
 
 const (
@@ -30,6 +42,11 @@ const (
 		FROM nodes
 		WHERE id = $1 AND deleted_at IS NULL`
 
+	queryGetNodesByIDs = `
+		SELECT id, layer, type, name, data, created_at, updated_at, deleted_at
+		FROM nodes
+		WHERE id = ANY($1) AND deleted_at IS NULL`
+
 	queryListNodes = `
 		SELECT id, layer, type, name, data, created_at, updated_at, deleted_at
 		FROM nodes
@@ -53,6 +70,12 @@ const (
 		FROM nodes
 		WHERE layer = $1 AND type = $2 AND name = $3 AND deleted_at IS NULL`
 
+	queryFindNodesByName = `
+		SELECT id, layer, type, name, data, created_at, updated_at, deleted_at
+		FROM nodes
+		WHERE name = $1 AND deleted_at IS NULL
+		ORDER BY layer, type`
+
 	queryInsertNode = `
 		INSERT INTO nodes (layer, type, name, data)
 		VALUES ($1, $2, $3, $4)
@@ -64,12 +87,34 @@ const (
 		WHERE id = $1 AND deleted_at IS NULL
 		RETURNING updated_at`
 
+	queryUpdateNodeDataCAS = `
+		UPDATE nodes
+		SET data = $2
+		WHERE id = $1 AND updated_at = $3 AND deleted_at IS NULL
+		RETURNING updated_at`
+
+	// SYSTEM.file nodes also carry their path in data; keep it in step with the name.
+	queryRenameNode = `
+		UPDATE nodes
+		SET name = $2,
+		    data = CASE WHEN layer = 'SYSTEM' AND type = 'file' AND data ? 'path'
+		                THEN jsonb_set(data, '{path}', to_jsonb($2::text))
+		                ELSE data END
+		WHERE id = $1 AND deleted_at IS NULL
+		RETURNING updated_at`
+
 	querySoftDeleteNode = `
 		UPDATE nodes
 		SET deleted_at = NOW()
 		WHERE id = $1 AND deleted_at IS NULL
 		RETURNING deleted_at`
 
+	queryRestoreNode = `
+		UPDATE nodes
+		SET deleted_at = NULL
+		WHERE id = $1 AND deleted_at IS NOT NULL
+		RETURNING updated_at`
+
 	querySearchNodes = `
 		SELECT id, layer, type, name, data, created_at, updated_at, deleted_at
 		FROM nodes
@@ -102,6 +147,31 @@ func (d *Dash) GetNodeActive(ctx context.Context, id uuid.UUID) (*Node, error) {
 	return node, err
 }
 
+// GetNodesByIDs retrieves active nodes for the given IDs in a single query.
+// Soft-deleted and unknown IDs are omitted from the returned map.
+func (d *Dash) GetNodesByIDs(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]*Node, error) {
+	if len(ids) == 0 {
+		return map[uuid.UUID]*Node{}, nil
+	}
+
+	rows, err := d.db.QueryContext(ctx, queryGetNodesByIDs, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	nodes, err := scanNodes(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[uuid.UUID]*Node, len(nodes))
+	for _, n := range nodes {
+		byID[n.ID] = n
+	}
+	return byID, nil
+}
+
 // GetNodeByName retrieves an active node by layer, type, and name.
 func (d *Dash) GetNodeByName(ctx context.Context, layer Layer, nodeType, name string) (*Node, error) {
 	row := d.db.QueryRowContext(ctx, queryGetNodeByName, layer, nodeType, name)
@@ -112,6 +182,33 @@ func (d *Dash) GetNodeByName(ctx context.Context, layer Layer, nodeType, name st
 	return node, err
 }
 
+// FindNodeByName retrieves an active node by name alone, across all layers
+// and types. Returns ErrNodeNotFound when nothing matches and ErrNodeAmbiguous,
+// listing the candidate layer.types, when more than one node does.
+func (d *Dash) FindNodeByName(ctx context.Context, name string) (*Node, error) {
+	rows, err := d.db.QueryContext(ctx, queryFindNodesByName, name)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	nodes, err := scanNodes(rows)
+	if err != nil {
+		return nil, err
+	}
+	switch len(nodes) {
+	case 0:
+		return nil, ErrNodeNotFound
+	case 1:
+		return nodes[0], nil
+	}
+	candidates := make([]string, len(nodes))
+	for i, n := range nodes {
+		candidates[i] = fmt.Sprintf("%s.%s", n.Layer, n.Type)
+	}
+	return nil, fmt.Errorf("%w: %q matches %s", ErrNodeAmbiguous, name, strings.Join(candidates, ", "))
+}
+
 // GetNodeByPath finds a SYSTEM.file node by path. It normalizes the path,
 // tries an exact match first, then falls back to matching by basename.
 func (d *Dash) GetNodeByPath(ctx context.Context, path string) (*Node, error) {
@@ -229,10 +326,12 @@ func (d *Dash) SearchNodes(ctx context.Context, filter NodeFilter) ([]*Node, err
 }
 
 // CreateNode creates a new node and returns it with generated fields populated.
+// The caller in ctx (see WithCaller) is stamped as created_by/updated_by.
 func (d *Dash) CreateNode(ctx context.Context, node *Node) error {
 	if node.Data == nil {
 		node.Data = json.RawMessage(`{}`)
 	}
+	node.Data = stampCreated(node.Data, CallerFromContext(ctx))
 
 	err := d.db.QueryRowContext(
 		ctx,
@@ -246,11 +345,13 @@ func (d *Dash) CreateNode(ctx context.Context, node *Node) error {
 	return err
 }
 
-// UpdateNode updates an existing active node.
+// UpdateNode updates an existing active node, stamping the caller in ctx as
+// updated_by.
 func (d *Dash) UpdateNode(ctx context.Context, node *Node) error {
 	if node.Data == nil {
 		node.Data = json.RawMessage(`{}`)
 	}
+	node.Data = stampUpdated(node.Data, CallerFromContext(ctx))
 
 	err := d.db.QueryRowContext(
 		ctx,
@@ -268,6 +369,74 @@ func (d *Dash) UpdateNode(ctx context.Context, node *Node) error {
 	return err
 }
 
+// UpdateNodeDataCAS merges patch into node.Data and writes it only if the row's
+// updated_at still equals expectedUpdatedAt. Returns ErrNodeConflict when
+// another writer got there first; the caller should re-read and retry.
+func (d *Dash) UpdateNodeDataCAS(ctx context.Context, node *Node, expectedUpdatedAt time.Time, patch map[string]any) error {
+	merged, err := mergeNodeData(node.Data, withUpdatedBy(patch, CallerFromContext(ctx)))
+	if err != nil {
+		return err
+	}
+
+	var updatedAt time.Time
+	err = d.db.QueryRowContext(ctx, queryUpdateNodeDataCAS, node.ID, merged, expectedUpdatedAt).Scan(&updatedAt)
+	if err == sql.ErrNoRows {
+		if _, getErr := d.GetNodeActive(ctx, node.ID); getErr != nil {
+			return getErr
+		}
+		return ErrNodeConflict
+	}
+	if err != nil {
+		return err
+	}
+
+	node.Data = merged
+	node.UpdatedAt = updatedAt
+	return nil
+}
+
+// mergeNodeData shallow-merges patch into the JSON object in data.
+func mergeNodeData(data json.RawMessage, patch map[string]any) (json.RawMessage, error) {
+	var existing map[string]any
+	if err := json.Unmarshal(data, &existing); err != nil || existing == nil {
+		existing = make(map[string]any)
+	}
+	for k, v := range patch {
+		existing[k] = v
+	}
+	return json.Marshal(existing)
+}
+
+// RenameNode changes a node's name in place, so its ID, edges, observations
+// and embedding are kept. Returns ErrNodeNameTaken if another active node of
+// the same layer and type already has newName.
+func (d *Dash) RenameNode(ctx context.Context, id uuid.UUID, newName string) error {
+	if newName == "" {
+		return fmt.Errorf("new name is required")
+	}
+	node, err := d.GetNodeActive(ctx, id)
+	if err != nil {
+		return err
+	}
+	if node.Name == newName {
+		return nil
+	}
+	if existing, err := d.GetNodeByName(ctx, node.Layer, node.Type, newName); err == nil && existing != nil {
+		return fmt.Errorf("%w: %s.%s %q", ErrNodeNameTaken, node.Layer, node.Type, newName)
+	}
+
+	var updatedAt time.Time
+	err = d.db.QueryRowContext(ctx, queryRenameNode, id, newName).Scan(&updatedAt)
+	if err == sql.ErrNoRows {
+		return ErrNodeNotFound
+	}
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" { // unique_violation: lost a race
+		return fmt.Errorf("%w: %s.%s %q", ErrNodeNameTaken, node.Layer, node.Type, newName)
+	}
+	return err
+}
+
 // SoftDeleteNode soft-deletes a node by setting deleted_at.
 // This also cascades to deprecate related edges via trigger.
 func (d *Dash) SoftDeleteNode(ctx context.Context, id uuid.UUID) error {
@@ -280,6 +449,18 @@ func (d *Dash) SoftDeleteNode(ctx context.Context, id uuid.UUID) error {
 	return err
 }
 
+// RestoreNode clears deleted_at on a soft-deleted node, undoing SoftDeleteNode.
+// Edges deprecated by the delete are not revived.
+func (d *Dash) RestoreNode(ctx context.Context, id uuid.UUID) error {
+	var updatedAt time.Time
+	err := d.db.QueryRowContext(ctx, queryRestoreNode, id).Scan(&updatedAt)
+
+	if err == sql.ErrNoRows {
+		return ErrNodeNotFound
+	}
+	return err
+}
+
 // UpdateTaskStatus updates the status field in a task node's data.
 func (d *Dash) UpdateTaskStatus(ctx context.Context, id uuid.UUID, newStatus string) error {
 	node, err := d.GetNodeActive(ctx, id)
@@ -321,7 +502,7 @@ func scanNodes(rows *sql.Rows) ([]*Node, error) {
 
 const queryUpdateNodeEmbedding = `
 	UPDATE nodes
-	SET embedding = $2, content_hash = $3, embedding_at = NOW()
+	SET embedding = $2, content_hash = $3, embedding_at = NOW(), embedding_model = NULLIF($4, '')
 	WHERE id = $1 AND deleted_at IS NULL
 	RETURNING embedding_at`
 
@@ -331,6 +512,7 @@ const queryGetNodeWithEmbedding = `
 	WHERE id = $1 AND deleted_at IS NULL`
 
 // UpdateNodeEmbedding updates the embedding, content hash, and embedding timestamp for a node.
+// The active embedding model is recorded alongside the vector.
 func (d *Dash) UpdateNodeEmbedding(ctx context.Context, id uuid.UUID, embedding []float32, contentHash string) error {
 	// Convert []float32 to pgvector format string: [0.1,0.2,...]
 	var embeddingArg any
@@ -339,7 +521,7 @@ func (d *Dash) UpdateNodeEmbedding(ctx context.Context, id uuid.UUID, embedding
 	}
 
 	var embeddingAt sql.NullTime
-	err := d.db.QueryRowContext(ctx, queryUpdateNodeEmbedding, id, embeddingArg, contentHash).Scan(&embeddingAt)
+	err := d.db.QueryRowContext(ctx, queryUpdateNodeEmbedding, id, embeddingArg, contentHash, d.EmbeddingModel()).Scan(&embeddingAt)
 	if err == sql.ErrNoRows {
 		return ErrNodeNotFound
 	}
@@ -362,6 +544,60 @@ func (d *Dash) GetNodeContentHash(ctx context.Context, id uuid.UUID) (string, er
 	return hash.String, nil
 }
 
+const queryGetNodeContentHashes = `
+	SELECT id, content_hash FROM nodes
+	WHERE id = ANY($1) AND deleted_at IS NULL AND content_hash IS NOT NULL`
+
+// GetNodeContentHashes is the batch form of GetNodeContentHash. Nodes that
+// are deleted, unknown or have no hash are omitted from the returned map.
+func (d *Dash) GetNodeContentHashes(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]string, error) {
+	hashes := make(map[uuid.UUID]string, len(ids))
+	if len(ids) == 0 {
+		return hashes, nil
+	}
+	rows, err := d.db.QueryContext(ctx, queryGetNodeContentHashes, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id uuid.UUID
+		var hash string
+		if err := rows.Scan(&id, &hash); err != nil {
+			return nil, err
+		}
+		hashes[id] = hash
+	}
+	return hashes, rows.Err()
+}
+
+// GetNodeEmbeddingModel returns the model that produced a node's embedding.
+// Returns empty string if the node doesn't exist or the model is unknown.
+func (d *Dash) GetNodeEmbeddingModel(ctx context.Context, id uuid.UUID) (string, error) {
+	var model sql.NullString
+	err := d.db.QueryRowContext(ctx, `
+		SELECT embedding_model FROM nodes WHERE id = $1 AND deleted_at IS NULL
+	`, id).Scan(&model)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return model.String, nil
+}
+
+// EmbeddingUpToDate reports whether a node's stored embedding was produced
+// from contentHash by the active embedding model.
+func (d *Dash) EmbeddingUpToDate(ctx context.Context, id uuid.UUID, contentHash string) bool {
+	existingHash, _ := d.GetNodeContentHash(ctx, id)
+	if existingHash != contentHash {
+		return false
+	}
+	existingModel, _ := d.GetNodeEmbeddingModel(ctx, id)
+	return existingModel == d.EmbeddingModel()
+}
+
 // float32SliceToVector converts a float32 slice to pgvector format string.
 func float32SliceToVector(v []float32) string {
 	if len(v) == 0 {
@@ -0,0 +1,866 @@
+package dash
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// testDash returns a Dash client backed by DASH_TEST_DATABASE_URL, or skips the
+// test when no test database is configured.
+func testDash(t *testing.T) *Dash {
+	t.Helper()
+	dbURL := os.Getenv("DASH_TEST_DATABASE_URL")
+	if dbURL == "" {
+		t.Skip("DASH_TEST_DATABASE_URL not set")
+	}
+	db, err := sql.Open("postgres", dbURL)
+	if err != nil {
+		t.Fatalf("db open: %v", err)
+	}
+	if err := db.Ping(); err != nil {
+		db.Close()
+		t.Skipf("db ping: %v", err)
+	}
+	d, err := New(Config{DB: db})
+	if err != nil {
+		db.Close()
+		t.Fatalf("new dash: %v", err)
+	}
+	t.Cleanup(func() { d.Close() })
+	return d
+}
+
+func TestGetNodesByIDsOmitsDeleted(t *testing.T) {
+	d := testDash(t)
+	ctx := context.Background()
+	prefix := fmt.Sprintf("test-bulk-%d", time.Now().UnixNano())
+
+	var ids []uuid.UUID
+	for i := 0; i < 4; i++ {
+		n := &Node{Layer: LayerContext, Type: "test_node", Name: fmt.Sprintf("%s-%d", prefix, i)}
+		if err := d.CreateNode(ctx, n); err != nil {
+			t.Fatalf("create node %d: %v", i, err)
+		}
+		ids = append(ids, n.ID)
+		t.Cleanup(func() { d.SoftDeleteNode(ctx, n.ID) })
+	}
+
+	// Delete two of the four.
+	if err := d.SoftDeleteNode(ctx, ids[1]); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if err := d.SoftDeleteNode(ctx, ids[3]); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+
+	// Request in reverse order, plus an unknown ID.
+	req := []uuid.UUID{ids[3], ids[2], uuid.New(), ids[1], ids[0]}
+	got, err := d.GetNodesByIDs(ctx, req)
+	if err != nil {
+		t.Fatalf("GetNodesByIDs: %v", err)
+	}
+
+	if len(got) != 2 {
+		t.Fatalf("len = %d, want 2", len(got))
+	}
+	for _, id := range []uuid.UUID{ids[0], ids[2]} {
+		if n, ok := got[id]; !ok || n.ID != id {
+			t.Errorf("missing active node %s", id)
+		}
+	}
+	for _, id := range []uuid.UUID{ids[1], ids[3]} {
+		if _, ok := got[id]; ok {
+			t.Errorf("deleted node %s should be omitted", id)
+		}
+	}
+}
+
+func TestGetNodesByIDsEmpty(t *testing.T) {
+	d := &Dash{}
+	got, err := d.GetNodesByIDs(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("GetNodesByIDs: %v", err)
+	}
+	if len(got) != 0 {
+		t.Errorf("len = %d, want 0", len(got))
+	}
+}
+
+func TestUpdateNodeDataConcurrentPatchesMerge(t *testing.T) {
+	d := testDash(t)
+	ctx := context.Background()
+
+	n := &Node{Layer: LayerContext, Type: "test_node", Name: fmt.Sprintf("test-cas-%d", time.Now().UnixNano())}
+	if err := d.CreateNode(ctx, n); err != nil {
+		t.Fatalf("create node: %v", err)
+	}
+	t.Cleanup(func() { d.SoftDeleteNode(ctx, n.ID) })
+
+	// Both writers start from the same stale snapshot.
+	a, err := d.GetNodeActive(ctx, n.ID)
+	if err != nil {
+		t.Fatalf("get a: %v", err)
+	}
+	b := *a
+
+	if err := d.UpdateNodeData(ctx, a, map[string]any{"summary": "s"}); err != nil {
+		t.Fatalf("update a: %v", err)
+	}
+	if err := d.UpdateNodeDataCAS(ctx, &b, b.UpdatedAt, map[string]any{"embedding_note": "e"}); err != ErrNodeConflict {
+		t.Fatalf("stale CAS err = %v, want ErrNodeConflict", err)
+	}
+	if err := d.UpdateNodeData(ctx, &b, map[string]any{"embedding_note": "e"}); err != nil {
+		t.Fatalf("update b: %v", err)
+	}
+
+	got, err := d.GetNodeActive(ctx, n.ID)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	var data map[string]any
+	if err := json.Unmarshal(got.Data, &data); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if data["summary"] != "s" || data["embedding_note"] != "e" {
+		t.Errorf("data = %v, want both patches", data)
+	}
+}
+
+func TestUpdateNodeDataParallel(t *testing.T) {
+	d := testDash(t)
+	ctx := context.Background()
+
+	n := &Node{Layer: LayerContext, Type: "test_node", Name: fmt.Sprintf("test-cas-par-%d", time.Now().UnixNano())}
+	if err := d.CreateNode(ctx, n); err != nil {
+		t.Fatalf("create node: %v", err)
+	}
+	t.Cleanup(func() { d.SoftDeleteNode(ctx, n.ID) })
+
+	const writers = 4
+	var wg sync.WaitGroup
+	errs := make(chan error, writers)
+	for i := 0; i < writers; i++ {
+		snapshot := *n
+		wg.Add(1)
+		go func(i int, node *Node) {
+			defer wg.Done()
+			errs <- d.UpdateNodeData(ctx, node, map[string]any{fmt.Sprintf("k%d", i): i})
+		}(i, &snapshot)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("update: %v", err)
+		}
+	}
+
+	got, err := d.GetNodeActive(ctx, n.ID)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	var data map[string]any
+	json.Unmarshal(got.Data, &data)
+	for i := 0; i < writers; i++ {
+		if _, ok := data[fmt.Sprintf("k%d", i)]; !ok {
+			t.Errorf("patch k%d lost: %v", i, data)
+		}
+	}
+}
+
+func TestGetOrCreateNodeWithStatusConcurrent(t *testing.T) {
+	d := testDash(t)
+	ctx := context.Background()
+	name := fmt.Sprintf("test-get-or-create-%d", time.Now().UnixNano())
+
+	const callers = 8
+	type result struct {
+		node    *Node
+		created bool
+		err     error
+	}
+	results := make(chan result, callers)
+	start := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			n, created, err := d.GetOrCreateNodeWithStatus(ctx, LayerContext, "test_node", name, map[string]any{"k": 1})
+			results <- result{n, created, err}
+		}()
+	}
+	close(start)
+	wg.Wait()
+	close(results)
+
+	var id uuid.UUID
+	created := 0
+	for r := range results {
+		if r.err != nil {
+			t.Fatalf("get or create: %v", r.err)
+		}
+		if id == uuid.Nil {
+			id = r.node.ID
+			t.Cleanup(func() { d.SoftDeleteNode(ctx, id) })
+		}
+		if r.node.ID != id {
+			t.Errorf("callers got different nodes: %s and %s", id, r.node.ID)
+		}
+		if r.created {
+			created++
+		}
+	}
+	if created != 1 {
+		t.Errorf("%d callers reported creating the node, want 1", created)
+	}
+
+	var rows int
+	if err := d.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM nodes WHERE layer = 'CONTEXT' AND type = 'test_node' AND name = $1`, name).Scan(&rows); err != nil {
+		t.Fatal(err)
+	}
+	if rows != 1 {
+		t.Errorf("%d rows for %s, want 1", rows, name)
+	}
+
+	if _, created, err := d.GetOrCreateNodeWithStatus(ctx, LayerContext, "test_node", name, nil); err != nil || created {
+		t.Errorf("second call: created=%v err=%v, want existing node", created, err)
+	}
+}
+
+func TestMergeNodeData(t *testing.T) {
+	got, err := mergeNodeData(json.RawMessage(`{"a":1,"b":2}`), map[string]any{"b": 3, "c": 4})
+	if err != nil {
+		t.Fatalf("merge: %v", err)
+	}
+	var m map[string]float64
+	json.Unmarshal(got, &m)
+	if m["a"] != 1 || m["b"] != 3 || m["c"] != 4 {
+		t.Errorf("merged = %v", m)
+	}
+
+	got, err = mergeNodeData(nil, map[string]any{"x": "y"})
+	if err != nil || string(got) != `{"x":"y"}` {
+		t.Errorf("merge nil = %s, %v", got, err)
+	}
+}
+
+func TestRestoreNode(t *testing.T) {
+	d := testDash(t)
+	ctx := context.Background()
+
+	n := &Node{Layer: LayerContext, Type: "test_node", Name: fmt.Sprintf("test-restore-%d", time.Now().UnixNano())}
+	if err := d.CreateNode(ctx, n); err != nil {
+		t.Fatalf("create node: %v", err)
+	}
+	t.Cleanup(func() { d.SoftDeleteNode(ctx, n.ID) })
+
+	if err := d.RestoreNode(ctx, n.ID); err != ErrNodeNotFound {
+		t.Errorf("restore active node err = %v, want ErrNodeNotFound", err)
+	}
+	if err := d.SoftDeleteNode(ctx, n.ID); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if err := d.RestoreNode(ctx, n.ID); err != nil {
+		t.Fatalf("restore: %v", err)
+	}
+	if _, err := d.GetNodeActive(ctx, n.ID); err != nil {
+		t.Errorf("node should be active after restore: %v", err)
+	}
+}
+
+// modelEmbedder is a fixed-vector embedder that reports a configurable model.
+type modelEmbedder struct{ model string }
+
+func (e *modelEmbedder) Embed(ctx context.Context, text string) ([]float32, error) {
+	return make([]float32, 1536), nil
+}
+
+func (e *modelEmbedder) EmbeddingModel() string { return e.model }
+
+func TestEmbeddingUpToDateTracksModel(t *testing.T) {
+	base := testDash(t)
+	emb := &modelEmbedder{model: "model-a"}
+	d, err := New(Config{DB: base.db, Embedder: emb})
+	if err != nil {
+		t.Fatalf("new dash: %v", err)
+	}
+	ctx := context.Background()
+
+	n := &Node{Layer: LayerContext, Type: "test_node", Name: fmt.Sprintf("test-embed-model-%d", time.Now().UnixNano())}
+	if err := d.CreateNode(ctx, n); err != nil {
+		t.Fatalf("create node: %v", err)
+	}
+	t.Cleanup(func() { d.SoftDeleteNode(ctx, n.ID) })
+
+	if err := d.UpdateNodeEmbedding(ctx, n.ID, make([]float32, 1536), "h1"); err != nil {
+		t.Fatalf("update embedding: %v", err)
+	}
+	if model, err := d.GetNodeEmbeddingModel(ctx, n.ID); err != nil || model != "model-a" {
+		t.Errorf("stored model = %q, %v; want model-a", model, err)
+	}
+	if !d.EmbeddingUpToDate(ctx, n.ID, "h1") {
+		t.Error("same hash and model should be up to date")
+	}
+	if d.EmbeddingUpToDate(ctx, n.ID, "h2") {
+		t.Error("changed hash should be stale")
+	}
+	emb.model = "model-b"
+	if d.EmbeddingUpToDate(ctx, n.ID, "h1") {
+		t.Error("changed model should be stale")
+	}
+}
+
+func TestRenameNode(t *testing.T) {
+	d := testDash(t)
+	ctx := context.Background()
+	suffix := time.Now().UnixNano()
+
+	a := &Node{Layer: LayerContext, Type: "test_node", Name: fmt.Sprintf("test-rename-a-%d", suffix)}
+	b := &Node{Layer: LayerContext, Type: "test_node", Name: fmt.Sprintf("test-rename-b-%d", suffix)}
+	for _, n := range []*Node{a, b} {
+		if err := d.CreateNode(ctx, n); err != nil {
+			t.Fatalf("create node: %v", err)
+		}
+		t.Cleanup(func() { d.SoftDeleteNode(ctx, n.ID) })
+	}
+	if err := d.CreateEdge(ctx, &Edge{SourceID: a.ID, TargetID: b.ID, Relation: RelationDependsOn}); err != nil {
+		t.Fatalf("create edge: %v", err)
+	}
+
+	newName := fmt.Sprintf("test-rename-moved-%d", suffix)
+	if err := d.RenameNode(ctx, a.ID, newName); err != nil {
+		t.Fatalf("rename: %v", err)
+	}
+	got, err := d.GetNodeByName(ctx, LayerContext, "test_node", newName)
+	if err != nil || got.ID != a.ID {
+		t.Fatalf("lookup by new name = %v, %v; want id %s", got, err, a.ID)
+	}
+	edges, err := d.ListEdgesBySource(ctx, a.ID)
+	if err != nil || len(edges) != 1 || edges[0].TargetID != b.ID {
+		t.Errorf("edges after rename = %v, %v", edges, err)
+	}
+
+	if err := d.RenameNode(ctx, a.ID, b.Name); !errors.Is(err, ErrNodeNameTaken) {
+		t.Errorf("rename onto existing name err = %v, want ErrNodeNameTaken", err)
+	}
+	if err := d.RenameNode(ctx, a.ID, newName); err != nil {
+		t.Errorf("rename to current name should be a no-op: %v", err)
+	}
+}
+
+func TestStoreObservationForNodeCreatesFileNode(t *testing.T) {
+	d := testDash(t)
+	ctx := context.Background()
+	path := fmt.Sprintf("/tmp/test-obs-%d.go", time.Now().UnixNano())
+
+	if err := d.StoreObservationForNode(ctx, LayerSystem, "file", path, "lint_result", map[string]any{"ok": false}); err != nil {
+		t.Fatalf("store: %v", err)
+	}
+	node, err := d.GetNodeByName(ctx, LayerSystem, "file", path)
+	if err != nil {
+		t.Fatalf("file node not created: %v", err)
+	}
+	t.Cleanup(func() { d.SoftDeleteNode(ctx, node.ID) })
+
+	// A second call reuses the same node.
+	if err := d.StoreObservationForNode(ctx, LayerSystem, "file", path, "lint_result", map[string]any{"ok": true}); err != nil {
+		t.Fatalf("store again: %v", err)
+	}
+	tr := TimeRange{Start: time.Now().Add(-time.Minute), End: time.Now().Add(time.Minute)}
+	obs, err := d.ListObservationsByNodeType(ctx, node.ID, "lint_result", tr)
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(obs) != 2 {
+		t.Errorf("observations = %d, want 2", len(obs))
+	}
+}
+
+func TestLinkedIntentFollowsOneHop(t *testing.T) {
+	d := testDash(t)
+	ctx := context.Background()
+	prefix := fmt.Sprintf("test-intent-%d", time.Now().UnixNano())
+
+	mk := func(typ string) *Node {
+		n := &Node{Layer: LayerContext, Type: typ, Name: prefix + "-" + typ}
+		if err := d.CreateNode(ctx, n); err != nil {
+			t.Fatalf("create %s: %v", typ, err)
+		}
+		t.Cleanup(func() { d.SoftDeleteNode(ctx, n.ID) })
+		return n
+	}
+	intent, task, plan := mk("intent"), mk("task"), mk("plan")
+	for _, e := range []*Edge{
+		{SourceID: task.ID, TargetID: intent.ID, Relation: RelationImplements},
+		{SourceID: plan.ID, TargetID: task.ID, Relation: RelationImplements},
+	} {
+		if err := d.CreateEdge(ctx, e); err != nil {
+			t.Fatalf("create edge: %v", err)
+		}
+	}
+
+	for _, n := range []*Node{task, plan} {
+		got, err := d.LinkedIntent(ctx, n.ID)
+		if err != nil || got.ID != intent.ID {
+			t.Errorf("LinkedIntent(%s) = %v, %v; want %s", n.Type, got, err, intent.Name)
+		}
+	}
+	if _, err := d.LinkedIntent(ctx, intent.ID); !errors.Is(err, ErrNodeNotFound) {
+		t.Errorf("LinkedIntent(intent) err = %v, want ErrNodeNotFound", err)
+	}
+}
+
+func TestRelatedNodesExcludesSelf(t *testing.T) {
+	d := testDash(t)
+	ctx := context.Background()
+	prefix := fmt.Sprintf("test-related-%d", time.Now().UnixNano())
+
+	vec := func(x, y float32) []float32 {
+		v := make([]float32, 1536)
+		v[0], v[1] = x, y
+		return v
+	}
+	var nodes []*Node
+	for i, v := range [][]float32{vec(1, 0), vec(1, 0.1), vec(0, 1)} {
+		n := &Node{Layer: LayerContext, Type: "test_node", Name: fmt.Sprintf("%s-%d", prefix, i)}
+		if err := d.CreateNode(ctx, n); err != nil {
+			t.Fatalf("create node %d: %v", i, err)
+		}
+		t.Cleanup(func() { d.SoftDeleteNode(ctx, n.ID) })
+		if err := d.UpdateNodeEmbedding(ctx, n.ID, v, fmt.Sprintf("h%d", i)); err != nil {
+			t.Fatalf("embed node %d: %v", i, err)
+		}
+		nodes = append(nodes, n)
+	}
+
+	results, err := d.RelatedNodes(ctx, nodes[0].ID, 100)
+	if err != nil {
+		t.Fatalf("RelatedNodes: %v", err)
+	}
+	pos := map[uuid.UUID]int{}
+	for i, r := range results {
+		if r.ID == nodes[0].ID {
+			t.Fatal("RelatedNodes returned the node itself")
+		}
+		pos[r.ID] = i
+	}
+	near, okNear := pos[nodes[1].ID]
+	far, okFar := pos[nodes[2].ID]
+	if !okNear || !okFar || near > far {
+		t.Errorf("want %s ranked before %s, got positions %d/%v and %d/%v", nodes[1].Name, nodes[2].Name, near, okNear, far, okFar)
+	}
+}
+
+func TestFullTextSearchMatchesData(t *testing.T) {
+	d := testDash(t)
+	ctx := context.Background()
+	word := fmt.Sprintf("zqxft%d", time.Now().UnixNano())
+
+	hit := &Node{Layer: LayerContext, Type: "test_node", Name: word + "-hit",
+		Data: json.RawMessage(fmt.Sprintf(`{"description": "retry the %s build with backoff"}`, word))}
+	miss := &Node{Layer: LayerContext, Type: "test_node", Name: word + "-miss",
+		Data: json.RawMessage(`{"description": "unrelated"}`)}
+	for _, n := range []*Node{hit, miss} {
+		if err := d.CreateNode(ctx, n); err != nil {
+			t.Fatalf("create node: %v", err)
+		}
+		t.Cleanup(func() { d.SoftDeleteNode(ctx, n.ID) })
+	}
+
+	results, err := d.FullTextSearch(ctx, word+" backoff", 10)
+	if err != nil {
+		t.Fatalf("FullTextSearch: %v", err)
+	}
+	if len(results) != 1 || results[0].ID != hit.ID {
+		t.Fatalf("got %d results, want only %s", len(results), hit.Name)
+	}
+	if !strings.Contains(results[0].Snippet, "**backoff**") {
+		t.Errorf("snippet %q does not highlight the match", results[0].Snippet)
+	}
+}
+
+func TestPendingToolUseConsumedOnce(t *testing.T) {
+	d := testDash(t)
+	ctx := context.Background()
+	id := fmt.Sprintf("test-pending-%d", time.Now().UnixNano())
+	started := time.Now().Add(-3 * time.Second).Truncate(time.Millisecond)
+
+	if err := d.RecordPendingToolUse(ctx, id, "test-session", "Bash", started); err != nil {
+		t.Fatalf("RecordPendingToolUse: %v", err)
+	}
+	got, err := d.ConsumePendingToolUse(ctx, id)
+	if err != nil {
+		t.Fatalf("ConsumePendingToolUse: %v", err)
+	}
+	if !got.Equal(started) {
+		t.Errorf("started_at = %v, want %v", got, started)
+	}
+	again, err := d.ConsumePendingToolUse(ctx, id)
+	if err != nil || !again.IsZero() {
+		t.Errorf("second consume = %v, %v; want zero time", again, err)
+	}
+}
+
+func TestDanglingToolUsesReadsPending(t *testing.T) {
+	d := testDash(t)
+	ctx := context.Background()
+	stale := fmt.Sprintf("test-dangling-%d", time.Now().UnixNano())
+	fresh := stale + "-fresh"
+
+	if err := d.RecordPendingToolUse(ctx, stale, "test-session", "Bash", time.Now().Add(-10*time.Minute)); err != nil {
+		t.Fatalf("RecordPendingToolUse: %v", err)
+	}
+	if err := d.RecordPendingToolUse(ctx, fresh, "test-session", "Read", time.Now()); err != nil {
+		t.Fatalf("RecordPendingToolUse: %v", err)
+	}
+	t.Cleanup(func() {
+		d.ConsumePendingToolUse(ctx, stale)
+		d.ConsumePendingToolUse(ctx, fresh)
+	})
+
+	found := func() map[string]DanglingToolUse {
+		got, err := d.DanglingToolUses(ctx, 5*time.Minute)
+		if err != nil {
+			t.Fatalf("DanglingToolUses: %v", err)
+		}
+		byID := make(map[string]DanglingToolUse)
+		for _, u := range got {
+			byID[u.ToolUseID] = u
+		}
+		return byID
+	}
+
+	got := found()
+	if u, ok := got[stale]; !ok || u.ToolName != "Bash" || u.SessionID != "test-session" {
+		t.Errorf("stale call = %+v (found %v), want Bash in test-session", u, ok)
+	}
+	if _, ok := got[fresh]; ok {
+		t.Error("fresh call should not be dangling yet")
+	}
+
+	if _, err := d.ConsumePendingToolUse(ctx, stale); err != nil {
+		t.Fatalf("ConsumePendingToolUse: %v", err)
+	}
+	if _, ok := found()[stale]; ok {
+		t.Error("completed call should no longer be dangling")
+	}
+}
+
+func TestFindNodeByNameAmbiguous(t *testing.T) {
+	d := testDash(t)
+	ctx := context.Background()
+	name := fmt.Sprintf("test-find-%d", time.Now().UnixNano())
+
+	a := &Node{Layer: LayerContext, Type: "test_node", Name: name}
+	if err := d.CreateNode(ctx, a); err != nil {
+		t.Fatalf("create node: %v", err)
+	}
+	t.Cleanup(func() { d.SoftDeleteNode(ctx, a.ID) })
+
+	got, err := d.FindNodeByName(ctx, name)
+	if err != nil || got.ID != a.ID {
+		t.Fatalf("FindNodeByName = %v, %v; want %s", got, err, a.ID)
+	}
+
+	b := &Node{Layer: LayerSystem, Type: "test_node", Name: name}
+	if err := d.CreateNode(ctx, b); err != nil {
+		t.Fatalf("create node: %v", err)
+	}
+	t.Cleanup(func() { d.SoftDeleteNode(ctx, b.ID) })
+
+	_, err = d.FindNodeByName(ctx, name)
+	if !errors.Is(err, ErrNodeAmbiguous) {
+		t.Fatalf("err = %v, want ErrNodeAmbiguous", err)
+	}
+	if !strings.Contains(err.Error(), "CONTEXT.test_node") || !strings.Contains(err.Error(), "SYSTEM.test_node") {
+		t.Errorf("error %q does not list candidates", err)
+	}
+
+	if _, err := d.FindNodeByName(ctx, name+"-missing"); !errors.Is(err, ErrNodeNotFound) {
+		t.Errorf("missing name: err = %v, want ErrNodeNotFound", err)
+	}
+}
+
+func TestFileHotspotsCountsSessions(t *testing.T) {
+	d := testDash(t)
+	ctx := context.Background()
+	prefix := fmt.Sprintf("test-hotspot-%d", time.Now().UnixNano())
+
+	file := &Node{Layer: LayerSystem, Type: "file", Name: "/tmp/" + prefix + ".go"}
+	if err := d.CreateNode(ctx, file); err != nil {
+		t.Fatalf("create file node: %v", err)
+	}
+	t.Cleanup(func() { d.SoftDeleteNode(ctx, file.ID) })
+
+	since := time.Now().Add(-time.Minute)
+	for i := 0; i < 2; i++ {
+		session := &Node{Layer: LayerContext, Type: "session", Name: fmt.Sprintf("%s-s%d", prefix, i)}
+		if err := d.CreateNode(ctx, session); err != nil {
+			t.Fatalf("create session: %v", err)
+		}
+		t.Cleanup(func() { d.SoftDeleteNode(ctx, session.ID) })
+		for j := 0; j < 2; j++ {
+			if err := d.CreateEdgeEvent(ctx, &EdgeEvent{
+				SourceID: session.ID, TargetID: file.ID,
+				Relation: EventRelationModified, Success: true, OccurredAt: time.Now(),
+			}); err != nil {
+				t.Fatalf("create edge event: %v", err)
+			}
+		}
+	}
+
+	hotspots, err := d.FileHotspots(ctx, since, 100)
+	if err != nil {
+		t.Fatalf("FileHotspots: %v", err)
+	}
+	for _, h := range hotspots {
+		if h.FilePath == file.Name {
+			if h.ModifyCount != 4 || h.SessionCount != 2 || h.LastModified.Before(since) {
+				t.Errorf("got %+v, want 4 modifications across 2 sessions", h)
+			}
+			return
+		}
+	}
+	t.Errorf("%s missing from hotspots", file.Name)
+}
+
+func TestNormalizeTags(t *testing.T) {
+	got := normalizeTags([]string{" WIP", "reviewed", "wip", "", "  ", "External"})
+	want := []string{"external", "reviewed", "wip"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Fatalf("normalizeTags = %v, want %v", got, want)
+	}
+}
+
+func TestNodeTags(t *testing.T) {
+	d := testDash(t)
+	ctx := context.Background()
+	tag := fmt.Sprintf("test-tag-%d", time.Now().UnixNano())
+
+	n := &Node{Layer: LayerContext, Type: "test_node", Name: tag + "-node"}
+	if err := d.CreateNode(ctx, n); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	t.Cleanup(func() { d.SoftDeleteNode(ctx, n.ID) })
+
+	tags, err := d.AddTags(ctx, n.ID, strings.ToUpper(tag), "wip", "WIP")
+	if err != nil {
+		t.Fatalf("add tags: %v", err)
+	}
+	if strings.Join(tags, ",") != tag+",wip" {
+		t.Fatalf("tags after add = %v", tags)
+	}
+
+	nodes, err := d.NodesByTag(ctx, tag, LayerContext, "test_node")
+	if err != nil {
+		t.Fatalf("by tag: %v", err)
+	}
+	if len(nodes) != 1 || nodes[0].ID != n.ID {
+		t.Fatalf("NodesByTag returned %d nodes", len(nodes))
+	}
+
+	tags, err = d.RemoveTags(ctx, n.ID, tag)
+	if err != nil {
+		t.Fatalf("remove tags: %v", err)
+	}
+	if strings.Join(tags, ",") != "wip" {
+		t.Fatalf("tags after remove = %v", tags)
+	}
+	nodes, err = d.NodesByTag(ctx, tag, "", "")
+	if err != nil {
+		t.Fatalf("by tag: %v", err)
+	}
+	if len(nodes) != 0 {
+		t.Fatalf("NodesByTag after remove returned %d nodes", len(nodes))
+	}
+}
+
+func TestMergeDuplicateData(t *testing.T) {
+	got, err := mergeDuplicateData(json.RawMessage(`{"a":"keep","b":"keep"}`), json.RawMessage(`{"b":"drop","c":"drop"}`))
+	if err != nil {
+		t.Fatalf("merge: %v", err)
+	}
+	var m map[string]string
+	json.Unmarshal(got, &m)
+	if m["a"] != "keep" || m["b"] != "keep" || m["c"] != "drop" {
+		t.Errorf("merged = %v", m)
+	}
+}
+
+func TestMergeNodes(t *testing.T) {
+	d := testDash(t)
+	ctx := context.Background()
+	suffix := time.Now().UnixNano()
+
+	newNode := func(typ, name, data string) *Node {
+		n := &Node{Layer: LayerContext, Type: typ, Name: fmt.Sprintf("test-merge-%s-%d", name, suffix), Data: json.RawMessage(data)}
+		if err := d.CreateNode(ctx, n); err != nil {
+			t.Fatalf("create node: %v", err)
+		}
+		t.Cleanup(func() { d.SoftDeleteNode(ctx, n.ID) })
+		return n
+	}
+	keep := newNode("test_node", "keep", `{"summary":"keep"}`)
+	drop := newNode("test_node", "drop", `{"summary":"drop","extra":"drop"}`)
+	x := newNode("test_node", "x", `{}`)
+	y := newNode("test_node", "y", `{}`)
+	other := newNode("test_other", "other", `{}`)
+
+	for _, e := range []*Edge{
+		{SourceID: drop.ID, TargetID: x.ID, Relation: RelationDependsOn},
+		{SourceID: y.ID, TargetID: drop.ID, Relation: RelationDependsOn},
+		{SourceID: keep.ID, TargetID: x.ID, Relation: RelationDependsOn}, // duplicate after merge
+		{SourceID: keep.ID, TargetID: drop.ID, Relation: RelationDependsOn},
+	} {
+		if err := d.CreateEdge(ctx, e); err != nil {
+			t.Fatalf("create edge: %v", err)
+		}
+	}
+	if err := d.CreateEdgeEvent(ctx, &EdgeEvent{SourceID: drop.ID, TargetID: x.ID, Relation: EventRelationObserved}); err != nil {
+		t.Fatalf("create edge event: %v", err)
+	}
+
+	if err := d.MergeNodes(ctx, keep.ID, other.ID); err == nil {
+		t.Error("merging nodes of different types should fail")
+	}
+	if err := d.MergeNodes(ctx, keep.ID, drop.ID); err != nil {
+		t.Fatalf("merge: %v", err)
+	}
+
+	if _, err := d.GetNodeActive(ctx, drop.ID); err == nil {
+		t.Error("dropped node should be soft-deleted")
+	}
+	out, _ := d.ListEdgesBySource(ctx, drop.ID)
+	in, _ := d.ListEdgesByTarget(ctx, drop.ID)
+	if len(out)+len(in) != 0 {
+		t.Errorf("dangling active edges on dropped node: %d out, %d in", len(out), len(in))
+	}
+	if edges, _ := d.ListEdgesBetween(ctx, keep.ID, x.ID); len(edges) != 1 {
+		t.Errorf("keep→x edges = %d, want 1", len(edges))
+	}
+	if edges, _ := d.ListEdgesBetween(ctx, y.ID, keep.ID); len(edges) != 1 {
+		t.Errorf("y→keep edges = %d, want 1", len(edges))
+	}
+
+	window := TimeRange{Start: time.Now().Add(-time.Hour), End: time.Now().Add(time.Hour)}
+	if events, _ := d.ListEdgeEventsBySource(ctx, drop.ID, window); len(events) != 0 {
+		t.Errorf("edge events still on dropped node: %d", len(events))
+	}
+	if events, _ := d.ListEdgeEventsBetween(ctx, keep.ID, x.ID, window); len(events) != 1 {
+		t.Errorf("keep→x edge events = %d, want 1", len(events))
+	}
+
+	got, err := d.GetNodeActive(ctx, keep.ID)
+	if err != nil {
+		t.Fatalf("get keep: %v", err)
+	}
+	data := parseNodeData(got)
+	if data["summary"] != "keep" || data["extra"] != "drop" {
+		t.Errorf("merged data = %v", data)
+	}
+	if obs, _ := d.GetLatestObservation(ctx, keep.ID, "node_merge"); obs == nil {
+		t.Error("merge should be logged as an observation")
+	}
+}
+
+func TestListStaleEmbeddings(t *testing.T) {
+	d := testDash(t)
+	ctx := context.Background()
+	root := t.TempDir()
+
+	write := func(name, content string) string {
+		path := filepath.Join(root, name)
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	track := func(path string) *Node {
+		hash, err := computeFileHash(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		n, err := d.GetOrCreateNode(ctx, LayerSystem, "file", path, map[string]any{"path": path})
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { d.SoftDeleteNode(ctx, n.ID) })
+		if err := d.UpdateNodeEmbedding(ctx, n.ID, nil, hash); err != nil {
+			t.Fatal(err)
+		}
+		return n
+	}
+
+	track(write("same.go", "package same\n"))
+	changed := track(write("changed.go", "package a\n"))
+	write("changed.go", "package b\n")
+	gone := track(write("gone.go", "package gone\n"))
+	os.Remove(gone.Name)
+
+	stale, err := d.ListStaleEmbeddings(ctx, root)
+	if err != nil {
+		t.Fatalf("ListStaleEmbeddings: %v", err)
+	}
+	byID := map[uuid.UUID]StaleFile{}
+	for _, sf := range stale {
+		byID[sf.NodeID] = sf
+	}
+	if len(byID) != 2 {
+		t.Fatalf("stale = %+v, want changed.go and gone.go", stale)
+	}
+	if sf := byID[changed.ID]; sf.Missing || sf.DiskHash == "" || sf.DiskHash == sf.StoredHash {
+		t.Errorf("changed file = %+v", sf)
+	}
+	if sf := byID[gone.ID]; !sf.Missing || sf.DiskHash != "" {
+		t.Errorf("missing file = %+v", sf)
+	}
+
+	hashes, err := d.GetNodeContentHashes(ctx, []uuid.UUID{changed.ID, gone.ID, uuid.New()})
+	if err != nil || len(hashes) != 2 || hashes[changed.ID] != byID[changed.ID].StoredHash {
+		t.Errorf("GetNodeContentHashes = %v, %v", hashes, err)
+	}
+}
+
+func TestListOrphanFiles(t *testing.T) {
+	d := testDash(t)
+	ctx := context.Background()
+	path := fmt.Sprintf("/tmp/test-orphan-%d.go", time.Now().UnixNano())
+
+	node, err := d.GetOrCreateNode(ctx, LayerSystem, "file", path, map[string]any{"path": path})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { d.SoftDeleteNode(ctx, node.ID) })
+
+	hasOrphan := func(olderThan time.Duration) bool {
+		orphans, err := d.ListOrphanFiles(ctx, olderThan)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, o := range orphans {
+			if o.ID == node.ID {
+				return true
+			}
+		}
+		return false
+	}
+	if hasOrphan(time.Hour) {
+		t.Error("recently updated file node should not be an orphan")
+	}
+	if !hasOrphan(-time.Minute) {
+		t.Error("file node with no embedding and no events should be an orphan")
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"fmt"
+	"sort"
 	"time"
 
 	"github.com/google/uuid"
@@ -24,6 +26,17 @@ const (
 		ORDER BY observed_at DESC
 		LIMIT 1`
 
+	queryListToolDurations = `
+		SELECT
+			data->'claude_code'->>'tool_name' AS tool,
+			(data->'normalized'->'outcome'->>'duration_ms')::int AS duration_ms
+		FROM observations
+		WHERE type = 'tool_event'
+		  AND observed_at > NOW() - $1::interval
+		  AND data->'normalized'->>'event' IN ('tool.post', 'tool.failure')
+		  AND data->'claude_code'->>'tool_name' IS NOT NULL
+		  AND data->'normalized'->'outcome'->>'duration_ms' IS NOT NULL`
+
 	queryListObservationsByNode = `
 		SELECT id, node_id, type, value, data, observed_at
 		FROM observations
@@ -49,6 +62,18 @@ const (
 		ORDER BY observed_at DESC
 		LIMIT $4`
 
+	// queryListObservationsByTypePage pages backwards from the cursor
+	// ($4, $5) in (observed_at, id) order.
+	queryListObservationsByTypePage = `
+		SELECT id, node_id, type, value, data, observed_at
+		FROM observations
+		WHERE type = $1
+		  AND observed_at >= $2
+		  AND observed_at < $3
+		  AND (observed_at, id) < ($4, $5)
+		ORDER BY observed_at DESC, id DESC
+		LIMIT $6`
+
 	queryGetLatestObservation = `
 		SELECT id, node_id, type, value, data, observed_at
 		FROM observations
@@ -56,6 +81,15 @@ const (
 		ORDER BY observed_at DESC
 		LIMIT 1`
 
+	queryPruneObservations = `
+		DELETE FROM observations
+		WHERE node_id = $1 AND type = $2
+		  AND id NOT IN (
+			SELECT id FROM observations
+			WHERE node_id = $1 AND type = $2
+			ORDER BY observed_at DESC
+			LIMIT $3)`
+
 	queryCountObservationsByNode = `
 		SELECT COUNT(*)
 		FROM observations
@@ -85,6 +119,20 @@ type ObservationAggregates struct {
 	SumValue *float64
 }
 
+// minTimingSamples is the fewest durations needed before percentiles are reported.
+const minTimingSamples = 5
+
+// ToolTiming holds latency percentiles for one tool, in milliseconds.
+// Percentiles are nil when the tool has fewer than minTimingSamples samples.
+type ToolTiming struct {
+	Tool  string `json:"tool"`
+	Count int    `json:"count"`
+	P50   *int   `json:"p50_ms,omitempty"`
+	P90   *int   `json:"p90_ms,omitempty"`
+	P99   *int   `json:"p99_ms,omitempty"`
+	Max   *int   `json:"max_ms,omitempty"`
+}
+
 // CreateObservation creates a new observation.
 // If ObservedAt is zero, the current time will be used.
 func (d *Dash) CreateObservation(ctx context.Context, obs *Observation) error {
@@ -128,6 +176,32 @@ func (d *Dash) StoreObservation(ctx context.Context, sessionName, obsType string
 	})
 }
 
+// StoreObservationForNode resolves the node by layer/type/name, creating it if
+// it does not exist, and attaches an observation to it. Useful for per-file
+// events where only the path is known, e.g. StoreObservationForNode(ctx,
+// LayerSystem, "file", path, "lint_result", data).
+func (d *Dash) StoreObservationForNode(ctx context.Context, layer Layer, nodeType, name, obsType string, data map[string]any) error {
+	var node *Node
+	var err error
+	if layer == LayerSystem && nodeType == "file" {
+		node, err = d.GetFileNode(ctx, name)
+	} else {
+		node, err = d.GetOrCreateNode(ctx, layer, nodeType, name, nil)
+	}
+	if err != nil {
+		return err
+	}
+	dataJSON, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	return d.CreateObservation(ctx, &Observation{
+		NodeID: node.ID,
+		Type:   obsType,
+		Data:   dataJSON,
+	})
+}
+
 // ListObservationsByNode retrieves observations for a node within a time range.
 // IMPORTANT: Always specify a time range for partition pruning.
 func (d *Dash) ListObservationsByNode(ctx context.Context, nodeID uuid.UUID, timeRange TimeRange) ([]*Observation, error) {
@@ -169,6 +243,35 @@ func (d *Dash) ListObservationsByType(ctx context.Context, obsType string, timeR
 	return scanObservations(rows)
 }
 
+// observationPageSize is how many rows ListAllObservationsByType reads per query.
+const observationPageSize = 1000
+
+// ListAllObservationsByType retrieves every observation of a type across all
+// nodes in timeRange, newest first. Unlike ListObservationsByType it is not
+// capped; it pages through the rows observationPageSize at a time.
+func (d *Dash) ListAllObservationsByType(ctx context.Context, obsType string, timeRange TimeRange) ([]*Observation, error) {
+	var all []*Observation
+	cursorAt, cursorID := timeRange.End, uuid.Nil
+	for {
+		rows, err := d.db.QueryContext(ctx, queryListObservationsByTypePage,
+			obsType, timeRange.Start, timeRange.End, cursorAt, cursorID, observationPageSize)
+		if err != nil {
+			return nil, err
+		}
+		page, err := scanObservations(rows)
+		rows.Close()
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, page...)
+		if len(page) < observationPageSize {
+			return all, nil
+		}
+		last := page[len(page)-1]
+		cursorAt, cursorID = last.ObservedAt, last.ID
+	}
+}
+
 // GetLatestObservation retrieves the most recent observation of a type for a node.
 func (d *Dash) GetLatestObservation(ctx context.Context, nodeID uuid.UUID, obsType string) (*Observation, error) {
 	row := d.db.QueryRowContext(ctx, queryGetLatestObservation, nodeID, obsType)
@@ -179,6 +282,17 @@ func (d *Dash) GetLatestObservation(ctx context.Context, nodeID uuid.UUID, obsTy
 	return obs, err
 }
 
+// PruneObservations deletes all but the keep most recent observations of a
+// type for a node and returns how many were removed. It suits snapshot-style
+// observations where only the latest few matter.
+func (d *Dash) PruneObservations(ctx context.Context, nodeID uuid.UUID, obsType string, keep int) (int64, error) {
+	res, err := d.db.ExecContext(ctx, queryPruneObservations, nodeID, obsType, keep)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
 // CountObservationsByNode counts observations for a node in a time range.
 func (d *Dash) CountObservationsByNode(ctx context.Context, nodeID uuid.UUID, timeRange TimeRange) (int, error) {
 	var count int
@@ -232,6 +346,74 @@ func (d *Dash) GetPreToolUseTime(ctx context.Context, toolUseID string) (time.Ti
 	return observedAt, err
 }
 
+// ToolTimingStats returns per-tool duration percentiles over the last hours,
+// computed from the outcome duration_ms stored by PostToolUse hooks.
+// Results are ordered by sample count, highest first.
+func (d *Dash) ToolTimingStats(ctx context.Context, hours int) ([]ToolTiming, error) {
+	if hours <= 0 {
+		hours = 24
+	}
+
+	rows, err := d.db.QueryContext(ctx, queryListToolDurations, fmt.Sprintf("%d hours", hours))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	durations := make(map[string][]int)
+	for rows.Next() {
+		var tool string
+		var ms int
+		if err := rows.Scan(&tool, &ms); err != nil {
+			return nil, err
+		}
+		durations[tool] = append(durations[tool], ms)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return computeToolTimings(durations), nil
+}
+
+// computeToolTimings builds ToolTiming entries from raw per-tool durations.
+func computeToolTimings(durations map[string][]int) []ToolTiming {
+	timings := make([]ToolTiming, 0, len(durations))
+	for tool, ms := range durations {
+		t := ToolTiming{Tool: tool, Count: len(ms)}
+		if len(ms) > 0 {
+			sorted := append([]int(nil), ms...)
+			sort.Ints(sorted)
+			max := sorted[len(sorted)-1]
+			t.Max = &max
+			if len(sorted) >= minTimingSamples {
+				p50, p90, p99 := percentile(sorted, 50), percentile(sorted, 90), percentile(sorted, 99)
+				t.P50, t.P90, t.P99 = &p50, &p90, &p99
+			}
+		}
+		timings = append(timings, t)
+	}
+	sort.Slice(timings, func(i, j int) bool {
+		if timings[i].Count != timings[j].Count {
+			return timings[i].Count > timings[j].Count
+		}
+		return timings[i].Tool < timings[j].Tool
+	})
+	return timings
+}
+
+// percentile returns the nearest-rank percentile p (0-100) of an ascending slice.
+func percentile(sorted []int, p int) int {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := (p*len(sorted) + 99) / 100 // ceil(p/100 * n)
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
 // scanObservations scans multiple observations from rows.
 func scanObservations(rows *sql.Rows) ([]*Observation, error) {
 	var observations []*Observation
@@ -0,0 +1,52 @@
+package dash
+
+import "testing"
+
+func TestComputeToolTimings(t *testing.T) {
+	durations := map[string][]int{
+		"Bash": {100, 10, 50, 20, 30, 40, 60, 70, 80, 90},
+		"Read": {5, 7},
+	}
+
+	got := computeToolTimings(durations)
+	if len(got) != 2 {
+		t.Fatalf("len = %d, want 2", len(got))
+	}
+
+	bash := got[0]
+	if bash.Tool != "Bash" || bash.Count != 10 {
+		t.Fatalf("first = %s/%d, want Bash/10", bash.Tool, bash.Count)
+	}
+	if bash.P50 == nil || *bash.P50 != 50 {
+		t.Errorf("p50 = %v, want 50", bash.P50)
+	}
+	if bash.P90 == nil || *bash.P90 != 90 {
+		t.Errorf("p90 = %v, want 90", bash.P90)
+	}
+	if bash.P99 == nil || *bash.P99 != 100 {
+		t.Errorf("p99 = %v, want 100", bash.P99)
+	}
+	if bash.Max == nil || *bash.Max != 100 {
+		t.Errorf("max = %v, want 100", bash.Max)
+	}
+
+	read := got[1]
+	if read.Count != 2 {
+		t.Errorf("read count = %d, want 2", read.Count)
+	}
+	if read.P50 != nil || read.P90 != nil || read.P99 != nil {
+		t.Error("percentiles should be nil below minTimingSamples")
+	}
+	if read.Max == nil || *read.Max != 7 {
+		t.Errorf("read max = %v, want 7", read.Max)
+	}
+}
+
+func TestPercentileSingle(t *testing.T) {
+	if got := percentile([]int{42}, 99); got != 42 {
+		t.Errorf("percentile = %d, want 42", got)
+	}
+	if got := percentile(nil, 50); got != 0 {
+		t.Errorf("percentile(nil) = %d, want 0", got)
+	}
+}
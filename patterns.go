@@ -71,9 +71,10 @@ func (d *Dash) DetectCoEditingPatterns(ctx context.Context, minCoEdits int) ([]C
 
 // FileChurn represents a file with high modification frequency.
 type FileChurn struct {
-	FilePath     string `json:"file_path"`
-	ModifyCount  int    `json:"modify_count"`
-	SessionCount int    `json:"session_count"`
+	FilePath     string    `json:"file_path"`
+	ModifyCount  int       `json:"modify_count"`
+	SessionCount int       `json:"session_count"`
+	LastModified time.Time `json:"last_modified"`
 }
 
 // ToolSequence represents a common tool usage pattern.
@@ -84,7 +85,7 @@ type ToolSequence struct {
 }
 
 const queryFileChurn = `
-	SELECT n.name, COUNT(*) as modifications, COUNT(DISTINCT ee.source_id) as sessions
+	SELECT n.name, COUNT(*) as modifications, COUNT(DISTINCT ee.source_id) as sessions, MAX(ee.occurred_at) as last_modified
 	FROM edge_events ee
 	JOIN nodes n ON n.id = ee.target_id AND n.deleted_at IS NULL
 	WHERE ee.relation = 'modified'
@@ -130,7 +131,7 @@ func (d *Dash) DetectFileChurn(ctx context.Context, minModifications int) ([]Fil
 	var results []FileChurn
 	for rows.Next() {
 		var fc FileChurn
-		if err := rows.Scan(&fc.FilePath, &fc.ModifyCount, &fc.SessionCount); err != nil {
+		if err := rows.Scan(&fc.FilePath, &fc.ModifyCount, &fc.SessionCount, &fc.LastModified); err != nil {
 			continue
 		}
 		results = append(results, fc)
@@ -138,6 +139,42 @@ func (d *Dash) DetectFileChurn(ctx context.Context, minModifications int) ([]Fil
 	return results, rows.Err()
 }
 
+const queryFileHotspots = `
+	SELECT n.name, COUNT(*) as modifications, COUNT(DISTINCT ee.source_id) as sessions, MAX(ee.occurred_at) as last_modified
+	FROM edge_events ee
+	JOIN nodes n ON n.id = ee.target_id
+		AND n.layer = 'SYSTEM' AND n.type = 'file'
+		AND n.deleted_at IS NULL
+	WHERE ee.relation = 'modified'
+	  AND ee.occurred_at >= $1
+	GROUP BY n.name
+	ORDER BY modifications DESC, sessions DESC, last_modified DESC
+	LIMIT $2`
+
+// FileHotspots returns the files modified most often since the given time,
+// across all sessions. Unlike DetectFileChurn there is no minimum count, so
+// it always yields the top files for the period.
+func (d *Dash) FileHotspots(ctx context.Context, since time.Time, limit int) ([]FileChurn, error) {
+	if limit <= 0 {
+		limit = 20
+	}
+	rows, err := d.db.QueryContext(ctx, queryFileHotspots, since, limit)
+	if err != nil {
+		return nil, fmt.Errorf("query file hotspots: %w", err)
+	}
+	defer rows.Close()
+
+	var results []FileChurn
+	for rows.Next() {
+		var fc FileChurn
+		if err := rows.Scan(&fc.FilePath, &fc.ModifyCount, &fc.SessionCount, &fc.LastModified); err != nil {
+			return nil, err
+		}
+		results = append(results, fc)
+	}
+	return results, rows.Err()
+}
+
 // DetectToolSequences finds common tool usage patterns.
 func (d *Dash) DetectToolSequences(ctx context.Context, minFrequency int) ([]ToolSequence, error) {
 	if minFrequency < 2 {
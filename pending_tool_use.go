@@ -0,0 +1,116 @@
+package dash
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// pendingToolUseTTL is how long an unconsumed pending entry is kept before
+// the sweeper treats it as orphaned.
+const pendingToolUseTTL = 24 * time.Hour
+
+const (
+	queryInsertPendingToolUse = `
+		INSERT INTO pending_tool_uses (tool_use_id, session_id, tool_name, started_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (tool_use_id) DO NOTHING`
+
+	queryConsumePendingToolUse = `
+		DELETE FROM pending_tool_uses
+		WHERE tool_use_id = $1
+		RETURNING started_at`
+
+	querySweepPendingToolUses = `
+		DELETE FROM pending_tool_uses
+		WHERE started_at < NOW() - $1::interval`
+
+	queryDanglingToolUses = `
+		SELECT p.tool_use_id, p.tool_name, p.session_id, s.id, p.started_at
+		FROM pending_tool_uses p
+		LEFT JOIN nodes s
+		  ON s.layer = 'CONTEXT' AND s.type = 'session'
+		 AND s.name = p.session_id AND s.deleted_at IS NULL
+		WHERE p.started_at < NOW() - $1::interval
+		ORDER BY p.started_at DESC
+		LIMIT 100`
+)
+
+// DanglingToolUse is a tool call that started but never reported completion.
+type DanglingToolUse struct {
+	ToolUseID     string    `json:"tool_use_id"`
+	ToolName      string    `json:"tool_name"`
+	SessionID     string    `json:"session_id"`
+	SessionNodeID uuid.UUID `json:"session_node_id"`
+	StartedAt     time.Time `json:"started_at"`
+}
+
+// RecordPendingToolUse marks a tool call as in flight. A repeated
+// tool_use_id keeps the original start time.
+func (d *Dash) RecordPendingToolUse(ctx context.Context, toolUseID, sessionID, toolName string, startedAt time.Time) error {
+	_, err := d.db.ExecContext(ctx, queryInsertPendingToolUse, toolUseID, sessionID, toolName, startedAt)
+	return err
+}
+
+// ConsumePendingToolUse removes the pending entry for toolUseID and returns
+// its start time. Returns zero time if there is no entry.
+func (d *Dash) ConsumePendingToolUse(ctx context.Context, toolUseID string) (time.Time, error) {
+	var startedAt time.Time
+	err := d.db.QueryRowContext(ctx, queryConsumePendingToolUse, toolUseID).Scan(&startedAt)
+	if err == sql.ErrNoRows {
+		return time.Time{}, nil
+	}
+	return startedAt, err
+}
+
+// SweepPendingToolUses deletes pending entries older than ttl and returns
+// how many were removed.
+func (d *Dash) SweepPendingToolUses(ctx context.Context, ttl time.Duration) (int64, error) {
+	res, err := d.db.ExecContext(ctx, querySweepPendingToolUses, fmt.Sprintf("%d seconds", int64(ttl.Seconds())))
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+// DanglingToolUses returns tool calls still pending more than olderThan
+// after their PreToolUse, newest first: PostToolUse and PostToolUseFailure
+// consume the entry, so what remains are hung or crashed operations. Entries
+// are swept after pendingToolUseTTL. SessionNodeID is zero when the session
+// node is gone.
+func (d *Dash) DanglingToolUses(ctx context.Context, olderThan time.Duration) ([]DanglingToolUse, error) {
+	rows, err := d.db.QueryContext(ctx, queryDanglingToolUses, fmt.Sprintf("%d seconds", int64(olderThan.Seconds())))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []DanglingToolUse
+	for rows.Next() {
+		var t DanglingToolUse
+		var sessionNodeID uuid.NullUUID
+		if err := rows.Scan(&t.ToolUseID, &t.ToolName, &t.SessionID, &sessionNodeID, &t.StartedAt); err != nil {
+			return nil, err
+		}
+		t.SessionNodeID = sessionNodeID.UUID
+		out = append(out, t)
+	}
+	return out, rows.Err()
+}
+
+// toolUseStartTime returns when a tool call started, consuming its pending
+// entry. Falls back to the stored PreToolUse observation when the entry is
+// missing. Returns zero time if neither is found.
+func (d *Dash) toolUseStartTime(ctx context.Context, toolUseID string) time.Time {
+	if toolUseID == "" {
+		return time.Time{}
+	}
+	if t, err := d.ConsumePendingToolUse(ctx, toolUseID); err == nil && !t.IsZero() {
+		return t
+	}
+	t, _ := d.GetPreToolUseTime(ctx, toolUseID)
+	return t
+}
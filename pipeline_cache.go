@@ -0,0 +1,54 @@
+package dash
+
+import "context"
+
+// sourceCache memoizes queries that several sources of one pipeline run
+// share, so tasks, task_detail, sibling_tasks and the has_tasks condition
+// read the active tasks once per prompt build.
+type sourceCache struct {
+	loadTasks      func(context.Context) ([]TaskWithDeps, error)
+	loadWorkOrders func(context.Context) ([]*WorkOrder, error)
+
+	tasksLoaded bool
+	tasks       []TaskWithDeps
+	tasksErr    error
+
+	workOrdersLoaded bool
+	workOrders       []*WorkOrder
+	workOrdersErr    error
+}
+
+func newSourceCache(d *Dash) *sourceCache {
+	return &sourceCache{
+		loadTasks:      d.GetActiveTasksWithDeps,
+		loadWorkOrders: d.ListActiveWorkOrders,
+	}
+}
+
+// activeTasks returns GetActiveTasksWithDeps, fetched at most once per
+// pipeline run. Callers must not modify the returned tasks.
+func (p SourceParams) activeTasks() ([]TaskWithDeps, error) {
+	c := p.cache
+	if c == nil {
+		return p.D.GetActiveTasksWithDeps(p.Ctx)
+	}
+	if !c.tasksLoaded {
+		c.tasks, c.tasksErr = c.loadTasks(p.Ctx)
+		c.tasksLoaded = true
+	}
+	return c.tasks, c.tasksErr
+}
+
+// activeWorkOrders returns ListActiveWorkOrders, fetched at most once per
+// pipeline run.
+func (p SourceParams) activeWorkOrders() ([]*WorkOrder, error) {
+	c := p.cache
+	if c == nil {
+		return p.D.ListActiveWorkOrders(p.Ctx)
+	}
+	if !c.workOrdersLoaded {
+		c.workOrders, c.workOrdersErr = c.loadWorkOrders(p.Ctx)
+		c.workOrdersLoaded = true
+	}
+	return c.workOrders, c.workOrdersErr
+}
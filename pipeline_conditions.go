@@ -0,0 +1,126 @@
+package dash
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ConditionFunc decides whether a pipeline source should render.
+type ConditionFunc func(p SourceParams) bool
+
+// conditionRegistry holds the built-in conditions a PipelineSource.When can
+// name. Each maps to a cheap check of the params or the graph:
+//
+//	has_session      SourceParams.SessionID is set
+//	has_agent        SourceParams.AgentKey is set
+//	has_task         the CONTEXT.task named by SourceParams.TaskName exists
+//	has_plan         the CONTEXT.plan named by SourceParams.PlanName exists
+//	has_tasks        GetActiveTasksWithDeps returns at least one task
+//	has_work_orders  ListActiveWorkOrders returns at least one order
+//
+// Besides these, When accepts "output:<source>" (that source renders
+// non-empty text with the same params) and a leading "!" to negate.
+var conditionRegistry = map[string]ConditionFunc{
+	"has_session": func(p SourceParams) bool { return p.SessionID != "" },
+	"has_agent":   func(p SourceParams) bool { return p.AgentKey != "" },
+	"has_task": func(p SourceParams) bool {
+		return p.TaskName != "" && nodeExists(p, "task", p.TaskName)
+	},
+	"has_plan": func(p SourceParams) bool {
+		return p.PlanName != "" && nodeExists(p, "plan", p.PlanName)
+	},
+	"has_tasks": func(p SourceParams) bool {
+		tasks, err := p.activeTasks()
+		return err == nil && len(tasks) > 0
+	},
+	"has_work_orders": func(p SourceParams) bool {
+		orders, err := p.activeWorkOrders()
+		return err == nil && len(orders) > 0
+	},
+}
+
+const outputConditionPrefix = "output:"
+
+func nodeExists(p SourceParams, nodeType, name string) bool {
+	_, err := p.D.GetNodeByName(p.Ctx, LayerContext, nodeType, name)
+	return err == nil
+}
+
+// pipelineRun renders the sources of one pipeline execution. Source output
+// is memoized so an "output:" condition and the source itself only run once.
+type pipelineRun struct {
+	params  SourceParams
+	outputs map[string]string
+}
+
+func newPipelineRun(params SourceParams) *pipelineRun {
+	if params.cache == nil {
+		params.cache = newSourceCache(params.D)
+	}
+	return &pipelineRun{params: params, outputs: make(map[string]string)}
+}
+
+// render returns the section for src, or "" when the source is unknown, its
+// condition is false, or it has nothing to say.
+func (r *pipelineRun) render(src PipelineSource) string {
+	if _, ok := sourceRegistry[src.Name]; !ok {
+		return ""
+	}
+	if src.When != "" && !r.eval(src.When) {
+		return ""
+	}
+	return r.output(src)
+}
+
+// output runs src with its per-source overrides, reusing an earlier result.
+func (r *pipelineRun) output(src PipelineSource) string {
+	key := fmt.Sprintf("%s|%d|%s", src.Name, src.MaxItems, src.Format)
+	if out, ok := r.outputs[key]; ok {
+		return out
+	}
+	fn := sourceRegistry[src.Name]
+	if fn == nil {
+		return ""
+	}
+	sp := r.params
+	if src.MaxItems > 0 {
+		sp.MaxItems = src.MaxItems
+	}
+	if src.Format != "" {
+		sp.Format = src.Format
+	}
+	out := fn(sp)
+	r.outputs[key] = out
+	return out
+}
+
+// eval evaluates a When expression. Unknown conditions are false.
+func (r *pipelineRun) eval(when string) bool {
+	when = strings.TrimSpace(when)
+	if negated, ok := strings.CutPrefix(when, "!"); ok {
+		return !r.eval(negated)
+	}
+	if name, ok := strings.CutPrefix(when, outputConditionPrefix); ok {
+		return r.output(PipelineSource{Name: name}) != ""
+	}
+	if fn := conditionRegistry[when]; fn != nil {
+		return fn(r.params)
+	}
+	return false
+}
+
+// validateCondition returns an error for a When expression that names an
+// unknown condition or source.
+func validateCondition(when string) error {
+	when = strings.TrimLeft(strings.TrimSpace(when), "!")
+	if name, ok := strings.CutPrefix(when, outputConditionPrefix); ok {
+		if _, ok := sourceRegistry[name]; !ok {
+			return fmt.Errorf("unknown source %q in condition", name)
+		}
+		return nil
+	}
+	if _, ok := conditionRegistry[when]; !ok {
+		return fmt.Errorf("unknown condition %q", when)
+	}
+	return nil
+}
@@ -85,6 +85,7 @@ func (d *Dash) RunFullPipeline(ctx context.Context, woID uuid.UUID, git GitClien
 		return result, err
 	}
 	result.Gate = gateResult
+	d.RecordBuildGateResult(ctx, woID, gateResult)
 
 	if !gateResult.Passed {
 		result.Passed = false
@@ -37,10 +37,10 @@ type PlanState struct {
 	Insights []string `json:"insights,omitempty"`
 
 	// Plan fields
-	Milestones         []string   `json:"milestones,omitempty"`
-	Steps              []PlanStep `json:"steps,omitempty"`
-	AcceptanceCriteria []string   `json:"acceptance_criteria,omitempty"`
-	TestStrategy       string     `json:"test_strategy,omitempty"`
+	Milestones         []string              `json:"milestones,omitempty"`
+	Steps              []PlanStep            `json:"steps,omitempty"`
+	AcceptanceCriteria []AcceptanceCriterion `json:"acceptance_criteria,omitempty"`
+	TestStrategy       string                `json:"test_strategy,omitempty"`
 
 	// Prereqs fields
 	BlockedBy       []string `json:"blocked_by,omitempty"`
@@ -62,6 +62,42 @@ type PlanStep struct {
 	Files          []string `json:"files,omitempty"`
 	EstimatedLines int      `json:"estimated_lines,omitempty"`
 	Done           bool     `json:"done,omitempty"`
+	Milestone      string   `json:"milestone,omitempty"`
+}
+
+// AcceptanceCriterion is one acceptance criterion of a plan. TestRef names
+// the Go test function that proves it; Verified is set by VerifyAcceptance
+// once that test exists in a work order's changes and the build gate passed.
+type AcceptanceCriterion struct {
+	Text     string `json:"text"`
+	TestRef  string `json:"test_ref,omitempty"`
+	Verified bool   `json:"verified,omitempty"`
+
+	legacy bool // stored as a plain string, before criteria carried test_ref
+}
+
+// criteriaData returns criteria in their stored form. Legacy criteria that
+// gained no test_ref stay plain strings, so saving a plan does not turn them
+// into structured criteria.
+func criteriaData(criteria []AcceptanceCriterion) []any {
+	out := make([]any, len(criteria))
+	for i, c := range criteria {
+		if c.legacy && c.TestRef == "" && !c.Verified {
+			out[i] = c.Text
+		} else {
+			out[i] = c
+		}
+	}
+	return out
+}
+
+// criteriaTexts returns the text of each criterion.
+func criteriaTexts(criteria []AcceptanceCriterion) []string {
+	out := make([]string, len(criteria))
+	for i, c := range criteria {
+		out[i] = c.Text
+	}
+	return out
 }
 
 // PlanReview is the result of the deterministic critic.
@@ -82,7 +118,7 @@ type ReviewCheck struct {
 
 // PlanGate is the gate decision after review.
 type PlanGate struct {
-	Decision  string `json:"decision"` // "auto_run" or "user_approve"
+	Decision  string `json:"decision"` // "auto_run", "user_approve" or GateUserApproved
 	RiskScore int    `json:"risk_score"`
 	Reason    string `json:"reason"`
 }
@@ -99,6 +135,17 @@ func nextStage(current PlanStage) (PlanStage, bool) {
 	return "", false
 }
 
+// stagesBefore returns the stages a new plan must be advanced from to reach stopAt.
+// An unknown stopAt is treated as StageApproved.
+func stagesBefore(stopAt PlanStage) []PlanStage {
+	for i, s := range stageOrder {
+		if s == stopAt {
+			return stageOrder[:i]
+		}
+	}
+	return stageOrder[:len(stageOrder)-1]
+}
+
 // parsePlanData extracts PlanState from a CONTEXT.plan node.
 func parsePlanData(node *Node) (*PlanState, error) {
 	if node == nil {
@@ -107,80 +154,104 @@ func parsePlanData(node *Node) (*PlanState, error) {
 
 	ps := &PlanState{Node: node}
 
-	var data map[string]any
-	if err := json.Unmarshal(node.Data, &data); err != nil {
+	data, err := NewNodeData(node)
+	if err != nil {
 		return nil, fmt.Errorf("invalid plan data: %w", err)
 	}
 
-	ps.Stage = PlanStage(stringVal(data, "stage"))
+	ps.Stage = PlanStage(data.String("stage"))
 	if ps.Stage == "" {
 		ps.Stage = StageOutline
 	}
 
 	// Outline
-	ps.Goal = stringVal(data, "goal")
-	ps.Scope = stringVal(data, "scope")
-	ps.NonGoals = stringSlice(data, "non_goals")
-	ps.Assumptions = stringSlice(data, "assumptions")
-	ps.Risks = extractNames(data, "risks", "description")
-	ps.Insights = stringSlice(data, "insights")
+	ps.Goal = data.String("goal")
+	ps.Scope = data.String("scope")
+	ps.NonGoals = data.StringSlice("non_goals")
+	ps.Assumptions = data.StringSlice("assumptions")
+	ps.Risks = data.Names("risks", "description")
+	ps.Insights = data.StringSlice("insights")
 
 	// Plan
-	ps.Milestones = extractNames(data, "milestones", "name")
-	ps.AcceptanceCriteria = stringSlice(data, "acceptance_criteria")
-	ps.TestStrategy = stringVal(data, "test_strategy")
-
-	if stepsRaw, ok := data["steps"].([]any); ok {
-		for i, sr := range stepsRaw {
-			switch val := sr.(type) {
-			case string:
-				// AI sometimes returns steps as plain strings
-				if val != "" {
-					ps.Steps = append(ps.Steps, PlanStep{
-						Order:       i + 1,
-						Description: val,
-					})
-				}
-			case map[string]any:
-				step := PlanStep{
+	ps.Milestones = data.Names("milestones", "name")
+	ps.AcceptanceCriteria = parseAcceptanceCriteria(data)
+	ps.TestStrategy = data.String("test_strategy")
+
+	for i, sr := range data.List("steps") {
+		switch val := sr.(type) {
+		case string:
+			// AI sometimes returns steps as plain strings
+			if val != "" {
+				ps.Steps = append(ps.Steps, PlanStep{
 					Order:       i + 1,
-					Description: stringVal(val, "description"),
-					Files:       stringSlice(val, "files"),
-				}
-				if el, ok := val["estimated_lines"].(float64); ok {
-					step.EstimatedLines = int(el)
-				}
-				if d, ok := val["done"].(bool); ok {
-					step.Done = d
-				}
-				ps.Steps = append(ps.Steps, step)
+					Description: val,
+				})
 			}
+		case map[string]any:
+			sd := NodeData(val)
+			ps.Steps = append(ps.Steps, PlanStep{
+				Order:          i + 1,
+				Description:    sd.String("description"),
+				Files:          sd.StringSlice("files"),
+				Milestone:      sd.String("milestone"),
+				EstimatedLines: sd.Int("estimated_lines"),
+				Done:           sd.Bool("done"),
+			})
 		}
 	}
 
 	// Prereqs
-	ps.BlockedBy = stringSlice(data, "blocked_by")
-	ps.RequiredModules = stringSlice(data, "required_modules")
-	ps.MissingAPIs = stringSlice(data, "missing_apis")
-	ps.Migrations = stringSlice(data, "migrations")
+	ps.BlockedBy = data.StringSlice("blocked_by")
+	ps.RequiredModules = data.StringSlice("required_modules")
+	ps.MissingAPIs = data.StringSlice("missing_apis")
+	ps.Migrations = data.StringSlice("migrations")
 
 	// Review
-	if reviewRaw, ok := data["review"].(map[string]any); ok {
-		ps.Review = parseReview(reviewRaw)
+	if review := data.Map("review"); review != nil {
+		ps.Review = parseReview(review)
 	}
 
 	// Gate
-	if gateRaw, ok := data["gate"].(map[string]any); ok {
+	if gate := data.Map("gate"); gate != nil {
 		ps.Gate = &PlanGate{
-			Decision:  stringVal(gateRaw, "decision"),
-			RiskScore: intVal(gateRaw, "risk_score"),
-			Reason:    stringVal(gateRaw, "reason"),
+			Decision:  gate.String("decision"),
+			RiskScore: gate.Int("risk_score"),
+			Reason:    gate.String("reason"),
 		}
 	}
 
 	return ps, nil
 }
 
+// parseAcceptanceCriteria reads acceptance_criteria as either plain strings
+// (older plans) or {text, test_ref, verified} objects. Strings are upgraded
+// to criteria without a test reference.
+func parseAcceptanceCriteria(data map[string]any) []AcceptanceCriterion {
+	raw, ok := data["acceptance_criteria"].([]any)
+	if !ok {
+		return nil
+	}
+	var out []AcceptanceCriterion
+	for _, item := range raw {
+		switch val := item.(type) {
+		case string:
+			if val != "" {
+				out = append(out, AcceptanceCriterion{Text: val, legacy: true})
+			}
+		case map[string]any:
+			c := AcceptanceCriterion{
+				Text:     stringVal(val, "text"),
+				TestRef:  stringVal(val, "test_ref"),
+				Verified: boolVal(val, "verified"),
+			}
+			if c.Text != "" {
+				out = append(out, c)
+			}
+		}
+	}
+	return out
+}
+
 func parseReview(m map[string]any) *PlanReview {
 	r := &PlanReview{
 		Score:   intVal(m, "score"),
@@ -279,6 +350,30 @@ func reviewPlan(ps *PlanState) PlanReview {
 		checks = append(checks, ReviewCheck{Name: "acceptance_criteria", Passed: true, Detail: fmt.Sprintf("%d criteria defined", len(ps.AcceptanceCriteria))})
 	}
 
+	// Check: criteria mapped to tests. Legacy plain-string criteria predate
+	// test_ref and are not held to it.
+	unmapped, structured := 0, 0
+	for _, c := range ps.AcceptanceCriteria {
+		if c.legacy {
+			continue
+		}
+		structured++
+		if c.TestRef == "" {
+			unmapped++
+		}
+	}
+	if unmapped > 0 {
+		deduction := unmapped * 5
+		if deduction > 15 {
+			deduction = 15
+		}
+		score -= deduction
+		checks = append(checks, ReviewCheck{Name: "criteria_tests", Passed: false, Deduction: deduction, Detail: fmt.Sprintf("%d/%d criteria have no test_ref", unmapped, structured)})
+		issues = append(issues, "Map acceptance criteria to tests via test_ref")
+	} else if structured > 0 {
+		checks = append(checks, ReviewCheck{Name: "criteria_tests", Passed: true, Detail: "All criteria map to tests"})
+	}
+
 	// Check: steps without files
 	stepsWithoutFiles := 0
 	for _, s := range ps.Steps {
@@ -331,10 +426,10 @@ func reviewPlan(ps *PlanState) PlanReview {
 	for _, s := range ps.Steps {
 		totalLines += s.EstimatedLines
 	}
-	if totalLines > 500 {
+	if totalLines > planLineCap {
 		score -= 10
-		checks = append(checks, ReviewCheck{Name: "size_manageable", Passed: false, Deduction: 10, Detail: fmt.Sprintf("Total estimated lines: %d (>500)", totalLines)})
-		issues = append(issues, "Consider splitting into smaller plans")
+		checks = append(checks, ReviewCheck{Name: "size_manageable", Passed: false, Deduction: 10, Detail: fmt.Sprintf("Total estimated lines: %d (>%d)", totalLines, planLineCap)})
+		issues = append(issues, "Consider splitting into smaller plans (plan op=split)")
 	} else if totalLines > 0 {
 		checks = append(checks, ReviewCheck{Name: "size_manageable", Passed: true, Detail: fmt.Sprintf("Total estimated lines: %d", totalLines)})
 	}
@@ -420,8 +515,8 @@ Return ONLY valid JSON (no markdown fences, no explanation):
   "assumptions": ["key assumptions"],
   "risks": [{"description": "risk description"}],
   "milestones": [{"name": "phase name"}],
-  "steps": [{"description": "what to do", "files": ["/dash/path/to/file.go"], "estimated_lines": 50}],
-  "acceptance_criteria": ["verifiable criteria"],
+  "steps": [{"description": "what to do", "files": ["/dash/path/to/file.go"], "estimated_lines": 50, "milestone": "phase name"}],
+  "acceptance_criteria": [{"text": "verifiable criterion", "test_ref": "TestThatProvesIt"}],
   "test_strategy": "how to verify the change works",
   "blocked_by": [],
   "required_modules": ["dash/package"],
@@ -441,6 +536,18 @@ Rules:
 // through an LLM to produce a structured plan with milestones, steps, and acceptance criteria.
 // Falls back to a simple outline if AI is unavailable.
 func (d *Dash) GeneratePlanFromChat(ctx context.Context, messages []ChatMessage, scopeName string) (*Node, error) {
+	return d.GeneratePlanFromChatUntil(ctx, messages, scopeName, StageApproved)
+}
+
+// GenerateOutlineFromChat generates a plan from chat but leaves it at the outline
+// stage so goal and scope can be edited before committing to steps.
+func (d *Dash) GenerateOutlineFromChat(ctx context.Context, messages []ChatMessage, scopeName string) (*Node, error) {
+	return d.GeneratePlanFromChatUntil(ctx, messages, scopeName, StageOutline)
+}
+
+// GeneratePlanFromChatUntil generates a plan from chat and auto-advances it only
+// up to stopAt. Advancement still stops early if review sends the plan back to plan.
+func (d *Dash) GeneratePlanFromChatUntil(ctx context.Context, messages []ChatMessage, scopeName string, stopAt PlanStage) (*Node, error) {
 	if len(messages) == 0 {
 		return nil, fmt.Errorf("no messages to generate plan from")
 	}
@@ -465,7 +572,7 @@ func (d *Dash) GeneratePlanFromChat(ctx context.Context, messages []ChatMessage,
 
 	// Try AI generation
 	if !d.HasRealSummarizer() {
-		return d.fallbackPlan(ctx, messages, scopeName)
+		return d.fallbackPlan(ctx, messages, scopeName, stopAt)
 	}
 
 	// Assemble context pack for codebase awareness
@@ -501,34 +608,18 @@ func (d *Dash) GeneratePlanFromChat(ctx context.Context, messages []ChatMessage,
 	}
 
 	// Call AI
-	response, err := d.summarizer.Complete(ctx, planGenerationSystemPrompt, userPrompt.String())
-	if err != nil {
-		return d.fallbackPlan(ctx, messages, scopeName)
-	}
-
-	// Strip markdown code fences if present
-	response = strings.TrimSpace(response)
-	if strings.HasPrefix(response, "```") {
-		if idx := strings.Index(response[3:], "\n"); idx >= 0 {
-			response = response[3+idx+1:]
-		}
-		if strings.HasSuffix(response, "```") {
-			response = response[:len(response)-3]
-		}
-		response = strings.TrimSpace(response)
-	}
-
-	// Parse JSON response
+	tmpl := d.GetPromptTemplate(ctx, PromptPlanGeneration)
+	ctx = withPromptTemplate(ctx, tmpl)
 	var planData map[string]any
-	if err := json.Unmarshal([]byte(response), &planData); err != nil {
-		return d.fallbackPlan(ctx, messages, scopeName)
+	if err := CompleteJSON(ctx, d.summarizer, tmpl.Text, userPrompt.String(), &planData); err != nil {
+		return d.fallbackPlan(ctx, messages, scopeName, stopAt)
 	}
 
 	// Validate minimum fields
 	name, _ := planData["name"].(string)
 	goal, _ := planData["goal"].(string)
 	if name == "" || goal == "" {
-		return d.fallbackPlan(ctx, messages, scopeName)
+		return d.fallbackPlan(ctx, messages, scopeName, stopAt)
 	}
 
 	// Ensure required fields exist for stage advancement
@@ -547,6 +638,7 @@ func (d *Dash) GeneratePlanFromChat(ctx context.Context, messages []ChatMessage,
 			planData[field] = []any{}
 		}
 	}
+	planData["prompt_template"] = tmpl.Ref()
 
 	// Create plan node
 	node, err := d.CreatePlan(ctx, name, planData)
@@ -554,9 +646,9 @@ func (d *Dash) GeneratePlanFromChat(ctx context.Context, messages []ChatMessage,
 		return nil, fmt.Errorf("create plan: %w", err)
 	}
 
-	// Auto-advance through stages: outline → plan → prereqs → review
+	// Auto-advance through stages up to stopAt: outline → plan → prereqs → review
 	var advanceErr error
-	for _, expectedStage := range []PlanStage{StageOutline, StagePlan, StagePrereqs, StageReview} {
+	for _, expectedStage := range stagesBefore(stopAt) {
 		ps, err := d.AdvancePlan(ctx, node.ID)
 		if err != nil {
 			advanceErr = fmt.Errorf("advance from %s failed: %w", expectedStage, err)
@@ -591,7 +683,9 @@ func (d *Dash) GeneratePlanFromChat(ctx context.Context, messages []ChatMessage,
 }
 
 // fallbackPlan creates a simple outline plan when AI generation is unavailable.
-func (d *Dash) fallbackPlan(ctx context.Context, messages []ChatMessage, scopeName string) (*Node, error) {
+// The outline cannot advance without steps, so advance_error is only set when
+// the caller asked for a stage beyond outline.
+func (d *Dash) fallbackPlan(ctx context.Context, messages []ChatMessage, scopeName string, stopAt PlanStage) (*Node, error) {
 	// Extract the last substantive user message as goal
 	goal := ""
 	for i := len(messages) - 1; i >= 0; i-- {
@@ -645,6 +739,9 @@ func (d *Dash) fallbackPlan(ctx context.Context, messages []ChatMessage, scopeNa
 		"missing_apis":     []any{},
 		"migrations":       []any{},
 	}
+	if stopAt == StageOutline {
+		delete(data, "advance_error")
+	}
 
 	return d.CreatePlan(ctx, name, data)
 }
@@ -719,6 +816,8 @@ func (d *Dash) AdvancePlan(ctx context.Context, planID uuid.UUID) (*PlanState, e
 		if err := validatePrereqs(ps); err != nil {
 			return ps, err
 		}
+		// Best-effort: detection failures never block advancement.
+		_ = d.DetectPrereqs(ctx, ps)
 		return d.setPlanStage(ctx, ps, StageReview)
 
 	case StageReview:
@@ -750,6 +849,7 @@ func (d *Dash) AdvancePlan(ctx context.Context, planID uuid.UUID) (*PlanState, e
 			ps.Stage = StagePlan
 		}
 
+		d.snapshotPlanRevision(ctx, node)
 		dataJSON, _ := json.Marshal(data)
 		node.Data = dataJSON
 		ps.Node = node
@@ -843,6 +943,7 @@ func (d *Dash) setPlanStage(ctx context.Context, ps *PlanState, stage PlanStage)
 	json.Unmarshal(ps.Node.Data, &data)
 	data["stage"] = string(stage)
 
+	d.snapshotPlanRevision(ctx, ps.Node)
 	dataJSON, _ := json.Marshal(data)
 	ps.Node.Data = dataJSON
 	ps.Stage = stage
@@ -856,53 +957,23 @@ func (d *Dash) setPlanStage(ctx context.Context, ps *PlanState, stage PlanStage)
 // --- JSON helpers ---
 
 func stringVal(m map[string]any, key string) string {
-	v, _ := m[key].(string)
-	return v
+	return NodeData(m).String(key)
 }
 
 // extractNames handles arrays that can be either strings or objects with a named field.
 // e.g. milestones: ["foo"] or milestones: [{"name":"foo","done":false}]
 func extractNames(m map[string]any, key, field string) []string {
-	raw, ok := m[key].([]any)
-	if !ok {
-		return nil
-	}
-	var out []string
-	for _, v := range raw {
-		switch val := v.(type) {
-		case string:
-			out = append(out, val)
-		case map[string]any:
-			if s, ok := val[field].(string); ok && s != "" {
-				out = append(out, s)
-			}
-		}
-	}
-	return out
+	return NodeData(m).Names(key, field)
 }
 
 func stringSlice(m map[string]any, key string) []string {
-	raw, ok := m[key].([]any)
-	if !ok {
-		return nil
-	}
-	var out []string
-	for _, v := range raw {
-		if s, ok := v.(string); ok {
-			out = append(out, s)
-		}
-	}
-	return out
+	return NodeData(m).StringSlice(key)
 }
 
 func intVal(m map[string]any, key string) int {
-	if v, ok := m[key].(float64); ok {
-		return int(v)
-	}
-	return 0
+	return NodeData(m).Int(key)
 }
 
 func boolVal(m map[string]any, key string) bool {
-	v, _ := m[key].(bool)
-	return v
+	return NodeData(m).Bool(key)
 }
@@ -0,0 +1,210 @@
+package dash
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// testFuncPattern matches top-level Go test function declarations.
+var testFuncPattern = regexp.MustCompile(`(?m)^func (Test\w+)\(`)
+
+// AcceptanceCheck is the verification outcome of one acceptance criterion.
+type AcceptanceCheck struct {
+	AcceptanceCriterion
+	TestFile string `json:"test_file,omitempty"`
+	Detail   string `json:"detail,omitempty"`
+}
+
+// AcceptanceReport lists which of a plan's acceptance criteria are backed by
+// a passing test in the work orders that implement it.
+type AcceptanceReport struct {
+	PlanID     uuid.UUID         `json:"plan_id"`
+	WorkOrders []string          `json:"work_orders"`
+	Checks     []AcceptanceCheck `json:"checks"`
+	Unverified []string          `json:"unverified,omitempty"`
+}
+
+// VerifyAcceptance checks each acceptance criterion of a plan against the
+// work orders implementing it: the criterion's TestRef must be declared in
+// one of their changed _test.go files, and that order's latest build gate
+// must have passed without failing the test. Verified flags are written back
+// to the plan.
+func (d *Dash) VerifyAcceptance(ctx context.Context, planID uuid.UUID) (*AcceptanceReport, error) {
+	node, err := d.GetNodeActive(ctx, planID)
+	if err != nil {
+		return nil, err
+	}
+	if node.Type != "plan" || node.Layer != LayerContext {
+		return nil, fmt.Errorf("node %s is not a CONTEXT.plan", planID)
+	}
+	ps, err := parsePlanData(node)
+	if err != nil {
+		return nil, err
+	}
+
+	edges, err := d.ListEdgesByTarget(ctx, planID)
+	if err != nil {
+		return nil, err
+	}
+	report := &AcceptanceReport{PlanID: planID}
+	var evidence []testEvidence
+	for _, e := range edges {
+		if e.Relation != RelationImplements {
+			continue
+		}
+		wo, err := d.GetWorkOrder(ctx, e.SourceID)
+		if err != nil {
+			continue
+		}
+		report.WorkOrders = append(report.WorkOrders, wo.Node.Name)
+		ev := testEvidence{tests: findTestFuncs(workOrderFileReader(wo), wo.FilesChanged)}
+		if gateObs, err := d.GetLatestObservation(ctx, wo.Node.ID, buildGateResultType); err == nil && gateObs != nil {
+			var gate BuildGateResult
+			if json.Unmarshal(gateObs.Data, &gate) == nil {
+				ev.gate = &gate
+			}
+		}
+		evidence = append(evidence, ev)
+	}
+
+	report.Checks = verifyCriteria(ps.AcceptanceCriteria, evidence)
+	changed := false
+	for i, c := range report.Checks {
+		if !c.Verified {
+			report.Unverified = append(report.Unverified, c.Text)
+		}
+		if c.Verified != ps.AcceptanceCriteria[i].Verified {
+			ps.AcceptanceCriteria[i].Verified = c.Verified
+			changed = true
+		}
+	}
+
+	if changed {
+		var data map[string]any
+		if err := json.Unmarshal(node.Data, &data); err != nil {
+			return nil, fmt.Errorf("invalid plan data: %w", err)
+		}
+		data["acceptance_criteria"] = criteriaData(ps.AcceptanceCriteria)
+		dataJSON, err := json.Marshal(data)
+		if err != nil {
+			return nil, err
+		}
+		node.Data = dataJSON
+		if err := d.UpdateNode(ctx, node); err != nil {
+			return nil, err
+		}
+	}
+	return report, nil
+}
+
+// testEvidence is what one work order offers towards verifying criteria:
+// the test functions its changes declare (name → file) and its last gate.
+type testEvidence struct {
+	tests map[string]string
+	gate  *BuildGateResult
+}
+
+// verifyCriteria checks each criterion against the evidence of all work
+// orders. A criterion is verified once any order both declares its test and
+// has a passing gate in which that test did not fail.
+func verifyCriteria(criteria []AcceptanceCriterion, evidence []testEvidence) []AcceptanceCheck {
+	checks := make([]AcceptanceCheck, 0, len(criteria))
+	for _, c := range criteria {
+		check := AcceptanceCheck{AcceptanceCriterion: c}
+		check.Verified = false // re-verified from scratch on every run
+		if c.TestRef == "" {
+			check.Detail = "no test_ref"
+			checks = append(checks, check)
+			continue
+		}
+		check.Detail = fmt.Sprintf("%s not found in changed test files", c.TestRef)
+		for _, ev := range evidence {
+			file, ok := ev.tests[c.TestRef]
+			if !ok {
+				continue
+			}
+			check.TestFile = file
+			switch {
+			case ev.gate == nil:
+				check.Detail = "no build gate result"
+			case strings.Contains(ev.gate.Test.Output, "--- FAIL: "+c.TestRef):
+				check.Detail = fmt.Sprintf("%s failed", c.TestRef)
+			case !ev.gate.Test.Passed:
+				check.Detail = "test step did not pass"
+			default:
+				check.Verified = true
+				check.Detail = ""
+			}
+			if check.Verified {
+				break
+			}
+		}
+		checks = append(checks, check)
+	}
+	return checks
+}
+
+// workOrderFileReader returns a reader for the work order's version of a
+// repo-relative file: from its worktree while that exists, else from its
+// branch. The repo root's checkout is not the order's tree and is never read.
+// Returns nil when the order has neither.
+func workOrderFileReader(wo *WorkOrder) func(string) ([]byte, error) {
+	if wo.WorktreePath != "" {
+		if _, err := os.Stat(wo.WorktreePath); err == nil {
+			return func(f string) ([]byte, error) {
+				if filepath.IsAbs(f) {
+					rel, err := filepath.Rel(wo.RepoRoot, f)
+					if err != nil || strings.HasPrefix(rel, "..") {
+						return nil, fmt.Errorf("%s is outside the repo", f)
+					}
+					f = rel
+				}
+				return os.ReadFile(filepath.Join(wo.WorktreePath, f))
+			}
+		}
+	}
+	if wo.RepoRoot == "" || wo.BranchName == "" {
+		return nil
+	}
+	git := NewExecGitClient(wo.RepoRoot)
+	return func(f string) ([]byte, error) {
+		if filepath.IsAbs(f) {
+			rel, err := filepath.Rel(wo.RepoRoot, f)
+			if err != nil {
+				return nil, err
+			}
+			f = rel
+		}
+		return git.ShowFileAtRef(wo.BranchName, filepath.ToSlash(f))
+	}
+}
+
+// findTestFuncs returns the test functions declared in the _test.go files
+// among files, keyed by name. Files are read with read; unreadable files are
+// skipped.
+func findTestFuncs(read func(string) ([]byte, error), files []string) map[string]string {
+	tests := make(map[string]string)
+	if read == nil {
+		return tests
+	}
+	for _, f := range files {
+		if !strings.HasSuffix(f, "_test.go") {
+			continue
+		}
+		src, err := read(f)
+		if err != nil {
+			continue
+		}
+		for _, m := range testFuncPattern.FindAllSubmatch(src, -1) {
+			tests[string(m[1])] = f
+		}
+	}
+	return tests
+}
@@ -0,0 +1,174 @@
+package dash
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// GateUserApproved is the gate decision recorded once a user has approved a
+// plan the gate held for user_approve.
+const GateUserApproved = "user_approved"
+
+// NeedsUserApproval reports whether the plan passed review but its gate is
+// waiting for a user to approve or reject it.
+func (ps *PlanState) NeedsUserApproval() bool {
+	return ps.Stage == StageApproved && ps.Gate != nil && ps.Gate.Decision == "user_approve"
+}
+
+// ApprovePlanGate approves a plan held at the user_approve gate. The critic
+// is re-run with its verdict forced to approve, the gate is recorded as
+// user_approved, and a work order scoped to the files of the plan's steps is
+// created so the plan can move to execution. The work order is nil when no
+// step names a file.
+func (d *Dash) ApprovePlanGate(ctx context.Context, planID uuid.UUID, note string) (*PlanState, *WorkOrder, error) {
+	ps, err := d.ReviewPlan(ctx, planID, "approve")
+	if err != nil {
+		return nil, nil, err
+	}
+	stored, err := parsePlanData(ps.Node)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !stored.NeedsUserApproval() {
+		return stored, nil, fmt.Errorf("plan %s is not awaiting user approval", ps.Node.Name)
+	}
+
+	actor := planActor(ctx)
+	var wo *WorkOrder
+	if files := planStepFiles(ps); len(files) > 0 {
+		agentKey, err := d.SelectAgentForWorkOrder(ctx, &WorkOrder{Description: ps.Goal, ScopePaths: files})
+		if err != nil && !errors.Is(err, ErrNoMatchingAgent) {
+			return stored, nil, err
+		}
+		wo, err = d.CreateWorkOrder(ctx, ps.Node.Name, nil, agentKey, files, WorkOrderOpts{Description: ps.Goal})
+		if err != nil {
+			return stored, nil, fmt.Errorf("create work order: %w", err)
+		}
+		d.CreateEdge(ctx, &Edge{
+			SourceID: wo.Node.ID,
+			TargetID: ps.Node.ID,
+			Relation: RelationImplements,
+		})
+	}
+
+	reason := "Approved by " + actor
+	if note != "" {
+		reason += ": " + note
+	}
+	ps.Gate = &PlanGate{
+		Decision:  GateUserApproved,
+		RiskScore: stored.Gate.RiskScore,
+		Reason:    reason,
+	}
+	if err := d.savePlanDecision(ctx, ps); err != nil {
+		return ps, wo, err
+	}
+	d.observePlanDecision(ctx, ps, "plan_approved", actor, note, wo)
+	return ps, wo, nil
+}
+
+// RejectPlanGate sends a plan held at the user_approve gate back to the plan
+// stage. The critic is re-run with its verdict forced to revise and the note
+// is kept among the review issues so the next revision can address it.
+func (d *Dash) RejectPlanGate(ctx context.Context, planID uuid.UUID, note string) (*PlanState, error) {
+	ps, err := d.ReviewPlan(ctx, planID, "revise")
+	if err != nil {
+		return nil, err
+	}
+	stored, err := parsePlanData(ps.Node)
+	if err != nil {
+		return nil, err
+	}
+	if !stored.NeedsUserApproval() {
+		return stored, fmt.Errorf("plan %s is not awaiting user approval", ps.Node.Name)
+	}
+
+	actor := planActor(ctx)
+	if note != "" {
+		ps.Review.Issues = append(ps.Review.Issues, fmt.Sprintf("Rejected by %s: %s", actor, note))
+	}
+	ps.Gate = nil
+	ps.Stage = StagePlan
+	if err := d.savePlanDecision(ctx, ps); err != nil {
+		return ps, err
+	}
+	d.observePlanDecision(ctx, ps, "plan_rejected", actor, note, nil)
+	return ps, nil
+}
+
+// savePlanDecision persists a plan's stage, review and gate.
+func (d *Dash) savePlanDecision(ctx context.Context, ps *PlanState) error {
+	data := NodeDataOf(ps.Node)
+	data.Set("stage", string(ps.Stage))
+	reviewJSON, _ := json.Marshal(ps.Review)
+	var reviewMap map[string]any
+	json.Unmarshal(reviewJSON, &reviewMap)
+	data.Set("review", reviewMap)
+	if ps.Gate != nil {
+		gateJSON, _ := json.Marshal(ps.Gate)
+		var gateMap map[string]any
+		json.Unmarshal(gateJSON, &gateMap)
+		data.Set("gate", gateMap)
+	} else {
+		delete(data, "gate")
+	}
+
+	dataJSON, err := data.JSON()
+	if err != nil {
+		return fmt.Errorf("marshal plan: %w", err)
+	}
+	d.snapshotPlanRevision(ctx, ps.Node)
+	ps.Node.Data = dataJSON
+	return d.UpdateNode(ctx, ps.Node)
+}
+
+// observePlanDecision records who approved or rejected a plan and why.
+func (d *Dash) observePlanDecision(ctx context.Context, ps *PlanState, obsType, actor, note string, wo *WorkOrder) {
+	obs := map[string]any{
+		"actor": actor,
+		"stage": string(ps.Stage),
+	}
+	if note != "" {
+		obs["note"] = note
+	}
+	if wo != nil {
+		obs["work_order_id"] = wo.Node.ID.String()
+	}
+	obsData, _ := json.Marshal(obs)
+	d.CreateObservation(ctx, &Observation{
+		NodeID:     ps.Node.ID,
+		Type:       obsType,
+		Data:       obsData,
+		ObservedAt: time.Now().UTC(),
+	})
+}
+
+// planActor is the caller recorded on plan decisions.
+func planActor(ctx context.Context) string {
+	if actor := CallerFromContext(ctx); actor != "" {
+		return actor
+	}
+	return "operator"
+}
+
+// planStepFiles returns the distinct files named by the plan's steps, in
+// step order.
+func planStepFiles(ps *PlanState) []string {
+	var files []string
+	seen := make(map[string]bool)
+	for _, s := range ps.Steps {
+		for _, f := range s.Files {
+			if f == "" || seen[f] {
+				continue
+			}
+			seen[f] = true
+			files = append(files, f)
+		}
+	}
+	return files
+}
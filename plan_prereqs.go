@@ -0,0 +1,163 @@
+package dash
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// prereqProviderLimit caps how many providers are recorded per missing item.
+const prereqProviderLimit = 3
+
+// prereqProviderMaxDistance is the cosine distance under which a semantically
+// similar plan or task counts as a provider.
+const prereqProviderMaxDistance = 0.35
+
+// PrereqDetection records what DetectPrereqs found for a plan.
+type PrereqDetection struct {
+	CheckedAt    time.Time           `json:"checked_at"`
+	FoundModules []string            `json:"found_modules"`
+	Missing      []string            `json:"missing"`
+	Providers    map[string][]string `json:"providers"`
+}
+
+// DetectPrereqs checks the plan's required modules against SYSTEM.file nodes
+// and looks for CONTEXT plans/tasks that provide whatever is missing (modules
+// not found in the graph, plus all missing APIs). Providers are appended to
+// blocked_by as "plan:<name>" / "task:<name>" and the findings are stored
+// under prereq_detection in ps.Node.Data. Lookup failures are skipped; the
+// caller persists the data.
+func (d *Dash) DetectPrereqs(ctx context.Context, ps *PlanState) error {
+	var data map[string]any
+	if err := json.Unmarshal(ps.Node.Data, &data); err != nil {
+		return fmt.Errorf("invalid data: %w", err)
+	}
+
+	det := PrereqDetection{
+		CheckedAt:    time.Now().UTC(),
+		FoundModules: []string{},
+		Missing:      []string{},
+		Providers:    map[string][]string{},
+	}
+	for _, mod := range ps.RequiredModules {
+		if d.moduleExists(ctx, mod) {
+			det.FoundModules = append(det.FoundModules, mod)
+		} else {
+			det.Missing = append(det.Missing, mod)
+		}
+	}
+	det.Missing = append(det.Missing, ps.MissingAPIs...)
+
+	blockedBy := ps.BlockedBy
+	for _, item := range det.Missing {
+		providers := d.findPrereqProviders(ctx, ps, item)
+		if len(providers) == 0 {
+			continue
+		}
+		det.Providers[item] = providers
+		blockedBy = appendUnique(blockedBy, providers...)
+	}
+
+	if blockedBy == nil {
+		blockedBy = []string{}
+	}
+	ps.BlockedBy = blockedBy
+	data["blocked_by"] = blockedBy
+	data["prereq_detection"] = det
+	dataJSON, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	ps.Node.Data = dataJSON
+	return nil
+}
+
+// moduleExists reports whether any SYSTEM.file node name contains mod.
+func (d *Dash) moduleExists(ctx context.Context, mod string) bool {
+	mod = strings.TrimSpace(mod)
+	if mod == "" {
+		return false
+	}
+	layer, typ := LayerSystem, "file"
+	pattern := "%" + mod + "%"
+	nodes, err := d.SearchNodes(ctx, NodeFilter{Layer: &layer, Type: &typ, NamePattern: &pattern, Limit: 1})
+	return err == nil && len(nodes) > 0
+}
+
+// findPrereqProviders returns references to open plans and tasks (other than
+// the plan itself) whose name mentions item, falling back to semantic search
+// when an embedder is configured.
+func (d *Dash) findPrereqProviders(ctx context.Context, ps *PlanState, item string) []string {
+	item = strings.TrimSpace(item)
+	if item == "" {
+		return nil
+	}
+	var refs []string
+	for _, typ := range []string{"plan", "task"} {
+		layer, nodeType := LayerContext, typ
+		pattern := "%" + item + "%"
+		nodes, err := d.SearchNodes(ctx, NodeFilter{Layer: &layer, Type: &nodeType, NamePattern: &pattern, Limit: 10})
+		if err != nil {
+			continue
+		}
+		for _, n := range nodes {
+			if n.ID == ps.Node.ID || prereqProviderDone(n.Data) {
+				continue
+			}
+			refs = appendUnique(refs, typ+":"+n.Name)
+		}
+	}
+
+	if len(refs) == 0 && d.HasRealEmbedder() {
+		for _, typ := range []string{"plan", "task"} {
+			results, err := d.SearchSimilarByType(ctx, item, LayerContext, typ, prereqProviderLimit)
+			if err != nil {
+				continue
+			}
+			for _, r := range results {
+				if r.ID == ps.Node.ID || r.Distance > prereqProviderMaxDistance || prereqProviderDone(r.Data) {
+					continue
+				}
+				refs = appendUnique(refs, typ+":"+r.Name)
+			}
+		}
+	}
+
+	if len(refs) > prereqProviderLimit {
+		refs = refs[:prereqProviderLimit]
+	}
+	return refs
+}
+
+// prereqProviderDone reports whether a plan or task is already finished and
+// so can no longer block anything.
+func prereqProviderDone(raw json.RawMessage) bool {
+	var data map[string]any
+	if json.Unmarshal(raw, &data) != nil {
+		return false
+	}
+	switch stringVal(data, "status") {
+	case "completed", "done":
+		return true
+	}
+	return false
+}
+
+// appendUnique appends the values of add that are not already in list.
+func appendUnique(list []string, add ...string) []string {
+	for _, s := range add {
+		dup := false
+		for _, existing := range list {
+			if existing == s {
+				dup = true
+				break
+			}
+		}
+		if !dup {
+			list = append(list, s)
+		}
+	}
+	return list
+}
@@ -0,0 +1,224 @@
+package dash
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// planRevisionType is the observation type holding a plan's prior data.
+const planRevisionType = "plan_revision"
+
+// PlanRevision is one recorded state of a plan. Revisions are numbered from 1
+// in the order they were recorded; the last revision is the plan's current data.
+type PlanRevision struct {
+	Rev        int        `json:"rev"`
+	RecordedAt time.Time  `json:"recorded_at"`
+	Current    bool       `json:"current,omitempty"`
+	State      *PlanState `json:"state"`
+}
+
+// PlanChange is a single field-level difference between two plan revisions.
+type PlanChange struct {
+	Field string `json:"field"`
+	Kind  string `json:"kind"` // "added", "removed", "changed"
+	From  string `json:"from,omitempty"`
+	To    string `json:"to,omitempty"`
+}
+
+// PlanDiffResult holds the changes between two revisions of a plan.
+type PlanDiffResult struct {
+	PlanID    uuid.UUID    `json:"plan_id"`
+	FromRev   int          `json:"from_rev"`
+	ToRev     int          `json:"to_rev"`
+	FromStage PlanStage    `json:"from_stage"`
+	ToStage   PlanStage    `json:"to_stage"`
+	Changes   []PlanChange `json:"changes"`
+}
+
+// snapshotPlanRevision records node's current data as a plan_revision
+// observation before it is overwritten. Best-effort: failures are ignored so
+// snapshots never block stage advancement.
+func (d *Dash) snapshotPlanRevision(ctx context.Context, node *Node) {
+	data, err := json.Marshal(map[string]any{"data": node.Data})
+	if err != nil {
+		return
+	}
+	_ = d.CreateObservation(ctx, &Observation{
+		NodeID: node.ID,
+		Type:   planRevisionType,
+		Data:   data,
+	})
+}
+
+// ListPlanRevisions returns all recorded revisions of a plan, oldest first,
+// followed by the plan's current data.
+func (d *Dash) ListPlanRevisions(ctx context.Context, planID uuid.UUID) ([]PlanRevision, error) {
+	node, err := d.GetNodeActive(ctx, planID)
+	if err != nil {
+		return nil, err
+	}
+	obs, err := d.ListObservationsByNodeType(ctx, planID, planRevisionType, TimeRange{End: time.Now().Add(time.Minute)})
+	if err != nil {
+		return nil, err
+	}
+
+	var revs []PlanRevision
+	for i := len(obs) - 1; i >= 0; i-- { // observations come newest first
+		var snap struct {
+			Data json.RawMessage `json:"data"`
+		}
+		if err := json.Unmarshal(obs[i].Data, &snap); err != nil || len(snap.Data) == 0 {
+			continue
+		}
+		snapNode := *node
+		snapNode.Data = snap.Data
+		ps, err := parsePlanData(&snapNode)
+		if err != nil {
+			continue
+		}
+		revs = append(revs, PlanRevision{Rev: len(revs) + 1, RecordedAt: obs[i].ObservedAt, State: ps})
+	}
+
+	current, err := parsePlanData(node)
+	if err != nil {
+		return nil, err
+	}
+	revs = append(revs, PlanRevision{Rev: len(revs) + 1, RecordedAt: node.UpdatedAt, Current: true, State: current})
+	return revs, nil
+}
+
+// PlanDiff returns field-level changes between two revisions of a plan.
+// A toRev <= 0 means the current revision.
+func (d *Dash) PlanDiff(ctx context.Context, planID uuid.UUID, fromRev, toRev int) (*PlanDiffResult, error) {
+	revs, err := d.ListPlanRevisions(ctx, planID)
+	if err != nil {
+		return nil, err
+	}
+	if toRev <= 0 {
+		toRev = len(revs)
+	}
+	if fromRev < 1 || fromRev > len(revs) || toRev > len(revs) {
+		return nil, fmt.Errorf("revision out of range: have 1..%d, got %d..%d", len(revs), fromRev, toRev)
+	}
+
+	from, to := revs[fromRev-1].State, revs[toRev-1].State
+	return &PlanDiffResult{
+		PlanID:    planID,
+		FromRev:   fromRev,
+		ToRev:     toRev,
+		FromStage: from.Stage,
+		ToStage:   to.Stage,
+		Changes:   diffPlanStates(from, to),
+	}, nil
+}
+
+// diffPlanStates compares two plan states field by field.
+func diffPlanStates(a, b *PlanState) []PlanChange {
+	var changes []PlanChange
+	scalar := func(field, from, to string) {
+		if from != to {
+			changes = append(changes, PlanChange{Field: field, Kind: "changed", From: from, To: to})
+		}
+	}
+	list := func(field string, from, to []string) {
+		changes = append(changes, diffStringSets(field, from, to)...)
+	}
+
+	scalar("stage", string(a.Stage), string(b.Stage))
+	scalar("goal", a.Goal, b.Goal)
+	scalar("scope", a.Scope, b.Scope)
+	list("non_goals", a.NonGoals, b.NonGoals)
+	list("assumptions", a.Assumptions, b.Assumptions)
+	list("risks", a.Risks, b.Risks)
+	list("insights", a.Insights, b.Insights)
+	list("milestones", a.Milestones, b.Milestones)
+	changes = append(changes, diffPlanSteps(a.Steps, b.Steps)...)
+	list("acceptance_criteria", criteriaTexts(a.AcceptanceCriteria), criteriaTexts(b.AcceptanceCriteria))
+	scalar("test_strategy", a.TestStrategy, b.TestStrategy)
+	list("blocked_by", a.BlockedBy, b.BlockedBy)
+	list("required_modules", a.RequiredModules, b.RequiredModules)
+	list("missing_apis", a.MissingAPIs, b.MissingAPIs)
+	list("migrations", a.Migrations, b.Migrations)
+	scalar("review", reviewSummary(a.Review), reviewSummary(b.Review))
+	return changes
+}
+
+// diffStringSets reports items present in only one of from/to.
+func diffStringSets(field string, from, to []string) []PlanChange {
+	var changes []PlanChange
+	inFrom := make(map[string]bool, len(from))
+	for _, s := range from {
+		inFrom[s] = true
+	}
+	inTo := make(map[string]bool, len(to))
+	for _, s := range to {
+		inTo[s] = true
+	}
+	for _, s := range from {
+		if !inTo[s] {
+			changes = append(changes, PlanChange{Field: field, Kind: "removed", From: s})
+		}
+	}
+	for _, s := range to {
+		if !inFrom[s] {
+			changes = append(changes, PlanChange{Field: field, Kind: "added", To: s})
+		}
+	}
+	return changes
+}
+
+// diffPlanSteps matches steps by description and reports added, removed and
+// changed (files or done state) steps.
+func diffPlanSteps(from, to []PlanStep) []PlanChange {
+	var changes []PlanChange
+	byDesc := make(map[string]PlanStep, len(from))
+	for _, s := range from {
+		byDesc[s.Description] = s
+	}
+	seen := make(map[string]bool, len(to))
+	for _, s := range to {
+		seen[s.Description] = true
+		prev, ok := byDesc[s.Description]
+		if !ok {
+			changes = append(changes, PlanChange{Field: "steps", Kind: "added", To: s.Description})
+			continue
+		}
+		if prev.Done != s.Done || strings.Join(prev.Files, ",") != strings.Join(s.Files, ",") {
+			changes = append(changes, PlanChange{
+				Field: "steps",
+				Kind:  "changed",
+				From:  stepSummary(prev),
+				To:    stepSummary(s),
+			})
+		}
+	}
+	for _, s := range from {
+		if !seen[s.Description] {
+			changes = append(changes, PlanChange{Field: "steps", Kind: "removed", From: s.Description})
+		}
+	}
+	return changes
+}
+
+func stepSummary(s PlanStep) string {
+	out := s.Description
+	if len(s.Files) > 0 {
+		out += " [" + strings.Join(s.Files, ", ") + "]"
+	}
+	if s.Done {
+		out += " (done)"
+	}
+	return out
+}
+
+func reviewSummary(r *PlanReview) string {
+	if r == nil {
+		return ""
+	}
+	return fmt.Sprintf("%s (%d)", r.Verdict, r.Score)
+}
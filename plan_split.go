@@ -0,0 +1,244 @@
+package dash
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// planLineCap is the estimated size above which a plan should be split.
+const planLineCap = 500
+
+// planSplit is one milestone's share of an oversized plan.
+type planSplit struct {
+	Milestone string
+	Steps     []PlanStep
+	Lines     int
+}
+
+// SplitResult describes an umbrella plan and the sub-plans created from it.
+type SplitResult struct {
+	ParentID   uuid.UUID   `json:"parent_id"`
+	SubPlanIDs []uuid.UUID `json:"sub_plan_ids"`
+	Milestones []string    `json:"milestones"`
+	Lines      []int       `json:"lines"`
+}
+
+// splitPlanSteps groups a plan's steps by milestone and checks every group
+// fits under limit. Steps tagged with a milestone go to that milestone;
+// untagged steps follow the step before them. When no step is tagged, steps
+// are divided in order into one contiguous run per milestone.
+func splitPlanSteps(ps *PlanState, limit int) ([]planSplit, error) {
+	if len(ps.Milestones) < 2 {
+		return nil, fmt.Errorf("plan needs at least 2 milestones to split, has %d", len(ps.Milestones))
+	}
+
+	tagged := false
+	for _, s := range ps.Steps {
+		if s.Milestone != "" {
+			tagged = true
+			break
+		}
+	}
+
+	var groups []planSplit
+	if tagged {
+		index := map[string]int{}
+		for _, m := range ps.Milestones {
+			index[m] = len(groups)
+			groups = append(groups, planSplit{Milestone: m})
+		}
+		current := 0
+		for _, s := range ps.Steps {
+			if s.Milestone != "" {
+				i, ok := index[s.Milestone]
+				if !ok {
+					i = len(groups)
+					index[s.Milestone] = i
+					groups = append(groups, planSplit{Milestone: s.Milestone})
+				}
+				current = i
+			}
+			groups[current].Steps = append(groups[current].Steps, s)
+		}
+	} else {
+		per := (len(ps.Steps) + len(ps.Milestones) - 1) / len(ps.Milestones)
+		for i, m := range ps.Milestones {
+			lo, hi := i*per, (i+1)*per
+			if lo > len(ps.Steps) {
+				lo = len(ps.Steps)
+			}
+			if hi > len(ps.Steps) {
+				hi = len(ps.Steps)
+			}
+			groups = append(groups, planSplit{Milestone: m, Steps: ps.Steps[lo:hi]})
+		}
+	}
+
+	var out []planSplit
+	for _, g := range groups {
+		if len(g.Steps) == 0 {
+			continue
+		}
+		for _, s := range g.Steps {
+			g.Lines += s.EstimatedLines
+		}
+		if g.Lines > limit {
+			return nil, fmt.Errorf("milestone %q is estimated at %d lines, over the %d cap", g.Milestone, g.Lines, limit)
+		}
+		out = append(out, g)
+	}
+	if len(out) < 2 {
+		return nil, fmt.Errorf("all steps fall under one milestone, nothing to split")
+	}
+	return out, nil
+}
+
+// relevantCriteria returns the acceptance criteria that mention the
+// milestone or one of its files. Criteria that match no milestone at all
+// are shared and always included.
+func relevantCriteria(criteria []AcceptanceCriterion, group planSplit, all []planSplit) []AcceptanceCriterion {
+	mentions := func(c string, g planSplit) bool {
+		lc := strings.ToLower(c)
+		if strings.Contains(lc, strings.ToLower(g.Milestone)) {
+			return true
+		}
+		for _, s := range g.Steps {
+			for _, f := range s.Files {
+				if strings.Contains(lc, strings.ToLower(filepath.Base(f))) {
+					return true
+				}
+			}
+		}
+		return false
+	}
+
+	var out []AcceptanceCriterion
+	for _, c := range criteria {
+		if mentions(c.Text, group) {
+			out = append(out, c)
+			continue
+		}
+		shared := true
+		for _, g := range all {
+			if mentions(c.Text, g) {
+				shared = false
+				break
+			}
+		}
+		if shared {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// subPlanData builds the data of one sub-plan from its parent.
+func subPlanData(parent *PlanState, group planSplit, all []planSplit) map[string]any {
+	var parentData map[string]any
+	json.Unmarshal(parent.Node.Data, &parentData)
+
+	steps := make([]map[string]any, 0, len(group.Steps))
+	for _, s := range group.Steps {
+		steps = append(steps, map[string]any{
+			"description":     s.Description,
+			"files":           s.Files,
+			"estimated_lines": s.EstimatedLines,
+			"done":            s.Done,
+			"milestone":       group.Milestone,
+		})
+	}
+
+	data := map[string]any{
+		"goal":                fmt.Sprintf("%s — %s", parent.Goal, group.Milestone),
+		"scope":               parent.Scope,
+		"milestones":          []map[string]any{{"name": group.Milestone}},
+		"steps":               steps,
+		"acceptance_criteria": criteriaData(relevantCriteria(parent.AcceptanceCriteria, group, all)),
+		"test_strategy":       parent.TestStrategy,
+		"parent_plan":         parent.Node.ID.String(),
+	}
+	// Carry outline and prereq fields over verbatim.
+	for _, key := range []string{"non_goals", "assumptions", "risks", "blocked_by", "required_modules", "missing_apis", "migrations"} {
+		if v, ok := parentData[key]; ok {
+			data[key] = v
+		}
+	}
+	return data
+}
+
+// SplitPlan decomposes an oversized plan into one sub-plan per milestone.
+// Each sub-plan starts at the plan stage and links to the parent via
+// RelationPartOf. The parent becomes an approved umbrella listing its
+// sub-plans. Nothing is created unless every sub-plan fits under the cap.
+func (d *Dash) SplitPlan(ctx context.Context, planID uuid.UUID) (*SplitResult, error) {
+	ps, err := d.GetPlan(ctx, planID)
+	if err != nil {
+		return nil, err
+	}
+	if ps.Node.Type != "plan" || ps.Node.Layer != LayerContext {
+		return nil, fmt.Errorf("node %s is not a CONTEXT.plan", planID)
+	}
+
+	var data map[string]any
+	if err := json.Unmarshal(ps.Node.Data, &data); err != nil {
+		return nil, fmt.Errorf("invalid plan data: %w", err)
+	}
+	if boolVal(data, "umbrella") {
+		return nil, fmt.Errorf("plan %s is already split", ps.Node.Name)
+	}
+
+	total := 0
+	for _, s := range ps.Steps {
+		total += s.EstimatedLines
+	}
+	if total <= planLineCap {
+		return nil, fmt.Errorf("plan is estimated at %d lines, within the %d cap; nothing to split", total, planLineCap)
+	}
+
+	groups, err := splitPlanSteps(ps, planLineCap)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &SplitResult{ParentID: planID}
+	for _, g := range groups {
+		name := ps.Node.Name + "-" + slugify(g.Milestone, 40)
+		sub, err := d.CreatePlan(ctx, name, subPlanData(ps, g, groups))
+		if err != nil {
+			return result, fmt.Errorf("create sub-plan %s: %w", name, err)
+		}
+		subState, err := parsePlanData(sub)
+		if err == nil {
+			d.setPlanStage(ctx, subState, StagePlan)
+		}
+		d.CreateEdge(ctx, &Edge{
+			SourceID: sub.ID,
+			TargetID: planID,
+			Relation: RelationPartOf,
+		})
+		result.SubPlanIDs = append(result.SubPlanIDs, sub.ID)
+		result.Milestones = append(result.Milestones, g.Milestone)
+		result.Lines = append(result.Lines, g.Lines)
+	}
+
+	subIDs := make([]string, len(result.SubPlanIDs))
+	for i, id := range result.SubPlanIDs {
+		subIDs[i] = id.String()
+	}
+	data["umbrella"] = true
+	data["sub_plans"] = subIDs
+	data["stage"] = string(StageApproved)
+
+	d.snapshotPlanRevision(ctx, ps.Node)
+	dataJSON, _ := json.Marshal(data)
+	ps.Node.Data = dataJSON
+	if err := d.UpdateNode(ctx, ps.Node); err != nil {
+		return result, err
+	}
+	return result, nil
+}
@@ -0,0 +1,289 @@
+package dash
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Plan templates are SYSTEM.plan_template nodes holding the skeleton of a
+// recurring plan shape: outline and plan fields with {{var}} placeholders.
+// CreatePlanFromTemplate fills in the placeholders and creates the plan at
+// the plan stage, without a round trip through the LLM generator.
+const planTemplateType = "plan_template"
+
+// PlanTemplate is a reusable plan skeleton.
+type PlanTemplate struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description,omitempty"`
+	Vars        []string       `json:"vars"`     // placeholders used in Skeleton, sorted
+	Skeleton    map[string]any `json:"skeleton"` // plan data: name, goal, scope, steps, ...
+	Builtin     bool           `json:"builtin,omitempty"`
+}
+
+// builtinPlanTemplates are available without any stored nodes. A stored
+// template with the same name replaces the builtin one.
+var builtinPlanTemplates = []PlanTemplate{
+	{
+		Name:        "mcp-tool",
+		Description: "Add a new MCP tool",
+		Skeleton: map[string]any{
+			"name":        "add-{{tool}}-tool",
+			"goal":        "Add the {{tool}} MCP tool: {{description}}",
+			"scope":       "tool_{{tool}}.go and tool registration (tool_scanner.go, mcp.go, tool_run.go)",
+			"non_goals":   []any{"Changing the behaviour of existing tools"},
+			"assumptions": []any{"The tool can be built on existing *Dash methods"},
+			"risks":       []any{map[string]any{"description": "Write tools not classified in determineToolRelation are logged as reads"}},
+			"milestones":  []any{map[string]any{"name": "tool"}},
+			"steps": []any{
+				map[string]any{"description": "Define def{{Tool}} and the tool function", "files": []any{"/dash/tool_{{tool}}.go"}, "estimated_lines": 80, "milestone": "tool"},
+				map[string]any{"description": "Register the tool in AllToolDefs and the mcp.go fallback list", "files": []any{"/dash/tool_scanner.go", "/dash/mcp.go"}, "estimated_lines": 4, "milestone": "tool"},
+				map[string]any{"description": "Classify the tool's edge relation", "files": []any{"/dash/tool_run.go"}, "estimated_lines": 4, "milestone": "tool"},
+				map[string]any{"description": "Test the tool function", "files": []any{"/dash/tool_{{tool}}_test.go"}, "estimated_lines": 60, "milestone": "tool"},
+			},
+			"acceptance_criteria": []any{
+				map[string]any{"text": "{{tool}} is registered and callable through the registry", "test_ref": "Test{{Tool}}Tool"},
+			},
+			"test_strategy": "Unit test the tool function against the test database; go build ./... && go test ./...",
+		},
+		Builtin: true,
+	},
+	{
+		Name:        "cli-command",
+		Description: "Add a new CLI command under cmd/",
+		Skeleton: map[string]any{
+			"name":        "add-{{cmd}}-command",
+			"goal":        "Add the {{cmd}} command: {{description}}",
+			"scope":       "cmd/{{cmd}} and its documentation in CLAUDE.md",
+			"non_goals":   []any{"Changes to the dash package API beyond what the command needs"},
+			"assumptions": []any{"The command connects with dash.ConnectDB like the other binaries"},
+			"risks":       []any{map[string]any{"description": "The new module needs its own go.mod replace directive for dash"}},
+			"milestones":  []any{map[string]any{"name": "command"}},
+			"steps": []any{
+				map[string]any{"description": "Create the command entry point and flags", "files": []any{"/dash/cmd/{{cmd}}/main.go", "/dash/cmd/{{cmd}}/go.mod"}, "estimated_lines": 120, "milestone": "command"},
+				map[string]any{"description": "Document the command and its build line", "files": []any{"/dash/CLAUDE.md"}, "estimated_lines": 10, "milestone": "command"},
+			},
+			"acceptance_criteria": []any{
+				map[string]any{"text": "cmd/{{cmd}} builds and vets cleanly"},
+			},
+			"test_strategy": "cd cmd/{{cmd}} && go build ./... && go vet ./...",
+		},
+		Builtin: true,
+	},
+}
+
+// planTemplateVarRe matches {{var}} placeholders.
+var planTemplateVarRe = regexp.MustCompile(`\{\{\s*(\w+)\s*\}\}`)
+
+const queryListPlanTemplates = `
+	SELECT id, layer, type, name, data, created_at, updated_at, deleted_at
+	FROM nodes
+	WHERE layer = 'SYSTEM' AND type = 'plan_template'
+	  AND deleted_at IS NULL
+	ORDER BY name`
+
+// ListPlanTemplates returns the builtin templates and the stored ones,
+// sorted by name.
+func (d *Dash) ListPlanTemplates(ctx context.Context) ([]PlanTemplate, error) {
+	byName := make(map[string]PlanTemplate)
+	for _, t := range builtinPlanTemplates {
+		t.Vars = planTemplateVars(t.Skeleton)
+		byName[t.Name] = t
+	}
+
+	rows, err := d.db.QueryContext(ctx, queryListPlanTemplates)
+	if err != nil {
+		return nil, err
+	}
+	nodes, err := scanNodes(rows)
+	rows.Close()
+	if err != nil {
+		return nil, err
+	}
+	for _, n := range nodes {
+		data := NodeDataOf(n)
+		skeleton := data.Map("skeleton")
+		if skeleton == nil {
+			continue
+		}
+		byName[n.Name] = PlanTemplate{
+			Name:        n.Name,
+			Description: data.String("description"),
+			Vars:        planTemplateVars(skeleton),
+			Skeleton:    skeleton,
+		}
+	}
+
+	templates := make([]PlanTemplate, 0, len(byName))
+	for _, t := range byName {
+		templates = append(templates, t)
+	}
+	sort.Slice(templates, func(i, j int) bool { return templates[i].Name < templates[j].Name })
+	return templates, nil
+}
+
+// GetPlanTemplate returns the template called name.
+func (d *Dash) GetPlanTemplate(ctx context.Context, name string) (*PlanTemplate, error) {
+	templates, err := d.ListPlanTemplates(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range templates {
+		if templates[i].Name == name {
+			return &templates[i], nil
+		}
+	}
+	return nil, fmt.Errorf("plan template %q not found", name)
+}
+
+// SavePlanTemplate stores or replaces a plan template.
+func (d *Dash) SavePlanTemplate(ctx context.Context, t PlanTemplate) (*Node, error) {
+	if t.Name == "" {
+		return nil, fmt.Errorf("template name is required")
+	}
+	if len(t.Skeleton) == 0 {
+		return nil, fmt.Errorf("template skeleton is required")
+	}
+	data := map[string]any{
+		"description": t.Description,
+		"skeleton":    t.Skeleton,
+	}
+	node, err := d.GetOrCreateNode(ctx, LayerSystem, planTemplateType, t.Name, data)
+	if err != nil {
+		return nil, err
+	}
+	if err := d.UpdateNodeData(ctx, node, data); err != nil {
+		return nil, err
+	}
+	return node, nil
+}
+
+// CreatePlanFromTemplate instantiates templateName with vars and advances
+// the new plan to the plan stage. Every placeholder in the skeleton must
+// have a value. For a var "tool", "{{Tool}}" is filled with its value
+// capitalised, for use in Go identifiers.
+func (d *Dash) CreatePlanFromTemplate(ctx context.Context, templateName string, vars map[string]string) (*PlanState, error) {
+	t, err := d.GetPlanTemplate(ctx, templateName)
+	if err != nil {
+		return nil, err
+	}
+	data, err := instantiatePlanTemplate(t, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	name := stringVal(data, "name")
+	delete(data, "name")
+	if name == "" {
+		name = templateName
+	}
+	data["plan_template"] = templateName
+
+	node, err := d.CreatePlan(ctx, name, data)
+	if err != nil {
+		return nil, err
+	}
+	return d.AdvancePlan(ctx, node.ID)
+}
+
+// instantiatePlanTemplate returns a copy of t's skeleton with every
+// placeholder replaced, or an error naming the vars without a value.
+func instantiatePlanTemplate(t *PlanTemplate, vars map[string]string) (map[string]any, error) {
+	values := make(map[string]string, len(vars)*2)
+	for k, v := range vars {
+		values[k] = v
+		if title := capitalize(k); title != k {
+			if _, set := vars[title]; !set {
+				values[title] = capitalize(v)
+			}
+		}
+	}
+
+	var missing []string
+	for _, v := range planTemplateVars(t.Skeleton) {
+		if _, ok := values[v]; !ok {
+			missing = append(missing, v)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("plan template %q: missing vars %s", t.Name, strings.Join(missing, ", "))
+	}
+
+	// Round-trip through JSON for a deep copy with uniform types.
+	raw, err := json.Marshal(t.Skeleton)
+	if err != nil {
+		return nil, fmt.Errorf("invalid skeleton: %w", err)
+	}
+	var skeleton map[string]any
+	if err := json.Unmarshal(raw, &skeleton); err != nil {
+		return nil, fmt.Errorf("invalid skeleton: %w", err)
+	}
+	return substitutePlanVars(skeleton, values).(map[string]any), nil
+}
+
+// substitutePlanVars replaces placeholders in every string within v.
+func substitutePlanVars(v any, values map[string]string) any {
+	switch val := v.(type) {
+	case string:
+		return planTemplateVarRe.ReplaceAllStringFunc(val, func(m string) string {
+			return values[planTemplateVarRe.FindStringSubmatch(m)[1]]
+		})
+	case map[string]any:
+		for k, item := range val {
+			val[k] = substitutePlanVars(item, values)
+		}
+		return val
+	case []any:
+		for i, item := range val {
+			val[i] = substitutePlanVars(item, values)
+		}
+		return val
+	}
+	return v
+}
+
+// planTemplateVars returns the placeholder names used in skeleton, sorted.
+// Capitalised forms of another var ({{Tool}} beside {{tool}}) are derived
+// and not listed.
+func planTemplateVars(skeleton map[string]any) []string {
+	seen := make(map[string]bool)
+	var walk func(v any)
+	walk = func(v any) {
+		switch val := v.(type) {
+		case string:
+			for _, m := range planTemplateVarRe.FindAllStringSubmatch(val, -1) {
+				seen[m[1]] = true
+			}
+		case map[string]any:
+			for _, item := range val {
+				walk(item)
+			}
+		case []any:
+			for _, item := range val {
+				walk(item)
+			}
+		}
+	}
+	walk(skeleton)
+
+	vars := make([]string, 0, len(seen))
+	for v := range seen {
+		if lower := strings.ToLower(v[:1]) + v[1:]; lower != v && seen[lower] {
+			continue
+		}
+		vars = append(vars, v)
+	}
+	sort.Strings(vars)
+	return vars
+}
+
+// capitalize upper-cases the first letter of s.
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
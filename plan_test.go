@@ -0,0 +1,368 @@
+package dash
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestStagesBefore(t *testing.T) {
+	tests := []struct {
+		stopAt PlanStage
+		want   []PlanStage
+	}{
+		{StageOutline, []PlanStage{}},
+		{StagePlan, []PlanStage{StageOutline}},
+		{StageReview, []PlanStage{StageOutline, StagePlan, StagePrereqs}},
+		{StageApproved, []PlanStage{StageOutline, StagePlan, StagePrereqs, StageReview}},
+		{PlanStage("bogus"), []PlanStage{StageOutline, StagePlan, StagePrereqs, StageReview}},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.stopAt), func(t *testing.T) {
+			got := stagesBefore(tt.stopAt)
+			if len(got) == 0 && len(tt.want) == 0 {
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("stagesBefore(%s) = %v, want %v", tt.stopAt, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiffPlanStates(t *testing.T) {
+	from := &PlanState{
+		Stage:    StageOutline,
+		Goal:     "ship it",
+		NonGoals: []string{"rewrite", "perf"},
+		Steps: []PlanStep{
+			{Description: "add api"},
+			{Description: "old step"},
+		},
+	}
+	to := &PlanState{
+		Stage:    StagePlan,
+		Goal:     "ship it",
+		NonGoals: []string{"perf", "ui"},
+		Steps: []PlanStep{
+			{Description: "add api", Files: []string{"api.go"}},
+			{Description: "add tests"},
+		},
+		Review: &PlanReview{Verdict: "approve", Score: 90},
+	}
+
+	got := diffPlanStates(from, to)
+	want := []PlanChange{
+		{Field: "stage", Kind: "changed", From: "outline", To: "plan"},
+		{Field: "non_goals", Kind: "removed", From: "rewrite"},
+		{Field: "non_goals", Kind: "added", To: "ui"},
+		{Field: "steps", Kind: "changed", From: "add api", To: "add api [api.go]"},
+		{Field: "steps", Kind: "added", To: "add tests"},
+		{Field: "steps", Kind: "removed", From: "old step"},
+		{Field: "review", Kind: "changed", To: "approve (90)"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("diffPlanStates =\n%+v\nwant\n%+v", got, want)
+	}
+
+	if changes := diffPlanStates(to, to); len(changes) != 0 {
+		t.Errorf("identical states produced %d changes", len(changes))
+	}
+}
+
+func TestSplitPlanSteps(t *testing.T) {
+	t.Run("tagged steps group by milestone", func(t *testing.T) {
+		ps := &PlanState{
+			Milestones: []string{"schema", "api"},
+			Steps: []PlanStep{
+				{Description: "migration", EstimatedLines: 200, Milestone: "schema"},
+				{Description: "model", EstimatedLines: 150},
+				{Description: "handlers", EstimatedLines: 300, Milestone: "api"},
+			},
+		}
+		got, err := splitPlanSteps(ps, planLineCap)
+		if err != nil {
+			t.Fatalf("splitPlanSteps: %v", err)
+		}
+		if len(got) != 2 || got[0].Lines != 350 || got[1].Lines != 300 {
+			t.Fatalf("got %+v, want schema=350 api=300", got)
+		}
+		if len(got[0].Steps) != 2 || got[0].Steps[1].Description != "model" {
+			t.Errorf("untagged step should follow its predecessor, got %+v", got[0].Steps)
+		}
+	})
+
+	t.Run("untagged steps divide in order", func(t *testing.T) {
+		ps := &PlanState{
+			Milestones: []string{"a", "b", "c"},
+			Steps: []PlanStep{
+				{EstimatedLines: 100}, {EstimatedLines: 100}, {EstimatedLines: 100},
+				{EstimatedLines: 100}, {EstimatedLines: 100},
+			},
+		}
+		got, err := splitPlanSteps(ps, planLineCap)
+		if err != nil {
+			t.Fatalf("splitPlanSteps: %v", err)
+		}
+		if len(got) != 3 || len(got[0].Steps) != 2 || len(got[2].Steps) != 1 {
+			t.Fatalf("got %+v, want 2/2/1 steps", got)
+		}
+	})
+
+	t.Run("oversized milestone is rejected", func(t *testing.T) {
+		ps := &PlanState{
+			Milestones: []string{"big", "small"},
+			Steps: []PlanStep{
+				{EstimatedLines: 600, Milestone: "big"},
+				{EstimatedLines: 50, Milestone: "small"},
+			},
+		}
+		if _, err := splitPlanSteps(ps, planLineCap); err == nil {
+			t.Fatal("expected error for milestone over the cap")
+		}
+	})
+
+	t.Run("single milestone cannot split", func(t *testing.T) {
+		ps := &PlanState{Milestones: []string{"only"}, Steps: []PlanStep{{EstimatedLines: 900}}}
+		if _, err := splitPlanSteps(ps, planLineCap); err == nil {
+			t.Fatal("expected error for a single milestone")
+		}
+	})
+}
+
+func TestRelevantCriteria(t *testing.T) {
+	groups := []planSplit{
+		{Milestone: "schema", Steps: []PlanStep{{Files: []string{"sql/migrations/030_x.sql"}}}},
+		{Milestone: "api", Steps: []PlanStep{{Files: []string{"handlers.go"}}}},
+	}
+	criteria := []AcceptanceCriterion{
+		{Text: "030_x.sql applies cleanly", TestRef: "TestMigration030"},
+		{Text: "handlers.go returns 200"},
+		{Text: "go test passes"},
+	}
+
+	got := relevantCriteria(criteria, groups[0], groups)
+	want := []AcceptanceCriterion{{Text: "030_x.sql applies cleanly", TestRef: "TestMigration030"}, {Text: "go test passes"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("relevantCriteria(schema) = %v, want %v", got, want)
+	}
+}
+
+func TestParseAcceptanceCriteria(t *testing.T) {
+	node := &Node{Data: []byte(`{"acceptance_criteria": [
+		"legacy string criterion",
+		{"text": "retries back off", "test_ref": "TestRetryBackoff", "verified": true},
+		"",
+		{"test_ref": "TestNoText"}
+	]}`)}
+	ps, err := parsePlanData(node)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []AcceptanceCriterion{
+		{Text: "legacy string criterion", legacy: true},
+		{Text: "retries back off", TestRef: "TestRetryBackoff", Verified: true},
+	}
+	if !reflect.DeepEqual(ps.AcceptanceCriteria, want) {
+		t.Errorf("AcceptanceCriteria = %+v, want %+v", ps.AcceptanceCriteria, want)
+	}
+}
+
+func TestVerifyCriteria(t *testing.T) {
+	criteria := []AcceptanceCriterion{
+		{Text: "unmapped"},
+		{Text: "missing test", TestRef: "TestMissing"},
+		{Text: "passes", TestRef: "TestPasses", Verified: true},
+		{Text: "fails", TestRef: "TestFails", Verified: true},
+		{Text: "no gate", TestRef: "TestNoGate"},
+	}
+	evidence := []testEvidence{
+		{tests: map[string]string{"TestNoGate": "b_test.go"}},
+		{
+			tests: map[string]string{"TestPasses": "a_test.go", "TestFails": "a_test.go"},
+			gate:  &BuildGateResult{Test: BuildResult{Passed: false, Output: "--- FAIL: TestFails (0.00s)"}},
+		},
+		{
+			tests: map[string]string{"TestPasses": "c_test.go"},
+			gate:  &BuildGateResult{Test: BuildResult{Passed: true}},
+		},
+	}
+
+	checks := verifyCriteria(criteria, evidence)
+	want := []struct {
+		verified bool
+		file     string
+	}{{false, ""}, {false, ""}, {true, "c_test.go"}, {false, "a_test.go"}, {false, "b_test.go"}}
+	for i, w := range want {
+		if checks[i].Verified != w.verified || checks[i].TestFile != w.file {
+			t.Errorf("%s: verified=%v file=%q (%s), want verified=%v file=%q",
+				checks[i].Text, checks[i].Verified, checks[i].TestFile, checks[i].Detail, w.verified, w.file)
+		}
+	}
+}
+
+func TestFindTestFuncsReadsWorktree(t *testing.T) {
+	root, worktree := t.TempDir(), t.TempDir()
+	write := func(dir, src string) {
+		if err := os.WriteFile(filepath.Join(dir, "a_test.go"), []byte(src), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(root, "package a\n\nfunc TestOnBase(t *testing.T) {}\n")
+	write(worktree, "package a\n\nfunc TestOnBranch(t *testing.T) {}\n")
+
+	wo := &WorkOrder{RepoRoot: root, WorktreePath: worktree, BranchName: "agent/x"}
+	got := findTestFuncs(workOrderFileReader(wo), []string{"a_test.go", "a.go"})
+	want := map[string]string{"TestOnBranch": "a_test.go"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("findTestFuncs = %v, want %v", got, want)
+	}
+
+	if got := findTestFuncs(workOrderFileReader(&WorkOrder{RepoRoot: root}), []string{"a_test.go"}); len(got) != 0 {
+		t.Errorf("order without worktree or branch: got %v, want none", got)
+	}
+}
+
+func TestReviewPlanDeductsUnmappedCriteria(t *testing.T) {
+	deduction := func(criteria []AcceptanceCriterion) int {
+		ps := &PlanState{Node: &Node{Data: []byte(`{}`)}, AcceptanceCriteria: criteria}
+		for _, c := range reviewPlan(ps).Checks {
+			if c.Name == "criteria_tests" {
+				return c.Deduction
+			}
+		}
+		return -1
+	}
+	if got := deduction([]AcceptanceCriterion{{Text: "a", TestRef: "TestA"}}); got != 0 {
+		t.Errorf("all mapped: deduction = %d, want 0", got)
+	}
+	if got := deduction([]AcceptanceCriterion{{Text: "a"}, {Text: "b", TestRef: "TestB"}}); got != 5 {
+		t.Errorf("one unmapped: deduction = %d, want 5", got)
+	}
+	if got := deduction([]AcceptanceCriterion{{Text: "a"}, {Text: "b"}, {Text: "c"}, {Text: "d"}}); got != 15 {
+		t.Errorf("four unmapped: deduction = %d, want capped 15", got)
+	}
+	if got := deduction([]AcceptanceCriterion{{Text: "a", legacy: true}, {Text: "b", legacy: true}}); got != -1 {
+		t.Errorf("legacy only: deduction = %d, want no criteria_tests check", got)
+	}
+	if got := deduction([]AcceptanceCriterion{{Text: "a", legacy: true}, {Text: "b"}}); got != 5 {
+		t.Errorf("legacy and unmapped: deduction = %d, want 5", got)
+	}
+}
+
+func TestAppendUnique(t *testing.T) {
+	got := appendUnique([]string{"plan:a"}, "task:b", "plan:a", "task:b", "task:c")
+	want := []string{"plan:a", "task:b", "task:c"}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("got %v, want %v", got, want)
+		}
+	}
+}
+
+func TestPrereqProviderDone(t *testing.T) {
+	cases := map[string]bool{
+		`{"status":"completed"}`: true,
+		`{"status":"done"}`:      true,
+		`{"status":"pending"}`:   false,
+		`{}`:                     false,
+		`not json`:               false,
+	}
+	for raw, want := range cases {
+		if got := prereqProviderDone([]byte(raw)); got != want {
+			t.Errorf("prereqProviderDone(%s) = %v, want %v", raw, got, want)
+		}
+	}
+}
+
+func TestNeedsUserApproval(t *testing.T) {
+	tests := []struct {
+		name string
+		ps   PlanState
+		want bool
+	}{
+		{"held", PlanState{Stage: StageApproved, Gate: &PlanGate{Decision: "user_approve"}}, true},
+		{"auto_run", PlanState{Stage: StageApproved, Gate: &PlanGate{Decision: "auto_run"}}, false},
+		{"already approved", PlanState{Stage: StageApproved, Gate: &PlanGate{Decision: GateUserApproved}}, false},
+		{"no gate", PlanState{Stage: StageApproved}, false},
+		{"in review", PlanState{Stage: StageReview, Gate: &PlanGate{Decision: "user_approve"}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.ps.NeedsUserApproval(); got != tt.want {
+				t.Errorf("NeedsUserApproval() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPlanStepFiles(t *testing.T) {
+	ps := &PlanState{Steps: []PlanStep{
+		{Files: []string{"a.go", "b.go"}},
+		{},
+		{Files: []string{"b.go", "", "c.go"}},
+	}}
+	want := []string{"a.go", "b.go", "c.go"}
+	if got := planStepFiles(ps); !reflect.DeepEqual(got, want) {
+		t.Errorf("planStepFiles = %v, want %v", got, want)
+	}
+}
+
+func TestPlanTemplateVars(t *testing.T) {
+	skeleton := map[string]any{
+		"goal":  "Add {{tool}}: {{ description }}",
+		"steps": []any{map[string]any{"description": "Define def{{Tool}}", "files": []any{"/dash/tool_{{tool}}.go"}}},
+	}
+	got := planTemplateVars(skeleton)
+	if strings.Join(got, ",") != "description,tool" {
+		t.Errorf("vars = %v, want [description tool]", got)
+	}
+}
+
+func TestInstantiatePlanTemplate(t *testing.T) {
+	tmpl := &builtinPlanTemplates[0]
+	data, err := instantiatePlanTemplate(tmpl, map[string]string{"tool": "lint", "description": "run linters"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := stringVal(data, "goal"); got != "Add the lint MCP tool: run linters" {
+		t.Errorf("goal = %q", got)
+	}
+	ps, err := parsePlanData(&Node{Data: mustJSON(t, data)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ps.Steps[0].Description != "Define defLint and the tool function" || ps.Steps[0].Files[0] != "/dash/tool_lint.go" {
+		t.Errorf("step 0 = %+v", ps.Steps[0])
+	}
+	if err := validateOutline(ps); err != nil {
+		t.Errorf("instantiated outline invalid: %v", err)
+	}
+	if err := validatePlan(ps); err != nil {
+		t.Errorf("instantiated plan invalid: %v", err)
+	}
+
+	// The builtin skeleton itself is left untouched.
+	if !strings.Contains(stringVal(tmpl.Skeleton, "goal"), "{{tool}}") {
+		t.Error("instantiation modified the template skeleton")
+	}
+
+	if _, err := instantiatePlanTemplate(tmpl, map[string]string{"tool": "lint"}); err == nil || !strings.Contains(err.Error(), "description") {
+		t.Errorf("want missing description error, got %v", err)
+	}
+}
+
+func mustJSON(t *testing.T, v any) []byte {
+	t.Helper()
+	b, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
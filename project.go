@@ -2,7 +2,9 @@ package dash
 
 import (
 	"context"
+	"database/sql"
 	"encoding/json"
+	"strings"
 
 	"github.com/google/uuid"
 )
@@ -103,6 +105,38 @@ func (d *Dash) LinkTaskToProject(ctx context.Context, taskID, projectID uuid.UUI
 	})
 }
 
+const queryProjectForCwd = `
+	SELECT id, layer, type, name, data, created_at, updated_at, deleted_at
+	FROM nodes
+	WHERE layer = 'CONTEXT' AND type = 'project'
+	  AND deleted_at IS NULL
+	  AND COALESCE(data->>'path', '') != ''
+	  AND ($1 = data->>'path' OR left($1, length(data->>'path') + 1) = (data->>'path') || '/')
+	ORDER BY length(data->>'path') DESC
+	LIMIT 1`
+
+// ProjectForCwd returns the project whose path contains cwd, the deepest
+// one when project paths are nested, or sql.ErrNoRows when none does.
+func (d *Dash) ProjectForCwd(ctx context.Context, cwd string) (*Node, error) {
+	cwd = strings.TrimRight(cwd, "/")
+	if cwd == "" {
+		return nil, sql.ErrNoRows
+	}
+	rows, err := d.db.QueryContext(ctx, queryProjectForCwd, cwd)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	nodes, err := scanNodes(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(nodes) == 0 {
+		return nil, sql.ErrNoRows
+	}
+	return nodes[0], nil
+}
+
 // GetProjectByName retrieves a project node by name.
 func (d *Dash) GetProjectByName(ctx context.Context, name string) (*Node, error) {
 	return d.GetNodeByName(ctx, LayerContext, "project", name)
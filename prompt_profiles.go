@@ -26,6 +26,7 @@ type PromptProfile struct {
 type SourceOverride struct {
 	MaxItems int    `json:"max_items,omitempty"`
 	Format   string `json:"format,omitempty"`
+	When     string `json:"when,omitempty"` // e.g. "has_tasks", "!has_plan", "output:tasks"
 }
 
 // GetProfile retrieves a prompt profile by name.
@@ -2,6 +2,8 @@ package dash
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
@@ -156,12 +158,58 @@ func profileToPipeline(profile *PromptProfile) Pipeline {
 			if override.Format != "" {
 				src.Format = override.Format
 			}
+			src.When = override.When
 		}
 		p.Sources = append(p.Sources, src)
 	}
 	return p
 }
 
+// PipelineCheck reports problems in a stored profile's pipeline.
+type PipelineCheck struct {
+	Profile        string   `json:"profile"`
+	Sources        []string `json:"sources"`
+	UnknownSources []string `json:"unknown_sources,omitempty"`
+	EmptySections  []string `json:"empty_sections,omitempty"`
+	Errors         []string `json:"errors,omitempty"`
+	Render         string   `json:"render"`
+}
+
+// CheckProfilePipeline loads a profile, validates its source names and
+// dry-renders the pipeline with empty params. Known sources that render
+// nothing are listed in EmptySections; many need a task or plan to show output.
+func (d *Dash) CheckProfilePipeline(ctx context.Context, name string) (*PipelineCheck, error) {
+	profile, err := d.GetProfile(ctx, name)
+	if errors.Is(err, sql.ErrNoRows) || errors.Is(err, ErrNodeNotFound) {
+		return nil, fmt.Errorf("profile %q not found", name)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("load profile %q: %w", name, err)
+	}
+	p := profileToPipeline(profile)
+
+	check := &PipelineCheck{Profile: profile.Name, Sources: profile.Sources}
+	for _, e := range ValidatePipeline(p) {
+		check.Errors = append(check.Errors, e.Error())
+	}
+
+	var b strings.Builder
+	for _, src := range p.Sources {
+		if _, ok := sourceRegistry[src.Name]; !ok {
+			check.UnknownSources = append(check.UnknownSources, src.Name)
+			continue
+		}
+		section := d.RunPipeline(ctx, Pipeline{Sources: []PipelineSource{src}}, SourceParams{})
+		if section == "" {
+			check.EmptySections = append(check.EmptySections, src.Name)
+			continue
+		}
+		b.WriteString(section)
+	}
+	check.Render = b.String()
+	return check, nil
+}
+
 // getCachedPrompt checks for a cached system_prompt node within TTL.
 func (d *Dash) getCachedPrompt(ctx context.Context, cacheKey string, ttl time.Duration) (string, bool) {
 	node, err := d.GetNodeByName(ctx, LayerContext, "system_prompt", cacheKey)
@@ -0,0 +1,173 @@
+package dash
+
+import (
+	"context"
+	"hash/fnv"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Prompt templates are versioned SYSTEM.prompt_template nodes named
+// "<template>@<version>" with data {template, version, text, weight, active}.
+// Sessions are split between the active versions of a template by weight,
+// so the same session always gets the same version and its results can be
+// compared across versions.
+const promptTemplateType = "prompt_template"
+
+// DefaultPromptVersion is the version built from the hardcoded prompt. It
+// takes part in the split unless a stored version with this name replaces
+// it, and is the only version when none are stored.
+const DefaultPromptVersion = "default"
+
+// Template names.
+const (
+	PromptPlanGeneration = "plan_generation"
+	PromptSynthesis      = "synthesis"
+	PromptHandoffSummary = "handoff_summary"
+)
+
+// defaultPromptTemplates maps template names to their hardcoded default text.
+var defaultPromptTemplates = map[string]string{
+	PromptPlanGeneration: planGenerationSystemPrompt,
+	PromptSynthesis:      synthesisSystemPrompt,
+	PromptHandoffSummary: handoffSummaryPrompt,
+}
+
+// PromptTemplate is one version of a named prompt.
+type PromptTemplate struct {
+	Name    string  `json:"template"`
+	Version string  `json:"version"`
+	Text    string  `json:"text"`
+	Weight  float64 `json:"weight"` // share of sessions relative to the other active versions
+}
+
+// Ref is "<template>@<version>", the form recorded with completions.
+func (t PromptTemplate) Ref() string {
+	return t.Name + "@" + t.Version
+}
+
+const queryListPromptTemplates = `
+	SELECT id, layer, type, name, data, created_at, updated_at, deleted_at
+	FROM nodes
+	WHERE layer = 'SYSTEM' AND type = 'prompt_template'
+	  AND deleted_at IS NULL
+	  AND data->>'template' = $1
+	  AND COALESCE((data->>'active')::boolean, true)`
+
+// GetPromptTemplate returns the version of prompt name assigned to the
+// context's session (see WithLLMSession; the LLM agent key when no session
+// is set). Without stored versions, or when they cannot be read, it returns
+// the hardcoded default.
+func (d *Dash) GetPromptTemplate(ctx context.Context, name string) PromptTemplate {
+	versions := d.promptTemplateVersions(ctx, name)
+	key := LLMSessionFromContext(ctx)
+	if key == "" {
+		key = LLMAgentFromContext(ctx)
+	}
+	return assignPromptVersion(name, versions, key)
+}
+
+// SavePromptTemplate stores or replaces a version of a prompt template.
+// A weight of 0 is stored as 1.
+func (d *Dash) SavePromptTemplate(ctx context.Context, t PromptTemplate) (*Node, error) {
+	if t.Weight <= 0 {
+		t.Weight = 1
+	}
+	data := map[string]any{
+		"template": t.Name,
+		"version":  t.Version,
+		"text":     t.Text,
+		"weight":   t.Weight,
+		"active":   true,
+	}
+	node, err := d.GetOrCreateNode(ctx, LayerSystem, promptTemplateType, t.Ref(), data)
+	if err != nil {
+		return nil, err
+	}
+	if err := d.UpdateNodeData(ctx, node, data); err != nil {
+		return nil, err
+	}
+	return node, nil
+}
+
+// promptTemplateVersions returns the default version of name followed by
+// its active stored versions.
+func (d *Dash) promptTemplateVersions(ctx context.Context, name string) []PromptTemplate {
+	versions := []PromptTemplate{{Name: name, Version: DefaultPromptVersion, Text: defaultPromptTemplates[name], Weight: 1}}
+	if d.db == nil {
+		return versions
+	}
+	qCtx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+	rows, err := d.db.QueryContext(qCtx, queryListPromptTemplates, name)
+	if err != nil {
+		return versions
+	}
+	defer rows.Close()
+	nodes, err := scanNodes(rows)
+	if err != nil {
+		return versions
+	}
+	for _, n := range nodes {
+		data := NodeDataOf(n)
+		t := PromptTemplate{
+			Name:    name,
+			Version: data.String("version"),
+			Text:    data.String("text"),
+			Weight:  data.Float("weight"),
+		}
+		if t.Version == "" || strings.TrimSpace(t.Text) == "" {
+			continue
+		}
+		if t.Weight <= 0 {
+			t.Weight = 1
+		}
+		if t.Version == DefaultPromptVersion {
+			versions[0] = t
+			continue
+		}
+		versions = append(versions, t)
+	}
+	return versions
+}
+
+// assignPromptVersion picks one of versions for key by weight. The pick
+// depends only on name, key and the set of versions, so a session keeps its
+// version for as long as the versions are unchanged.
+func assignPromptVersion(name string, versions []PromptTemplate, key string) PromptTemplate {
+	if len(versions) == 0 {
+		return PromptTemplate{Name: name, Version: DefaultPromptVersion, Text: defaultPromptTemplates[name], Weight: 1}
+	}
+	sorted := append([]PromptTemplate(nil), versions...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Version < sorted[j].Version })
+
+	var total float64
+	for _, v := range sorted {
+		total += v.Weight
+	}
+	h := fnv.New64a()
+	h.Write([]byte(name + "\x00" + key))
+	point := float64(h.Sum64()%1_000_000) / 1_000_000 * total
+	for _, v := range sorted {
+		if point < v.Weight {
+			return v
+		}
+		point -= v.Weight
+	}
+	return sorted[len(sorted)-1]
+}
+
+type promptTemplateKey struct{}
+
+// withPromptTemplate records that completions made with ctx use t.
+func withPromptTemplate(ctx context.Context, t PromptTemplate) context.Context {
+	return context.WithValue(ctx, promptTemplateKey{}, t.Ref())
+}
+
+// promptTemplateFromContext returns the template ref set by
+// withPromptTemplate, or "".
+func promptTemplateFromContext(ctx context.Context) string {
+	ref, _ := ctx.Value(promptTemplateKey{}).(string)
+	return ref
+}
@@ -0,0 +1,58 @@
+package dash
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"testing"
+)
+
+func TestAssignPromptVersionIsStablePerSession(t *testing.T) {
+	versions := []PromptTemplate{
+		{Name: "p", Version: DefaultPromptVersion, Text: "a", Weight: 1},
+		{Name: "p", Version: "v2", Text: "b", Weight: 1},
+	}
+	for i := 0; i < 20; i++ {
+		key := fmt.Sprintf("session-%d", i)
+		first := assignPromptVersion("p", versions, key)
+		reversed := []PromptTemplate{versions[1], versions[0]}
+		if again := assignPromptVersion("p", reversed, key); again.Version != first.Version {
+			t.Fatalf("%s: got %s then %s", key, first.Version, again.Version)
+		}
+	}
+}
+
+func TestAssignPromptVersionFollowsWeights(t *testing.T) {
+	versions := []PromptTemplate{
+		{Name: "p", Version: DefaultPromptVersion, Weight: 3},
+		{Name: "p", Version: "v2", Weight: 1},
+	}
+	const n = 4000
+	counts := map[string]int{}
+	for i := 0; i < n; i++ {
+		counts[assignPromptVersion("p", versions, fmt.Sprintf("s%d", i)).Version]++
+	}
+	if share := float64(counts["v2"]) / n; math.Abs(share-0.25) > 0.04 {
+		t.Errorf("v2 share = %.3f, want ~0.25 (%v)", share, counts)
+	}
+}
+
+func TestAssignPromptVersionFallsBackToDefault(t *testing.T) {
+	got := assignPromptVersion(PromptPlanGeneration, nil, "s")
+	if got.Version != DefaultPromptVersion || got.Text != planGenerationSystemPrompt {
+		t.Errorf("got %s %q, want the hardcoded default", got.Version, got.Text[:20])
+	}
+	if got.Ref() != "plan_generation@default" {
+		t.Errorf("Ref() = %q", got.Ref())
+	}
+}
+
+func TestPromptTemplateRecordedOnUsage(t *testing.T) {
+	ctx := withPromptTemplate(context.Background(), PromptTemplate{Name: PromptSynthesis, Version: "v3"})
+	if got := promptTemplateFromContext(ctx); got != "synthesis@v3" {
+		t.Errorf("promptTemplateFromContext = %q, want synthesis@v3", got)
+	}
+	if got := promptTemplateFromContext(context.Background()); got != "" {
+		t.Errorf("no template should give \"\", got %q", got)
+	}
+}
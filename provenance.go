@@ -0,0 +1,89 @@
+package dash
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+type callerContextKey struct{}
+
+// defaultCallerID identifies tool calls that name no caller, i.e. CLI
+// scripts calling RunTool directly.
+const defaultCallerID = "cli"
+
+// WithCaller attaches the identity of whoever is writing (an agent key,
+// "mcp", "cockpit", "auto-promotion") to the context. CreateNode and the
+// node update functions stamp it into the node's created_by/updated_by.
+func WithCaller(ctx context.Context, caller string) context.Context {
+	return context.WithValue(ctx, callerContextKey{}, caller)
+}
+
+// CallerFromContext returns the caller set by WithCaller, or "" if none.
+func CallerFromContext(ctx context.Context) string {
+	caller, _ := ctx.Value(callerContextKey{}).(string)
+	return caller
+}
+
+// stampCreated sets created_by and updated_by in data to caller. A
+// created_by already present in data is kept, so callers that record a
+// more specific origin ("promotion") are not overwritten.
+func stampCreated(data json.RawMessage, caller string) json.RawMessage {
+	if caller == "" {
+		return data
+	}
+	var fields map[string]any
+	if err := json.Unmarshal(data, &fields); err != nil || fields == nil {
+		return data
+	}
+	if by, _ := fields["created_by"].(string); by == "" {
+		fields["created_by"] = caller
+	}
+	fields["updated_by"] = caller
+	out, err := json.Marshal(fields)
+	if err != nil {
+		return data
+	}
+	return out
+}
+
+// stampUpdated sets updated_by in data to caller.
+func stampUpdated(data json.RawMessage, caller string) json.RawMessage {
+	if caller == "" {
+		return data
+	}
+	out, err := mergeNodeData(data, map[string]any{"updated_by": caller})
+	if err != nil {
+		return data
+	}
+	return out
+}
+
+// withUpdatedBy returns patch plus updated_by for caller, leaving patch
+// itself untouched since callers retry with it after a conflict.
+func withUpdatedBy(patch map[string]any, caller string) map[string]any {
+	if caller == "" {
+		return patch
+	}
+	if _, ok := patch["updated_by"]; ok {
+		return patch
+	}
+	out := make(map[string]any, len(patch)+1)
+	for k, v := range patch {
+		out[k] = v
+	}
+	out["updated_by"] = caller
+	return out
+}
+
+// NodesByCreator returns active nodes whose created_by is creator, newest
+// first: everything a given agent, tool caller or "auto-promotion" made.
+func (d *Dash) NodesByCreator(ctx context.Context, creator string) ([]*Node, error) {
+	if creator == "" {
+		return nil, fmt.Errorf("creator is required")
+	}
+	return d.SearchNodes(ctx, NodeFilter{
+		DataFilter: map[string]any{"created_by": creator},
+		Limit:      1000,
+	})
+}
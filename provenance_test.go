@@ -0,0 +1,67 @@
+package dash
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestStampCreated(t *testing.T) {
+	var got map[string]any
+	json.Unmarshal(stampCreated(json.RawMessage(`{"text":"x"}`), "agent-1"), &got)
+	if got["created_by"] != "agent-1" || got["updated_by"] != "agent-1" {
+		t.Errorf("stamped = %v, want created_by and updated_by agent-1", got)
+	}
+
+	got = nil
+	json.Unmarshal(stampCreated(json.RawMessage(`{"created_by":"promotion"}`), "mcp"), &got)
+	if got["created_by"] != "promotion" || got["updated_by"] != "mcp" {
+		t.Errorf("explicit created_by overwritten: %v", got)
+	}
+
+	if out := stampCreated(json.RawMessage(`{"a":1}`), ""); string(out) != `{"a":1}` {
+		t.Errorf("no caller changed data: %s", out)
+	}
+}
+
+func TestWithUpdatedByLeavesPatchUntouched(t *testing.T) {
+	patch := map[string]any{"status": "done"}
+	got := withUpdatedBy(patch, "agent-1")
+	if got["updated_by"] != "agent-1" {
+		t.Errorf("updated_by = %v, want agent-1", got["updated_by"])
+	}
+	if _, ok := patch["updated_by"]; ok {
+		t.Error("patch was modified")
+	}
+}
+
+func TestNodesByCreator(t *testing.T) {
+	d := testDash(t)
+	creator := fmt.Sprintf("test-creator-%d", time.Now().UnixNano())
+	ctx := WithCaller(context.Background(), creator)
+
+	node := &Node{Layer: LayerContext, Type: "insight", Name: creator + "-insight"}
+	if err := d.CreateNode(ctx, node); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { d.SoftDeleteNode(context.Background(), node.ID) })
+
+	if err := d.UpdateNodeData(WithCaller(ctx, "other"), node, map[string]any{"text": "edited"}); err != nil {
+		t.Fatal(err)
+	}
+
+	nodes, err := d.NodesByCreator(context.Background(), creator)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(nodes) != 1 || nodes[0].ID != node.ID {
+		t.Fatalf("got %d nodes, want the created one", len(nodes))
+	}
+	var data map[string]any
+	json.Unmarshal(nodes[0].Data, &data)
+	if data["updated_by"] != "other" {
+		t.Errorf("updated_by = %v, want other", data["updated_by"])
+	}
+}
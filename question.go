@@ -0,0 +1,132 @@
+package dash
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Question statuses. A CONTEXT.question is open until a decision resolves it.
+const (
+	QuestionOpen     = "open"
+	QuestionResolved = "resolved"
+)
+
+const queryListOpenQuestions = `
+	SELECT id, layer, type, name, data, created_at, updated_at, deleted_at
+	FROM nodes
+	WHERE layer = 'CONTEXT' AND deleted_at IS NULL
+	  AND ((type = 'question' AND COALESCE(data->>'status', 'open') = 'open')
+	    OR (type = 'decision' AND data->>'status' = 'pending'))
+	ORDER BY created_at ASC
+	LIMIT $1`
+
+// CreateQuestion records an open question that blocks progress until it is
+// answered. raisedBy names the agent or user asking.
+func (d *Dash) CreateQuestion(ctx context.Context, text, contextStr, raisedBy string) (*Node, error) {
+	if text == "" {
+		return nil, fmt.Errorf("question text is required")
+	}
+	data := map[string]any{
+		"text":      text,
+		"status":    QuestionOpen,
+		"raised_by": raisedBy,
+	}
+	if contextStr != "" {
+		data["context"] = contextStr
+	}
+	dataJSON, _ := json.Marshal(data)
+
+	node := &Node{
+		Layer: LayerContext,
+		Type:  "question",
+		Name:  text,
+		Data:  dataJSON,
+	}
+	if len(node.Name) > 255 {
+		node.Name = node.Name[:252] + "..."
+	}
+	if err := d.CreateNode(ctx, node); err != nil {
+		return nil, err
+	}
+	go d.EmbedNode(context.Background(), node)
+	return node, nil
+}
+
+// ListOpenQuestions returns open questions and pending decisions, oldest
+// first, since those have blocked progress the longest.
+func (d *Dash) ListOpenQuestions(ctx context.Context, limit int) ([]*Node, error) {
+	if limit <= 0 {
+		limit = 10
+	}
+	rows, err := d.db.QueryContext(ctx, queryListOpenQuestions, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanNodes(rows)
+}
+
+// ResolveQuestion closes an open question with a decision. With decisionID
+// nil a new CONTEXT.decision is created from answer; otherwise the existing
+// decision is linked. The decision gets a resolves edge to the question,
+// which is marked resolved. Returns the decision.
+func (d *Dash) ResolveQuestion(ctx context.Context, questionID uuid.UUID, answer string, decisionID *uuid.UUID) (*Node, error) {
+	question, err := d.GetNodeActive(ctx, questionID)
+	if err != nil {
+		return nil, err
+	}
+	if question.Type != "question" || question.Layer != LayerContext {
+		return nil, fmt.Errorf("node %s is not a CONTEXT.question", questionID)
+	}
+	qd := NodeDataOf(question)
+	if status := qd.String("status"); status != "" && status != QuestionOpen {
+		return nil, fmt.Errorf("question is already %s", status)
+	}
+
+	var decision *Node
+	if decisionID != nil {
+		decision, err = d.GetNodeActive(ctx, *decisionID)
+		if err != nil {
+			return nil, fmt.Errorf("get decision: %w", err)
+		}
+		if decision.Type != "decision" {
+			return nil, fmt.Errorf("node %s is not a decision", *decisionID)
+		}
+	} else {
+		if answer == "" {
+			return nil, fmt.Errorf("answer or decision_id is required")
+		}
+		dataJSON, _ := json.Marshal(map[string]any{
+			"text":    answer,
+			"context": "Answers: " + qd.String("text"),
+		})
+		decision = &Node{Layer: LayerContext, Type: "decision", Name: answer, Data: dataJSON}
+		if len(decision.Name) > 255 {
+			decision.Name = decision.Name[:252] + "..."
+		}
+		if err := d.CreateNode(ctx, decision); err != nil {
+			return nil, fmt.Errorf("create decision: %w", err)
+		}
+		go d.EmbedNode(context.Background(), decision)
+	}
+
+	if err := d.CreateEdge(ctx, &Edge{
+		SourceID: decision.ID,
+		TargetID: question.ID,
+		Relation: RelationResolves,
+	}); err != nil {
+		return decision, fmt.Errorf("link decision: %w", err)
+	}
+	if err := d.UpdateNodeData(ctx, question, map[string]any{
+		"status":      QuestionResolved,
+		"resolved_at": time.Now().UTC().Format(time.RFC3339),
+		"decision_id": decision.ID.String(),
+	}); err != nil {
+		return decision, err
+	}
+	return decision, nil
+}
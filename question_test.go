@@ -0,0 +1,59 @@
+package dash
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestQuestionLifecycle(t *testing.T) {
+	d := testDash(t)
+	ctx := context.Background()
+	text := fmt.Sprintf("test-question-%d: which queue backend?", time.Now().UnixNano())
+
+	q, err := d.CreateQuestion(ctx, text, "blocks the worker task", "backend")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { d.SoftDeleteNode(ctx, q.ID) })
+
+	if !isOpenQuestion(t, d, q) {
+		t.Fatal("new question should be listed as open")
+	}
+
+	decision, err := d.ResolveQuestion(ctx, q.ID, "use postgres LISTEN/NOTIFY", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { d.SoftDeleteNode(ctx, decision.ID) })
+
+	if isOpenQuestion(t, d, q) {
+		t.Error("resolved question should not be listed")
+	}
+	edges, err := d.ListEdgesBetween(ctx, decision.ID, q.ID)
+	if err != nil || len(edges) != 1 || edges[0].Relation != RelationResolves {
+		t.Errorf("want one resolves edge, got %v (err %v)", edges, err)
+	}
+	got, _ := d.GetNodeActive(ctx, q.ID)
+	if s := NodeDataOf(got).String("status"); s != QuestionResolved {
+		t.Errorf("status = %q, want resolved", s)
+	}
+	if _, err := d.ResolveQuestion(ctx, q.ID, "again", nil); err == nil {
+		t.Error("resolving twice should fail")
+	}
+}
+
+func isOpenQuestion(t *testing.T, d *Dash, n *Node) bool {
+	t.Helper()
+	open, err := d.ListOpenQuestions(context.Background(), 1000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, o := range open {
+		if o.ID == n.ID {
+			return true
+		}
+	}
+	return false
+}
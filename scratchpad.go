@@ -0,0 +1,90 @@
+package dash
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// scratchpadType is the CONTEXT node type holding a shared scratchpad.
+const scratchpadType = "scratchpad"
+
+// Scratchpad limits; the oldest notes are dropped once either is exceeded.
+const (
+	maxScratchpadNotes = 50
+	maxScratchpadBytes = 16 * 1024 // total note text
+)
+
+// ScratchpadNote is one entry on a scratchpad.
+type ScratchpadNote struct {
+	At    time.Time `json:"at"`
+	Agent string    `json:"agent"`
+	Note  string    `json:"note"`
+}
+
+// AppendScratchpad adds a note to the scratchpad for key (a work order or
+// task name), creating it on first use. Concurrent appends are retried
+// against the fresh note list, so no note is lost.
+func (d *Dash) AppendScratchpad(ctx context.Context, key, agentKey, note string) error {
+	if key == "" || note == "" {
+		return fmt.Errorf("key and note are required")
+	}
+	node, err := d.GetOrCreateNode(ctx, LayerContext, scratchpadType, key, map[string]any{"notes": []any{}})
+	if err != nil {
+		return err
+	}
+	entry := ScratchpadNote{At: time.Now().UTC(), Agent: agentKey, Note: note}
+
+	for attempt := 0; attempt < maxNodeDataRetries; attempt++ {
+		notes := append(scratchpadNotes(node), entry)
+		err = d.UpdateNodeDataCAS(ctx, node, node.UpdatedAt, map[string]any{"notes": trimScratchpad(notes)})
+		if err != ErrNodeConflict {
+			return err
+		}
+		fresh, getErr := d.GetNodeActive(ctx, node.ID)
+		if getErr != nil {
+			return getErr
+		}
+		node = fresh
+	}
+	return err
+}
+
+// ReadScratchpad returns the notes on the scratchpad for key, oldest first.
+// A scratchpad nobody has written to yet is empty, not an error.
+func (d *Dash) ReadScratchpad(ctx context.Context, key string) ([]ScratchpadNote, error) {
+	node, err := d.GetNodeByName(ctx, LayerContext, scratchpadType, key)
+	if err == ErrNodeNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return scratchpadNotes(node), nil
+}
+
+func scratchpadNotes(node *Node) []ScratchpadNote {
+	var data struct {
+		Notes []ScratchpadNote `json:"notes"`
+	}
+	json.Unmarshal(node.Data, &data)
+	return data.Notes
+}
+
+// trimScratchpad drops the oldest notes until both the note count and the
+// total note size fit. The newest note is always kept.
+func trimScratchpad(notes []ScratchpadNote) []ScratchpadNote {
+	if len(notes) > maxScratchpadNotes {
+		notes = notes[len(notes)-maxScratchpadNotes:]
+	}
+	size := 0
+	for _, n := range notes {
+		size += len(n.Note)
+	}
+	for len(notes) > 1 && size > maxScratchpadBytes {
+		size -= len(notes[0].Note)
+		notes = notes[1:]
+	}
+	return notes
+}
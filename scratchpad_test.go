@@ -0,0 +1,68 @@
+package dash
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestTrimScratchpad(t *testing.T) {
+	var notes []ScratchpadNote
+	for i := 0; i < maxScratchpadNotes+5; i++ {
+		notes = append(notes, ScratchpadNote{Note: fmt.Sprintf("note-%d", i)})
+	}
+	got := trimScratchpad(notes)
+	if len(got) != maxScratchpadNotes {
+		t.Fatalf("kept %d notes, want %d", len(got), maxScratchpadNotes)
+	}
+	if got[0].Note != "note-5" {
+		t.Errorf("oldest kept = %q, want note-5", got[0].Note)
+	}
+
+	big := strings.Repeat("x", maxScratchpadBytes/2)
+	got = trimScratchpad([]ScratchpadNote{{Note: big}, {Note: big}, {Note: "last"}})
+	if len(got) != 2 || got[1].Note != "last" {
+		t.Errorf("size trim kept %d notes, want the newest 2", len(got))
+	}
+
+	huge := strings.Repeat("x", maxScratchpadBytes*2)
+	if got := trimScratchpad([]ScratchpadNote{{Note: "old"}, {Note: huge}}); len(got) != 1 {
+		t.Errorf("oversized newest note: kept %d, want 1", len(got))
+	}
+}
+
+func TestScratchpadConcurrentAppend(t *testing.T) {
+	d := testDash(t)
+	ctx := context.Background()
+	key := fmt.Sprintf("test-scratchpad-%d", time.Now().UnixNano())
+
+	if notes, err := d.ReadScratchpad(ctx, key); err != nil || len(notes) != 0 {
+		t.Fatalf("unwritten scratchpad = %v, %v; want empty", notes, err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if err := d.AppendScratchpad(ctx, key, fmt.Sprintf("agent-%d", i), "hello"); err != nil {
+				t.Errorf("append %d: %v", i, err)
+			}
+		}(i)
+	}
+	wg.Wait()
+	if node, err := d.GetNodeByName(ctx, LayerContext, scratchpadType, key); err == nil {
+		t.Cleanup(func() { d.SoftDeleteNode(ctx, node.ID) })
+	}
+
+	notes, err := d.ReadScratchpad(ctx, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(notes) != 4 {
+		t.Errorf("got %d notes, want 4", len(notes))
+	}
+}
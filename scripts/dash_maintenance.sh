@@ -86,6 +86,19 @@ for line in sys.stdin:
             print(f'  errors: {len(data.get(\"errors\",[]))}')
     except: pass
 " 2>/dev/null || log "Embeddings: nothing to backfill"
+    echo '{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"embed","arguments":{"op":"reembed_stale"}}}' \
+        | timeout 120 "$MCP_BIN" 2>/dev/null | python3 -c "
+import sys, json
+for line in sys.stdin:
+    try:
+        r = json.loads(line)
+        if 'result' in r:
+            d = r['result'].get('content',[{}])[0].get('text','{}')
+            data = json.loads(d)
+            print(f'  re-embedded: {data.get(\"reembedded\",0)} nodes ({data.get(\"model\",\"\")})')
+            print(f'  stale remaining: {data.get(\"remaining\",0)}')
+    except: pass
+" 2>/dev/null || log "Embeddings: nothing to re-embed"
 else
     log "Embeddings: skipped (no MCP binary or OPENROUTER_API_KEY)"
 fi
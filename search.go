@@ -98,6 +98,131 @@ func (d *Dash) SearchSimilar(ctx context.Context, query string, limit int) ([]*S
 	}
 	defer rows.Close()
 
+	return scanSearchResults(rows)
+}
+
+// SearchSimilarByType is SearchSimilar restricted to one layer and node type.
+func (d *Dash) SearchSimilarByType(ctx context.Context, query string, layer Layer, nodeType string, limit int) ([]*SearchResult, error) {
+	if !d.HasRealEmbedder() {
+		return nil, ErrNoEmbedder
+	}
+
+	queryEmbedding, err := d.embedder.Embed(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("embed query: %w", err)
+	}
+	if queryEmbedding == nil {
+		return nil, ErrNoEmbedder
+	}
+
+	if limit <= 0 {
+		limit = 10
+	}
+	if limit > 100 {
+		limit = 100
+	}
+
+	rows, err := d.db.QueryContext(ctx, `
+		SELECT id, layer, type, name, data, embedding <=> $1 as distance, embedding_at
+		FROM nodes
+		WHERE layer = $2 AND type = $3
+		  AND embedding IS NOT NULL
+		  AND deleted_at IS NULL
+		ORDER BY embedding <=> $1
+		LIMIT $4
+	`, float32SliceToVector(queryEmbedding), layer, nodeType, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanSearchResults(rows)
+}
+
+const (
+	queryNodeEmbeddingVector = `
+		SELECT embedding::text FROM nodes WHERE id = $1 AND deleted_at IS NULL`
+
+	queryRelatedNodes = `
+		SELECT id, layer, type, name, data, embedding <=> $1 as distance, embedding_at
+		FROM nodes
+		WHERE embedding IS NOT NULL
+		  AND deleted_at IS NULL
+		  AND id <> $2
+		ORDER BY embedding <=> $1
+		LIMIT $3`
+)
+
+// RelatedNodes returns the nodes whose embeddings are nearest to nodeID's,
+// excluding the node itself, ranked by cosine distance. A node without a
+// stored embedding is embedded on the fly from its text (not persisted).
+// Unlike graph traversal this is purely semantic.
+func (d *Dash) RelatedNodes(ctx context.Context, nodeID uuid.UUID, limit int) ([]*SearchResult, error) {
+	if limit <= 0 {
+		limit = 10
+	}
+	if limit > 100 {
+		limit = 100
+	}
+
+	var stored sql.NullString
+	err := d.db.QueryRowContext(ctx, queryNodeEmbeddingVector, nodeID).Scan(&stored)
+	if err == sql.ErrNoRows {
+		return nil, ErrNodeNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	vector := stored.String
+	if !stored.Valid || vector == "" {
+		node, err := d.GetNodeActive(ctx, nodeID)
+		if err != nil {
+			return nil, err
+		}
+		text := relatedNodeText(node)
+		if text == "" {
+			return nil, fmt.Errorf("node %s has no embedding and no text to embed", nodeID)
+		}
+		if !d.HasRealEmbedder() {
+			return nil, ErrNoEmbedder
+		}
+		embedding, err := d.embedder.Embed(ctx, text)
+		if err != nil {
+			return nil, fmt.Errorf("embed node %s: %w", nodeID, err)
+		}
+		if embedding == nil {
+			return nil, ErrNoEmbedder
+		}
+		vector = float32SliceToVector(embedding)
+	}
+
+	rows, err := d.db.QueryContext(ctx, queryRelatedNodes, vector, nodeID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanSearchResults(rows)
+}
+
+// relatedNodeText is the text embedded for a node that has no stored
+// embedding: a stored summary when present, otherwise the node's
+// embeddable text.
+func relatedNodeText(node *Node) string {
+	data := extractNodeData(node)
+	if summary, ok := data["summary"].(string); ok && summary != "" {
+		return summary
+	}
+	text := extractEmbeddableText(node)
+	if len(text) > MaxEmbeddingTextSize {
+		text = text[:MaxEmbeddingTextSize]
+	}
+	return text
+}
+
+// scanSearchResults reads rows of (id, layer, type, name, data, distance, embedding_at).
+func scanSearchResults(rows *sql.Rows) ([]*SearchResult, error) {
 	var results []*SearchResult
 	for rows.Next() {
 		var r SearchResult
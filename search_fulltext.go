@@ -0,0 +1,66 @@
+package dash
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// queryFullTextSearch takes $1 = query, $2 = limit. The tsvector column and
+// node_search_text come from migration 028.
+const queryFullTextSearch = `
+	WITH q AS (SELECT plainto_tsquery('simple', $1) AS query)
+	SELECT n.id, n.layer, n.type, n.name,
+	       ts_rank(n.search_tsv, q.query) AS rank,
+	       ts_headline('simple', node_search_text(n.name, n.data), q.query,
+	                   'StartSel=**, StopSel=**, MaxWords=30, MinWords=10, MaxFragments=2, FragmentDelimiter=" … "')
+	FROM nodes n, q
+	WHERE n.deleted_at IS NULL
+	  AND n.search_tsv @@ q.query
+	ORDER BY rank DESC, n.updated_at DESC
+	LIMIT $2`
+
+// FullTextResult is a node matched by FullTextSearch.
+type FullTextResult struct {
+	ID      uuid.UUID `json:"id"`
+	Layer   string    `json:"layer"`
+	Type    string    `json:"type"`
+	Name    string    `json:"name"`
+	Rank    float64   `json:"rank"`
+	Snippet string    `json:"snippet"` // matched words wrapped in **
+}
+
+// FullTextSearch finds nodes whose name or data text (description, text,
+// content, summary, ...) contains all words of query, best ranked first.
+// Unlike SearchSimilar it needs no embedder and matches exact words.
+func (d *Dash) FullTextSearch(ctx context.Context, query string, limit int) ([]*FullTextResult, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, fmt.Errorf("query is required")
+	}
+	if limit <= 0 {
+		limit = 20
+	}
+	if limit > 100 {
+		limit = 100
+	}
+
+	rows, err := d.db.QueryContext(ctx, queryFullTextSearch, query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("full-text search: %w", err)
+	}
+	defer rows.Close()
+
+	var results []*FullTextResult
+	for rows.Next() {
+		r := &FullTextResult{}
+		if err := rows.Scan(&r.ID, &r.Layer, &r.Type, &r.Name, &r.Rank, &r.Snippet); err != nil {
+			return nil, err
+		}
+		r.Snippet = strings.Join(strings.Fields(r.Snippet), " ")
+		results = append(results, r)
+	}
+	return results, rows.Err()
+}
@@ -0,0 +1,161 @@
+package dash
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// replayMaxEvents bounds how many observations SessionReplay loads.
+const replayMaxEvents = 2000
+
+// ReplayEvent is one step of a recorded session: a session start/end or a
+// tool call with its result. A tool.pre observation is folded into the
+// post/failure that completes it.
+type ReplayEvent struct {
+	At         time.Time       `json:"at"`
+	Gap        time.Duration   `json:"gap"`   // since the previous event
+	Event      string          `json:"event"` // session.start, tool.post, tool.failure, tool.pre (never completed), ...
+	ToolName   string          `json:"tool_name,omitempty"`
+	ToolUseID  string          `json:"tool_use_id,omitempty"`
+	Args       json.RawMessage `json:"args,omitempty"`
+	Result     string          `json:"result,omitempty"`
+	Error      string          `json:"error,omitempty"`
+	Success    *bool           `json:"success,omitempty"`
+	DurationMs int             `json:"duration_ms,omitempty"`
+}
+
+// SessionReplay returns the recorded events of the CONTEXT.session named
+// sessionID, oldest first, for stepping through a past session. Both hook
+// envelopes and cockpit tool_run observations are understood.
+func (d *Dash) SessionReplay(ctx context.Context, sessionID string) ([]ReplayEvent, error) {
+	session, err := d.GetNodeByName(ctx, LayerContext, "session", sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("session %q: %w", sessionID, err)
+	}
+	obs, err := d.ListObservationsByNode(ctx, session.ID, TimeRange{
+		Start: session.CreatedAt.Add(-time.Minute),
+		End:   time.Now().Add(time.Minute),
+	})
+	if err != nil {
+		return nil, err
+	}
+	// Newest first from the query; keep the latest replayMaxEvents.
+	if len(obs) > replayMaxEvents {
+		obs = obs[:replayMaxEvents]
+	}
+	chrono := make([]*Observation, 0, len(obs))
+	for i := len(obs) - 1; i >= 0; i-- {
+		chrono = append(chrono, obs[i])
+	}
+	return replayEvents(chrono), nil
+}
+
+// replayEvents turns observations (oldest first) into replay steps, folding
+// each tool.pre into its completion and filling in the gaps.
+func replayEvents(obs []*Observation) []ReplayEvent {
+	var events []ReplayEvent
+	pending := map[string]int{} // tool key → index of its unfinished tool.pre
+	for _, o := range obs {
+		ev, ok := replayEventFromObservation(o)
+		if !ok {
+			continue
+		}
+		key := ev.ToolUseID
+		if key == "" {
+			key = "name:" + ev.ToolName
+		}
+		switch ev.Event {
+		case "tool.pre":
+			pending[key] = len(events)
+			events = append(events, ev)
+			continue
+		case "tool.post", "tool.failure":
+			if i, ok := pending[key]; ok {
+				delete(pending, key)
+				pre := events[i]
+				ev.At = pre.At
+				if len(ev.Args) == 0 {
+					ev.Args = pre.Args
+				}
+				if ev.DurationMs == 0 {
+					ev.DurationMs = int(o.ObservedAt.Sub(pre.At).Milliseconds())
+				}
+				events[i] = ev
+				continue
+			}
+		}
+		events = append(events, ev)
+	}
+	for i := 1; i < len(events); i++ {
+		events[i].Gap = max(events[i].At.Sub(events[i-1].At), 0)
+	}
+	return events
+}
+
+// replayEventFromObservation reads a session_event or tool_event
+// observation in either the dashhook envelope or the tool_run format.
+func replayEventFromObservation(o *Observation) (ReplayEvent, bool) {
+	if o.Type != "tool_event" && o.Type != "session_event" {
+		return ReplayEvent{}, false
+	}
+	ev := ReplayEvent{At: o.ObservedAt}
+
+	var env DashHookEnvelope
+	if json.Unmarshal(o.Data, &env) == nil && env.Normalized != nil {
+		ev.Event = env.Normalized.Event
+		ev.ToolUseID = env.Normalized.CorrelationID
+		if cc := env.ClaudeCode; cc != nil {
+			ev.ToolName = cc.ToolName
+			ev.Args = cc.ToolInput
+			ev.Result = rawText(cc.ToolResponse)
+			ev.Error = cc.Error
+		}
+		if out := env.Normalized.Outcome; out != nil {
+			ev.Success = out.Success
+			if out.Error != "" {
+				ev.Error = out.Error
+			}
+			if out.DurationMs != nil {
+				ev.DurationMs = *out.DurationMs
+			}
+		}
+		return ev, ev.Event != ""
+	}
+
+	var run struct {
+		Phase      string         `json:"phase"`
+		ToolName   string         `json:"tool_name"`
+		Args       map[string]any `json:"args"`
+		Success    *bool          `json:"success"`
+		DurationMs int            `json:"duration_ms"`
+		Reason     string         `json:"reason"`
+	}
+	if json.Unmarshal(o.Data, &run) != nil || run.Phase == "" {
+		return ReplayEvent{}, false
+	}
+	ev.Event = run.Phase
+	ev.ToolName = run.ToolName
+	if run.Args != nil {
+		ev.Args, _ = json.Marshal(run.Args)
+	}
+	ev.Success = run.Success
+	ev.DurationMs = run.DurationMs
+	if run.Success != nil && !*run.Success {
+		ev.Error = run.Reason
+	}
+	return ev, true
+}
+
+// rawText returns a JSON string's value, or the raw JSON for anything else.
+func rawText(raw json.RawMessage) string {
+	if len(raw) == 0 || string(raw) == "null" {
+		return ""
+	}
+	var s string
+	if json.Unmarshal(raw, &s) == nil {
+		return s
+	}
+	return string(raw)
+}
@@ -0,0 +1,62 @@
+package dash
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestReplayEventsFoldsToolCalls(t *testing.T) {
+	t0 := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	obs := func(typ string, at time.Duration, data any) *Observation {
+		raw, _ := json.Marshal(data)
+		return &Observation{Type: typ, Data: raw, ObservedAt: t0.Add(at)}
+	}
+	hook := func(event, id string, outcome *Outcome) map[string]any {
+		return map[string]any{
+			"claude_code": map[string]any{
+				"tool_name":     "Read",
+				"tool_use_id":   id,
+				"tool_input":    map[string]any{"file_path": "/x.go"},
+				"tool_response": "package x",
+			},
+			"normalized": map[string]any{"event": event, "correlation_id": id, "outcome": outcome},
+		}
+	}
+	ms := 250
+
+	events := replayEvents([]*Observation{
+		obs("session_event", 0, map[string]any{"normalized": map[string]any{"event": "session.start"}}),
+		obs("tool_event", 2*time.Second, hook("tool.pre", "tu1", nil)),
+		obs("tool_event", 2*time.Second+250*time.Millisecond, hook("tool.post", "tu1", &Outcome{Success: boolPtr(true), DurationMs: &ms})),
+		obs("tool_event", 40*time.Second, map[string]any{"phase": "tool.pre", "tool_name": "grep", "args": map[string]any{"pattern": "foo"}}),
+		obs("tool_event", 41*time.Second, map[string]any{"phase": "tool.post", "tool_name": "grep", "success": false, "reason": "bad pattern"}),
+		obs("agent_reasoning", 42*time.Second, map[string]any{"content": "ignored"}),
+	})
+
+	if len(events) != 3 {
+		t.Fatalf("got %d events, want 3: %+v", len(events), events)
+	}
+	read := events[1]
+	if read.Event != "tool.post" || read.ToolName != "Read" || read.Result != "package x" || read.DurationMs != 250 {
+		t.Errorf("read event = %+v", read)
+	}
+	if !read.At.Equal(t0.Add(2*time.Second)) || read.Gap != 2*time.Second {
+		t.Errorf("read at=%v gap=%v, want the tool.pre time and a 2s gap", read.At, read.Gap)
+	}
+	grep := events[2]
+	if grep.ToolName != "grep" || string(grep.Args) != `{"pattern":"foo"}` || grep.Error != "bad pattern" || grep.DurationMs != 1000 {
+		t.Errorf("grep event = %+v", grep)
+	}
+	if grep.Gap != 38*time.Second {
+		t.Errorf("grep gap = %v, want 38s", grep.Gap)
+	}
+}
+
+func TestReplayEventsKeepsUnfinishedCalls(t *testing.T) {
+	raw, _ := json.Marshal(map[string]any{"phase": "tool.pre", "tool_name": "exec"})
+	events := replayEvents([]*Observation{{Type: "tool_event", Data: raw, ObservedAt: time.Now()}})
+	if len(events) != 1 || events[0].Event != "tool.pre" {
+		t.Errorf("unfinished call should stay as tool.pre, got %+v", events)
+	}
+}
@@ -0,0 +1,13 @@
+-- Migration: 022_embedding_model.sql
+-- Description: Track which embedding model produced each vector
+-- Used for: Re-embedding nodes when the active embedding model changes
+
+ALTER TABLE nodes ADD COLUMN IF NOT EXISTS embedding_model TEXT;
+
+COMMENT ON COLUMN nodes.embedding_model IS 'Embedding model identifier (e.g. openai/text-embedding-3-small) that produced embedding';
+
+-- Index för att hitta embeddings från en annan modell än den aktiva
+CREATE INDEX IF NOT EXISTS idx_nodes_embedding_model
+ON nodes (embedding_model)
+WHERE embedding IS NOT NULL
+  AND deleted_at IS NULL;
@@ -0,0 +1,13 @@
+-- Migration: 023_summary_cache.sql
+-- Description: Content-addressed cache of LLM file summaries
+-- Used for: Reusing summaries when identical content recurs (reverts, copies)
+
+CREATE TABLE IF NOT EXISTS summary_cache (
+    key         TEXT PRIMARY KEY,
+    summary     TEXT NOT NULL,
+    hit_count   INTEGER NOT NULL DEFAULT 0,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    last_hit_at TIMESTAMPTZ
+);
+
+COMMENT ON TABLE summary_cache IS 'Summaries keyed by sha256(system prompt + content)';
@@ -0,0 +1,9 @@
+-- Let the orchestrator announce to all agents via broadcast_agents.
+-- agent-continuous has an empty toolset (all tools) and needs no change.
+
+UPDATE prompt_profiles
+SET toolset = array_append(toolset, 'broadcast_agents'),
+    updated_at = NOW()
+WHERE name = 'orchestrator'
+  AND cardinality(toolset) > 0
+  AND NOT ('broadcast_agents' = ANY(toolset));
@@ -0,0 +1,4 @@
+-- Migration 025: Plan decomposition
+-- Sub-plans created by SplitPlan link to their umbrella plan.
+
+ALTER TYPE dash_relation ADD VALUE IF NOT EXISTS 'part_of';  -- sub-plan → umbrella plan
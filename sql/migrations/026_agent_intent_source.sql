@@ -0,0 +1,8 @@
+-- Add intent source to agent-continuous profile.
+-- Shows the intent behind the agent's active work order and its alignment.
+
+UPDATE prompt_profiles
+SET sources = '{agent_envelope,active_work_order,intent,recent_decisions,pending_decisions,active_agents,constraints}',
+    updated_at = NOW()
+WHERE name = 'agent-continuous'
+  AND NOT ('intent' = ANY(sources));
@@ -0,0 +1,14 @@
+-- Migration: 027_pending_tool_uses.sql
+-- Description: In-flight tool calls between PreToolUse and PostToolUse
+-- Used for: Accurate tool durations and detecting hung tool calls
+
+CREATE TABLE IF NOT EXISTS pending_tool_uses (
+    tool_use_id TEXT PRIMARY KEY,
+    session_id  TEXT NOT NULL,
+    tool_name   TEXT NOT NULL,
+    started_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_pending_tool_uses_started_at ON pending_tool_uses (started_at);
+
+COMMENT ON TABLE pending_tool_uses IS 'Written by PreToolUse, consumed by PostToolUse/PostToolUseFailure; orphans are swept after a TTL';
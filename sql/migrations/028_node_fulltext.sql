@@ -0,0 +1,30 @@
+-- Migration: 028_node_fulltext.sql
+-- Description: Full-text search over node names and the text fields of node data
+-- Used for: FullTextSearch (exact words and phrases where embeddings are weak)
+
+-- node_search_text is the document that is indexed and that snippets are cut from.
+CREATE OR REPLACE FUNCTION node_search_text(name TEXT, data JSONB)
+RETURNS TEXT
+LANGUAGE SQL IMMUTABLE PARALLEL SAFE AS $$
+    SELECT concat_ws(E'\n',
+        name,
+        data->>'title',
+        data->>'description',
+        data->>'summary',
+        data->>'text',
+        data->>'content',
+        data->>'mission',
+        data->>'decision',
+        data->>'rationale')
+$$;
+
+-- 'simple' config: no stemming, since node text mixes Swedish, English and code.
+ALTER TABLE nodes ADD COLUMN IF NOT EXISTS search_tsv tsvector
+    GENERATED ALWAYS AS (
+        setweight(to_tsvector('simple', coalesce(name, '')), 'A') ||
+        setweight(to_tsvector('simple', node_search_text('', data)), 'B')
+    ) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_nodes_search_tsv ON nodes USING GIN (search_tsv) WHERE deleted_at IS NULL;
+
+COMMENT ON COLUMN nodes.search_tsv IS 'Full-text vector: name (weight A) and data text fields (weight B)';
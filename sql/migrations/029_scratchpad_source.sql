@@ -0,0 +1,9 @@
+-- Add scratchpad source to agent-continuous profile.
+-- Shows peers' notes on the shared scratchpad of the agent's active work order.
+-- agent-continuous has an empty toolset (all tools), so the scratchpad tool needs no change.
+
+UPDATE prompt_profiles
+SET sources = array_append(sources, 'scratchpad'),
+    updated_at = NOW()
+WHERE name = 'agent-continuous'
+  AND NOT ('scratchpad' = ANY(sources));
@@ -0,0 +1,9 @@
+-- Add diff_since_last_session source to the default profile.
+-- Lists files modified since the previous session ended, right after the
+-- session source, so a resuming session sees intervening work.
+
+UPDATE prompt_profiles
+SET sources = array_append(sources, 'diff_since_last_session'),
+    updated_at = NOW()
+WHERE name = 'default'
+  AND NOT ('diff_since_last_session' = ANY(sources));
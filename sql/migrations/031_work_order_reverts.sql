@@ -0,0 +1,4 @@
+-- Migration 031: Work order rollback
+-- Revert work orders created by RevertWorkOrder link to the order they undo.
+
+ALTER TYPE dash_relation ADD VALUE IF NOT EXISTS 'reverts';  -- revert work_order → reverted work_order
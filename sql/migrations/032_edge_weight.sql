@@ -0,0 +1,9 @@
+-- Migration: 032_edge_weight.sql
+-- Description: Confidence weight on edges for probabilistic relations
+-- Used for: AutoLinkTaskToIntent (weight from match strength), GetTaskProximity and
+-- BatchGetGraphNeighbors (relation scores scaled by weight), RunGC (low-weight pruning)
+
+ALTER TABLE edges ADD COLUMN IF NOT EXISTS weight REAL NOT NULL DEFAULT 1.0;
+
+ALTER TABLE edges DROP CONSTRAINT IF EXISTS chk_edges_weight;
+ALTER TABLE edges ADD CONSTRAINT chk_edges_weight CHECK (weight >= 0 AND weight <= 1);
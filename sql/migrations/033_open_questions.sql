@@ -0,0 +1,11 @@
+-- Migration 033: Open questions
+-- CONTEXT.question nodes stay open until a decision resolves them (ResolveQuestion).
+-- The open_questions source shows them to agents before they forge ahead.
+
+ALTER TYPE dash_relation ADD VALUE IF NOT EXISTS 'resolves';  -- decision → question
+
+UPDATE prompt_profiles
+SET sources = array_append(sources, 'open_questions'),
+    updated_at = NOW()
+WHERE name = 'agent-continuous'
+  AND NOT ('open_questions' = ANY(sources));
@@ -0,0 +1,178 @@
+package dash
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// writeKeywords are statement-level keywords that modify data, schema or
+// session state. A read-only query may not contain any of them outside
+// string literals, quoted identifiers and comments.
+var writeKeywords = map[string]bool{
+	"INSERT": true, "UPDATE": true, "DELETE": true, "MERGE": true,
+	"DROP": true, "ALTER": true, "CREATE": true, "TRUNCATE": true,
+	"GRANT": true, "REVOKE": true, "COPY": true, "CALL": true,
+	"DO": true, "VACUUM": true, "REINDEX": true, "CLUSTER": true,
+	"LOCK": true, "INTO": true, "SET": true, "RESET": true,
+	"COMMENT": true, "REFRESH": true, "EXECUTE": true, "PREPARE": true,
+	"LISTEN": true, "NOTIFY": true, "DISCARD": true, "IMPORT": true,
+}
+
+// CheckReadOnlySQL returns ErrReadOnlyViolation unless query is exactly one
+// SELECT or WITH statement with no data-modifying keyword anywhere in it.
+// Literals, quoted identifiers and comments are skipped, so a value such as
+// 'DELETE me' or a column named "update" does not trip the check.
+func CheckReadOnlySQL(query string) error {
+	words, statements, err := sqlTokens(query)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrReadOnlyViolation, err)
+	}
+	if statements != 1 || len(words) == 0 {
+		return fmt.Errorf("%w: expected exactly one statement, got %d", ErrReadOnlyViolation, statements)
+	}
+	if words[0] != "SELECT" && words[0] != "WITH" {
+		return fmt.Errorf("%w: only SELECT/WITH queries allowed", ErrReadOnlyViolation)
+	}
+	for _, w := range words {
+		if writeKeywords[w] {
+			return fmt.Errorf("%w: %s not allowed", ErrReadOnlyViolation, w)
+		}
+	}
+	return nil
+}
+
+// sqlTokens returns the upper-cased bare words of query and the number of
+// non-empty statements separated by semicolons. Everything inside string
+// literals, dollar quotes, quoted identifiers and comments is skipped.
+func sqlTokens(query string) ([]string, int, error) {
+	var words []string
+	statements := 0
+	inStatement := false
+	rs := []rune(query)
+	n := len(rs)
+
+	for i := 0; i < n; {
+		r := rs[i]
+		switch {
+		case r == ';':
+			if inStatement {
+				statements++
+			}
+			inStatement = false
+			i++
+		case unicode.IsSpace(r):
+			i++
+		case r == '-' && i+1 < n && rs[i+1] == '-':
+			for i < n && rs[i] != '\n' {
+				i++
+			}
+		case r == '/' && i+1 < n && rs[i+1] == '*':
+			// Postgres block comments nest.
+			depth := 0
+			for i < n {
+				if rs[i] == '/' && i+1 < n && rs[i+1] == '*' {
+					depth++
+					i += 2
+				} else if rs[i] == '*' && i+1 < n && rs[i+1] == '/' {
+					depth--
+					i += 2
+					if depth == 0 {
+						break
+					}
+				} else {
+					i++
+				}
+			}
+			if depth != 0 {
+				return nil, 0, fmt.Errorf("unterminated comment")
+			}
+		case (r == 'E' || r == 'e') && i+1 < n && rs[i+1] == '\'':
+			// E'...' escape string: a backslash escapes the next character.
+			inStatement = true
+			end, ok := skipQuoted(rs, i+1, '\'', true)
+			if !ok {
+				return nil, 0, fmt.Errorf("unterminated quote")
+			}
+			i = end
+		case r == '\'' || r == '"':
+			inStatement = true
+			end, ok := skipQuoted(rs, i, r, false)
+			if !ok {
+				return nil, 0, fmt.Errorf("unterminated quote")
+			}
+			i = end
+		case r == '$':
+			inStatement = true
+			tag, ok := dollarTag(rs, i)
+			if !ok {
+				i++ // positional parameter such as $1
+				continue
+			}
+			end := indexRunes(rs, i+len(tag), tag)
+			if end < 0 {
+				return nil, 0, fmt.Errorf("unterminated dollar quote")
+			}
+			i = end + len(tag)
+		case unicode.IsLetter(r) || r == '_':
+			inStatement = true
+			start := i
+			for i < n && (unicode.IsLetter(rs[i]) || unicode.IsDigit(rs[i]) || rs[i] == '_' || rs[i] == '$') {
+				i++
+			}
+			words = append(words, strings.ToUpper(string(rs[start:i])))
+		default:
+			inStatement = true
+			i++
+		}
+	}
+	if inStatement {
+		statements++
+	}
+	return words, statements, nil
+}
+
+// skipQuoted returns the index just past the quote opened at rs[start].
+// A doubled quote character is an escaped quote; with backslash set, as in
+// E'...' strings, a backslash also escapes the character after it.
+func skipQuoted(rs []rune, start int, quote rune, backslash bool) (int, bool) {
+	for i := start + 1; i < len(rs); i++ {
+		if backslash && rs[i] == '\\' {
+			i++
+			continue
+		}
+		if rs[i] != quote {
+			continue
+		}
+		if i+1 < len(rs) && rs[i+1] == quote {
+			i++
+			continue
+		}
+		return i + 1, true
+	}
+	return 0, false
+}
+
+// dollarTag returns the dollar-quote opener ($$ or $tag$) at rs[start].
+func dollarTag(rs []rune, start int) ([]rune, bool) {
+	for i := start + 1; i < len(rs); i++ {
+		switch {
+		case rs[i] == '$':
+			return rs[start : i+1], true
+		case unicode.IsLetter(rs[i]) || rs[i] == '_' || (i > start+1 && unicode.IsDigit(rs[i])):
+		default:
+			return nil, false
+		}
+	}
+	return nil, false
+}
+
+// indexRunes returns the index of sub in rs at or after from, or -1.
+func indexRunes(rs []rune, from int, sub []rune) int {
+	for i := from; i+len(sub) <= len(rs); i++ {
+		if string(rs[i:i+len(sub)]) == string(sub) {
+			return i
+		}
+	}
+	return -1
+}
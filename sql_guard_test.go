@@ -0,0 +1,56 @@
+package dash
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCheckReadOnlySQL(t *testing.T) {
+	allowed := []string{
+		"SELECT 1",
+		"select name from nodes where deleted_at is null;",
+		"WITH recent AS (SELECT id FROM nodes ORDER BY created_at DESC LIMIT 5) SELECT * FROM recent",
+		"SELECT 'DELETE FROM nodes; DROP TABLE edges' AS note",
+		`SELECT "update" FROM t`,
+		"SELECT updated_at, deleted_at FROM nodes",
+		"SELECT $$drop table x$$ AS body",
+		"SELECT $tag$; INSERT$tag$",
+		"SELECT * FROM nodes WHERE id = $1",
+		"-- DELETE everything\nSELECT 1",
+		"SELECT /* outer /* nested DROP */ still comment */ 1",
+		`SELECT E'it\'s; DROP TABLE x' AS note`,
+		`SELECT e'\\' AS backslash`,
+		"SELECT name FROM t WHERE type = 'E'",
+	}
+	for _, q := range allowed {
+		if err := CheckReadOnlySQL(q); err != nil {
+			t.Errorf("CheckReadOnlySQL(%q) = %v, want nil", q, err)
+		}
+	}
+
+	blocked := []string{
+		"",
+		"   ;  ",
+		"DELETE FROM nodes",
+		"SELECT 1; DROP TABLE nodes",
+		"SELECT 1;DELETE FROM nodes;",
+		"WITH gone AS (DELETE FROM nodes RETURNING id) SELECT * FROM gone",
+		"WITH x AS (SELECT 1) UPDATE nodes SET name = 'x'",
+		"SELECT * INTO backup FROM nodes",
+		"SELECT * FROM nodes FOR UPDATE",
+		"SELECT 'unterminated",
+		"SELECT $$never closed",
+		"SELECT 1 /* open comment",
+		"select 1; -- trailing\ninsert into t values (1)",
+		"SELECT set_config('x', 'y', false); SET ROLE postgres",
+		`SELECT E'\'' ; DROP TABLE x; --' LIMIT 1`,
+		`SELECT e'\\'; DELETE FROM nodes`,
+		`SELECT E'never closed\'`,
+	}
+	for _, q := range blocked {
+		err := CheckReadOnlySQL(q)
+		if !errors.Is(err, ErrReadOnlyViolation) {
+			t.Errorf("CheckReadOnlySQL(%q) = %v, want ErrReadOnlyViolation", q, err)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package dash
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+const queryListHashedFileNodes = `
+	SELECT id, name, content_hash
+	FROM nodes
+	WHERE layer = 'SYSTEM' AND type = 'file'
+	  AND content_hash IS NOT NULL
+	  AND deleted_at IS NULL
+	ORDER BY name`
+
+// StaleFile is a SYSTEM.file node whose stored content hash no longer
+// matches the file on disk, or whose file is gone.
+type StaleFile struct {
+	NodeID     uuid.UUID `json:"node_id"`
+	Path       string    `json:"path"`
+	StoredHash string    `json:"stored_hash"`
+	DiskHash   string    `json:"disk_hash,omitempty"` // empty when Missing
+	Missing    bool      `json:"missing,omitempty"`
+}
+
+// ListStaleEmbeddings walks the hashed file nodes under root (all of them
+// when root is empty), rehashes each file on disk and returns those whose
+// hash differs from the stored one or whose file no longer exists. Files
+// that cannot be read for another reason are skipped.
+func (d *Dash) ListStaleEmbeddings(ctx context.Context, root string) ([]StaleFile, error) {
+	rows, err := d.db.QueryContext(ctx, queryListHashedFileNodes)
+	if err != nil {
+		return nil, err
+	}
+	var files []StaleFile
+	for rows.Next() {
+		var sf StaleFile
+		if err := rows.Scan(&sf.NodeID, &sf.Path, &sf.StoredHash); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		if pathUnder(sf.Path, root) {
+			files = append(files, sf)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	var stale []StaleFile
+	for _, sf := range files {
+		if err := ctx.Err(); err != nil {
+			return stale, err
+		}
+		disk, err := computeFileHash(sf.Path)
+		switch {
+		case errors.Is(err, fs.ErrNotExist):
+			sf.Missing = true
+		case err != nil:
+			continue
+		case disk == sf.StoredHash:
+			continue
+		default:
+			sf.DiskHash = disk
+		}
+		stale = append(stale, sf)
+	}
+	return stale, nil
+}
+
+// pathUnder reports whether path is root or inside it. An empty root
+// matches every path.
+func pathUnder(path, root string) bool {
+	if root == "" {
+		return true
+	}
+	path, root = filepath.Clean(path), filepath.Clean(root)
+	return path == root || strings.HasPrefix(path, strings.TrimSuffix(root, string(filepath.Separator))+string(filepath.Separator))
+}
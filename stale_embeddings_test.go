@@ -0,0 +1,24 @@
+package dash
+
+import "testing"
+
+func TestPathUnder(t *testing.T) {
+	tests := []struct {
+		path, root string
+		want       bool
+	}{
+		{"/repo/a.go", "", true},
+		{"/repo/a.go", "/repo", true},
+		{"/repo/a.go", "/repo/", true},
+		{"/repo", "/repo", true},
+		{"/repo/sub/b.go", "/repo", true},
+		{"/repository/a.go", "/repo", false},
+		{"/other/a.go", "/repo", false},
+		{"/a.go", "/", true},
+	}
+	for _, tt := range tests {
+		if got := pathUnder(tt.path, tt.root); got != tt.want {
+			t.Errorf("pathUnder(%q, %q) = %v, want %v", tt.path, tt.root, got, tt.want)
+		}
+	}
+}
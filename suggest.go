@@ -52,13 +52,7 @@ func (d *Dash) GenerateProposals(ctx context.Context) ([]Proposal, error) {
 			p.Alignment = 0
 			p.Intent = ""
 		} else {
-			// Normalize score to percentage (max observed score ~15-20)
-			maxScore := 20
-			pct := (matches[0].Score * 100) / maxScore
-			if pct > 100 {
-				pct = 100
-			}
-			p.Alignment = pct
+			p.Alignment = alignmentPct(matches[0].Score)
 			p.Intent = matches[0].IntentName
 		}
 
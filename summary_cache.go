@@ -0,0 +1,97 @@
+package dash
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+)
+
+// SummaryCache stores summaries by content-addressed key.
+type SummaryCache interface {
+	Get(ctx context.Context, key string) (string, bool)
+	Put(ctx context.Context, key, summary string) error
+}
+
+type summaryCacheBypassKey struct{}
+
+// WithSummaryCacheBypass makes Summarize calls on ctx skip the summary cache
+// and always ask the underlying model. The fresh result is still stored.
+func WithSummaryCacheBypass(ctx context.Context) context.Context {
+	return context.WithValue(ctx, summaryCacheBypassKey{}, true)
+}
+
+func summaryCacheBypassed(ctx context.Context) bool {
+	v, _ := ctx.Value(summaryCacheBypassKey{}).(bool)
+	return v
+}
+
+// summaryCacheKey hashes the system prompt and content a summary depends on.
+// The file path is left out so copies of a file share one summary.
+func summaryCacheKey(systemPrompt, content string) string {
+	h := sha256.New()
+	h.Write([]byte(systemPrompt))
+	h.Write([]byte{0})
+	h.Write([]byte(content))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// CachingSummarizer wraps a SummaryClient and reuses summaries for content it
+// has summarized before. Complete is not cached.
+type CachingSummarizer struct {
+	next  SummaryClient
+	cache SummaryCache
+}
+
+// NewCachingSummarizer returns next wrapped with cache.
+func NewCachingSummarizer(next SummaryClient, cache SummaryCache) *CachingSummarizer {
+	return &CachingSummarizer{next: next, cache: cache}
+}
+
+// Summarize returns a cached summary on hit, otherwise summarizes and caches.
+func (c *CachingSummarizer) Summarize(ctx context.Context, content, filePath string) (string, error) {
+	if len(content) > MaxEmbeddingTextSize {
+		content = content[:MaxEmbeddingTextSize]
+	}
+	key := summaryCacheKey(defaultSummaryPrompt, content)
+	if !summaryCacheBypassed(ctx) {
+		if summary, ok := c.cache.Get(ctx, key); ok {
+			return summary, nil
+		}
+	}
+
+	summary, err := c.next.Summarize(ctx, content, filePath)
+	if err != nil || summary == "" {
+		return summary, err
+	}
+	_ = c.cache.Put(ctx, key, summary) // best-effort
+	return summary, nil
+}
+
+// Complete passes through to the wrapped client.
+func (c *CachingSummarizer) Complete(ctx context.Context, systemPrompt, userMsg string) (string, error) {
+	return c.next.Complete(ctx, systemPrompt, userMsg)
+}
+
+// dbSummaryCache persists summaries in the summary_cache table.
+type dbSummaryCache struct {
+	db *sql.DB
+}
+
+func (c *dbSummaryCache) Get(ctx context.Context, key string) (string, bool) {
+	var summary string
+	err := c.db.QueryRowContext(ctx, `
+		UPDATE summary_cache
+		SET hit_count = hit_count + 1, last_hit_at = NOW()
+		WHERE key = $1
+		RETURNING summary`, key).Scan(&summary)
+	return summary, err == nil
+}
+
+func (c *dbSummaryCache) Put(ctx context.Context, key, summary string) error {
+	_, err := c.db.ExecContext(ctx, `
+		INSERT INTO summary_cache (key, summary)
+		VALUES ($1, $2)
+		ON CONFLICT (key) DO UPDATE SET summary = EXCLUDED.summary`, key, summary)
+	return err
+}
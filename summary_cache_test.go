@@ -0,0 +1,57 @@
+package dash
+
+import (
+	"context"
+	"testing"
+)
+
+type countingSummarizer struct{ calls int }
+
+func (c *countingSummarizer) Summarize(ctx context.Context, content, filePath string) (string, error) {
+	c.calls++
+	return "summary of " + filePath, nil
+}
+
+func (c *countingSummarizer) Complete(ctx context.Context, systemPrompt, userMsg string) (string, error) {
+	return "", nil
+}
+
+type memSummaryCache map[string]string
+
+func (m memSummaryCache) Get(ctx context.Context, key string) (string, bool) {
+	s, ok := m[key]
+	return s, ok
+}
+
+func (m memSummaryCache) Put(ctx context.Context, key, summary string) error {
+	m[key] = summary
+	return nil
+}
+
+func TestCachingSummarizerHitsModelOnce(t *testing.T) {
+	next := &countingSummarizer{}
+	s := NewCachingSummarizer(next, memSummaryCache{})
+	ctx := context.Background()
+
+	first, err := s.Summarize(ctx, "package x", "/a/x.go")
+	if err != nil {
+		t.Fatalf("first: %v", err)
+	}
+	second, err := s.Summarize(ctx, "package x", "/b/copy.go")
+	if err != nil {
+		t.Fatalf("second: %v", err)
+	}
+	if next.calls != 1 {
+		t.Errorf("model called %d times, want 1", next.calls)
+	}
+	if second != first {
+		t.Errorf("cached summary = %q, want %q", second, first)
+	}
+
+	if _, err := s.Summarize(ctx, "package y", "/a/y.go"); err != nil || next.calls != 2 {
+		t.Errorf("different content: calls=%d err=%v, want 2 calls", next.calls, err)
+	}
+	if _, err := s.Summarize(WithSummaryCacheBypass(ctx), "package x", "/a/x.go"); err != nil || next.calls != 3 {
+		t.Errorf("bypass: calls=%d err=%v, want 3 calls", next.calls, err)
+	}
+}
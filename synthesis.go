@@ -126,7 +126,9 @@ func (d *Dash) RunSynthesis(ctx context.Context, woID uuid.UUID, git GitClient)
 		return nil, fmt.Errorf("LLM router not configured")
 	}
 
-	response, err := d.router.CompleteWithRole(ctx, "synthesizer", synthesisSystemPrompt, userPrompt.String())
+	tmpl := d.GetPromptTemplate(ctx, PromptSynthesis)
+	ctx = withPromptTemplate(ctx, tmpl)
+	response, err := d.router.CompleteWithRole(ctx, "synthesizer", tmpl.Text, userPrompt.String())
 	if err != nil {
 		return nil, fmt.Errorf("synthesizer call: %w", err)
 	}
@@ -235,6 +237,9 @@ func (d *Dash) RunSynthesisPipeline(ctx context.Context, woID uuid.UUID, git Git
 
 			// Rebuild after patch (reuse the same worktree WITH patch applied)
 			gateResult, err := RunBuildGate(git, wo, wtPath)
+			if err == nil {
+				d.RecordBuildGateResult(ctx, woID, gateResult)
+			}
 			if err != nil || !gateResult.Passed {
 				reason := "rebuild after patch failed"
 				if err != nil {
@@ -313,17 +318,7 @@ func (d *Dash) RunSynthesisPipeline(ctx context.Context, woID uuid.UUID, git Git
 
 // parseSynthesisResponse parses the JSON response from the synthesizer model.
 func parseSynthesisResponse(response string) (*SynthesisResult, error) {
-	// Strip markdown code fences if present
-	response = strings.TrimSpace(response)
-	if strings.HasPrefix(response, "```") {
-		if idx := strings.Index(response[3:], "\n"); idx >= 0 {
-			response = response[3+idx+1:]
-		}
-		if strings.HasSuffix(response, "```") {
-			response = response[:len(response)-3]
-		}
-		response = strings.TrimSpace(response)
-	}
+	response = extractJSON(response)
 
 	var result SynthesisResult
 	if err := json.Unmarshal([]byte(response), &result); err != nil {
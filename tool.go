@@ -37,4 +37,5 @@ type Challenge struct {
 	ID       string   `json:"id"`
 	Question string   `json:"question"`
 	Options  []string `json:"options,omitempty"`
+	Data     any      `json:"data,omitempty"` // structured context for the caller (e.g. candidate IDs)
 }
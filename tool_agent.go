@@ -22,6 +22,7 @@ type AgentSession struct {
 	SpawnedBy       string    `json:"spawned_by"`
 	SpawnedAt       time.Time `json:"spawned_at"`
 	SessionID       string    `json:"session_id,omitempty"`
+	TokenBudget     int       `json:"token_budget,omitempty"`
 	Controller      string    `json:"controller"`         // "human", "llm", "idle"
 	ControllerSince time.Time `json:"controller_since"`
 }
@@ -51,6 +52,14 @@ func defSpawnAgent() *ToolDef {
 					"items":       map[string]any{"type": "string"},
 					"description": "Valfria söktermer eller filer agenten bör känna till vid start.",
 				},
+				"spawned_by": map[string]any{
+					"type":        "string",
+					"description": "Session ID eller agent_key för agenten som spawnar (valfritt, annars anroparens agent).",
+				},
+				"token_budget": map[string]any{
+					"type":        "integer",
+					"description": "Max antal tokens agenten får förbruka innan den pausas (valfritt, annars agentdefinitionens budget).",
+				},
 			},
 			"required": []string{"agent_key", "mission"},
 		},
@@ -64,6 +73,13 @@ func handleSpawnAgent(ctx context.Context, d *Dash, args map[string]any) (any, e
 	mission, _ := args["mission"].(string)
 	name, _ := args["name"].(string)
 	hintsRaw, _ := args["context_hints"].([]any)
+	spawnedBy, _ := args["spawned_by"].(string)
+	tokenBudget := intVal(args, "token_budget")
+	if spawnedBy == "" {
+		if caller := LLMAgentFromContext(ctx); caller != "default" {
+			spawnedBy = caller
+		}
+	}
 
 	if name == "" {
 		name = agentKey
@@ -91,12 +107,33 @@ func handleSpawnAgent(ctx context.Context, d *Dash, args map[string]any) (any, e
 		"controller":       "idle",
 		"controller_since": now.Format(time.RFC3339),
 	}
+	if tokenBudget > 0 {
+		nodeData["token_budget"] = tokenBudget
+	}
+
+	// Resolve the spawner to its session so lineage can follow the chain.
+	var parent *Node
+	if spawnedBy != "" {
+		if p, err := d.resolveSpawner(ctx, spawnedBy); err == nil {
+			parent = p
+			spawnedBy = p.Name
+		}
+		nodeData["spawned_by"] = spawnedBy
+	}
 
 	node, err := d.GetOrCreateNode(ctx, LayerContext, "agent_session", sessionID, nodeData)
 	if err != nil {
 		return nil, fmt.Errorf("create agent session: %w", err)
 	}
 
+	if parent != nil && parent.ID != node.ID {
+		_ = d.CreateEdge(ctx, &Edge{
+			SourceID: node.ID,
+			TargetID: parent.ID,
+			Relation: RelationGeneratedBy,
+		})
+	}
+
 	// If there are context hints, do semantic search and link relevant files
 	if len(hints) > 0 {
 		for _, hint := range hints {
@@ -115,13 +152,15 @@ func handleSpawnAgent(ctx context.Context, d *Dash, args map[string]any) (any, e
 	}
 
 	session := AgentSession{
-		ID:        node.ID.String(),
-		AgentKey:  agentKey,
-		Name:      name,
-		Mission:   mission,
-		Status:    "spawned",
-		SpawnedAt: time.Now(),
-		SessionID: sessionID,
+		ID:          node.ID.String(),
+		AgentKey:    agentKey,
+		Name:        name,
+		Mission:     mission,
+		Status:      "spawned",
+		SpawnedBy:   spawnedBy,
+		SpawnedAt:   time.Now(),
+		SessionID:   sessionID,
+		TokenBudget: tokenBudget,
 	}
 
 	return session, nil
@@ -195,34 +234,7 @@ func handleAgentStatus(ctx context.Context, d *Dash, args map[string]any) (any,
 			data = make(map[string]any)
 		}
 
-		agent := AgentSession{
-			ID:        id.String(),
-			Name:      name,
-			SpawnedAt: createdAt,
-		}
-
-		if v, ok := data["agent_key"].(string); ok {
-			agent.AgentKey = v
-		}
-		if v, ok := data["mission"].(string); ok {
-			agent.Mission = v
-		}
-		if v, ok := data["status"].(string); ok {
-			agent.Status = v
-		}
-		if v, ok := data["spawned_by"].(string); ok {
-			agent.SpawnedBy = v
-		}
-		if v, ok := data["controller"].(string); ok {
-			agent.Controller = v
-		}
-		if v, ok := data["controller_since"].(string); ok {
-			if t, err := time.Parse(time.RFC3339, v); err == nil {
-				agent.ControllerSince = t
-			}
-		}
-
-		agents = append(agents, agent)
+		agents = append(agents, agentSessionFromData(id, name, data, createdAt))
 	}
 
 	return map[string]any{
@@ -256,6 +268,10 @@ func defUpdateAgentStatus() *ToolDef {
 					"type":        "string",
 					"description": "Om status är 'blocked', beskriv vad som blockerar.",
 				},
+				"token_budget": map[string]any{
+					"type":        "integer",
+					"description": "Ny total token-budget. Höj för att återuppta en agent som pausats av budgetstopp.",
+				},
 			},
 			"required": []string{"agent_session_id", "status"},
 		},
@@ -359,6 +375,101 @@ func handleAnswerQuery(ctx context.Context, d *Dash, args map[string]any) (any,
 	}, nil
 }
 
+// defBroadcastAgents creates the broadcast_agents tool for announcements to all agents.
+func defBroadcastAgents() *ToolDef {
+	return &ToolDef{
+		Name:        "broadcast_agents",
+		Description: "Skicka ett meddelande till alla andra agenter (t.ex. 'base branch flyttad till main, rebasea ditt arbete'). Alla aktiva agenter ser det vid nästa tur; du får det inte själv.",
+		InputSchema: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"message": map[string]any{
+					"type":        "string",
+					"description": "Meddelandet att sända till alla agenter.",
+				},
+			},
+			"required": []string{"message"},
+		},
+		Fn:   handleBroadcastAgents,
+		Tags: []string{"write", "graph"},
+	}
+}
+
+func handleBroadcastAgents(ctx context.Context, d *Dash, args map[string]any) (any, error) {
+	message, _ := args["message"].(string)
+	if message == "" {
+		return nil, fmt.Errorf("message is required")
+	}
+	b, err := d.BroadcastToAgents(ctx, LLMAgentFromContext(ctx), message)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]any{
+		"broadcast_id": b.ID,
+		"from":         b.From,
+		"message":      b.Message,
+		"status":       "broadcast",
+	}, nil
+}
+
+// defScratchpad creates the scratchpad tool, a shared note list per work order or task.
+func defScratchpad() *ToolDef {
+	return &ToolDef{
+		Name:        "scratchpad",
+		Description: "Delat anteckningsblock per work order eller task som alla agenter kan läsa och skriva i. Actions: read (alla anteckningar), append (lägg till en anteckning). Utan key används din aktiva work order. Äldsta anteckningarna tas bort när blocket är fullt.",
+		InputSchema: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"action": map[string]any{
+					"type": "string",
+					"enum": []string{"read", "append"},
+				},
+				"key": map[string]any{
+					"type":        "string",
+					"description": "Work order- eller task-namn (valfritt, default: din aktiva work order).",
+				},
+				"note": map[string]any{
+					"type":        "string",
+					"description": "Anteckningen att lägga till (för append).",
+				},
+			},
+			"required": []string{"action"},
+		},
+		Fn:   handleScratchpad,
+		Tags: []string{"write", "graph"},
+	}
+}
+
+func handleScratchpad(ctx context.Context, d *Dash, args map[string]any) (any, error) {
+	action, _ := args["action"].(string)
+	key, _ := args["key"].(string)
+	agentKey := LLMAgentFromContext(ctx)
+	if key == "" {
+		wo, err := d.GetActiveWorkOrderForAgent(ctx, agentKey)
+		if err != nil || wo == nil {
+			return nil, fmt.Errorf("key is required when you have no active work order")
+		}
+		key = wo.Node.Name
+	}
+
+	switch action {
+	case "read":
+		notes, err := d.ReadScratchpad(ctx, key)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]any{"key": key, "count": len(notes), "notes": notes}, nil
+	case "append":
+		note, _ := args["note"].(string)
+		if err := d.AppendScratchpad(ctx, key, agentKey, note); err != nil {
+			return nil, err
+		}
+		return map[string]any{"key": key, "status": "appended"}, nil
+	default:
+		return nil, fmt.Errorf("unknown action: %s (valid: read, append)", action)
+	}
+}
+
 func handleUpdateAgent(ctx context.Context, d *Dash, args map[string]any) (any, error) {
 	sessionID, _ := args["agent_session_id"].(string)
 	status, _ := args["status"].(string)
@@ -366,6 +477,7 @@ func handleUpdateAgent(ctx context.Context, d *Dash, args map[string]any) (any,
 	blocker, _ := args["blocker"].(string)
 	controller, hasController := args["controller"].(string)
 	controllerSince, _ := args["controller_since"].(string)
+	tokenBudget := intVal(args, "token_budget")
 
 	// Find the agent session node
 	node, err := d.GetNodeByName(ctx, LayerContext, "agent_session", sessionID)
@@ -384,6 +496,9 @@ func handleUpdateAgent(ctx context.Context, d *Dash, args map[string]any) (any,
 	if blocker != "" {
 		updates["blocker"] = blocker
 	}
+	if tokenBudget > 0 {
+		updates["token_budget"] = tokenBudget
+	}
 	if hasController {
 		updates["controller"] = controller
 		if controllerSince != "" {
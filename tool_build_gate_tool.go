@@ -50,6 +50,7 @@ func handleBuildGateTool(ctx context.Context, d *Dash, args map[string]any) (any
 	if err != nil {
 		return nil, fmt.Errorf("build gate error: %w", err)
 	}
+	d.RecordBuildGateResult(ctx, woID, result)
 
 	// Advance status based on result
 	if result.Passed {
@@ -28,6 +28,25 @@ func defContextPack() *ToolDef {
 					"type":        "string",
 					"description": "Optional task name for graph proximity boosting",
 				},
+				"exclude": map[string]any{
+					"type":        "array",
+					"items":       map[string]any{"type": "string"},
+					"description": "Glob patterns matched against path/name to drop, e.g. ['*_test.go', 'sql/migrations/*']",
+				},
+				"exclude_types": map[string]any{
+					"type":        "array",
+					"items":       map[string]any{"type": "string"},
+					"description": "LAYER.type pairs to drop, e.g. ['CONTEXT.session']",
+				},
+				"max_constraints": map[string]any{
+					"type":        "integer",
+					"description": "Max constraints to include, most relevant to the query first (default: 5)",
+				},
+				"format": map[string]any{
+					"type":        "string",
+					"description": "Output format: 'json' (structured, default) or 'markdown' (table for display)",
+					"enum":        []string{"json", "markdown"},
+				},
 			},
 		},
 		Tags: []string{"read"},
@@ -66,10 +85,23 @@ func toolContextPack(ctx context.Context, d *Dash, args map[string]any) (any, er
 		}
 	}
 
-	pack, err := d.AssembleContextPack(ctx, query, profile, taskID)
+	opts := PackOptions{
+		Exclude: PackExclude{
+			Patterns: stringSlice(args, "exclude"),
+			Types:    stringSlice(args, "exclude_types"),
+		},
+	}
+	if n, ok := args["max_constraints"].(float64); ok {
+		opts.MaxConstraints = int(n)
+	}
+
+	pack, err := d.AssembleContextPackWithOptions(ctx, query, profile, taskID, opts)
 	if err != nil {
 		return nil, err
 	}
 
+	if format, _ := args["format"].(string); format == "markdown" {
+		return pack.RenderMarkdown(), nil
+	}
 	return pack.ToMap(), nil
 }
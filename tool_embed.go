@@ -10,12 +10,12 @@ import (
 func defEmbed() *ToolDef {
 	return &ToolDef{
 		Name:        "embed",
-		Description: "Manage file embeddings for semantic search. Operations: status (show stats), backfill (generate missing).",
+		Description: "Manage file embeddings for semantic search. Operations: status (show stats), backfill (generate missing), reembed_stale (re-embed vectors from another model).",
 		InputSchema: map[string]any{
 			"type":     "object",
 			"required": []string{"op"},
 			"properties": map[string]any{
-				"op":    map[string]any{"type": "string", "enum": []string{"status", "backfill"}, "description": "Operation: status or backfill"},
+				"op":    map[string]any{"type": "string", "enum": []string{"status", "backfill", "reembed_stale"}, "description": "Operation: status, backfill or reembed_stale"},
 				"limit": map[string]any{"type": "integer", "description": "Max files to process for backfill (default: 10)"},
 			},
 		},
@@ -62,6 +62,14 @@ func toolEmbed(ctx context.Context, d *Dash, args map[string]any) (any, error) {
 			return nil, err
 		}
 
+		// Embeddings produced by a model other than the active one
+		var staleModel int
+		if model := d.EmbeddingModel(); model != "" {
+			if err := d.db.QueryRowContext(ctx, queryCountStaleEmbeddings, model).Scan(&staleModel); err != nil {
+				return nil, err
+			}
+		}
+
 		return map[string]any{
 			"files": map[string]any{
 				"total":           fileTotal,
@@ -77,8 +85,13 @@ func toolEmbed(ctx context.Context, d *Dash, args map[string]any) (any, error) {
 			"total_with_embedding": fileEmbed + ctxEmbed,
 			"total_needs":          fileNeeds + ctxNeeds,
 			"embedder_ready":       d.HasRealEmbedder(),
+			"embedding_model":      d.EmbeddingModel(),
+			"stale_model":          staleModel,
 		}, nil
 
+	case "reembed_stale":
+		return d.ReembedStale(ctx, "")
+
 	case "backfill":
 		if !d.HasRealEmbedder() {
 			return nil, fmt.Errorf("embedder not configured (no LLM provider with embedding support)")
@@ -35,10 +35,22 @@ func defForget() *ToolDef {
 			if ids, ok := args["confirm_ids"].([]any); ok && len(ids) > 0 {
 				return nil
 			}
+			// Include the matching IDs so the caller can re-call with confirm_ids
+			query, _ := args["query"].(string)
+			noteType, _ := args["type"].(string)
+			matches := findForgetMatches(ctx, d, query, noteType, nil)
+			ids := make([]string, len(matches))
+			for i, m := range matches {
+				ids[i] = m.ID
+			}
 			return &Challenge{
 				ID:       "forget-confirm",
-				Question: "This will soft-delete matching remembered nodes. Set confirm=true to proceed.",
-				Options:  []string{"confirm=true", "cancel"},
+				Question: fmt.Sprintf("This will soft-delete %d matching remembered node(s). Re-call with confirm_ids to proceed.", len(matches)),
+				Options:  []string{"confirm_ids", "cancel"},
+				Data: map[string]any{
+					"confirm_ids": ids,
+					"matches":     matches,
+				},
 			}
 		},
 	}
@@ -55,6 +67,63 @@ func toolForget(ctx context.Context, d *Dash, args map[string]any) (any, error)
 		return nil, fmt.Errorf("type must be 'insight', 'decision', or 'todo'")
 	}
 
+	matches := findForgetMatches(ctx, d, query, noteType, confirmIDs)
+
+	if len(matches) == 0 {
+		return map[string]any{
+			"found":     0,
+			"deleted":   0,
+			"matches":   []ForgetMatch{},
+			"dry_run":   dryRun,
+			"message":   "No matching nodes found",
+		}, nil
+	}
+
+	// If dry run, just return what would be deleted
+	if dryRun {
+		return map[string]any{
+			"found":     len(matches),
+			"deleted":   0,
+			"matches":   matches,
+			"dry_run":   true,
+			"message":   fmt.Sprintf("Would delete %d node(s). Set dry_run=false to confirm.", len(matches)),
+		}, nil
+	}
+
+	// Perform soft-deletes
+	deleted := 0
+	failed := 0
+	var deletedIDs []string
+	for _, match := range matches {
+		id, err := uuid.Parse(match.ID)
+		if err != nil {
+			failed++
+			continue
+		}
+		if err := d.SoftDeleteNode(ctx, id); err != nil {
+			failed++
+			continue
+		}
+		deleted++
+		deletedIDs = append(deletedIDs, match.ID)
+	}
+
+	result := map[string]any{
+		"found":       len(matches),
+		"deleted":     deleted,
+		"deleted_ids": deletedIDs,
+		"failed":      failed,
+		"matches":     matches,
+		"dry_run":     false,
+		"message":     fmt.Sprintf("Soft-deleted %d of %d node(s)", deleted, len(matches)),
+	}
+
+	return result, nil
+}
+
+// findForgetMatches returns the rememberable nodes selected by confirmIDs, or
+// by a case-insensitive name/text search when no IDs are given.
+func findForgetMatches(ctx context.Context, d *Dash, query, noteType string, confirmIDs []any) []ForgetMatch {
 	var matches []ForgetMatch
 
 	// If specific IDs provided, fetch those nodes
@@ -118,53 +187,7 @@ func toolForget(ctx context.Context, d *Dash, args map[string]any) (any, error)
 		}
 	}
 
-	if len(matches) == 0 {
-		return map[string]any{
-			"found":     0,
-			"deleted":   0,
-			"matches":   []ForgetMatch{},
-			"dry_run":   dryRun,
-			"message":   "No matching nodes found",
-		}, nil
-	}
-
-	// If dry run, just return what would be deleted
-	if dryRun {
-		return map[string]any{
-			"found":     len(matches),
-			"deleted":   0,
-			"matches":   matches,
-			"dry_run":   true,
-			"message":   fmt.Sprintf("Would delete %d node(s). Set dry_run=false to confirm.", len(matches)),
-		}, nil
-	}
-
-	// Perform soft-deletes
-	deleted := 0
-	failed := 0
-	for _, match := range matches {
-		id, err := uuid.Parse(match.ID)
-		if err != nil {
-			failed++
-			continue
-		}
-		if err := d.SoftDeleteNode(ctx, id); err != nil {
-			failed++
-			continue
-		}
-		deleted++
-	}
-
-	result := map[string]any{
-		"found":     len(matches),
-		"deleted":   deleted,
-		"failed":    failed,
-		"matches":   matches,
-		"dry_run":   false,
-		"message":   fmt.Sprintf("Soft-deleted %d of %d node(s)", deleted, len(matches)),
-	}
-
-	return result, nil
+	return matches
 }
 
 // ForgetMatch represents a node that matches forget criteria
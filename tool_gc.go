@@ -5,13 +5,15 @@ import "context"
 func defGC() *ToolDef {
 	return &ToolDef{
 		Name:        "gc",
-		Description: "Run garbage collection on old sessions. Only soft-deletes sessions past retention.",
+		Description: "Run garbage collection on old sessions. Only soft-deletes sessions past retention, and optionally deprecates low-weight edges and soft-deletes orphaned file nodes. Run with dry_run first to see what would go.",
 		InputSchema: map[string]any{
 			"type": "object",
 			"properties": map[string]any{
 				"session_retention_days":    map[string]any{"type": "integer", "description": "Days to keep non-compressed sessions (default: 14)"},
 				"compressed_retention_days": map[string]any{"type": "integer", "description": "Days to keep compressed sessions (default: 30)"},
 				"dry_run":                   map[string]any{"type": "boolean", "description": "If true, report without deleting (default: false)"},
+				"min_edge_weight":           map[string]any{"type": "number", "description": "Deprecate active edges with weight below this (0-1, default: no pruning)"},
+				"orphan_file_days":          map[string]any{"type": "integer", "description": "Soft-delete SYSTEM.file nodes with no embedding and no events, not updated in this many days (default: off)"},
 			},
 		},
 		Tags: []string{"admin"},
@@ -30,5 +32,11 @@ func toolGC(ctx context.Context, d *Dash, args map[string]any) (any, error) {
 	if dr, ok := args["dry_run"].(bool); ok {
 		policy.DryRun = dr
 	}
+	if w, ok := args["min_edge_weight"].(float64); ok {
+		policy.MinEdgeWeight = w
+	}
+	if od, ok := args["orphan_file_days"].(float64); ok {
+		policy.OrphanFileDays = int(od)
+	}
 	return d.RunGC(ctx, policy)
 }
@@ -0,0 +1,111 @@
+package dash
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+func defRequestPeerReview() *ToolDef {
+	return &ToolDef{
+		Name:        "request_peer_review",
+		Description: "Be en annan agent granska en work order i synthesis_pending. Granskningen, med diffen, dispatchar till reviewer-agenten som svarar med submit_peer_review. Fire-and-forget.",
+		InputSchema: map[string]any{
+			"type":     "object",
+			"required": []string{"work_order_id", "reviewer"},
+			"properties": map[string]any{
+				"work_order_id": map[string]any{
+					"type":        "string",
+					"description": "UUID för work ordern som ska granskas",
+				},
+				"reviewer": map[string]any{
+					"type":        "string",
+					"description": "Agent-nyckel för granskaren (får inte vara work orderns egen agent)",
+				},
+			},
+		},
+		Fn:   handleRequestPeerReview,
+		Tags: []string{"write", "graph"},
+	}
+}
+
+func handleRequestPeerReview(ctx context.Context, d *Dash, args map[string]any) (any, error) {
+	id, err := uuid.Parse(fmt.Sprint(args["work_order_id"]))
+	if err != nil {
+		return nil, fmt.Errorf("work_order_id must be a UUID")
+	}
+	reviewer, _ := args["reviewer"].(string)
+	review, err := d.RequestPeerReview(ctx, id, reviewer)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]any{
+		"review_id":     review.ReviewID,
+		"work_order_id": review.WorkOrderID.String(),
+		"work_order":    review.WorkOrder,
+		"reviewer":      review.Reviewer,
+		"prompt":        review.Prompt,
+		"status":        "dispatched",
+	}, nil
+}
+
+func defSubmitPeerReview() *ToolDef {
+	return &ToolDef{
+		Name:        "submit_peer_review",
+		Description: "Lämna ditt granskningsbeslut för en work order du blivit ombedd att granska. approve=true flyttar den till merge_pending, approve=false avvisar den (kommentar krävs).",
+		InputSchema: map[string]any{
+			"type":     "object",
+			"required": []string{"work_order_id", "approve"},
+			"properties": map[string]any{
+				"work_order_id": map[string]any{
+					"type":        "string",
+					"description": "UUID för work ordern",
+				},
+				"approve": map[string]any{
+					"type":        "boolean",
+					"description": "true = godkänn, false = avvisa",
+				},
+				"comments": map[string]any{
+					"type":        "string",
+					"description": "Granskningskommentarer; krävs vid avvisning",
+				},
+			},
+		},
+		Fn:   handleSubmitPeerReview,
+		Tags: []string{"write", "graph"},
+	}
+}
+
+func handleSubmitPeerReview(ctx context.Context, d *Dash, args map[string]any) (any, error) {
+	id, err := uuid.Parse(fmt.Sprint(args["work_order_id"]))
+	if err != nil {
+		return nil, fmt.Errorf("work_order_id must be a UUID")
+	}
+	approve, ok := args["approve"].(bool)
+	if !ok {
+		return nil, fmt.Errorf("approve is required")
+	}
+	comments, _ := args["comments"].(string)
+
+	// Only the assigned reviewer may submit when the caller is known.
+	if caller := LLMAgentFromContext(ctx); caller != "default" {
+		wo, err := d.GetWorkOrder(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if wo.Reviewer != "" && wo.Reviewer != caller {
+			return nil, fmt.Errorf("work order %s is assigned for review to %s, not %s", wo.Node.Name, wo.Reviewer, caller)
+		}
+	}
+
+	wo, err := d.SubmitPeerReview(ctx, id, approve, comments)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]any{
+		"work_order_id": id.String(),
+		"verdict":       wo.ReviewVerdict,
+		"status":        string(wo.Status),
+	}, nil
+}
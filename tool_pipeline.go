@@ -10,7 +10,7 @@ import (
 func defPipeline() *ToolDef {
 	return &ToolDef{
 		Name:        "pipeline",
-		Description: "Kör pipeline-steg för en work order. Steps: full (build gate + synthesis + merge), synthesis (bara synthesis), merge (förbereda merge).",
+		Description: "Kör pipeline-steg för en work order. Steps: full (build gate + synthesis + merge), synthesis (bara synthesis), score (deterministisk 0–1-poäng för synthesis_pending → merge_pending/rejected), merge (förbereda merge).",
 		InputSchema: map[string]any{
 			"type":     "object",
 			"required": []string{"work_order_id", "step"},
@@ -21,7 +21,7 @@ func defPipeline() *ToolDef {
 				},
 				"step": map[string]any{
 					"type":        "string",
-					"enum":        []string{"full", "synthesis", "prepare_branch"},
+					"enum":        []string{"full", "synthesis", "score", "prepare_branch"},
 					"description": "Pipeline-steg att köra.",
 				},
 			},
@@ -89,6 +89,18 @@ func handlePipeline(ctx context.Context, d *Dash, args map[string]any) (any, err
 			"reasoning": result.Reasoning,
 		}, nil
 
+	case "score":
+		score, report, err := d.SynthesizeWorkOrder(ctx, woID)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]any{
+			"score":     score,
+			"threshold": synthesisPassThreshold,
+			"passed":    score >= synthesisPassThreshold,
+			"report":    report,
+		}, nil
+
 	case "prepare_branch":
 		wo, err := d.GetWorkOrder(ctx, woID)
 		if err != nil {
@@ -104,6 +116,6 @@ func handlePipeline(ctx context.Context, d *Dash, args map[string]any) (any, err
 		}, nil
 
 	default:
-		return nil, fmt.Errorf("unknown step: %s (use: full, synthesis, prepare_branch)", step)
+		return nil, fmt.Errorf("unknown step: %s (use: full, synthesis, score, prepare_branch)", step)
 	}
 }
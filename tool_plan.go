@@ -12,15 +12,19 @@ import (
 func defPlan() *ToolDef {
 	return &ToolDef{
 		Name:        "plan",
-		Description: "Manage implementation plans. Plans progress through stages: outline → plan → prereqs → review → approved. Operations: create, advance, update, get, list.",
+		Description: "Manage implementation plans. Plans progress through stages: outline → plan → prereqs → review → approved. Operations: create, advance, update, get, list, diff, split (break a plan over 500 estimated lines into one sub-plan per milestone), verify (check each acceptance criterion's test_ref against the tests and build gate of the work orders implementing the plan), templates (list reusable plan skeletons), from_template (create a plan at the plan stage from a template, filling its {{var}} placeholders from vars).",
 		InputSchema: map[string]any{
 			"type":     "object",
 			"required": []string{"op"},
 			"properties": map[string]any{
-				"op":   map[string]any{"type": "string", "enum": []string{"create", "advance", "update", "get", "list"}, "description": "Operation to perform"},
-				"id":   map[string]any{"type": "string", "description": "Plan UUID (for advance/update/get)"},
-				"name": map[string]any{"type": "string", "description": "Plan name in kebab-case (required for create, or used for get by name). Auto-generated from goal if omitted on create."},
-				"data": map[string]any{"type": "object", "description": "Plan data (for create/update). Fields depend on stage: outline needs goal/scope/non_goals, plan needs milestones/steps/acceptance_criteria/test_strategy, prereqs needs blocked_by/required_modules/missing_apis/migrations"},
+				"op":       map[string]any{"type": "string", "enum": []string{"create", "advance", "update", "get", "list", "diff", "split", "verify", "templates", "from_template"}, "description": "Operation to perform"},
+				"id":       map[string]any{"type": "string", "description": "Plan UUID (for advance/update/get/diff/split/verify)"},
+				"name":     map[string]any{"type": "string", "description": "Plan name in kebab-case (required for create, or used for get by name). Auto-generated from goal if omitted on create."},
+				"data":     map[string]any{"type": "object", "description": "Plan data (for create/update). Fields depend on stage: outline needs goal/scope/non_goals, plan needs milestones/steps/acceptance_criteria/test_strategy (criteria as strings or {text, test_ref} objects), prereqs needs blocked_by/required_modules/missing_apis/migrations"},
+				"from_rev": map[string]any{"type": "integer", "description": "Revision to diff from (for diff, default 1 = oldest)"},
+				"to_rev":   map[string]any{"type": "integer", "description": "Revision to diff to (for diff, default current)"},
+				"template": map[string]any{"type": "string", "description": "Plan template name (for from_template)"},
+				"vars":     map[string]any{"type": "object", "description": "Placeholder values for from_template, e.g. {\"tool\": \"lint\", \"description\": \"...\"}"},
 			},
 		},
 		Tags: []string{"graph", "write"},
@@ -89,6 +93,7 @@ func toolPlan(ctx context.Context, d *Dash, args map[string]any) (any, error) {
 		if err != nil {
 			return nil, fmt.Errorf("invalid data: %w", err)
 		}
+		d.snapshotPlanRevision(ctx, node)
 		node.Data = dataBytes
 
 		if err := d.UpdateNode(ctx, node); err != nil {
@@ -113,8 +118,49 @@ func toolPlan(ctx context.Context, d *Dash, args map[string]any) (any, error) {
 	case "list":
 		return d.ListActivePlans(ctx)
 
+	case "split":
+		id, err := parsePlanID(args)
+		if err != nil {
+			return nil, err
+		}
+		return d.SplitPlan(ctx, id)
+
+	case "verify":
+		id, err := parsePlanID(args)
+		if err != nil {
+			return nil, err
+		}
+		return d.VerifyAcceptance(ctx, id)
+
+	case "templates":
+		return d.ListPlanTemplates(ctx)
+
+	case "from_template":
+		template, _ := args["template"].(string)
+		if template == "" {
+			return nil, fmt.Errorf("template is required for from_template")
+		}
+		vars := make(map[string]string)
+		if raw, ok := args["vars"].(map[string]any); ok {
+			for k, v := range raw {
+				vars[k] = fmt.Sprint(v)
+			}
+		}
+		return d.CreatePlanFromTemplate(ctx, template, vars)
+
+	case "diff":
+		id, err := parsePlanID(args)
+		if err != nil {
+			return nil, err
+		}
+		fromRev := intVal(args, "from_rev")
+		if fromRev == 0 {
+			fromRev = 1
+		}
+		return d.PlanDiff(ctx, id, fromRev, intVal(args, "to_rev"))
+
 	default:
-		return nil, fmt.Errorf("unknown operation: %s (valid: create, advance, update, get, list)", op)
+		return nil, fmt.Errorf("unknown operation: %s (valid: create, advance, update, get, list, diff, split, verify, templates, from_template)", op)
 	}
 }
 
@@ -167,6 +213,7 @@ func toolPlanReview(ctx context.Context, d *Dash, args map[string]any) (any, err
 		json.Unmarshal(reviewJSON, &reviewMap)
 		data["review"] = reviewMap
 		dataBytes, _ := json.Marshal(data)
+		d.snapshotPlanRevision(ctx, ps.Node)
 		ps.Node.Data = dataBytes
 		d.UpdateNode(ctx, ps.Node)
 	}
@@ -23,7 +23,7 @@ func defPromptProfile() *ToolDef {
 						"system_prompt": map[string]any{"type": "string"},
 						"toolset":       map[string]any{"type": "array", "items": map[string]any{"type": "string"}},
 						"sources":       map[string]any{"type": "array", "items": map[string]any{"type": "string"}},
-						"source_config": map[string]any{"type": "object"},
+						"source_config": map[string]any{"type": "object", "description": "Per-source overrides: {source: {max_items, format, when}}. when gates the source: has_session, has_agent, has_task, has_plan, has_tasks, has_work_orders, output:<source>; prefix ! to negate"},
 						"active":        map[string]any{"type": "boolean"},
 					},
 				},
@@ -155,6 +155,9 @@ func parseSourceConfig(raw map[string]any) map[string]SourceOverride {
 			if v, ok := m["format"].(string); ok {
 				so.Format = v
 			}
+			if v, ok := m["when"].(string); ok {
+				so.When = v
+			}
 			result[key] = so
 		}
 	}
@@ -0,0 +1,69 @@
+package dash
+
+import (
+	"context"
+	"fmt"
+)
+
+func defPromptTemplate() *ToolDef {
+	return &ToolDef{
+		Name:        "prompt_template",
+		Description: "Manage versioned prompt templates (plan_generation, synthesis, handoff_summary). Sessions are split between the active versions by weight for A/B comparison.",
+		InputSchema: map[string]any{
+			"type":     "object",
+			"required": []string{"op", "template"},
+			"properties": map[string]any{
+				"op":       map[string]any{"type": "string", "enum": []string{"list", "save"}, "description": "Operation to perform"},
+				"template": map[string]any{"type": "string", "enum": []string{PromptPlanGeneration, PromptSynthesis, PromptHandoffSummary}, "description": "Template name"},
+				"version":  map[string]any{"type": "string", "description": "Version name (required for save; 'default' replaces the hardcoded prompt)"},
+				"text":     map[string]any{"type": "string", "description": "Prompt text (required for save)"},
+				"weight":   map[string]any{"type": "number", "description": "Share of sessions relative to the other active versions (default: 1)"},
+			},
+		},
+		Tags: []string{"write"},
+		Fn:   toolPromptTemplate,
+	}
+}
+
+func toolPromptTemplate(ctx context.Context, d *Dash, args map[string]any) (any, error) {
+	op, _ := args["op"].(string)
+	name, _ := args["template"].(string)
+	if _, ok := defaultPromptTemplates[name]; !ok {
+		return nil, fmt.Errorf("unknown template %q", name)
+	}
+
+	switch op {
+	case "list":
+		versions := d.promptTemplateVersions(ctx, name)
+		out := make([]map[string]any, len(versions))
+		for i, v := range versions {
+			out[i] = map[string]any{
+				"version": v.Version,
+				"weight":  v.Weight,
+				"chars":   len(v.Text),
+			}
+		}
+		return map[string]any{"template": name, "versions": out}, nil
+
+	case "save":
+		version, _ := args["version"].(string)
+		text, _ := args["text"].(string)
+		if version == "" || text == "" {
+			return nil, fmt.Errorf("version and text are required for save")
+		}
+		weight, _ := args["weight"].(float64)
+		t := PromptTemplate{Name: name, Version: version, Text: text, Weight: weight}
+		node, err := d.SavePromptTemplate(ctx, t)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]any{
+			"saved":   true,
+			"ref":     t.Ref(),
+			"node_id": node.ID,
+		}, nil
+
+	default:
+		return nil, fmt.Errorf("unknown op %q (use list or save)", op)
+	}
+}
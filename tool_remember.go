@@ -5,17 +5,19 @@ import (
 	"encoding/json"
 	"fmt"
 	"time"
+
+	"github.com/google/uuid"
 )
 
 func defRemember() *ToolDef {
 	return &ToolDef{
 		Name:        "remember",
-		Description: "Save an insight, decision, or todo to the graph.",
+		Description: "Save an insight, decision, todo, or open question to the graph. Questions stay open until resolve_question answers them.",
 		InputSchema: map[string]any{
 			"type":     "object",
 			"required": []string{"type", "text"},
 			"properties": map[string]any{
-				"type":       map[string]any{"type": "string", "enum": []string{"insight", "decision", "todo"}, "description": "Type of note"},
+				"type":       map[string]any{"type": "string", "enum": []string{"insight", "decision", "todo", "question"}, "description": "Type of note"},
 				"text":       map[string]any{"type": "string", "description": "The content to remember"},
 				"context":    map[string]any{"type": "string", "description": "Additional context"},
 				"session_id": map[string]any{"type": "string", "description": "Session ID to link to"},
@@ -36,37 +38,48 @@ func toolRemember(ctx context.Context, d *Dash, args map[string]any) (any, error
 		return nil, fmt.Errorf("type and text are required")
 	}
 
-	if noteType != "insight" && noteType != "decision" && noteType != "todo" {
-		return nil, fmt.Errorf("type must be 'insight', 'decision', or 'todo'")
+	if noteType != "insight" && noteType != "decision" && noteType != "todo" && noteType != "question" {
+		return nil, fmt.Errorf("type must be 'insight', 'decision', 'todo', or 'question'")
 	}
 
-	data := map[string]any{
-		"text":       text,
-		"created_by": "mcp",
-	}
-	if contextStr != "" {
-		data["context"] = contextStr
-	}
+	var node *Node
+	if noteType == "question" {
+		raisedBy := CallerFromContext(ctx)
+		if raisedBy == "" {
+			raisedBy = LLMAgentFromContext(ctx)
+		}
+		var err error
+		if node, err = d.CreateQuestion(ctx, text, contextStr, raisedBy); err != nil {
+			return nil, err
+		}
+	} else {
+		data := map[string]any{
+			"text": text,
+		}
+		if contextStr != "" {
+			data["context"] = contextStr
+		}
 
-	dataBytes, _ := json.Marshal(data)
+		dataBytes, _ := json.Marshal(data)
 
-	node := &Node{
-		Layer: LayerContext,
-		Type:  noteType,
-		Name:  text,
-		Data:  dataBytes,
-	}
+		node = &Node{
+			Layer: LayerContext,
+			Type:  noteType,
+			Name:  text,
+			Data:  dataBytes,
+		}
 
-	if len(node.Name) > 255 {
-		node.Name = node.Name[:252] + "..."
-	}
+		if len(node.Name) > 255 {
+			node.Name = node.Name[:252] + "..."
+		}
 
-	if err := d.CreateNode(ctx, node); err != nil {
-		return nil, err
-	}
+		if err := d.CreateNode(ctx, node); err != nil {
+			return nil, err
+		}
 
-	// Embed the node async (non-blocking, best-effort)
-	go d.EmbedNode(context.Background(), node)
+		// Embed the node async (non-blocking, best-effort)
+		go d.EmbedNode(context.Background(), node)
+	}
 
 	if sessionID != "" {
 		session, err := d.GetNodeByName(ctx, LayerContext, "session", sessionID)
@@ -103,3 +116,52 @@ func toolRemember(ctx context.Context, d *Dash, args map[string]any) (any, error
 
 	return result, nil
 }
+
+func defResolveQuestion() *ToolDef {
+	return &ToolDef{
+		Name:        "resolve_question",
+		Description: "Resolve an open question with a decision. Give answer to record a new decision, or decision_id to link an existing one.",
+		InputSchema: map[string]any{
+			"type":     "object",
+			"required": []string{"id"},
+			"properties": map[string]any{
+				"id":          map[string]any{"type": "string", "description": "Question node UUID"},
+				"answer":      map[string]any{"type": "string", "description": "The decision that answers the question"},
+				"decision_id": map[string]any{"type": "string", "description": "Existing decision node UUID (instead of answer)"},
+			},
+		},
+		Tags: []string{"write"},
+		Fn:   toolResolveQuestion,
+	}
+}
+
+func toolResolveQuestion(ctx context.Context, d *Dash, args map[string]any) (any, error) {
+	idStr, _ := args["id"].(string)
+	if idStr == "" {
+		return nil, fmt.Errorf("id is required")
+	}
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid UUID: %w", err)
+	}
+	answer, _ := args["answer"].(string)
+	var decisionID *uuid.UUID
+	if s, _ := args["decision_id"].(string); s != "" {
+		did, err := uuid.Parse(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid decision_id: %w", err)
+		}
+		decisionID = &did
+	}
+
+	decision, err := d.ResolveQuestion(ctx, id, answer, decisionID)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]any{
+		"resolved":    true,
+		"question_id": id,
+		"decision_id": decision.ID,
+		"decision":    decision.Name,
+	}, nil
+}
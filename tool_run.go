@@ -13,6 +13,17 @@ func (d *Dash) RunTool(ctx context.Context, name string, args map[string]any, op
 	if opts == nil {
 		opts = &ToolOpts{}
 	}
+	if CallerFromContext(ctx) == "" {
+		caller := opts.CallerID
+		if caller == "" {
+			caller = defaultCallerID
+		}
+		ctx = WithCaller(ctx, caller)
+	}
+	// Prompt template versions are assigned per session.
+	if opts.SessionID != "" && LLMSessionFromContext(ctx) == "" {
+		ctx = WithLLMSession(ctx, opts.SessionID)
+	}
 
 	// 1. Lookup tool
 	def, ok := d.registry.Get(name)
@@ -143,7 +154,7 @@ func determineToolRelation(toolName string) EventRelation {
 	case "file", "search", "query", "activity", "session", "summary", "working_set", "embed",
 		"read", "grep", "glob", "ls":
 		return EventRelationObserved
-	case "node", "link", "remember", "promote", "gc",
+	case "node", "link", "remember", "resolve_question", "promote", "gc",
 		"write", "edit", "mkdir":
 		return EventRelationModified
 	case "exec":
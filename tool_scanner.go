@@ -27,12 +27,14 @@ func AllToolDefs() map[string]func() *ToolDef {
 		"defSession":           defSession,
 		"defFile":               defFile,
 		"defSearch":             defSearch,
+		"defRelated":            defRelated,
 		"defQuery":              defQuery,
 		"defNode":               defNode,
 		"defLink":               defLink,
 		"defTraverse":           defTraverse,
 		"defSummary":            defSummary,
 		"defRemember":           defRemember,
+		"defResolveQuestion":    defResolveQuestion,
 		"defForget":             defForget,
 		"defWorkingSet":         defWorkingSet,
 		"defPromote":            defPromote,
@@ -52,13 +54,16 @@ func AllToolDefs() map[string]func() *ToolDef {
 		// Prompt service
 		"defPrompt":        defPrompt,
 		"defPromptProfile":  defPromptProfile,
+		"defPromptTemplate": defPromptTemplate,
 		// Agent management
 		"defSpawnAgent":       defSpawnAgent,
 		"defAgentStatus":      defAgentStatus,
 		"defUpdateAgentStatus": defUpdateAgentStatus,
 		// Cross-agent communication
-		"defAskAgent":    defAskAgent,
-		"defAnswerQuery": defAnswerQuery,
+		"defAskAgent":        defAskAgent,
+		"defAnswerQuery":     defAnswerQuery,
+		"defBroadcastAgents": defBroadcastAgents,
+		"defScratchpad":      defScratchpad,
 		// Pipeline tools
 		"defGiveToPlanner": defGiveToPlanner,
 		"defWorkOrder":     defWorkOrder,
@@ -3,6 +3,8 @@ package dash
 import (
 	"context"
 	"fmt"
+
+	"github.com/google/uuid"
 )
 
 func defSearch() *ToolDef {
@@ -28,6 +30,62 @@ func defSearch() *ToolDef {
 	}
 }
 
+func defRelated() *ToolDef {
+	return &ToolDef{
+		Name:        "related",
+		Description: "Find nodes semantically similar to a given node (nearest neighbors by embedding). Use for duplicate detection and 'see also'. Distinct from traverse, which follows graph edges.",
+		InputSchema: map[string]any{
+			"type":     "object",
+			"required": []string{"id"},
+			"properties": map[string]any{
+				"id": map[string]any{
+					"type":        "string",
+					"description": "UUID of the node to find neighbors for",
+				},
+				"limit": map[string]any{
+					"type":        "integer",
+					"description": "Maximum results (default: 10)",
+				},
+			},
+		},
+		Tags: []string{"read"},
+		Fn:   toolRelated,
+	}
+}
+
+func toolRelated(ctx context.Context, d *Dash, args map[string]any) (any, error) {
+	idStr, _ := args["id"].(string)
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid id: %w", err)
+	}
+	limit := 10
+	if l, ok := args["limit"].(float64); ok {
+		limit = int(l)
+	}
+
+	results, err := d.RelatedNodes(ctx, id, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	var output []map[string]any
+	for _, r := range results {
+		item := map[string]any{
+			"id":       r.ID,
+			"name":     r.Name,
+			"layer":    r.Layer,
+			"type":     r.Type,
+			"distance": r.Distance,
+		}
+		if r.Path != "" {
+			item["file_path"] = r.Path
+		}
+		output = append(output, item)
+	}
+	return output, nil
+}
+
 func toolSearch(ctx context.Context, d *Dash, args map[string]any) (any, error) {
 	query, ok := args["query"].(string)
 	if !ok || query == "" {
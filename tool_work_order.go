@@ -32,14 +32,14 @@ func validateAgentKey(key string) error {
 func defWorkOrder() *ToolDef {
 	return &ToolDef{
 		Name:        "work_order",
-		Description: "Hantera work orders i pipeline. Actions: create, assign, advance, list, get. Agent keys: orchestrator, cockpit-backend, cockpit-frontend, systemprompt-agent, database-agent, system-agent, shift-agent, planner-agent.",
+		Description: "Hantera work orders i pipeline. Actions: create, assign, advance, list, get, depend (id beror på depends_on, som måste vara merged innan id kan tilldelas), ready (created-ordrar vars beroenden är merged, samt build_failed-ordrar vars retry-backoff har löpt ut), report (slutrapport för en merged order: task, agent, branch, PR, filer, försök, tid och score). Agent keys: orchestrator, cockpit-backend, cockpit-frontend, systemprompt-agent, database-agent, system-agent, shift-agent, planner-agent.",
 		InputSchema: map[string]any{
 			"type":     "object",
 			"required": []string{"action"},
 			"properties": map[string]any{
 				"action": map[string]any{
 					"type":        "string",
-					"enum":        []string{"create", "assign", "advance", "list", "get"},
+					"enum":        []string{"create", "assign", "advance", "list", "get", "depend", "ready", "report"},
 					"description": "Operationen att utföra.",
 				},
 				"name": map[string]any{
@@ -48,7 +48,11 @@ func defWorkOrder() *ToolDef {
 				},
 				"id": map[string]any{
 					"type":        "string",
-					"description": "Work order UUID (för assign/advance/get).",
+					"description": "Work order UUID (för assign/advance/get/depend/report).",
+				},
+				"depends_on": map[string]any{
+					"type":        "string",
+					"description": "UUID för work order som måste vara merged först (för depend).",
 				},
 				"description": map[string]any{
 					"type":        "string",
@@ -61,7 +65,7 @@ func defWorkOrder() *ToolDef {
 				},
 				"agent_key": map[string]any{
 					"type":        "string",
-					"description": "Agent att tilldela (för create/assign). Måste vara en registrerad agent-key.",
+					"description": "Agent att tilldela (för create/assign). Måste vara en registrerad agent-key. Utelämnas den vid assign väljs bäst matchande agent utifrån agenternas capabilities och scope_globs.",
 				},
 				"base_branch": map[string]any{
 					"type":        "string",
@@ -132,27 +136,33 @@ func handleWorkOrder(ctx context.Context, d *Dash, args map[string]any) (any, er
 		if err != nil {
 			return nil, err
 		}
+		wo, err := d.GetWorkOrder(ctx, id)
+		if err != nil {
+			return nil, err
+		}
 		agentKey, _ := args["agent_key"].(string)
-		if agentKey == "" {
-			return nil, fmt.Errorf("agent_key is required for assign")
+		selected := agentKey == ""
+		if selected {
+			// No agent named: route by capabilities and scope globs.
+			agentKey, err = d.SelectAgentForWorkOrder(ctx, wo)
+			if err != nil {
+				return nil, fmt.Errorf("%w; pass agent_key to assign manually", err)
+			}
 		}
 		if err := validateAgentKey(agentKey); err != nil {
 			return nil, err
 		}
-		wo, err := d.GetWorkOrder(ctx, id)
-		if err != nil {
-			return nil, err
-		}
 		branchName := fmt.Sprintf("agent/%s/%s", agentKey, wo.Node.Name)
 		wo, err = d.AssignWorkOrder(ctx, id, agentKey, branchName)
 		if err != nil {
 			return nil, err
 		}
 		return map[string]any{
-			"id":     wo.Node.ID.String(),
-			"status": string(wo.Status),
-			"branch": wo.BranchName,
-			"agent":  wo.AgentKey,
+			"id":       wo.Node.ID.String(),
+			"status":   string(wo.Status),
+			"branch":   wo.BranchName,
+			"agent":    wo.AgentKey,
+			"selected": selected,
 		}, nil
 
 	case "advance":
@@ -169,10 +179,16 @@ func handleWorkOrder(ctx context.Context, d *Dash, args map[string]any) (any, er
 		if err != nil {
 			return nil, err
 		}
-		return map[string]any{
+		result := map[string]any{
 			"id":     wo.Node.ID.String(),
 			"status": string(wo.Status),
-		}, nil
+		}
+		if wo.Status == WOStatusMerged {
+			if report, err := d.WorkOrderCompletionReport(ctx, id); err == nil {
+				result["summary"] = report.Summary()
+			}
+		}
+		return result, nil
 
 	case "list":
 		orders, err := d.ListActiveWorkOrders(ctx)
@@ -214,10 +230,65 @@ func handleWorkOrder(ctx context.Context, d *Dash, args map[string]any) (any, er
 			"pr_id":         wo.PRID,
 			"pr_url":        wo.PRUrl,
 			"last_error":    wo.LastError,
+			"next_attempt":  wo.NextAttemptAt,
+		}, nil
+
+	case "depend":
+		id, err := parseWOID(args)
+		if err != nil {
+			return nil, err
+		}
+		prereqStr, _ := args["depends_on"].(string)
+		prereqID, err := uuid.Parse(prereqStr)
+		if err != nil {
+			return nil, fmt.Errorf("depends_on must be a work order UUID")
+		}
+		if err := d.AddWorkOrderDependency(ctx, id, prereqID); err != nil {
+			return nil, err
+		}
+		prereqs, err := d.WorkOrderPrerequisites(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]any{
+			"id":      id.String(),
+			"blocked": len(unmetPrerequisites(prereqs)) > 0,
+			"unmet":   unmetPrerequisites(prereqs),
+		}, nil
+
+	case "ready":
+		orders, err := d.ReadyWorkOrders(ctx)
+		if err != nil {
+			return nil, err
+		}
+		var result []map[string]any
+		for _, wo := range orders {
+			result = append(result, map[string]any{
+				"id":      wo.Node.ID.String(),
+				"name":    wo.Node.Name,
+				"status":  string(wo.Status),
+				"agent":   wo.AgentKey,
+				"attempt": wo.Attempt,
+			})
+		}
+		return map[string]any{"work_orders": result, "count": len(result)}, nil
+
+	case "report":
+		id, err := parseWOID(args)
+		if err != nil {
+			return nil, err
+		}
+		report, err := d.WorkOrderCompletionReport(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]any{
+			"report":  report,
+			"summary": report.Summary(),
 		}, nil
 
 	default:
-		return nil, fmt.Errorf("unknown action: %s (use: create, assign, advance, list, get)", action)
+		return nil, fmt.Errorf("unknown action: %s (use: create, assign, advance, list, get, depend, ready, report)", action)
 	}
 }
 
@@ -42,6 +42,9 @@ const (
 	RelationAssignedTo   Relation = "assigned_to"   // work_order → agent
 	RelationProduces     Relation = "produces"      // work_order → file/commit
 	RelationScopedTo     Relation = "scoped_to"     // work_order → file (scope boundary)
+	RelationPartOf       Relation = "part_of"       // sub-plan → umbrella plan
+	RelationReverts      Relation = "reverts"       // revert work_order → reverted work_order
+	RelationResolves     Relation = "resolves"      // decision → question
 )
 
 // EventRelation represents causal/lineage relationships in edge_events.
@@ -80,6 +83,7 @@ type Edge struct {
 	TargetID     uuid.UUID       `json:"target_id"`
 	Relation     Relation        `json:"relation"`
 	Data         json.RawMessage `json:"data"`
+	Weight       float64         `json:"weight"` // confidence 0–1; 1.0 for certain relations
 	CreatedAt    time.Time       `json:"created_at"`
 	DeprecatedAt *time.Time      `json:"deprecated_at,omitempty"`
 }
@@ -133,6 +137,12 @@ type Dash struct {
 	summarizer SummaryClient
 	registry   *ToolRegistry
 	router     *LLMRouter
+
+	workOrderRetryBackoff time.Duration
+	hookAllowedRoots      []string
+	leaderboardWeights    LeaderboardWeights
+	packDedupThreshold    float64
+	hookMaxInputBytes     int
 }
 
 // Config holds configuration for creating a new Dash client.
@@ -142,6 +152,27 @@ type Config struct {
 	Embedder        EmbeddingClient // Optional: if nil, embeddings are disabled
 	Summarizer      SummaryClient   // Optional: if nil, summaries are disabled
 	Router          *LLMRouter      // Optional: if set, used as embedder + summarizer
+
+	// WorkOrderRetryBackoff is the delay before the first retry of a failed
+	// build; it doubles with each further attempt. Zero uses the default.
+	WorkOrderRetryBackoff time.Duration
+
+	// HookAllowedRoots limits hook recording to sessions whose cwd is under
+	// one of these directories. Empty records every session.
+	HookAllowedRoots []string
+
+	// LeaderboardWeights weighs the components of AgentLeaderboard's
+	// composite score. The zero value uses DefaultLeaderboardWeights.
+	LeaderboardWeights LeaderboardWeights
+
+	// PackDedupThreshold is the embedding cosine similarity above which a
+	// context pack item counts as a duplicate of a higher-ranked one and is
+	// dropped. Zero uses the default; a value above 1 disables the check.
+	PackDedupThreshold float64
+
+	// HookMaxInputBytes caps the tool input stored with hook events; larger
+	// inputs are compacted and flagged as truncated. Zero uses the default.
+	HookMaxInputBytes int
 }
 
 // New creates a new Dash client with the given configuration.
@@ -159,6 +190,24 @@ func New(cfg Config) (*Dash, error) {
 		summarizer: cfg.Summarizer,
 		registry:   NewToolRegistry(),
 		router:     cfg.Router,
+
+		workOrderRetryBackoff: cfg.WorkOrderRetryBackoff,
+		hookAllowedRoots:      cfg.HookAllowedRoots,
+		leaderboardWeights:    cfg.LeaderboardWeights,
+		packDedupThreshold:    cfg.PackDedupThreshold,
+		hookMaxInputBytes:     cfg.HookMaxInputBytes,
+	}
+	if d.workOrderRetryBackoff <= 0 {
+		d.workOrderRetryBackoff = defaultWorkOrderRetryBackoff
+	}
+	if d.leaderboardWeights == (LeaderboardWeights{}) {
+		d.leaderboardWeights = DefaultLeaderboardWeights
+	}
+	if d.packDedupThreshold <= 0 {
+		d.packDedupThreshold = defaultPackDedupThreshold
+	}
+	if d.hookMaxInputBytes <= 0 {
+		d.hookMaxInputBytes = defaultHookMaxInputBytes
 	}
 
 	// If router is provided, use it as embedder and summarizer
@@ -169,6 +218,10 @@ func New(cfg Config) (*Dash, error) {
 		if d.summarizer == nil {
 			d.summarizer = d.router
 		}
+		if cfg.DB != nil {
+			d.router.SetBudgetStore(d)
+			d.router.SetUsageRecorder(d)
+		}
 	}
 
 	// If no embedder provided, use NoOp
@@ -181,6 +234,11 @@ func New(cfg Config) (*Dash, error) {
 		d.summarizer = &NoOpSummarizer{}
 	}
 
+	// Reuse summaries of previously seen content
+	if _, isNoOp := d.summarizer.(*NoOpSummarizer); !isNoOp && cfg.DB != nil {
+		d.summarizer = NewCachingSummarizer(d.summarizer, &dbSummaryCache{db: cfg.DB})
+	}
+
 	// Register default executors
 	d.RegisterExecutor("sql", &SQLExecutor{db: cfg.DB})
 	d.RegisterExecutor("filesystem_read", &FileReadExecutor{config: fc})
@@ -203,6 +261,15 @@ func (d *Dash) EmbedText(ctx context.Context, text string) ([]float32, error) {
 	return d.embedder.Embed(ctx, text)
 }
 
+// EmbeddingModel returns the active embedder's model identifier, or "" when
+// the embedder does not report one.
+func (d *Dash) EmbeddingModel() string {
+	if m, ok := d.embedder.(EmbeddingModeler); ok {
+		return m.EmbeddingModel()
+	}
+	return ""
+}
+
 // HasRealEmbedder returns true if a real (non-NoOp) embedder is configured.
 func (d *Dash) HasRealEmbedder() bool {
 	if d.embedder == nil {
@@ -213,6 +280,8 @@ func (d *Dash) HasRealEmbedder() bool {
 }
 
 // HasRealSummarizer returns true if a real (non-NoOp) summarizer is configured.
+// A router counts as real whatever its providers, including the offline mock
+// from MockRouterConfig.
 func (d *Dash) HasRealSummarizer() bool {
 	if d.summarizer == nil {
 		return false
@@ -292,6 +361,7 @@ func scanEdge(scanner interface {
 		&e.TargetID,
 		&e.Relation,
 		&e.Data,
+		&e.Weight,
 		&e.CreatedAt,
 		&deprecatedAt,
 	)
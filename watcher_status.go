@@ -0,0 +1,94 @@
+package dash
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+)
+
+const (
+	// watcherHeartbeatType is the observation type dashwatch writes each beat.
+	watcherHeartbeatType = "watcher_heartbeat"
+
+	// WatcherStaleAfter is how old the last heartbeat may be before the
+	// watcher is considered stale (three missed one-minute beats).
+	WatcherStaleAfter = 3 * time.Minute
+
+	// keptWatcherHeartbeats is how many heartbeats RecordWatcherHeartbeat
+	// keeps; only the latest is read, so an hour of history is plenty.
+	keptWatcherHeartbeats = 60
+)
+
+const queryLatestWatcherHeartbeat = `
+	SELECT data, observed_at
+	FROM observations
+	WHERE type = 'watcher_heartbeat'
+	ORDER BY observed_at DESC
+	LIMIT 1`
+
+// WatcherHeartbeat is the payload of one dashwatch liveness beat.
+// DirsWatched is the number of directories registered with the watcher;
+// Embedded and Errors count events since the previous beat.
+type WatcherHeartbeat struct {
+	DirsWatched int `json:"dirs_watched"`
+	Embedded    int `json:"embedded"`
+	Errors      int `json:"errors"`
+}
+
+// WatcherStatus summarizes the most recent dashwatch heartbeat.
+type WatcherStatus struct {
+	LastBeat  *time.Time       `json:"last_beat,omitempty"`
+	Age       time.Duration    `json:"age"`
+	Stale     bool             `json:"stale"`
+	Heartbeat WatcherHeartbeat `json:"heartbeat"`
+}
+
+// RecordWatcherHeartbeat stores a heartbeat observation on the
+// SYSTEM.service "dashwatch" node and prunes all but the most recent
+// keptWatcherHeartbeats.
+func (d *Dash) RecordWatcherHeartbeat(ctx context.Context, hb WatcherHeartbeat) error {
+	node, err := d.GetOrCreateNode(ctx, LayerSystem, "service", "dashwatch", nil)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(hb)
+	if err != nil {
+		return err
+	}
+	if err := d.CreateObservation(ctx, &Observation{
+		NodeID: node.ID,
+		Type:   watcherHeartbeatType,
+		Data:   data,
+	}); err != nil {
+		return err
+	}
+	_, err = d.PruneObservations(ctx, node.ID, watcherHeartbeatType, keptWatcherHeartbeats)
+	return err
+}
+
+// WatcherStatus returns the age of the latest dashwatch heartbeat.
+// When no heartbeat exists, the status is stale with a nil LastBeat.
+func (d *Dash) WatcherStatus(ctx context.Context) (*WatcherStatus, error) {
+	var data json.RawMessage
+	var observedAt time.Time
+	err := d.db.QueryRowContext(ctx, queryLatestWatcherHeartbeat).Scan(&data, &observedAt)
+	if err == sql.ErrNoRows {
+		return &WatcherStatus{Stale: true}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return watcherStatusFrom(data, observedAt, time.Now()), nil
+}
+
+// watcherStatusFrom builds a WatcherStatus from a heartbeat row.
+func watcherStatusFrom(data json.RawMessage, observedAt, now time.Time) *WatcherStatus {
+	s := &WatcherStatus{
+		LastBeat: &observedAt,
+		Age:      now.Sub(observedAt),
+	}
+	s.Stale = s.Age > WatcherStaleAfter
+	json.Unmarshal(data, &s.Heartbeat)
+	return s
+}
@@ -0,0 +1,28 @@
+package dash
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestWatcherStatusFrom(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	data := json.RawMessage(`{"dirs_watched":42,"embedded":3,"errors":1}`)
+
+	fresh := watcherStatusFrom(data, now.Add(-70*time.Second), now)
+	if fresh.Stale {
+		t.Error("70s old heartbeat should not be stale")
+	}
+	if fresh.Age != 70*time.Second {
+		t.Errorf("age = %v, want 70s", fresh.Age)
+	}
+	if fresh.Heartbeat.DirsWatched != 42 || fresh.Heartbeat.Embedded != 3 || fresh.Heartbeat.Errors != 1 {
+		t.Errorf("heartbeat = %+v", fresh.Heartbeat)
+	}
+
+	stale := watcherStatusFrom(data, now.Add(-10*time.Minute), now)
+	if !stale.Stale {
+		t.Error("10m old heartbeat should be stale")
+	}
+}
@@ -3,6 +3,7 @@ package dash
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
 
@@ -26,6 +27,20 @@ const (
 
 const maxWorkOrderAttempts = 3
 
+// Retry backoff after a failed build: the base delay doubles per attempt up
+// to maxWorkOrderRetryBackoff.
+const (
+	defaultWorkOrderRetryBackoff = 30 * time.Second
+	maxWorkOrderRetryBackoff     = time.Hour
+)
+
+// ErrWorkOrderAlreadyAssigned is returned when another agent claimed the work order first.
+var ErrWorkOrderAlreadyAssigned = errors.New("work order already assigned")
+
+// ErrWorkOrderBackoff is returned when a failed work order is retried before
+// its backoff has elapsed.
+var ErrWorkOrderBackoff = errors.New("work order retry backoff has not elapsed")
+
 // WorkOrderEvent is the most recent event snapshot kept inline in the WorkOrder JSON.
 type WorkOrderEvent struct {
 	Status string `json:"status"`
@@ -61,6 +76,8 @@ type WorkOrder struct {
 	LastError   string `json:"last_error,omitempty"`
 	LastErrorAt string `json:"last_error_at,omitempty"`
 
+	NextAttemptAt string `json:"next_attempt_at,omitempty"` // earliest retry after build_failed
+
 	WorktreePath string `json:"worktree_path,omitempty"`
 
 	LastEvent  *WorkOrderEvent `json:"last_event,omitempty"`
@@ -68,6 +85,21 @@ type WorkOrder struct {
 
 	AllowPublicAPIChange bool   `json:"allow_public_api_change,omitempty"`
 	Description          string `json:"description,omitempty"`
+
+	SynthesisScore  *float64 `json:"synthesis_score,omitempty"` // 0-1, set by SynthesizeWorkOrder
+	SynthesisReport string   `json:"synthesis_report,omitempty"`
+
+	Reviewer       string `json:"reviewer,omitempty"`       // agent asked to peer review
+	ReviewVerdict  string `json:"review_verdict,omitempty"` // "approved" | "rejected"
+	ReviewComments string `json:"review_comments,omitempty"`
+
+	ForceMergedBy    string `json:"force_merged_by,omitempty"` // set by ForceMergeWorkOrder
+	ForceMergeReason string `json:"force_merge_reason,omitempty"`
+
+	Reverts    *uuid.UUID `json:"reverts,omitempty"`     // order this revert undoes, see RevertWorkOrder
+	RevertedBy *uuid.UUID `json:"reverted_by,omitempty"` // revert order that undoes this one
+
+	Tags []string `json:"tags,omitempty"` // node tags (see AddTags), kept across saves
 }
 
 // validTransitions defines allowed status transitions.
@@ -265,20 +297,9 @@ func (d *Dash) AdvanceWorkOrder(ctx context.Context, id uuid.UUID, targetStatus
 		return wo, fmt.Errorf("invalid transition: %s → %s (allowed: %v)", wo.Status, targetStatus, allowed)
 	}
 
-	// Handle build_failed retry logic
-	if targetStatus == WOStatusBuildFailed {
-		wo.Attempt++
-		if wo.Attempt >= maxWorkOrderAttempts {
-			// Max retries exceeded → reject
-			targetStatus = WOStatusRejected
-			detail = fmt.Sprintf("max attempts (%d) exceeded: %s", maxWorkOrderAttempts, detail)
-		}
-	}
-
-	// Handle error tracking
-	if targetStatus == WOStatusBuildFailed || targetStatus == WOStatusRejected {
-		wo.LastError = detail
-		wo.LastErrorAt = time.Now().UTC().Format(time.RFC3339)
+	targetStatus, detail, err = applyWorkOrderRetry(wo, targetStatus, detail, time.Now().UTC(), d.workOrderRetryBackoff)
+	if err != nil {
+		return wo, err
 	}
 
 	wo.Status = targetStatus
@@ -291,9 +312,76 @@ func (d *Dash) AdvanceWorkOrder(ctx context.Context, id uuid.UUID, targetStatus
 		return wo, fmt.Errorf("save work_order: %w", err)
 	}
 
+	if targetStatus == WOStatusMerged {
+		d.recordCompletionReport(ctx, id)
+	}
+
 	return wo, nil
 }
 
+// applyWorkOrderRetry handles attempt counting, retry backoff and error
+// tracking for a transition of wo to target at now. A build failure that still
+// has attempts left schedules NextAttemptAt; retrying it earlier returns
+// ErrWorkOrderBackoff. It returns the status and detail to record, which
+// become rejected once maxWorkOrderAttempts is reached.
+func applyWorkOrderRetry(wo *WorkOrder, target WorkOrderStatus, detail string, now time.Time, base time.Duration) (WorkOrderStatus, string, error) {
+	if wo.Status == WOStatusBuildFailed && target == WOStatusMutating {
+		if at, ok := wo.nextAttemptAt(); ok && now.Before(at) {
+			return target, detail, fmt.Errorf("%w: next attempt at %s", ErrWorkOrderBackoff, wo.NextAttemptAt)
+		}
+	}
+
+	if target == WOStatusBuildFailed {
+		wo.Attempt++
+		if wo.Attempt >= maxWorkOrderAttempts {
+			// Max retries exceeded → reject
+			target = WOStatusRejected
+			detail = fmt.Sprintf("max attempts (%d) exceeded: %s", maxWorkOrderAttempts, detail)
+		}
+	}
+
+	if target == WOStatusBuildFailed || target == WOStatusRejected {
+		wo.LastError = detail
+		wo.LastErrorAt = now.Format(time.RFC3339)
+	}
+
+	wo.NextAttemptAt = ""
+	if target == WOStatusBuildFailed {
+		wo.NextAttemptAt = now.Add(workOrderRetryBackoff(base, wo.Attempt)).Format(time.RFC3339)
+	}
+	return target, detail, nil
+}
+
+// workOrderRetryBackoff returns the delay before retrying after the given
+// failed attempt: base, 2·base, 4·base, ... capped at maxWorkOrderRetryBackoff.
+func workOrderRetryBackoff(base time.Duration, attempt int) time.Duration {
+	if base <= 0 {
+		base = defaultWorkOrderRetryBackoff
+	}
+	delay := base
+	for i := 1; i < attempt && delay < maxWorkOrderRetryBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, maxWorkOrderRetryBackoff)
+}
+
+// nextAttemptAt parses NextAttemptAt; ok is false when no backoff is recorded.
+func (wo *WorkOrder) nextAttemptAt() (time.Time, bool) {
+	if wo.NextAttemptAt == "" {
+		return time.Time{}, false
+	}
+	t, err := time.Parse(time.RFC3339, wo.NextAttemptAt)
+	return t, err == nil
+}
+
+// queryClaimWorkOrder moves a work order out of 'created' only if no one else
+// has, so concurrent assigns cannot both succeed.
+const queryClaimWorkOrder = `
+	UPDATE nodes
+	SET data = data || $2::jsonb, updated_at = NOW()
+	WHERE id = $1 AND deleted_at IS NULL
+	  AND COALESCE(data->>'status', 'created') = 'created'`
+
 // AssignWorkOrder assigns a work order to an agent and sets the branch name.
 func (d *Dash) AssignWorkOrder(ctx context.Context, id uuid.UUID, agentKey, branchName string) (*WorkOrder, error) {
 	wo, err := d.GetWorkOrder(ctx, id)
@@ -306,16 +394,46 @@ func (d *Dash) AssignWorkOrder(ctx context.Context, id uuid.UUID, agentKey, bran
 		return wo, nil
 	}
 
+	if wo.Status == WOStatusAssigned {
+		return wo, fmt.Errorf("%w to '%s'", ErrWorkOrderAlreadyAssigned, wo.AgentKey)
+	}
 	if wo.Status != WOStatusCreated {
 		return wo, fmt.Errorf("can only assign from 'created' state, currently '%s'", wo.Status)
 	}
+	if err := d.checkWorkOrderPrerequisites(ctx, id); err != nil {
+		return wo, err
+	}
 
-	wo.AgentKey = agentKey
 	if branchName == "" {
 		branchName = fmt.Sprintf("agent/%s/%s", agentKey, wo.Node.ID)
 	}
-	wo.BranchName = branchName
 
+	// Claim atomically; the loser of a race re-reads to report the winner.
+	claim, _ := json.Marshal(map[string]any{
+		"status":      WOStatusAssigned,
+		"agent_key":   agentKey,
+		"branch_name": branchName,
+	})
+	res, err := d.db.ExecContext(ctx, queryClaimWorkOrder, id, claim)
+	if err != nil {
+		return wo, err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		current, err := d.GetWorkOrder(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if current.Status == WOStatusAssigned && current.AgentKey == agentKey {
+			return current, nil
+		}
+		if current.Status == WOStatusAssigned {
+			return current, fmt.Errorf("%w to '%s'", ErrWorkOrderAlreadyAssigned, current.AgentKey)
+		}
+		return current, fmt.Errorf("can only assign from 'created' state, currently '%s'", current.Status)
+	}
+
+	wo.AgentKey = agentKey
+	wo.BranchName = branchName
 	wo.Status = WOStatusAssigned
 
 	// Create assigned_to edge if we can find the agent node
@@ -387,16 +505,23 @@ func (d *Dash) appendWorkOrderEvent(ctx context.Context, wo *WorkOrder, status W
 	wo.EventCount++
 
 	// Write to observations table
-	obsData, _ := json.Marshal(map[string]any{
-		"status":     string(status),
-		"actor":      actor,
-		"detail":     detail,
-		"revision":   wo.Revision,
-		"attempt":    wo.Attempt,
-		"event_num":  wo.EventCount,
-		"branch":     wo.BranchName,
-		"agent_key":  wo.AgentKey,
-	})
+	eventData := map[string]any{
+		"status":    string(status),
+		"actor":     actor,
+		"detail":    detail,
+		"revision":  wo.Revision,
+		"attempt":   wo.Attempt,
+		"event_num": wo.EventCount,
+		"branch":    wo.BranchName,
+		"agent_key": wo.AgentKey,
+	}
+	if wo.SynthesisScore != nil {
+		eventData["score"] = *wo.SynthesisScore
+	}
+	if status == WOStatusMerged && wo.ForceMergedBy != "" {
+		eventData["forced"] = true
+	}
+	obsData, _ := json.Marshal(eventData)
 
 	d.CreateObservation(ctx, &Observation{
 		NodeID:     wo.Node.ID,
@@ -405,3 +530,75 @@ func (d *Dash) appendWorkOrderEvent(ctx context.Context, wo *WorkOrder, status W
 		ObservedAt: now,
 	})
 }
+
+// lastActivity returns when the work order last made progress: the inline
+// LastEvent timestamp if present, otherwise the node's updated_at.
+func (wo *WorkOrder) lastActivity() time.Time {
+	if wo.LastEvent != nil && wo.LastEvent.At != "" {
+		if t, err := time.Parse(time.RFC3339, wo.LastEvent.At); err == nil {
+			return t
+		}
+	}
+	if wo.Node != nil {
+		return wo.Node.UpdatedAt
+	}
+	return time.Time{}
+}
+
+// filterStuckWorkOrders returns the non-terminal orders whose last activity is older than maxAge.
+func filterStuckWorkOrders(orders []*WorkOrder, now time.Time, maxAge time.Duration) []*WorkOrder {
+	var stuck []*WorkOrder
+	for _, wo := range orders {
+		if wo.Status == WOStatusMerged || wo.Status == WOStatusRejected {
+			continue
+		}
+		last := wo.lastActivity()
+		if last.IsZero() {
+			continue
+		}
+		if now.Sub(last) > maxAge {
+			stuck = append(stuck, wo)
+		}
+	}
+	return stuck
+}
+
+// FindStuckWorkOrders returns active work orders that have made no progress for longer than maxAge.
+// Orders can sit in mutating or synthesis_pending forever if their agent dies.
+func (d *Dash) FindStuckWorkOrders(ctx context.Context, maxAge time.Duration) ([]*WorkOrder, error) {
+	if maxAge <= 0 {
+		return nil, fmt.Errorf("maxAge must be positive")
+	}
+	orders, err := d.ListActiveWorkOrders(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return filterStuckWorkOrders(orders, time.Now().UTC(), maxAge), nil
+}
+
+// AutoRejectStuck rejects every stuck work order, bypassing the normal transition
+// rules since a stuck order may be in any non-terminal state. Returns the rejected orders.
+func (d *Dash) AutoRejectStuck(ctx context.Context, maxAge time.Duration) ([]*WorkOrder, error) {
+	stuck, err := d.FindStuckWorkOrders(ctx, maxAge)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	var rejected []*WorkOrder
+	for _, wo := range stuck {
+		idle := now.Sub(wo.lastActivity()).Round(time.Minute)
+		detail := fmt.Sprintf("stuck, no progress for %s", idle)
+
+		wo.Status = WOStatusRejected
+		wo.LastError = detail
+		wo.LastErrorAt = now.Format(time.RFC3339)
+		d.appendWorkOrderEvent(ctx, wo, WOStatusRejected, "system", detail)
+
+		if err := d.saveWorkOrder(ctx, wo); err != nil {
+			return rejected, fmt.Errorf("reject stuck work_order %s: %w", wo.Node.Name, err)
+		}
+		rejected = append(rejected, wo)
+	}
+	return rejected, nil
+}
@@ -0,0 +1,151 @@
+package dash
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrWorkOrderBlocked is returned when a work order is started before all of
+// its prerequisites are merged.
+var ErrWorkOrderBlocked = errors.New("work order has unmerged prerequisites")
+
+// Work order dependencies are depends_on edges from the dependent order to
+// its prerequisite.
+
+const queryWorkOrderPrerequisites = `
+	SELECT n.id, n.layer, n.type, n.name, n.data, n.created_at, n.updated_at, n.deleted_at
+	FROM edges e
+	JOIN nodes n ON n.id = e.target_id
+	WHERE e.source_id = $1 AND e.relation = 'depends_on' AND e.deprecated_at IS NULL
+	  AND n.layer = 'AUTOMATION' AND n.type = 'work_order' AND n.deleted_at IS NULL
+	ORDER BY n.created_at`
+
+// queryWorkOrderDependsOnPath reports whether $1 (transitively) depends on $2.
+const queryWorkOrderDependsOnPath = `
+	WITH RECURSIVE deps AS (
+		SELECT e.target_id AS id, 1 AS depth
+		FROM edges e
+		WHERE e.source_id = $1 AND e.relation = 'depends_on' AND e.deprecated_at IS NULL
+		UNION
+		SELECT e.target_id, deps.depth + 1
+		FROM edges e
+		JOIN deps ON e.source_id = deps.id
+		WHERE e.relation = 'depends_on' AND e.deprecated_at IS NULL AND deps.depth < 50
+	)
+	SELECT EXISTS (SELECT 1 FROM deps WHERE id = $2)`
+
+// queryReadyWorkOrders takes $1 = now; failed orders are ready to retry once
+// their next_attempt_at has passed.
+const queryReadyWorkOrders = `
+	SELECT n.id, n.layer, n.type, n.name, n.data, n.created_at, n.updated_at, n.deleted_at
+	FROM nodes n
+	WHERE n.layer = 'AUTOMATION' AND n.type = 'work_order'
+	  AND n.deleted_at IS NULL
+	  AND (COALESCE(n.data->>'status', 'created') = 'created'
+	    OR (n.data->>'status' = 'build_failed'
+	      AND COALESCE(NULLIF(n.data->>'next_attempt_at', '')::timestamptz, '-infinity') <= $1))
+	  AND NOT EXISTS (
+		SELECT 1
+		FROM edges e
+		JOIN nodes p ON p.id = e.target_id
+		WHERE e.source_id = n.id AND e.relation = 'depends_on' AND e.deprecated_at IS NULL
+		  AND p.layer = 'AUTOMATION' AND p.type = 'work_order' AND p.deleted_at IS NULL
+		  AND COALESCE(p.data->>'status', 'created') != 'merged'
+	  )
+	ORDER BY n.created_at`
+
+// AddWorkOrderDependency records that dependentID cannot start until
+// prerequisiteID is merged. Adding an existing dependency is a no-op; one
+// that would create a cycle is refused.
+func (d *Dash) AddWorkOrderDependency(ctx context.Context, dependentID, prerequisiteID uuid.UUID) error {
+	if dependentID == prerequisiteID {
+		return fmt.Errorf("a work order cannot depend on itself")
+	}
+	if _, err := d.GetWorkOrder(ctx, dependentID); err != nil {
+		return fmt.Errorf("dependent: %w", err)
+	}
+	if _, err := d.GetWorkOrder(ctx, prerequisiteID); err != nil {
+		return fmt.Errorf("prerequisite: %w", err)
+	}
+
+	if exists, _ := d.hasEdge(ctx, dependentID, prerequisiteID, RelationDependsOn); exists {
+		return nil
+	}
+	var cycle bool
+	if err := d.db.QueryRowContext(ctx, queryWorkOrderDependsOnPath, prerequisiteID, dependentID).Scan(&cycle); err != nil {
+		return fmt.Errorf("check dependency cycle: %w", err)
+	}
+	if cycle {
+		return fmt.Errorf("dependency would create a cycle: %s already depends on %s", prerequisiteID, dependentID)
+	}
+
+	return d.CreateEdge(ctx, &Edge{
+		SourceID: dependentID,
+		TargetID: prerequisiteID,
+		Relation: RelationDependsOn,
+	})
+}
+
+// WorkOrderPrerequisites returns the work orders id directly depends on.
+func (d *Dash) WorkOrderPrerequisites(ctx context.Context, id uuid.UUID) ([]*WorkOrder, error) {
+	return d.queryWorkOrders(ctx, queryWorkOrderPrerequisites, id)
+}
+
+// ReadyWorkOrders returns work orders whose prerequisites are all merged and
+// that can start now, oldest first: created orders and failed builds whose
+// retry backoff has elapsed.
+func (d *Dash) ReadyWorkOrders(ctx context.Context) ([]*WorkOrder, error) {
+	return d.queryWorkOrders(ctx, queryReadyWorkOrders, time.Now().UTC())
+}
+
+// checkWorkOrderPrerequisites returns ErrWorkOrderBlocked naming every
+// prerequisite of id that is not merged.
+func (d *Dash) checkWorkOrderPrerequisites(ctx context.Context, id uuid.UUID) error {
+	prereqs, err := d.WorkOrderPrerequisites(ctx, id)
+	if err != nil {
+		return err
+	}
+	if unmet := unmetPrerequisites(prereqs); len(unmet) > 0 {
+		return fmt.Errorf("%w: %s", ErrWorkOrderBlocked, strings.Join(unmet, ", "))
+	}
+	return nil
+}
+
+// unmetPrerequisites lists "name (status)" for prerequisites not yet merged.
+func unmetPrerequisites(prereqs []*WorkOrder) []string {
+	var unmet []string
+	for _, p := range prereqs {
+		if p.Status != WOStatusMerged {
+			unmet = append(unmet, fmt.Sprintf("%s (%s)", p.Node.Name, p.Status))
+		}
+	}
+	return unmet
+}
+
+// queryWorkOrders runs a node query and parses each row as a work order.
+func (d *Dash) queryWorkOrders(ctx context.Context, query string, args ...any) ([]*WorkOrder, error) {
+	rows, err := d.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var orders []*WorkOrder
+	for rows.Next() {
+		node, err := scanNode(rows)
+		if err != nil {
+			continue
+		}
+		wo, err := parseWorkOrder(node)
+		if err != nil {
+			continue
+		}
+		orders = append(orders, wo)
+	}
+	return orders, rows.Err()
+}
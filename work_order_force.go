@@ -0,0 +1,74 @@
+package dash
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// forceMergedTag marks work orders merged through ForceMergeWorkOrder, so
+// metrics and NodesByTag can single them out.
+const forceMergedTag = "force-merged"
+
+// ForceMergeWorkOrder moves a work order straight to merged whatever its
+// status and ChecksStatus, for emergency hotfixes that cannot wait for a
+// failing non-critical check. A reason is required. The operator is the
+// caller in ctx (see WithCaller). The order is tagged force-merged and a
+// force_merged observation records who did it and why.
+//
+// This transition is deliberately not in validTransitions: AdvanceWorkOrder
+// can never skip the build gate, only this explicit call can.
+func (d *Dash) ForceMergeWorkOrder(ctx context.Context, id uuid.UUID, reason string) (*WorkOrder, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, fmt.Errorf("force merge requires a reason")
+	}
+	wo, err := d.GetWorkOrder(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	switch wo.Status {
+	case WOStatusMerged:
+		return wo, fmt.Errorf("work order %s is already merged", id)
+	case WOStatusRejected:
+		return wo, fmt.Errorf("work order %s is rejected and cannot be force-merged", id)
+	}
+
+	operator := CallerFromContext(ctx)
+	if operator == "" {
+		operator = "operator"
+	}
+	from := wo.Status
+
+	wo.Status = WOStatusMerged
+	wo.ForceMergedBy = operator
+	wo.ForceMergeReason = reason
+	wo.Tags = normalizeTags(append(wo.Tags, forceMergedTag))
+
+	d.appendWorkOrderEvent(ctx, wo, WOStatusMerged, operator, "FORCE MERGE: "+reason)
+	if err := d.saveWorkOrder(ctx, wo); err != nil {
+		return wo, fmt.Errorf("save work_order: %w", err)
+	}
+
+	obsData, _ := json.Marshal(map[string]any{
+		"operator":      operator,
+		"reason":        reason,
+		"from_status":   string(from),
+		"checks_status": wo.ChecksStatus,
+		"agent_key":     wo.AgentKey,
+		"branch":        wo.BranchName,
+	})
+	d.CreateObservation(ctx, &Observation{
+		NodeID:     wo.Node.ID,
+		Type:       "force_merged",
+		Data:       obsData,
+		ObservedAt: time.Now().UTC(),
+	})
+
+	d.recordCompletionReport(ctx, id)
+	return wo, nil
+}
@@ -0,0 +1,103 @@
+package dash
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// funnelStages is the path a work order takes from creation to merge.
+var funnelStages = []WorkOrderStatus{
+	WOStatusCreated,
+	WOStatusAssigned,
+	WOStatusMutating,
+	WOStatusBuildPassed,
+	WOStatusSynthesisPending,
+	WOStatusMergePending,
+	WOStatusMerged,
+}
+
+// Funnel shows how far the work orders created in a period got through the
+// pipeline.
+type Funnel struct {
+	Period TimeRange     `json:"period"`
+	Stages []FunnelStage `json:"stages"`
+	// WorstStage is the stage that loses the largest share of the orders
+	// reaching the stage before it, or "" when nothing was lost.
+	WorstStage WorkOrderStatus `json:"worst_stage,omitempty"`
+}
+
+// FunnelStage is one step of the funnel.
+type FunnelStage struct {
+	Status  WorkOrderStatus `json:"status"`
+	Reached int             `json:"reached"`
+	Pct     float64         `json:"pct"`      // reached / created, 0-1
+	DropOff float64         `json:"drop_off"` // share of the previous stage that never got here, 0-1
+}
+
+// WorkOrderFunnel counts how many of the work orders created in period
+// reached each pipeline stage, with the drop-off between stages. An order
+// counts as having reached every stage up to the furthest one it recorded,
+// so a stage that was skipped is not counted as a loss.
+func (d *Dash) WorkOrderFunnel(ctx context.Context, period TimeRange) (*Funnel, error) {
+	observations, err := d.ListAllObservationsByType(ctx, "work_order_event", period)
+	if err != nil {
+		return nil, err
+	}
+	grouped, err := groupEventsByNode(observations)
+	if err != nil {
+		return nil, err
+	}
+	f := workOrderFunnel(grouped)
+	f.Period = period
+	return f, nil
+}
+
+// workOrderFunnel builds the funnel from events grouped per work order.
+// Orders whose created event falls outside the period are left out.
+func workOrderFunnel(grouped map[uuid.UUID][]timestampedEvent) *Funnel {
+	stageIndex := make(map[string]int, len(funnelStages))
+	for i, s := range funnelStages {
+		stageIndex[string(s)] = i
+	}
+
+	reached := make([]int, len(funnelStages))
+	for _, events := range grouped {
+		furthest := -1
+		created := false
+		for _, te := range events {
+			i, ok := stageIndex[te.Event.Status]
+			if !ok {
+				continue
+			}
+			if i == 0 {
+				created = true
+			}
+			furthest = max(furthest, i)
+		}
+		if !created {
+			continue
+		}
+		for i := 0; i <= furthest; i++ {
+			reached[i]++
+		}
+	}
+
+	f := &Funnel{Stages: make([]FunnelStage, len(funnelStages))}
+	worst := 0.0
+	for i, s := range funnelStages {
+		st := FunnelStage{Status: s, Reached: reached[i]}
+		if reached[0] > 0 {
+			st.Pct = float64(reached[i]) / float64(reached[0])
+		}
+		if i > 0 && reached[i-1] > 0 {
+			st.DropOff = 1 - float64(reached[i])/float64(reached[i-1])
+			if st.DropOff > worst {
+				worst = st.DropOff
+				f.WorstStage = s
+			}
+		}
+		f.Stages[i] = st
+	}
+	return f
+}
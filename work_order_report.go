@@ -0,0 +1,134 @@
+package dash
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CompletionReport gathers what a merged work order produced: the task it
+// served, who did it, where the change landed and how long it took.
+type CompletionReport struct {
+	WorkOrderID    uuid.UUID     `json:"work_order_id"`
+	WorkOrder      string        `json:"work_order"`
+	Task           string        `json:"task,omitempty"`
+	AgentKey       string        `json:"agent_key,omitempty"`
+	Branch         string        `json:"branch,omitempty"`
+	PRUrl          string        `json:"pr_url,omitempty"`
+	FilesChanged   []string      `json:"files_changed,omitempty"`
+	Attempts       int           `json:"attempts"`
+	Duration       time.Duration `json:"duration"` // first event to merged
+	SynthesisScore *float64      `json:"synthesis_score,omitempty"`
+	MergedAt       time.Time     `json:"merged_at"`
+}
+
+// WorkOrderCompletionReport assembles the completion report of a merged work
+// order from its node and work_order_event history.
+func (d *Dash) WorkOrderCompletionReport(ctx context.Context, id uuid.UUID) (*CompletionReport, error) {
+	wo, err := d.GetWorkOrder(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if wo.Status != WOStatusMerged {
+		return nil, fmt.Errorf("work order %s is not merged (status %s)", wo.Node.Name, wo.Status)
+	}
+
+	obs, err := d.ListObservationsByNodeType(ctx, id, "work_order_event", TimeRange{
+		Start: wo.Node.CreatedAt.Add(-time.Minute),
+		End:   time.Now().Add(time.Minute),
+	})
+	if err != nil {
+		return nil, err
+	}
+	grouped, err := groupEventsByNode(obs)
+	if err != nil {
+		return nil, err
+	}
+
+	report := buildCompletionReport(wo, grouped[id])
+	if wo.TaskID != nil {
+		if task, err := d.GetNode(ctx, *wo.TaskID); err == nil {
+			report.Task = task.Name
+		}
+	}
+	return report, nil
+}
+
+// buildCompletionReport fills a report from a work order and its events
+// (oldest first). Attempts counts the mutating passes; a merged order that
+// never logged one still counts a single attempt.
+func buildCompletionReport(wo *WorkOrder, events []timestampedEvent) *CompletionReport {
+	r := &CompletionReport{
+		WorkOrderID:    wo.Node.ID,
+		WorkOrder:      wo.Node.Name,
+		AgentKey:       wo.AgentKey,
+		Branch:         wo.BranchName,
+		PRUrl:          wo.PRUrl,
+		FilesChanged:   wo.FilesChanged,
+		SynthesisScore: wo.SynthesisScore,
+		MergedAt:       wo.Node.UpdatedAt,
+	}
+	for _, te := range events {
+		switch WorkOrderStatus(te.Event.Status) {
+		case WOStatusMutating:
+			r.Attempts++
+		case WOStatusMerged:
+			r.MergedAt = te.At
+		}
+	}
+	r.Attempts = max(r.Attempts, 1)
+	if len(events) > 0 {
+		r.Duration = max(r.MergedAt.Sub(events[0].At), 0)
+	}
+	return r
+}
+
+// Summary renders the report as a short multi-line text for chat.
+func (r *CompletionReport) Summary() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "✔ %s merged", r.WorkOrder)
+	if r.AgentKey != "" {
+		fmt.Fprintf(&b, " by %s", r.AgentKey)
+	}
+	fmt.Fprintf(&b, " after %s (%d attempt", r.Duration.Round(time.Second), r.Attempts)
+	if r.Attempts != 1 {
+		b.WriteString("s")
+	}
+	b.WriteString(")\n")
+	if r.Task != "" {
+		fmt.Fprintf(&b, "  task:   %s\n", r.Task)
+	}
+	if r.Branch != "" {
+		fmt.Fprintf(&b, "  branch: %s\n", r.Branch)
+	}
+	if r.PRUrl != "" {
+		fmt.Fprintf(&b, "  PR:     %s\n", r.PRUrl)
+	}
+	if r.SynthesisScore != nil {
+		fmt.Fprintf(&b, "  score:  %.2f\n", *r.SynthesisScore)
+	}
+	if len(r.FilesChanged) > 0 {
+		fmt.Fprintf(&b, "  files:  %s\n", strings.Join(r.FilesChanged, ", "))
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// recordCompletionReport stores the report of a just-merged work order as a
+// work_order_completion observation for metrics.
+func (d *Dash) recordCompletionReport(ctx context.Context, id uuid.UUID) {
+	report, err := d.WorkOrderCompletionReport(ctx, id)
+	if err != nil {
+		return
+	}
+	data, _ := json.Marshal(report)
+	d.CreateObservation(ctx, &Observation{
+		NodeID:     id,
+		Type:       "work_order_completion",
+		Data:       data,
+		ObservedAt: report.MergedAt,
+	})
+}
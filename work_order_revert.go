@@ -0,0 +1,114 @@
+package dash
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RevertWorkOrder undoes a merged work order. It branches revert/<name> off
+// the order's base branch, reverts the order's commit there, pushes the
+// branch and opens a revert PR. The PR is tracked by a new work order in
+// merge_pending that links to the original with a reverts edge; the
+// original records it in RevertedBy.
+func (d *Dash) RevertWorkOrder(ctx context.Context, id uuid.UUID, git GitClient) (*WorkOrder, error) {
+	wo, err := d.GetWorkOrder(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("get work order: %w", err)
+	}
+	if wo.Status != WOStatusMerged {
+		return nil, fmt.Errorf("can only revert a merged work order, currently '%s'", wo.Status)
+	}
+	if wo.CommitHash == "" {
+		return nil, fmt.Errorf("work order %s has no commit hash to revert", id)
+	}
+	if wo.RevertedBy != nil {
+		return nil, fmt.Errorf("work order %s is already reverted by %s", id, wo.RevertedBy)
+	}
+
+	base := wo.BaseBranch
+	if base == "" {
+		base = "main"
+	}
+	branch := "revert/" + wo.Node.Name
+
+	if err := git.CheckoutBranch(base); err != nil {
+		return nil, fmt.Errorf("checkout %s: %w", base, err)
+	}
+	defer git.CheckoutBranch(base)
+	if err := git.CreateBranch(branch); err != nil {
+		return nil, fmt.Errorf("create branch %s: %w", branch, err)
+	}
+	if err := git.CheckoutBranch(branch); err != nil {
+		return nil, fmt.Errorf("checkout branch %s: %w", branch, err)
+	}
+	if err := git.RevertCommit(wo.CommitHash); err != nil {
+		return nil, fmt.Errorf("revert %s: %w", wo.CommitHash, err)
+	}
+	revertHash, _ := git.CurrentHash()
+	if err := git.PushBranch(branch); err != nil {
+		return nil, fmt.Errorf("push %s: %w", branch, err)
+	}
+	title := "Revert " + wo.Node.Name
+	body := fmt.Sprintf("Reverts work order %s (commit %s).", wo.Node.Name, wo.CommitHash)
+	if wo.PRUrl != "" {
+		body += "\n\nOriginal PR: " + wo.PRUrl
+	}
+	prNum, prURL, err := git.CreatePR(title, body, base)
+	if err != nil {
+		return nil, fmt.Errorf("create revert PR: %w", err)
+	}
+
+	actor := CallerFromContext(ctx)
+	if actor == "" {
+		actor = "operator"
+	}
+	revert, err := d.CreateWorkOrder(ctx, "revert-"+wo.Node.Name, wo.TaskID, wo.AgentKey, wo.ScopePaths, WorkOrderOpts{
+		BaseBranch:  base,
+		RepoRoot:    wo.RepoRoot,
+		Description: body,
+	})
+	if err != nil {
+		return nil, err
+	}
+	revert.Status = WOStatusMergePending
+	revert.BranchName = branch
+	revert.CommitHash = revertHash
+	revert.FilesChanged = wo.FilesChanged
+	revert.PRID = prNum
+	revert.PRUrl = prURL
+	revert.Reverts = &wo.Node.ID
+	d.appendWorkOrderEvent(ctx, revert, WOStatusMergePending, actor, fmt.Sprintf("revert PR #%d opened for %s", prNum, wo.CommitHash))
+	if err := d.saveWorkOrder(ctx, revert); err != nil {
+		return revert, fmt.Errorf("save revert work_order: %w", err)
+	}
+	d.CreateEdge(ctx, &Edge{
+		SourceID: revert.Node.ID,
+		TargetID: wo.Node.ID,
+		Relation: RelationReverts,
+	})
+
+	wo.RevertedBy = &revert.Node.ID
+	if err := d.saveWorkOrder(ctx, wo); err != nil {
+		return revert, fmt.Errorf("save work_order: %w", err)
+	}
+
+	obsData, _ := json.Marshal(map[string]any{
+		"actor":         actor,
+		"commit_hash":   wo.CommitHash,
+		"revert_branch": branch,
+		"revert_pr":     prURL,
+		"reverted_by":   revert.Node.ID.String(),
+	})
+	d.CreateObservation(ctx, &Observation{
+		NodeID:     wo.Node.ID,
+		Type:       "reverted",
+		Data:       obsData,
+		ObservedAt: time.Now().UTC(),
+	})
+
+	return revert, nil
+}
@@ -0,0 +1,166 @@
+package dash
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// peerReviewMaxDiff caps the diff injected into a review request, in bytes.
+const peerReviewMaxDiff = 20000
+
+// PeerReview is a review request handed to a reviewer agent.
+type PeerReview struct {
+	ReviewID    string    `json:"review_id"`
+	WorkOrderID uuid.UUID `json:"work_order_id"`
+	WorkOrder   string    `json:"work_order"`
+	Author      string    `json:"author,omitempty"`
+	Reviewer    string    `json:"reviewer"`
+	Prompt      string    `json:"prompt"`
+}
+
+// RequestPeerReview assigns the review of a synthesis_pending work order to
+// reviewerKey. The returned request carries a prompt with the order's
+// description, synthesis report and unified diff for the reviewer to act on
+// with SubmitPeerReview. An agent cannot review its own work order.
+func (d *Dash) RequestPeerReview(ctx context.Context, woID uuid.UUID, reviewerKey string) (*PeerReview, error) {
+	if reviewerKey == "" {
+		return nil, fmt.Errorf("reviewer is required")
+	}
+	wo, err := d.GetWorkOrder(ctx, woID)
+	if err != nil {
+		return nil, err
+	}
+	if wo.Status != WOStatusSynthesisPending {
+		return nil, fmt.Errorf("work order must be in synthesis_pending state, currently %s", wo.Status)
+	}
+	if reviewerKey == wo.AgentKey {
+		return nil, fmt.Errorf("agent %q cannot review its own work order", reviewerKey)
+	}
+
+	diff, _ := workOrderDiff(wo)
+
+	review := &PeerReview{
+		ReviewID:    fmt.Sprintf("review-%d-%s", time.Now().UnixMilli(), reviewerKey),
+		WorkOrderID: woID,
+		WorkOrder:   wo.Node.Name,
+		Author:      wo.AgentKey,
+		Reviewer:    reviewerKey,
+		Prompt:      peerReviewPrompt(wo, diff),
+	}
+
+	wo.Reviewer = reviewerKey
+	wo.ReviewVerdict = ""
+	wo.ReviewComments = ""
+	d.appendWorkOrderEvent(ctx, wo, wo.Status, reviewerKey, "peer review requested")
+	if err := d.saveWorkOrder(ctx, wo); err != nil {
+		return nil, fmt.Errorf("save work_order: %w", err)
+	}
+	return review, nil
+}
+
+// workOrderDiff returns the changes on the work order's branch against its
+// base. It diffs inside the order's worktree when that still exists and
+// falls back to comparing the branch from the repo root; the repo root's own
+// HEAD is never the order's branch.
+func workOrderDiff(wo *WorkOrder) (string, error) {
+	if wo.WorktreePath != "" {
+		if _, err := os.Stat(wo.WorktreePath); err == nil {
+			return NewExecGitClient(wo.WorktreePath).UnifiedDiff(wo.BaseBranch)
+		}
+	}
+	if wo.RepoRoot == "" || wo.BranchName == "" {
+		return "", fmt.Errorf("work order %s has no worktree or branch to diff", wo.Node.Name)
+	}
+	return NewExecGitClient(wo.RepoRoot).BranchDiff(wo.BaseBranch, wo.BranchName)
+}
+
+// SubmitPeerReview records the reviewer's verdict on a work order as a
+// peer_review observation and advances the order to merge_pending when
+// approved or rejects it otherwise. Rejections must explain why.
+func (d *Dash) SubmitPeerReview(ctx context.Context, woID uuid.UUID, approve bool, comments string) (*WorkOrder, error) {
+	comments = strings.TrimSpace(comments)
+	if !approve && comments == "" {
+		return nil, fmt.Errorf("comments are required when rejecting")
+	}
+	wo, err := d.GetWorkOrder(ctx, woID)
+	if err != nil {
+		return nil, err
+	}
+	if wo.Status != WOStatusSynthesisPending {
+		return nil, fmt.Errorf("work order must be in synthesis_pending state, currently %s", wo.Status)
+	}
+	if wo.Reviewer == "" {
+		return nil, fmt.Errorf("no peer review requested for work order %s", wo.Node.Name)
+	}
+
+	verdict, target := "rejected", WOStatusRejected
+	if approve {
+		verdict, target = "approved", WOStatusMergePending
+	}
+
+	obsData, _ := json.Marshal(map[string]any{
+		"reviewer": wo.Reviewer,
+		"author":   wo.AgentKey,
+		"verdict":  verdict,
+		"comments": comments,
+	})
+	if err := d.CreateObservation(ctx, &Observation{
+		NodeID:     woID,
+		Type:       "peer_review",
+		Data:       obsData,
+		ObservedAt: time.Now(),
+	}); err != nil {
+		return nil, fmt.Errorf("record review: %w", err)
+	}
+
+	wo.ReviewVerdict = verdict
+	wo.ReviewComments = comments
+	if err := d.saveWorkOrder(ctx, wo); err != nil {
+		return nil, fmt.Errorf("save work_order: %w", err)
+	}
+
+	detail := "peer review " + verdict
+	if comments != "" {
+		detail += ": " + truncateString(comments, 200)
+	}
+	return d.AdvanceWorkOrder(ctx, woID, target, wo.Reviewer, detail)
+}
+
+// peerReviewPrompt is the message injected into the reviewer's chat.
+func peerReviewPrompt(wo *WorkOrder, diff string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Review work order %s (id: %s)", wo.Node.Name, wo.Node.ID)
+	if wo.AgentKey != "" {
+		fmt.Fprintf(&b, " by %s", wo.AgentKey)
+	}
+	b.WriteString(".\n")
+	if wo.Description != "" {
+		fmt.Fprintf(&b, "\nDescription:\n%s\n", wo.Description)
+	}
+	if len(wo.FilesChanged) > 0 {
+		fmt.Fprintf(&b, "\nFiles changed: %s\n", strings.Join(wo.FilesChanged, ", "))
+	}
+	if wo.SynthesisReport != "" {
+		fmt.Fprintf(&b, "\nSynthesis report:\n%s\n", strings.TrimSpace(wo.SynthesisReport))
+	}
+	switch {
+	case diff == "":
+		b.WriteString("\nNo diff available; inspect the branch")
+		if wo.BranchName != "" {
+			fmt.Fprintf(&b, " %s", wo.BranchName)
+		}
+		b.WriteString(" directly.\n")
+	case len(diff) > peerReviewMaxDiff:
+		fmt.Fprintf(&b, "\nDiff (truncated to %d of %d bytes):\n%s\n", peerReviewMaxDiff, len(diff), truncateString(diff, peerReviewMaxDiff))
+	default:
+		fmt.Fprintf(&b, "\nDiff:\n%s\n", diff)
+	}
+	b.WriteString("\nWhen done, call submit_peer_review with work_order_id, approve and comments.")
+	return b.String()
+}
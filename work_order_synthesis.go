@@ -0,0 +1,191 @@
+package dash
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// synthesisPassThreshold is the minimum score for a work order to go to merge_pending.
+	synthesisPassThreshold = 0.7
+
+	// synthesisDiffBudget is the changed-line count a diff can reach at full
+	// marks; the size component falls to zero at twice the budget.
+	synthesisDiffBudget = 400
+
+	// Component weights; components without inputs are left out and the
+	// remaining weights renormalized.
+	synthesisWeightClaims   = 0.5
+	synthesisWeightDiff     = 0.2
+	synthesisWeightCriteria = 0.3
+)
+
+// synthesisInputs is everything the scorer looks at for one work order.
+type synthesisInputs struct {
+	WO          *WorkOrder
+	BuildResult *BuildGateResult
+	Diff        string // unified diff; empty when unavailable
+	Criteria    []string
+}
+
+// SynthesizeWorkOrder scores a work order in synthesis_pending between 0 and
+// 1 from its claim checks, diff size and acceptance criteria coverage, stores
+// the score and report on the work order, and advances it to merge_pending
+// or rejected depending on synthesisPassThreshold.
+func (d *Dash) SynthesizeWorkOrder(ctx context.Context, id uuid.UUID) (float64, string, error) {
+	wo, err := d.GetWorkOrder(ctx, id)
+	if err != nil {
+		return 0, "", fmt.Errorf("get work order: %w", err)
+	}
+	if wo.Status != WOStatusSynthesisPending {
+		return 0, "", fmt.Errorf("work order must be in synthesis_pending state, currently %s", wo.Status)
+	}
+
+	in := synthesisInputs{WO: wo}
+	if gateObs, err := d.GetLatestObservation(ctx, id, buildGateResultType); err == nil && gateObs != nil {
+		var r BuildGateResult
+		if json.Unmarshal(gateObs.Data, &r) == nil {
+			in.BuildResult = &r
+		}
+	}
+	if diff, err := workOrderDiff(wo); err == nil {
+		in.Diff = diff
+	}
+	if wo.TaskID != nil {
+		if task, err := d.GetNodeActive(ctx, *wo.TaskID); err == nil {
+			var taskData map[string]any
+			if json.Unmarshal(task.Data, &taskData) == nil {
+				in.Criteria = stringSlice(taskData, "acceptance_criteria")
+			}
+		}
+	}
+
+	score, report := scoreSynthesis(in)
+	wo.SynthesisScore = &score
+	wo.SynthesisReport = report
+	if err := d.saveWorkOrder(ctx, wo); err != nil {
+		return score, report, fmt.Errorf("save work_order: %w", err)
+	}
+
+	target := WOStatusMergePending
+	if score < synthesisPassThreshold {
+		target = WOStatusRejected
+	}
+	detail := fmt.Sprintf("synthesis score %.2f (threshold %.2f)", score, synthesisPassThreshold)
+	if _, err := d.AdvanceWorkOrder(ctx, id, target, "synthesizer", detail); err != nil {
+		return score, report, err
+	}
+	return score, report, nil
+}
+
+// scoreSynthesis combines the scoring components into a 0–1 score and a
+// line-per-component report.
+func scoreSynthesis(in synthesisInputs) (float64, string) {
+	var report strings.Builder
+	var total, weights float64
+
+	// Claims: the checks that can hold before merge.
+	checks := []DivergenceCheck{
+		checkTestsPass(in.BuildResult),
+		checkFilesCreated(in.WO),
+		checkNoViolations(in.BuildResult),
+	}
+	matched := 0
+	for _, c := range checks {
+		if c.Match {
+			matched++
+		} else {
+			report.WriteString(fmt.Sprintf("claim %q diverged: %s\n", c.Claim, c.Detail))
+		}
+	}
+	claims := float64(matched) / float64(len(checks))
+	total += synthesisWeightClaims * claims
+	weights += synthesisWeightClaims
+	report.WriteString(fmt.Sprintf("claims: %d/%d matched (%.2f)\n", matched, len(checks), claims))
+
+	// Diff size.
+	if in.Diff != "" {
+		lines := diffChangedLines(in.Diff)
+		size := 1.0
+		if lines > synthesisDiffBudget {
+			size = 1 - float64(lines-synthesisDiffBudget)/float64(synthesisDiffBudget)
+			if size < 0 {
+				size = 0
+			}
+		}
+		total += synthesisWeightDiff * size
+		weights += synthesisWeightDiff
+		report.WriteString(fmt.Sprintf("diff: %d changed lines, budget %d (%.2f)\n", lines, synthesisDiffBudget, size))
+	} else {
+		report.WriteString("diff: unavailable, not scored\n")
+	}
+
+	// Acceptance criteria.
+	if len(in.Criteria) > 0 {
+		haystack := strings.ToLower(in.Diff + "\n" + strings.Join(in.WO.FilesChanged, "\n"))
+		addressed := 0
+		for _, c := range in.Criteria {
+			if criterionAddressed(c, haystack) {
+				addressed++
+			} else {
+				report.WriteString(fmt.Sprintf("criterion not evidently addressed: %s\n", c))
+			}
+		}
+		cov := float64(addressed) / float64(len(in.Criteria))
+		total += synthesisWeightCriteria * cov
+		weights += synthesisWeightCriteria
+		report.WriteString(fmt.Sprintf("criteria: %d/%d addressed (%.2f)\n", addressed, len(in.Criteria), cov))
+	} else {
+		report.WriteString("criteria: none defined, not scored\n")
+	}
+
+	score := total / weights
+	verdict := "pass"
+	if score < synthesisPassThreshold {
+		verdict = "reject"
+	}
+	report.WriteString(fmt.Sprintf("score: %.2f → %s", score, verdict))
+	return score, report.String()
+}
+
+// diffChangedLines counts added and removed lines in a unified diff.
+func diffChangedLines(diff string) int {
+	n := 0
+	for _, line := range strings.Split(diff, "\n") {
+		if strings.HasPrefix(line, "+++") || strings.HasPrefix(line, "---") {
+			continue
+		}
+		if strings.HasPrefix(line, "+") || strings.HasPrefix(line, "-") {
+			n++
+		}
+	}
+	return n
+}
+
+// criterionAddressed reports whether at least half of a criterion's
+// keywords (words of four or more letters) occur in haystack, which must be
+// lower-cased. A criterion without keywords counts as addressed.
+func criterionAddressed(criterion, haystack string) bool {
+	var keywords []string
+	for _, w := range strings.FieldsFunc(strings.ToLower(criterion), func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '_')
+	}) {
+		if len(w) >= 4 {
+			keywords = append(keywords, w)
+		}
+	}
+	if len(keywords) == 0 {
+		return true
+	}
+	hits := 0
+	for _, w := range keywords {
+		if strings.Contains(haystack, w) {
+			hits++
+		}
+	}
+	return hits*2 >= len(keywords)
+}
@@ -1,8 +1,15 @@
 package dash
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -166,3 +173,568 @@ func TestWorkOrderSerialization(t *testing.T) {
 		t.Error("last_event not preserved through serialization")
 	}
 }
+
+func TestFilterStuckWorkOrders(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) *WorkOrderEvent {
+		return &WorkOrderEvent{At: now.Add(-d).Format(time.RFC3339)}
+	}
+
+	fresh := &WorkOrder{Status: WOStatusMutating, LastEvent: at(5 * time.Minute)}
+	stale := &WorkOrder{Status: WOStatusMutating, LastEvent: at(2 * time.Hour)}
+	staleSynth := &WorkOrder{Status: WOStatusSynthesisPending, LastEvent: at(3 * time.Hour)}
+	merged := &WorkOrder{Status: WOStatusMerged, LastEvent: at(10 * time.Hour)}
+	// No inline event: falls back to node updated_at
+	noEvent := &WorkOrder{Status: WOStatusAssigned, Node: &Node{UpdatedAt: now.Add(-90 * time.Minute)}}
+
+	got := filterStuckWorkOrders([]*WorkOrder{fresh, stale, staleSynth, merged, noEvent}, now, time.Hour)
+
+	want := []*WorkOrder{stale, staleSynth, noEvent}
+	if len(got) != len(want) {
+		t.Fatalf("got %d stuck orders, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("stuck[%d] status = %q, want %q", i, got[i].Status, want[i].Status)
+		}
+	}
+}
+
+func TestWorkOrderLastActivityFallback(t *testing.T) {
+	updated := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	wo := &WorkOrder{
+		Node:      &Node{UpdatedAt: updated},
+		LastEvent: &WorkOrderEvent{At: "not-a-time"},
+	}
+	if got := wo.lastActivity(); !got.Equal(updated) {
+		t.Errorf("lastActivity = %v, want %v", got, updated)
+	}
+
+	if got := (&WorkOrder{}).lastActivity(); !got.IsZero() {
+		t.Errorf("lastActivity with no data = %v, want zero", got)
+	}
+}
+
+func TestWorkOrderRetryBackoff(t *testing.T) {
+	base := 30 * time.Second
+	tests := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{1, 30 * time.Second},
+		{2, time.Minute},
+		{3, 2 * time.Minute},
+		{20, maxWorkOrderRetryBackoff},
+	}
+	for _, tt := range tests {
+		if got := workOrderRetryBackoff(base, tt.attempt); got != tt.want {
+			t.Errorf("backoff(attempt %d) = %v, want %v", tt.attempt, got, tt.want)
+		}
+	}
+	if got := workOrderRetryBackoff(0, 1); got != defaultWorkOrderRetryBackoff {
+		t.Errorf("backoff with zero base = %v, want default %v", got, defaultWorkOrderRetryBackoff)
+	}
+}
+
+func TestApplyWorkOrderRetryBackoff(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	base := time.Minute
+	wo := &WorkOrder{Status: WOStatusMutating}
+
+	status, _, err := applyWorkOrderRetry(wo, WOStatusBuildFailed, "tests failed", now, base)
+	if err != nil || status != WOStatusBuildFailed {
+		t.Fatalf("first failure = %s, %v; want build_failed", status, err)
+	}
+	if want := now.Add(time.Minute).Format(time.RFC3339); wo.NextAttemptAt != want {
+		t.Errorf("next_attempt_at = %q, want %q", wo.NextAttemptAt, want)
+	}
+	wo.Status = status
+
+	// Retrying inside the backoff window is refused and leaves the order alone.
+	if _, _, err := applyWorkOrderRetry(wo, WOStatusMutating, "", now.Add(30*time.Second), base); !errors.Is(err, ErrWorkOrderBackoff) {
+		t.Fatalf("early retry err = %v, want ErrWorkOrderBackoff", err)
+	}
+	if wo.Attempt != 1 || wo.NextAttemptAt == "" {
+		t.Errorf("early retry changed the order: attempt=%d next=%q", wo.Attempt, wo.NextAttemptAt)
+	}
+
+	// Once elapsed the retry goes through and clears the backoff.
+	retryAt := now.Add(time.Minute)
+	if _, _, err := applyWorkOrderRetry(wo, WOStatusMutating, "", retryAt, base); err != nil {
+		t.Fatalf("retry after backoff: %v", err)
+	}
+	if wo.NextAttemptAt != "" {
+		t.Errorf("next_attempt_at = %q after retry, want cleared", wo.NextAttemptAt)
+	}
+	wo.Status = WOStatusMutating
+
+	// The second failure waits twice as long.
+	if _, _, err := applyWorkOrderRetry(wo, WOStatusBuildFailed, "tests failed", retryAt, base); err != nil {
+		t.Fatal(err)
+	}
+	if want := retryAt.Add(2 * time.Minute).Format(time.RFC3339); wo.NextAttemptAt != want {
+		t.Errorf("second next_attempt_at = %q, want %q", wo.NextAttemptAt, want)
+	}
+	wo.Status = WOStatusBuildFailed
+
+	// Rejecting a failed order is never held back by the backoff.
+	if _, _, err := applyWorkOrderRetry(wo, WOStatusRejected, "giving up", retryAt, base); err != nil {
+		t.Errorf("reject during backoff: %v", err)
+	}
+}
+
+func TestApplyWorkOrderRetryMaxAttempts(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	wo := &WorkOrder{Status: WOStatusMutating, Attempt: maxWorkOrderAttempts - 1}
+
+	status, detail, err := applyWorkOrderRetry(wo, WOStatusBuildFailed, "tests failed", now, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status != WOStatusRejected {
+		t.Errorf("status = %s, want rejected", status)
+	}
+	if !strings.Contains(detail, "max attempts") || wo.LastError != detail {
+		t.Errorf("detail = %q, last_error = %q", detail, wo.LastError)
+	}
+	if wo.NextAttemptAt != "" {
+		t.Errorf("rejected order has next_attempt_at = %q", wo.NextAttemptAt)
+	}
+}
+
+func TestAssignWorkOrderConcurrent(t *testing.T) {
+	d := testDash(t)
+	ctx := context.Background()
+
+	wo, err := d.CreateWorkOrder(ctx, fmt.Sprintf("test-assign-race-%d", time.Now().UnixNano()), nil, "", []string{"/tmp/x.go"}, WorkOrderOpts{})
+	if err != nil {
+		t.Fatalf("create work order: %v", err)
+	}
+	t.Cleanup(func() { d.SoftDeleteNode(ctx, wo.Node.ID) })
+
+	agents := []string{"agent-a", "agent-b"}
+	errs := make([]error, len(agents))
+	var wg sync.WaitGroup
+	for i, key := range agents {
+		wg.Add(1)
+		go func(i int, key string) {
+			defer wg.Done()
+			_, errs[i] = d.AssignWorkOrder(ctx, wo.Node.ID, key, "")
+		}(i, key)
+	}
+	wg.Wait()
+
+	winner := ""
+	for i, err := range errs {
+		switch {
+		case err == nil:
+			if winner != "" {
+				t.Fatalf("both %s and %s were assigned", winner, agents[i])
+			}
+			winner = agents[i]
+		case !errors.Is(err, ErrWorkOrderAlreadyAssigned):
+			t.Errorf("%s: err = %v, want ErrWorkOrderAlreadyAssigned", agents[i], err)
+		}
+	}
+	if winner == "" {
+		t.Fatal("no assign succeeded")
+	}
+
+	got, err := d.GetWorkOrder(ctx, wo.Node.ID)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if got.Status != WOStatusAssigned || got.AgentKey != winner {
+		t.Errorf("stored status=%s agent=%s, want assigned/%s", got.Status, got.AgentKey, winner)
+	}
+	if _, err := d.AssignWorkOrder(ctx, wo.Node.ID, winner, ""); err != nil {
+		t.Errorf("re-assign to winner should be idempotent: %v", err)
+	}
+}
+
+func TestScoreSynthesis(t *testing.T) {
+	passing := &BuildGateResult{
+		Test:  BuildResult{Passed: true},
+		Scope: ScopeCheckResult{Passed: true},
+		AST:   ASTValidationResult{Passed: true},
+	}
+	diff := "--- a/cache.go\n+++ b/cache.go\n+func evictExpired() {}\n+// cache eviction runs hourly\n"
+
+	t.Run("clean order passes", func(t *testing.T) {
+		score, report := scoreSynthesis(synthesisInputs{
+			WO:          &WorkOrder{FilesChanged: []string{"cache.go"}},
+			BuildResult: passing,
+			Diff:        diff,
+			Criteria:    []string{"expired cache entries are evicted"},
+		})
+		if score < synthesisPassThreshold {
+			t.Errorf("score = %.2f, want >= %.2f\n%s", score, synthesisPassThreshold, report)
+		}
+	})
+
+	t.Run("diverged claims reject", func(t *testing.T) {
+		score, report := scoreSynthesis(synthesisInputs{
+			WO:   &WorkOrder{},
+			Diff: diff,
+		})
+		if score >= synthesisPassThreshold {
+			t.Errorf("score = %.2f, want < %.2f\n%s", score, synthesisPassThreshold, report)
+		}
+		if !strings.Contains(report, `claim "tests pass" diverged`) {
+			t.Errorf("report missing diverged claim:\n%s", report)
+		}
+	})
+
+	t.Run("oversized diff loses size marks", func(t *testing.T) {
+		big := strings.Repeat("+x\n", 2*synthesisDiffBudget)
+		score, _ := scoreSynthesis(synthesisInputs{
+			WO:          &WorkOrder{FilesChanged: []string{"x.go"}},
+			BuildResult: passing,
+			Diff:        big,
+		})
+		want := synthesisWeightClaims / (synthesisWeightClaims + synthesisWeightDiff)
+		if math.Abs(score-want) > 1e-9 {
+			t.Errorf("score = %.3f, want %.3f", score, want)
+		}
+	})
+}
+
+func TestUnmetPrerequisites(t *testing.T) {
+	prereqs := []*WorkOrder{
+		{Node: &Node{Name: "api-change"}, Status: WOStatusMerged},
+		{Node: &Node{Name: "schema"}, Status: WOStatusMergePending},
+		{Node: &Node{Name: "abandoned"}, Status: WOStatusRejected},
+	}
+	got := unmetPrerequisites(prereqs)
+	want := []string{"schema (merge_pending)", "abandoned (rejected)"}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("unmetPrerequisites = %v, want %v", got, want)
+	}
+	if unmetPrerequisites(prereqs[:1]) != nil {
+		t.Error("merged prerequisites should not be unmet")
+	}
+}
+
+func TestWorkOrderDependencies(t *testing.T) {
+	d := testDash(t)
+	ctx := context.Background()
+	prefix := fmt.Sprintf("test-wo-deps-%d", time.Now().UnixNano())
+
+	create := func(name string) *WorkOrder {
+		wo, err := d.CreateWorkOrder(ctx, prefix+"-"+name, nil, "", []string{"/tmp/x.go"}, WorkOrderOpts{})
+		if err != nil {
+			t.Fatalf("create %s: %v", name, err)
+		}
+		t.Cleanup(func() { d.SoftDeleteNode(ctx, wo.Node.ID) })
+		return wo
+	}
+	isReady := func(wo *WorkOrder) bool {
+		ready, err := d.ReadyWorkOrders(ctx)
+		if err != nil {
+			t.Fatalf("ready: %v", err)
+		}
+		for _, r := range ready {
+			if r.Node.ID == wo.Node.ID {
+				return true
+			}
+		}
+		return false
+	}
+
+	api, feature := create("api"), create("feature")
+	if err := d.AddWorkOrderDependency(ctx, feature.Node.ID, api.Node.ID); err != nil {
+		t.Fatalf("add dependency: %v", err)
+	}
+	if err := d.AddWorkOrderDependency(ctx, feature.Node.ID, api.Node.ID); err != nil {
+		t.Fatalf("re-adding a dependency should be a no-op: %v", err)
+	}
+	if err := d.AddWorkOrderDependency(ctx, api.Node.ID, feature.Node.ID); err == nil {
+		t.Fatal("expected cycle to be refused")
+	}
+
+	if !isReady(api) || isReady(feature) {
+		t.Fatalf("before merge: api ready=%v feature ready=%v, want true/false", isReady(api), isReady(feature))
+	}
+	if _, err := d.AssignWorkOrder(ctx, feature.Node.ID, "agent-a", ""); !errors.Is(err, ErrWorkOrderBlocked) {
+		t.Fatalf("assign blocked order: err = %v, want ErrWorkOrderBlocked", err)
+	}
+
+	api.Status = WOStatusMerged
+	if err := d.saveWorkOrder(ctx, api); err != nil {
+		t.Fatalf("mark merged: %v", err)
+	}
+	if !isReady(feature) {
+		t.Fatal("feature should be ready once api is merged")
+	}
+	if _, err := d.AssignWorkOrder(ctx, feature.Node.ID, "agent-a", ""); err != nil {
+		t.Fatalf("assign after merge: %v", err)
+	}
+}
+
+func TestPeerReviewPrompt(t *testing.T) {
+	wo := &WorkOrder{
+		Node:         &Node{ID: uuid.New(), Name: "wo-login"},
+		AgentKey:     "cockpit-backend",
+		BranchName:   "agent/cockpit-backend/wo-login",
+		Description:  "Add login form",
+		FilesChanged: []string{"login.go"},
+	}
+
+	p := peerReviewPrompt(wo, "+func Login() {}\n")
+	for _, want := range []string{"wo-login", "by cockpit-backend", "Add login form", "login.go", "+func Login() {}", "submit_peer_review"} {
+		if !strings.Contains(p, want) {
+			t.Errorf("prompt missing %q:\n%s", want, p)
+		}
+	}
+
+	if p := peerReviewPrompt(wo, ""); !strings.Contains(p, "inspect the branch agent/cockpit-backend/wo-login") {
+		t.Errorf("prompt without diff should point at the branch:\n%s", p)
+	}
+
+	big := strings.Repeat("+x\n", peerReviewMaxDiff)
+	if p := peerReviewPrompt(wo, big); !strings.Contains(p, "truncated") || len(p) > peerReviewMaxDiff+1000 {
+		t.Errorf("large diff not truncated: prompt is %d bytes", len(p))
+	}
+}
+
+func TestPeerReviewWorkflow(t *testing.T) {
+	d := testDash(t)
+	ctx := context.Background()
+
+	create := func(suffix string) *WorkOrder {
+		wo, err := d.CreateWorkOrder(ctx, fmt.Sprintf("test-peer-review-%s-%d", suffix, time.Now().UnixNano()), nil, "agent-author", []string{"/tmp/x.go"}, WorkOrderOpts{})
+		if err != nil {
+			t.Fatalf("create work order: %v", err)
+		}
+		t.Cleanup(func() { d.SoftDeleteNode(ctx, wo.Node.ID) })
+		return wo
+	}
+
+	wo := create("approve")
+	if _, err := d.RequestPeerReview(ctx, wo.Node.ID, "agent-reviewer"); err == nil {
+		t.Fatal("expected review of a created work order to be refused")
+	}
+	wo.Status = WOStatusSynthesisPending
+	if err := d.saveWorkOrder(ctx, wo); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := d.SubmitPeerReview(ctx, wo.Node.ID, true, ""); err == nil {
+		t.Fatal("expected submit without a requested review to fail")
+	}
+	if _, err := d.RequestPeerReview(ctx, wo.Node.ID, "agent-author"); err == nil {
+		t.Fatal("expected self-review to be refused")
+	}
+
+	review, err := d.RequestPeerReview(ctx, wo.Node.ID, "agent-reviewer")
+	if err != nil {
+		t.Fatalf("request review: %v", err)
+	}
+	if review.Reviewer != "agent-reviewer" || review.Prompt == "" {
+		t.Errorf("review = %+v", review)
+	}
+
+	got, err := d.SubmitPeerReview(ctx, wo.Node.ID, true, "looks good")
+	if err != nil {
+		t.Fatalf("submit review: %v", err)
+	}
+	if got.Status != WOStatusMergePending || got.ReviewVerdict != "approved" {
+		t.Errorf("after approve: status=%s verdict=%q", got.Status, got.ReviewVerdict)
+	}
+	if obs, err := d.GetLatestObservation(ctx, wo.Node.ID, "peer_review"); err != nil || obs == nil {
+		t.Errorf("peer_review observation not recorded: %v", err)
+	}
+
+	rejected := create("reject")
+	rejected.Status = WOStatusSynthesisPending
+	if err := d.saveWorkOrder(ctx, rejected); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := d.RequestPeerReview(ctx, rejected.Node.ID, "agent-reviewer"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := d.SubmitPeerReview(ctx, rejected.Node.ID, false, ""); err == nil {
+		t.Fatal("expected rejection without comments to fail")
+	}
+	got, err = d.SubmitPeerReview(ctx, rejected.Node.ID, false, "missing tests")
+	if err != nil {
+		t.Fatalf("submit rejection: %v", err)
+	}
+	if got.Status != WOStatusRejected || got.ReviewComments != "missing tests" {
+		t.Errorf("after reject: status=%s comments=%q", got.Status, got.ReviewComments)
+	}
+}
+
+func TestBuildCompletionReport(t *testing.T) {
+	start := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	score := 0.9
+	wo := &WorkOrder{
+		Node:           &Node{ID: uuid.New(), Name: "wo-report", UpdatedAt: start.Add(time.Hour)},
+		Status:         WOStatusMerged,
+		AgentKey:       "cockpit-backend",
+		BranchName:     "agent/cockpit-backend/wo-report",
+		PRUrl:          "https://example.com/pr/7",
+		FilesChanged:   []string{"a.go", "b.go"},
+		SynthesisScore: &score,
+	}
+	at := func(min int, status WorkOrderStatus) timestampedEvent {
+		return timestampedEvent{Event: woEventData{Status: string(status)}, At: start.Add(time.Duration(min) * time.Minute)}
+	}
+	r := buildCompletionReport(wo, []timestampedEvent{
+		at(0, WOStatusCreated),
+		at(1, WOStatusAssigned),
+		at(2, WOStatusMutating),
+		at(5, WOStatusBuildFailed),
+		at(6, WOStatusMutating),
+		at(9, WOStatusBuildPassed),
+		at(12, WOStatusMergePending),
+		at(15, WOStatusMerged),
+	})
+
+	if r.Attempts != 2 {
+		t.Errorf("Attempts = %d, want 2", r.Attempts)
+	}
+	if r.Duration != 15*time.Minute {
+		t.Errorf("Duration = %v, want 15m", r.Duration)
+	}
+	if !r.MergedAt.Equal(start.Add(15 * time.Minute)) {
+		t.Errorf("MergedAt = %v", r.MergedAt)
+	}
+	sum := r.Summary()
+	for _, want := range []string{"wo-report merged by cockpit-backend after 15m0s (2 attempts)", wo.PRUrl, "a.go, b.go", "0.90"} {
+		if !strings.Contains(sum, want) {
+			t.Errorf("Summary missing %q:\n%s", want, sum)
+		}
+	}
+
+	if r := buildCompletionReport(wo, nil); r.Attempts != 1 || r.Duration != 0 {
+		t.Errorf("no events: attempts=%d duration=%v", r.Attempts, r.Duration)
+	}
+}
+
+func TestWorkOrderCompletionReport(t *testing.T) {
+	d := testDash(t)
+	ctx := context.Background()
+
+	wo, err := d.CreateWorkOrder(ctx, fmt.Sprintf("test-completion-%d", time.Now().UnixNano()), nil, "agent-author", []string{"/tmp/x.go"}, WorkOrderOpts{})
+	if err != nil {
+		t.Fatalf("create work order: %v", err)
+	}
+	t.Cleanup(func() { d.SoftDeleteNode(ctx, wo.Node.ID) })
+
+	if _, err := d.WorkOrderCompletionReport(ctx, wo.Node.ID); err == nil {
+		t.Fatal("expected an error for a work order that is not merged")
+	}
+
+	wo.Status = WOStatusMergePending
+	if err := d.saveWorkOrder(ctx, wo); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := d.AdvanceWorkOrder(ctx, wo.Node.ID, WOStatusMerged, "test", "merged"); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := d.WorkOrderCompletionReport(ctx, wo.Node.ID)
+	if err != nil {
+		t.Fatalf("WorkOrderCompletionReport: %v", err)
+	}
+	if r.WorkOrder != wo.Node.Name || r.AgentKey != "agent-author" || r.Attempts != 1 {
+		t.Errorf("report = %+v", r)
+	}
+	if obs, err := d.GetLatestObservation(ctx, wo.Node.ID, "work_order_completion"); err != nil || obs == nil {
+		t.Errorf("work_order_completion observation not recorded: %v", err)
+	}
+}
+
+func TestForceMergeWorkOrder(t *testing.T) {
+	d := testDash(t)
+	ctx := WithCaller(context.Background(), "ops-alice")
+	name := fmt.Sprintf("test-wo-force-%d", time.Now().UnixNano())
+
+	wo, err := d.CreateWorkOrder(ctx, name, nil, "", []string{"/tmp/x.go"}, WorkOrderOpts{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { d.SoftDeleteNode(ctx, wo.Node.ID) })
+	if err := d.UpdateWorkOrderChecks(ctx, wo.Node.ID, "fail"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := d.AdvanceWorkOrder(ctx, wo.Node.ID, WOStatusMerged, "test", ""); err == nil {
+		t.Fatal("AdvanceWorkOrder must not skip to merged")
+	}
+	if _, err := d.ForceMergeWorkOrder(ctx, wo.Node.ID, "  "); err == nil {
+		t.Fatal("expected an error without a reason")
+	}
+
+	merged, err := d.ForceMergeWorkOrder(ctx, wo.Node.ID, "hotfix for prod outage")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if merged.Status != WOStatusMerged || merged.ForceMergedBy != "ops-alice" {
+		t.Errorf("status=%s by=%q, want merged by ops-alice", merged.Status, merged.ForceMergedBy)
+	}
+	if tags := NodeTags(merged.Node); len(tags) != 1 || tags[0] != forceMergedTag {
+		t.Errorf("tags = %v, want [%s]", tags, forceMergedTag)
+	}
+	if _, err := d.ForceMergeWorkOrder(ctx, wo.Node.ID, "again"); err == nil {
+		t.Error("expected an error force-merging a merged order")
+	}
+}
+
+func TestRevertWorkOrder(t *testing.T) {
+	d := testDash(t)
+	ctx := context.Background()
+	name := fmt.Sprintf("test-wo-revert-%d", time.Now().UnixNano())
+
+	wo, err := d.CreateWorkOrder(ctx, name, nil, "", []string{"/tmp/x.go"}, WorkOrderOpts{BaseBranch: "main"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { d.SoftDeleteNode(ctx, wo.Node.ID) })
+
+	git := NewFakeGitClient()
+	if _, err := d.RevertWorkOrder(ctx, wo.Node.ID, git); err == nil {
+		t.Fatal("expected an error reverting an unmerged order")
+	}
+	if err := d.UpdateWorkOrderFiles(ctx, wo.Node.ID, []string{"/tmp/x.go"}, "abc123"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := d.ForceMergeWorkOrder(ctx, wo.Node.ID, "test setup"); err != nil {
+		t.Fatal(err)
+	}
+
+	revert, err := d.RevertWorkOrder(ctx, wo.Node.ID, git)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { d.SoftDeleteNode(ctx, revert.Node.ID) })
+
+	branch := "revert/" + name
+	if !git.Branches[branch] {
+		t.Errorf("revert branch %s not created", branch)
+	}
+	if len(git.Reverted) != 1 || git.Reverted[0] != "abc123" {
+		t.Errorf("Reverted = %v, want [abc123]", git.Reverted)
+	}
+	pr, ok := git.PRs[revert.PRID]
+	if !ok || pr.Title != "Revert "+name {
+		t.Errorf("revert PR = %+v, want title %q", pr, "Revert "+name)
+	}
+	if git.CurrentBranch != "main" {
+		t.Errorf("CurrentBranch = %q, want main restored", git.CurrentBranch)
+	}
+	if revert.Status != WOStatusMergePending || revert.Reverts == nil || *revert.Reverts != wo.Node.ID {
+		t.Errorf("revert status=%s reverts=%v", revert.Status, revert.Reverts)
+	}
+
+	orig, err := d.GetWorkOrder(ctx, wo.Node.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if orig.RevertedBy == nil || *orig.RevertedBy != revert.Node.ID {
+		t.Errorf("RevertedBy = %v, want %s", orig.RevertedBy, revert.Node.ID)
+	}
+	if _, err := d.RevertWorkOrder(ctx, wo.Node.ID, git); err == nil {
+		t.Error("expected an error reverting twice")
+	}
+}
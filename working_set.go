@@ -3,6 +3,8 @@ package dash
 import (
 	"context"
 	"time"
+
+	"github.com/lib/pq"
 )
 
 // WorkingSet represents the bounded set of canonical nodes needed for reasoning.
@@ -61,19 +63,14 @@ const (
 		ORDER BY created_at ASC
 		LIMIT 5`
 
-	queryGetRecentInsights = `
-		SELECT id, layer, type, name, data, created_at, updated_at, deleted_at
-		FROM nodes
-		WHERE layer = 'CONTEXT' AND type = 'insight'
-		  AND deleted_at IS NULL
-		ORDER BY created_at DESC`
-
-	queryGetRecentDecisions = `
+	queryNodesByTimeAndType = `
 		SELECT id, layer, type, name, data, created_at, updated_at, deleted_at
 		FROM nodes
-		WHERE layer = 'CONTEXT' AND type = 'decision'
+		WHERE layer = $1 AND type = ANY($2)
+		  AND updated_at >= $3
 		  AND deleted_at IS NULL
-		ORDER BY created_at DESC`
+		ORDER BY updated_at DESC
+		LIMIT NULLIF($4, 0)`
 
 	queryGetPromotionCandidates = `
 		SELECT id, layer, type, name, data, created_at, updated_at, deleted_at
@@ -128,12 +125,12 @@ func (d *Dash) AssembleWorkingSet(ctx context.Context) (*WorkingSet, error) {
 	}
 
 	// Recent insights (max 5)
-	if nodes, err := d.queryMultipleNodes(ctx, queryGetRecentInsights, timeout); err == nil {
+	if nodes, err := d.recentContextNodes(ctx, 5, "insight"); err == nil {
 		ws.RecentInsights = nodes
 	}
 
 	// Recent decisions (max 3)
-	if nodes, err := d.queryMultipleNodes(ctx, queryGetRecentDecisions, timeout); err == nil {
+	if nodes, err := d.recentContextNodes(ctx, 3, "decision"); err == nil {
 		ws.RecentDecisions = nodes
 	}
 
@@ -157,7 +154,7 @@ func (d *Dash) QueryContextFrame(ctx context.Context) (*Node, error) {
 
 // QueryRecentDecisions returns recent decision nodes.
 func (d *Dash) QueryRecentDecisions(ctx context.Context) ([]*Node, error) {
-	return d.queryMultipleNodes(ctx, queryGetRecentDecisions, 2*time.Second)
+	return d.recentContextNodes(ctx, 0, "decision")
 }
 
 // QueryActiveAgents returns active agent nodes from the AUTOMATION layer.
@@ -175,6 +172,34 @@ func (d *Dash) QueryActiveTasks(ctx context.Context) ([]*Node, error) {
 	return d.queryMultipleNodes(ctx, queryGetActiveTasks, 2*time.Second)
 }
 
+// SearchByTimeAndType returns nodes in layer whose type is one of types and
+// that were updated at or after since, most recently updated first.
+// A zero since matches all nodes; limit <= 0 means no limit.
+func (d *Dash) SearchByTimeAndType(ctx context.Context, layer Layer, types []string, since time.Time, limit int) ([]*Node, error) {
+	if len(types) == 0 {
+		return nil, nil
+	}
+	if limit < 0 {
+		limit = 0
+	}
+	rows, err := d.db.QueryContext(ctx, queryNodesByTimeAndType, layer, pq.Array(types), since, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanNodes(rows)
+}
+
+// recentContextNodes is SearchByTimeAndType over all of CONTEXT with the
+// working-set query timeout.
+func (d *Dash) recentContextNodes(ctx context.Context, limit int, types ...string) ([]*Node, error) {
+	qCtx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	return d.SearchByTimeAndType(qCtx, LayerContext, types, time.Time{}, limit)
+}
+
 func (d *Dash) querySingleNode(ctx context.Context, query string, timeout time.Duration) (*Node, error) {
 	qCtx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
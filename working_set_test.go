@@ -0,0 +1,58 @@
+package dash
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestSearchByTimeAndType(t *testing.T) {
+	d := testDash(t)
+	ctx := context.Background()
+	since := time.Now().Add(-time.Second)
+	suffix := time.Now().UnixNano()
+
+	var created []*Node
+	for i, typ := range []string{"insight", "decision", "constraint"} {
+		n := &Node{Layer: LayerContext, Type: typ, Name: fmt.Sprintf("test-feed-%d-%d", suffix, i)}
+		if err := d.CreateNode(ctx, n); err != nil {
+			t.Fatalf("create %s: %v", typ, err)
+		}
+		created = append(created, n)
+		t.Cleanup(func() { d.SoftDeleteNode(ctx, n.ID) })
+	}
+
+	nodes, err := d.SearchByTimeAndType(ctx, LayerContext, []string{"insight", "decision"}, since, 0)
+	if err != nil {
+		t.Fatalf("search: %v", err)
+	}
+	found := map[string]bool{}
+	for i, n := range nodes {
+		found[n.Name] = true
+		if n.Type != "insight" && n.Type != "decision" {
+			t.Errorf("unexpected type %s", n.Type)
+		}
+		if i > 0 && n.UpdatedAt.After(nodes[i-1].UpdatedAt) {
+			t.Errorf("results not ordered by updated_at DESC at %d", i)
+		}
+	}
+	if !found[created[0].Name] || !found[created[1].Name] {
+		t.Errorf("expected insight and decision in results, got %d nodes", len(nodes))
+	}
+	if found[created[2].Name] {
+		t.Error("constraint should be excluded")
+	}
+
+	limited, err := d.SearchByTimeAndType(ctx, LayerContext, []string{"insight", "decision"}, since, 1)
+	if err != nil {
+		t.Fatalf("search limited: %v", err)
+	}
+	if len(limited) != 1 {
+		t.Errorf("limit 1 returned %d nodes", len(limited))
+	}
+
+	if nodes, err := d.SearchByTimeAndType(ctx, LayerContext, nil, since, 0); err != nil || nodes != nil {
+		t.Errorf("empty types = %v, %v; want nil, nil", nodes, err)
+	}
+}